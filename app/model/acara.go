@@ -0,0 +1,134 @@
+package model
+
+import "time"
+
+// Jenis acara
+const (
+	JenisAcaraReuni   = "reuni"
+	JenisAcaraKarier  = "karier"
+	JenisAcaraSeminar = "seminar"
+	JenisAcaraLainnya = "lainnya"
+)
+
+// Status acara
+const (
+	StatusAcaraDraft          = "draft"
+	StatusAcaraDipublikasikan = "dipublikasikan"
+	StatusAcaraDibatalkan     = "dibatalkan"
+)
+
+// Status pendaftaran acara
+const (
+	StatusPendaftaranTerdaftar    = "terdaftar"
+	StatusPendaftaranDaftarTunggu = "daftar_tunggu"
+	StatusPendaftaranDibatalkan   = "dibatalkan"
+)
+
+// Acara acara alumni yang ditujukan ke angkatan/jurusan tertentu (kosong berarti semua alumni).
+// Kapasitas 0 berarti tanpa batas. StatusPendaftaran diisi dari sudut pandang alumni yang sedang login.
+type Acara struct {
+	ID                 int        `json:"id"`
+	Judul              string     `json:"judul"`
+	Deskripsi          string     `json:"deskripsi"`
+	Jenis              string     `json:"jenis"`
+	Lokasi             string     `json:"lokasi"`
+	MulaiAt            time.Time  `json:"mulai_at"`
+	SelesaiAt          time.Time  `json:"selesai_at"`
+	BatasDaftar        *time.Time `json:"batas_daftar"`
+	Kapasitas          int        `json:"kapasitas"`
+	Angkatan           int        `json:"angkatan,omitempty"`
+	Jurusan            string     `json:"jurusan,omitempty"`
+	Status             string     `json:"status"`
+	JumlahTerdaftar    int        `json:"jumlah_terdaftar"`
+	JumlahDaftarTunggu int        `json:"jumlah_daftar_tunggu"`
+	JumlahHadir        int        `json:"jumlah_hadir"`
+	StatusPendaftaran  string     `json:"status_pendaftaran,omitempty"`
+	DibuatOleh         int        `json:"dibuat_oleh,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// AcaraRequest untuk membuat atau mengubah acara; acara baru selalu berstatus draft
+type AcaraRequest struct {
+	Judul       string     `json:"judul"`
+	Deskripsi   string     `json:"deskripsi"`
+	Jenis       string     `json:"jenis"`
+	Lokasi      string     `json:"lokasi"`
+	MulaiAt     time.Time  `json:"mulai_at"`
+	SelesaiAt   time.Time  `json:"selesai_at"`
+	BatasDaftar *time.Time `json:"batas_daftar"`
+	Kapasitas   int        `json:"kapasitas"`
+	Angkatan    int        `json:"angkatan"`
+	Jurusan     string     `json:"jurusan"`
+}
+
+// UpdateStatusAcaraRequest mempublikasikan atau membatalkan acara
+type UpdateStatusAcaraRequest struct {
+	Status string `json:"status"`
+}
+
+// AcaraFilter pencarian dan filter daftar acara; nilai nol berarti tidak difilter.
+// Sasaran diisi untuk non-admin: hanya acara yang dipublikasikan dan menyasar alumni tersebut.
+type AcaraFilter struct {
+	Search    string
+	Status    string
+	Jenis     string
+	Mendatang bool
+	Sasaran   *Alumni
+	AlumniID  int
+}
+
+type AcaraResponse struct {
+	Data []Acara  `json:"data"`
+	Meta MetaInfo `json:"meta"`
+}
+
+// PendaftaranAcara RSVP alumni ke satu acara. UrutanTunggu posisi di daftar tunggu (mulai 1).
+type PendaftaranAcara struct {
+	ID           int        `json:"id"`
+	AcaraID      int        `json:"acara_id"`
+	JudulAcara   string     `json:"judul_acara"`
+	MulaiAt      time.Time  `json:"mulai_at"`
+	AlumniID     int        `json:"alumni_id"`
+	NIM          string     `json:"nim"`
+	NamaAlumni   string     `json:"nama_alumni"`
+	Angkatan     int        `json:"angkatan"`
+	Jurusan      string     `json:"jurusan"`
+	Email        string     `json:"email"`
+	Disamarkan   []string   `json:"disamarkan,omitempty"`
+	Status       string     `json:"status"`
+	UrutanTunggu int        `json:"urutan_tunggu,omitempty"`
+	DidaftarAt   time.Time  `json:"didaftar_at"`
+	CheckInAt    *time.Time `json:"check_in_at"`
+	CheckInOleh  int        `json:"check_in_oleh,omitempty"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TiketAcara tiket peserta terdaftar; Kode adalah payload bertanda tangan yang di-encode ke QR code
+type TiketAcara struct {
+	PendaftaranID int       `json:"pendaftaran_id"`
+	AcaraID       int       `json:"acara_id"`
+	JudulAcara    string    `json:"judul_acara"`
+	Lokasi        string    `json:"lokasi"`
+	MulaiAt       time.Time `json:"mulai_at"`
+	NamaAlumni    string    `json:"nama_alumni"`
+	Kode          string    `json:"kode"`
+}
+
+// CheckInRequest kode tiket hasil scan QR
+type CheckInRequest struct {
+	Kode string `json:"kode"`
+}
+
+// PetugasAcaraRequest menambah akun panitia check-in untuk satu acara
+type PetugasAcaraRequest struct {
+	UserID int `json:"user_id"`
+}
+
+func ValidJenisAcara(jenis string) bool {
+	switch jenis {
+	case JenisAcaraReuni, JenisAcaraKarier, JenisAcaraSeminar, JenisAcaraLainnya:
+		return true
+	}
+	return false
+}
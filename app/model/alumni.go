@@ -16,6 +16,10 @@ type Alumni struct {
     Alamat     string    `json:"alamat"`
     CreatedAt  time.Time `json:"created_at"`
     UpdatedAt  time.Time `json:"updated_at"`
+    // Disamarkan field kontak yang disamarkan atau dikosongkan sesuai visibilitas untuk user yang melihat
+    Disamarkan []string  `json:"disamarkan,omitempty"`
+    // Pendidikan riwayat pendidikan; Jurusan/Angkatan/TahunLulus di atas sama dengan pendidikan utama
+    Pendidikan []PendidikanAlumni `json:"pendidikan,omitempty"`
 }
 
 type CreateAlumniRequest struct {
@@ -27,6 +31,8 @@ type CreateAlumniRequest struct {
     Email      string `json:"email"`
     NoTelepon  string `json:"no_telepon"`
     Alamat     string `json:"alamat"`
+    // Jenjang pendidikan utama; kosong berarti S1
+    Jenjang    string `json:"jenjang"`
 }
 
 type UpdateAlumniRequest struct {
@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// DuplikatAlumni pasangan alumni yang kemungkinan orang yang sama; Alasan berisi kriteria yang cocok
+type DuplikatAlumni struct {
+	Alumni   Alumni   `json:"alumni"`
+	Duplikat Alumni   `json:"duplikat"`
+	Skor     float64  `json:"skor"`
+	Alasan   []string `json:"alasan"`
+}
+
+// AbaikanDuplikatRequest menandai dua alumni sebagai bukan duplikat
+type AbaikanDuplikatRequest struct {
+	AlumniIDs []int `json:"alumni_ids"`
+}
+
+// MergeAlumniRequest menggabungkan alumni duplikat ke alumni :id (survivor)
+type MergeAlumniRequest struct {
+	DuplikatID int    `json:"duplikat_id"`
+	Catatan    string `json:"catatan"`
+}
+
+// AlumniMergeSnapshot data yang dibutuhkan untuk membatalkan penggabungan: nilai survivor sebelum
+// field kosongnya diisi dari duplikat, serta id baris yang dipindahkan dari duplikat ke survivor (satu
+// field per tabel di mergeTargets repository).
+type AlumniMergeSnapshot struct {
+	SurvivorSebelum Alumni   `json:"survivor_sebelum"`
+	Duplikat        Alumni   `json:"duplikat"`
+	FieldDiisi      []string `json:"field_diisi"`
+	PekerjaanIDs    []int    `json:"pekerjaan_ids"`
+	UserIDs         []int    `json:"user_ids"`
+	PengajuanIDs    []int    `json:"pengajuan_ids"`
+	ResponIDs       []int    `json:"respon_ids"`
+	PendidikanIDs   []int    `json:"pendidikan_ids"`
+	KeahlianIDs     []int    `json:"keahlian_ids"`
+	TagIDs          []int    `json:"tag_ids"`
+	LamaranIDs      []int    `json:"lamaran_ids"`
+	PendaftaranIDs  []int    `json:"pendaftaran_acara_ids"`
+	MentorIDs       []int    `json:"mentor_ids"`
+	PermintaanIDs   []int    `json:"permintaan_mentor_ids"`
+	BerkasIDs       []int    `json:"berkas_ids"`
+	SimpanIDs       []int    `json:"lowongan_simpan_ids"`
+}
+
+type AlumniMerge struct {
+	ID             int                 `json:"id"`
+	SurvivorID     int                 `json:"survivor_id"`
+	DuplikatID     int                 `json:"duplikat_id"`
+	Skor           float64             `json:"skor"`
+	Catatan        string              `json:"catatan"`
+	Snapshot       AlumniMergeSnapshot `json:"snapshot"`
+	DigabungOleh   int                 `json:"digabung_oleh,omitempty"`
+	CreatedAt      time.Time           `json:"created_at"`
+	DibatalkanAt   *time.Time          `json:"dibatalkan_at,omitempty"`
+	DibatalkanOleh int                 `json:"dibatalkan_oleh,omitempty"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Aksi audit log
+const (
+	AuditAksiCreate  = "create"
+	AuditAksiUpdate  = "update"
+	AuditAksiDelete  = "delete"
+	AuditAksiRestore = "restore"
+	AuditAksiLogin   = "login"
+)
+
+// AuditLog satu entri audit. Sebelum dan Sesudah disimpan apa adanya (JSON) agar hash dapat dihitung ulang.
+type AuditLog struct {
+	ID             int64           `json:"id"`
+	Waktu          time.Time       `json:"waktu"`
+	UserID         int             `json:"user_id,omitempty"`
+	RoleID         int             `json:"role_id,omitempty"`
+	ImpersonatorID int             `json:"impersonator_id,omitempty"`
+	Aksi           string          `json:"aksi"`
+	ResourceTipe   string          `json:"resource_tipe"`
+	ResourceID     string          `json:"resource_id"`
+	Method         string          `json:"method"`
+	Path           string          `json:"path"`
+	Status         int             `json:"status"`
+	IP             string          `json:"ip"`
+	RequestID      string          `json:"request_id"`
+	Sebelum        json.RawMessage `json:"sebelum,omitempty"`
+	Sesudah        json.RawMessage `json:"sesudah,omitempty"`
+	HashSebelumnya string          `json:"hash_sebelumnya"`
+	Hash           string          `json:"hash"`
+}
+
+// AuditFilter filter daftar audit log; nilai nol berarti tidak difilter
+type AuditFilter struct {
+	UserID       int
+	Aksi         string
+	ResourceTipe string
+	ResourceID   string
+	Dari         time.Time
+	Sampai       time.Time
+}
+
+// AuditVerifikasi hasil pemeriksaan rantai hash. RusakDiID berisi id entri pertama yang tidak cocok.
+type AuditVerifikasi struct {
+	Valid        bool   `json:"valid"`
+	JumlahEntri  int64  `json:"jumlah_entri"`
+	RusakDiID    int64  `json:"rusak_di_id,omitempty"`
+	Alasan       string `json:"alasan,omitempty"`
+	HashTerakhir string `json:"hash_terakhir"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// Jenis berkas unggahan
+const (
+	JenisBerkasFoto    = "foto"
+	JenisBerkasDokumen = "dokumen"
+)
+
+// Kategori dokumen bukti pekerjaan
+const (
+	KategoriDokumenOfferLetter = "offer_letter"
+	KategoriDokumenSK          = "sk"
+	KategoriDokumenKontrak     = "kontrak"
+	KategoriDokumenLainnya     = "lainnya"
+)
+
+// Berkas metadata file unggahan. StorageKey tidak dikirim ke client; URL dan ThumbnailURL adalah
+// signed URL yang hanya berlaku sementara.
+type Berkas struct {
+	ID           int       `json:"id"`
+	AlumniID     *int      `json:"alumni_id,omitempty"`
+	PekerjaanID  *int      `json:"pekerjaan_id,omitempty"`
+	Jenis        string    `json:"jenis"`
+	Kategori     string    `json:"kategori,omitempty"`
+	NamaAsli     string    `json:"nama_asli"`
+	ContentType  string    `json:"content_type"`
+	Ukuran       int64     `json:"ukuran"`
+	SHA256       string    `json:"sha256"`
+	StorageKey   string    `json:"-"`
+	ThumbnailKey string    `json:"-"`
+	DiunggahOleh int       `json:"diunggah_oleh,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	URL          string    `json:"url,omitempty"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	URLExpiresAt time.Time `json:"url_expires_at,omitempty"`
+}
+
+func ValidKategoriDokumen(kategori string) bool {
+	switch kategori {
+	case KategoriDokumenOfferLetter, KategoriDokumenSK, KategoriDokumenKontrak, KategoriDokumenLainnya:
+		return true
+	}
+	return false
+}
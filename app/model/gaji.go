@@ -0,0 +1,197 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Periode dan mata uang gaji
+const (
+	GajiPeriodeBulan    = "bulan"
+	GajiPeriodeTahun    = "tahun"
+	GajiMataUangDefault = "IDR"
+)
+
+// GajiRange rentang gaji terstruktur. Max 0 berarti tanpa batas atas (mis. "> 10jt").
+type GajiRange struct {
+	Min      int64  `json:"min"`
+	Max      int64  `json:"max"`
+	MataUang string `json:"mata_uang"`
+	Periode  string `json:"periode"`
+}
+
+var (
+	gajiAngkaPattern    = regexp.MustCompile(`(\d+(?:[.,]\d+)*)\s*(juta|jt|ribu|rb|k)?\b`)
+	gajiMataUangPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+	// urutan penting: token yang lebih spesifik (sgd, us$) dicek sebelum "$"
+	gajiMataUangTokens = []struct{ token, kode string }{
+		{"sgd", "SGD"}, {"s$", "SGD"}, {"usd", "USD"}, {"us$", "USD"}, {"$", "USD"},
+		{"eur", "EUR"}, {"€", "EUR"}, {"idr", "IDR"}, {"rp", "IDR"},
+	}
+	gajiTahunanTokens   = []string{"tahun", "/th", "/thn", "per th", "/yr", "year", "annual", "p.a"}
+	gajiBatasAtasToken  = []string{"<", "≤", "kurang dari", "di bawah", "dibawah", "maks", "max"}
+	gajiBatasBawahToken = []string{">", "≥", "lebih dari", "di atas", "diatas", "min", "+", "ke atas"}
+)
+
+// ParseGajiRange mengurai teks gaji lama seperti "5-7jt", "Rp 5.000.000 - 7.000.000",
+// "> 10 juta", "USD 2k-3k / year". Untuk IDR, angka tanpa satuan di bawah 1000 dianggap juta
+// (kebiasaan penulisan "5-7"), dan satuan di angka terakhir berlaku juga untuk angka sebelumnya.
+func ParseGajiRange(value string) (GajiRange, error) {
+	text := strings.ToLower(strings.TrimSpace(value))
+	if text == "" {
+		return GajiRange{}, fmt.Errorf("gaji kosong")
+	}
+
+	gaji := GajiRange{MataUang: GajiMataUangDefault, Periode: GajiPeriodeBulan}
+	for _, t := range gajiMataUangTokens {
+		if strings.Contains(text, t.token) {
+			gaji.MataUang = t.kode
+			break
+		}
+	}
+	if containsAny(text, gajiTahunanTokens) {
+		gaji.Periode = GajiPeriodeTahun
+	}
+
+	matches := gajiAngkaPattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 || len(matches) > 2 {
+		return GajiRange{}, fmt.Errorf("format gaji tidak dikenali: %q", value)
+	}
+
+	satuan := ""
+	for _, m := range matches {
+		if m[2] != "" {
+			satuan = m[2]
+		}
+	}
+
+	nilai := make([]int64, 0, len(matches))
+	for _, m := range matches {
+		angka, err := parseGajiAngka(m[1])
+		if err != nil {
+			return GajiRange{}, fmt.Errorf("format gaji tidak dikenali: %q", value)
+		}
+		s := m[2]
+		if s == "" && angka < 1000 {
+			s = satuan
+			if s == "" && gaji.MataUang == "IDR" {
+				s = "juta"
+			}
+		}
+		nilai = append(nilai, int64(math.Round(angka*gajiPengali(s))))
+	}
+
+	switch {
+	case len(nilai) == 2:
+		gaji.Min, gaji.Max = nilai[0], nilai[1]
+		if gaji.Min > gaji.Max {
+			gaji.Min, gaji.Max = gaji.Max, gaji.Min
+		}
+	case containsAny(text, gajiBatasAtasToken):
+		gaji.Max = nilai[0]
+	case containsAny(text, gajiBatasBawahToken):
+		gaji.Min = nilai[0]
+	default:
+		gaji.Min, gaji.Max = nilai[0], nilai[0]
+	}
+	return gaji, gaji.Validate()
+}
+
+// parseGajiAngka membaca "5.000.000" dan "5,000,000" sebagai ribuan, "7.5" dan "7,5" sebagai desimal
+func parseGajiAngka(value string) (float64, error) {
+	groups := strings.FieldsFunc(value, func(r rune) bool { return r == '.' || r == ',' })
+	if len(groups) > 1 {
+		ribuan := true
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				ribuan = false
+			}
+		}
+		switch {
+		case ribuan:
+			value = strings.Join(groups, "")
+		case len(groups) == 2:
+			value = groups[0] + "." + groups[1]
+		default:
+			return 0, fmt.Errorf("angka tidak valid: %s", value)
+		}
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func gajiPengali(satuan string) float64 {
+	switch satuan {
+	case "juta", "jt":
+		return 1e6
+	case "ribu", "rb", "k":
+		return 1e3
+	}
+	return 1
+}
+
+func containsAny(text string, tokens []string) bool {
+	for _, token := range tokens {
+		if strings.Contains(text, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize mengisi mata uang dan periode default
+func (g *GajiRange) Normalize() {
+	g.MataUang = strings.ToUpper(strings.TrimSpace(g.MataUang))
+	if g.MataUang == "" {
+		g.MataUang = GajiMataUangDefault
+	}
+	g.Periode = strings.ToLower(strings.TrimSpace(g.Periode))
+	if g.Periode == "" {
+		g.Periode = GajiPeriodeBulan
+	}
+}
+
+func (g GajiRange) Validate() error {
+	switch {
+	case g.Min < 0 || g.Max < 0:
+		return fmt.Errorf("gaji tidak boleh negatif")
+	case g.Min == 0 && g.Max == 0:
+		return fmt.Errorf("gaji minimal atau maksimal harus diisi")
+	case g.Max != 0 && g.Max < g.Min:
+		return fmt.Errorf("gaji maksimal tidak boleh lebih kecil dari gaji minimal")
+	case !gajiMataUangPattern.MatchString(g.MataUang):
+		return fmt.Errorf("mata uang harus kode 3 huruf (mis. IDR, USD)")
+	case g.Periode != GajiPeriodeBulan && g.Periode != GajiPeriodeTahun:
+		return fmt.Errorf("periode gaji harus bulan atau tahun")
+	}
+	return nil
+}
+
+// String label kanonis, mis. "IDR 5.000.000 - 7.000.000 / bulan"
+func (g GajiRange) String() string {
+	var nominal string
+	switch {
+	case g.Max == 0:
+		nominal = formatRibuan(g.Min) + "+"
+	case g.Min == g.Max:
+		nominal = formatRibuan(g.Min)
+	default:
+		nominal = formatRibuan(g.Min) + " - " + formatRibuan(g.Max)
+	}
+	return fmt.Sprintf("%s %s / %s", g.MataUang, nominal, g.Periode)
+}
+
+func formatRibuan(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
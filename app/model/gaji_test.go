@@ -0,0 +1,67 @@
+package model
+
+import "testing"
+
+func TestParseGajiRange(t *testing.T) {
+	kasus := []struct {
+		input string
+		ingin GajiRange
+	}{
+		{"5-7jt", GajiRange{Min: 5000000, Max: 7000000, MataUang: "IDR", Periode: "bulan"}},
+		{"5-7", GajiRange{Min: 5000000, Max: 7000000, MataUang: "IDR", Periode: "bulan"}},
+		{"Rp 5.000.000 - 7.000.000", GajiRange{Min: 5000000, Max: 7000000, MataUang: "IDR", Periode: "bulan"}},
+		{"7,5 juta", GajiRange{Min: 7500000, Max: 7500000, MataUang: "IDR", Periode: "bulan"}},
+		{"> 10 juta", GajiRange{Min: 10000000, MataUang: "IDR", Periode: "bulan"}},
+		{"di bawah 4jt", GajiRange{Max: 4000000, MataUang: "IDR", Periode: "bulan"}},
+		{"10jt - 8jt", GajiRange{Min: 8000000, Max: 10000000, MataUang: "IDR", Periode: "bulan"}},
+		{"USD 2k-3k / year", GajiRange{Min: 2000, Max: 3000, MataUang: "USD", Periode: "tahun"}},
+		{"SGD 4,500", GajiRange{Min: 4500, Max: 4500, MataUang: "SGD", Periode: "bulan"}},
+		{"120 juta per tahun", GajiRange{Min: 120000000, Max: 120000000, MataUang: "IDR", Periode: "tahun"}},
+	}
+	for _, k := range kasus {
+		got, err := ParseGajiRange(k.input)
+		if err != nil {
+			t.Errorf("ParseGajiRange(%q): %v", k.input, err)
+			continue
+		}
+		if got != k.ingin {
+			t.Errorf("ParseGajiRange(%q) = %+v, ingin %+v", k.input, got, k.ingin)
+		}
+	}
+}
+
+func TestParseGajiRangeTidakDikenali(t *testing.T) {
+	for _, input := range []string{"", "   ", "negotiable", "1-2-3 juta", "1.2.3"} {
+		if got, err := ParseGajiRange(input); err == nil {
+			t.Errorf("ParseGajiRange(%q) = %+v, ingin error", input, got)
+		}
+	}
+}
+
+func TestGajiRangeString(t *testing.T) {
+	kasus := map[string]GajiRange{
+		"IDR 5.000.000 - 7.000.000 / bulan": {Min: 5000000, Max: 7000000, MataUang: "IDR", Periode: "bulan"},
+		"IDR 10.000.000+ / bulan":           {Min: 10000000, MataUang: "IDR", Periode: "bulan"},
+		"USD 2.000 / tahun":                 {Min: 2000, Max: 2000, MataUang: "USD", Periode: "tahun"},
+	}
+	for ingin, g := range kasus {
+		if got := g.String(); got != ingin {
+			t.Errorf("%+v.String() = %q, ingin %q", g, got, ingin)
+		}
+	}
+}
+
+func TestGajiRangeValidate(t *testing.T) {
+	invalid := []GajiRange{
+		{Min: -1, Max: 5, MataUang: "IDR", Periode: "bulan"},
+		{MataUang: "IDR", Periode: "bulan"},
+		{Min: 7, Max: 5, MataUang: "IDR", Periode: "bulan"},
+		{Min: 5, MataUang: "rupiah", Periode: "bulan"},
+		{Min: 5, MataUang: "IDR", Periode: "minggu"},
+	}
+	for _, g := range invalid {
+		if err := g.Validate(); err == nil {
+			t.Errorf("%+v.Validate() = nil, ingin error", g)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// Jenis keahlian alumni
+const (
+	JenisKeahlianSkill       = "skill"
+	JenisKeahlianSertifikasi = "sertifikasi"
+)
+
+// KeahlianAlumni satu skill atau sertifikasi alumni. Nama skill selalu nama kanonis referensi skill;
+// Tags berisi tag skill yang melekat (untuk skill, termasuk skill itu sendiri). Sertifikasi yang sudah
+// lewat BerlakuSampai tetap ditampilkan tetapi tidak dihitung saat mencari alumni berdasarkan skill.
+type KeahlianAlumni struct {
+	ID            int       `json:"id"`
+	AlumniID      int       `json:"alumni_id"`
+	Jenis         string    `json:"jenis"`
+	Nama          string    `json:"nama"`
+	Penerbit      string    `json:"penerbit"`
+	Tahun         *int      `json:"tahun"`
+	BerlakuSampai Tanggal   `json:"berlaku_sampai"`
+	Kedaluwarsa   bool      `json:"kedaluwarsa"`
+	Tags          []string  `json:"tags"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// KeahlianAlumniRequest untuk menambah atau mengubah keahlian lewat /api/me/keahlian.
+// Tags berisi nama atau alias dari /api/referensi/skill.
+type KeahlianAlumniRequest struct {
+	Jenis         string   `json:"jenis"`
+	Nama          string   `json:"nama"`
+	Penerbit      string   `json:"penerbit"`
+	Tahun         *int     `json:"tahun"`
+	BerlakuSampai Tanggal  `json:"berlaku_sampai"`
+	Tags          []string `json:"tags"`
+}
+
+// TagAlumni tag skill yang diberikan admin ke alumni
+type TagAlumni struct {
+	ReferensiID  int       `json:"referensi_id"`
+	Nama         string    `json:"nama"`
+	DitandaiOleh int       `json:"ditandai_oleh,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TagAlumniRequest mengganti seluruh tag admin pada alumni; tag yang belum dikenal dibuat otomatis
+type TagAlumniRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// ProfilKeahlian keahlian alumni beserta tag dari admin dan gabungan seluruh skill yang masih berlaku
+type ProfilKeahlian struct {
+	Keahlian   []KeahlianAlumni `json:"keahlian"`
+	TagAdmin   []TagAlumni      `json:"tag_admin"`
+	SkillAktif []string         `json:"skill_aktif"`
+}
+
+func ValidJenisKeahlian(jenis string) bool {
+	return jenis == JenisKeahlianSkill || jenis == JenisKeahlianSertifikasi
+}
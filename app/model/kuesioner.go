@@ -0,0 +1,126 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Tipe pertanyaan kuesioner
+const (
+	TipePertanyaanSingle = "single"
+	TipePertanyaanMulti  = "multi"
+	TipePertanyaanScale  = "scale"
+	TipePertanyaanText   = "text"
+	TipePertanyaanDate   = "date"
+)
+
+// Status kuesioner
+const (
+	StatusKuesionerDraft   = "draft"
+	StatusKuesionerAktif   = "aktif"
+	StatusKuesionerDitutup = "ditutup"
+)
+
+type Kuesioner struct {
+	ID        int               `json:"id"`
+	Judul     string            `json:"judul"`
+	Deskripsi string            `json:"deskripsi"`
+	Periode   int               `json:"periode"`
+	Angkatan  int               `json:"angkatan,omitempty"`
+	Jurusan   string            `json:"jurusan,omitempty"`
+	Status    string            `json:"status"`
+	Bagian    []KuesionerBagian `json:"bagian,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type KuesionerBagian struct {
+	ID          int                   `json:"id"`
+	KuesionerID int                   `json:"kuesioner_id"`
+	Judul       string                `json:"judul"`
+	Urutan      int                   `json:"urutan"`
+	Pertanyaan  []KuesionerPertanyaan `json:"pertanyaan"`
+}
+
+type KuesionerPertanyaan struct {
+	ID         int      `json:"id"`
+	BagianID   int      `json:"bagian_id"`
+	Pertanyaan string   `json:"pertanyaan"`
+	Tipe       string   `json:"tipe"`
+	Opsi       []string `json:"opsi"`
+	SkalaMin   int      `json:"skala_min"`
+	SkalaMax   int      `json:"skala_max"`
+	Wajib      bool     `json:"wajib"`
+	Urutan     int      `json:"urutan"`
+}
+
+// CreateKuesionerRequest untuk membuat kuesioner beserta bagian dan pertanyaannya
+type CreateKuesionerRequest struct {
+	Judul     string                  `json:"judul"`
+	Deskripsi string                  `json:"deskripsi"`
+	Periode   int                     `json:"periode"`
+	Angkatan  int                     `json:"angkatan"`
+	Jurusan   string                  `json:"jurusan"`
+	Bagian    []CreateKuesionerBagian `json:"bagian"`
+}
+
+type CreateKuesionerBagian struct {
+	Judul      string                      `json:"judul"`
+	Pertanyaan []CreateKuesionerPertanyaan `json:"pertanyaan"`
+}
+
+type CreateKuesionerPertanyaan struct {
+	Pertanyaan string   `json:"pertanyaan"`
+	Tipe       string   `json:"tipe"`
+	Opsi       []string `json:"opsi"`
+	SkalaMin   int      `json:"skala_min"`
+	SkalaMax   int      `json:"skala_max"`
+	Wajib      bool     `json:"wajib"`
+}
+
+// UpdateKuesionerStatusRequest untuk mengubah status atau membuka periode baru
+type UpdateKuesionerStatusRequest struct {
+	Status  string `json:"status"`
+	Periode int    `json:"periode"`
+}
+
+// JawabanItem jawaban untuk satu pertanyaan; bentuk nilai tergantung tipe pertanyaan
+// (string untuk single/text/date, array string untuk multi, angka untuk scale)
+type JawabanItem struct {
+	PertanyaanID int             `json:"pertanyaan_id"`
+	Jawaban      json.RawMessage `json:"jawaban"`
+}
+
+type SubmitJawabanRequest struct {
+	Jawaban []JawabanItem `json:"jawaban"`
+}
+
+type KuesionerRespon struct {
+	ID          int           `json:"id"`
+	KuesionerID int           `json:"kuesioner_id"`
+	AlumniID    int           `json:"alumni_id"`
+	Periode     int           `json:"periode"`
+	Versi       int           `json:"versi"`
+	SubmittedAt time.Time     `json:"submitted_at"`
+	Jawaban     []JawabanItem `json:"jawaban"`
+}
+
+// HasilPertanyaan agregasi jawaban satu pertanyaan
+type HasilPertanyaan struct {
+	PertanyaanID int            `json:"pertanyaan_id"`
+	Pertanyaan   string         `json:"pertanyaan"`
+	Tipe         string         `json:"tipe"`
+	JumlahJawab  int            `json:"jumlah_jawab"`
+	Frekuensi    map[string]int `json:"frekuensi,omitempty"`
+	RataRata     float64        `json:"rata_rata,omitempty"`
+	Contoh       []string       `json:"contoh,omitempty"`
+}
+
+type HasilKuesioner struct {
+	KuesionerID    int               `json:"kuesioner_id"`
+	Periode        int               `json:"periode"`
+	TotalSasaran   int               `json:"total_sasaran"`
+	TotalResponden int               `json:"total_responden"`
+	TingkatRespon  float64           `json:"tingkat_respon"`
+	Pertanyaan     []HasilPertanyaan `json:"pertanyaan"`
+}
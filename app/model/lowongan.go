@@ -0,0 +1,124 @@
+package model
+
+import "time"
+
+// Status lowongan
+const (
+	StatusLowonganDibuka      = "dibuka"
+	StatusLowonganDitutup     = "ditutup"
+	StatusLowonganKedaluwarsa = "kedaluwarsa"
+)
+
+// Status lamaran lowongan
+const (
+	StatusLamaranDikirim    = "dikirim"
+	StatusLamaranDiproses   = "diproses"
+	StatusLamaranWawancara  = "wawancara"
+	StatusLamaranDiterima   = "diterima"
+	StatusLamaranDitolak    = "ditolak"
+	StatusLamaranDibatalkan = "dibatalkan"
+)
+
+// Lowongan lowongan kerja yang dipasang admin atau mitra perusahaan. Disimpan dan StatusLamaran
+// diisi dari sudut pandang alumni yang sedang login.
+type Lowongan struct {
+	ID             int        `json:"id"`
+	PerusahaanID   int        `json:"perusahaan_id"`
+	NamaPerusahaan string     `json:"nama_perusahaan"`
+	Posisi         string     `json:"posisi"`
+	Deskripsi      string     `json:"deskripsi"`
+	BidangIndustri string     `json:"bidang_industri"`
+	Lokasi         string     `json:"lokasi"`
+	Gaji           *GajiRange `json:"gaji"`
+	BatasLamaran   Tanggal    `json:"batas_lamaran"`
+	Status         string     `json:"status"`
+	DibuatOleh     int        `json:"dibuat_oleh,omitempty"`
+	DitutupAt      *time.Time `json:"ditutup_at"`
+	JumlahPelamar  int        `json:"jumlah_pelamar"`
+	Disimpan       bool       `json:"disimpan"`
+	StatusLamaran  string     `json:"status_lamaran,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// LowonganRequest untuk memasang atau mengubah lowongan. Gaji boleh dikirim terstruktur (gaji) atau
+// sebagai teks gaji_range yang akan diurai. Mitra selalu memasang atas nama perusahaannya sendiri.
+type LowonganRequest struct {
+	PerusahaanID   int        `json:"perusahaan_id"`
+	NamaPerusahaan string     `json:"nama_perusahaan"`
+	Posisi         string     `json:"posisi"`
+	Deskripsi      string     `json:"deskripsi"`
+	BidangIndustri string     `json:"bidang_industri"`
+	Lokasi         string     `json:"lokasi"`
+	GajiRange      string     `json:"gaji_range"`
+	Gaji           *GajiRange `json:"gaji"`
+	BatasLamaran   Tanggal    `json:"batas_lamaran"`
+}
+
+// UpdateStatusLowonganRequest menutup (ditutup) atau membuka kembali (dibuka) lowongan
+type UpdateStatusLowonganRequest struct {
+	Status string `json:"status"`
+}
+
+// LowonganFilter pencarian dan filter daftar lowongan; nilai nol berarti tidak difilter.
+// Status kosong berarti lowongan yang masih menerima lamaran. AlumniID mengisi Disimpan/StatusLamaran.
+type LowonganFilter struct {
+	Search         string
+	Status         string
+	BidangIndustri string
+	Lokasi         string
+	PerusahaanID   int
+	GajiMin        int64
+	GajiMax        int64
+	MataUang       string
+	AlumniID       int
+	DisimpanSaja   bool
+}
+
+type LowonganResponse struct {
+	Data []Lowongan `json:"data"`
+	Meta MetaInfo   `json:"meta"`
+}
+
+// LamaranLowongan lamaran alumni ke satu lowongan beserta ringkasan pelamar untuk perekrut
+type LamaranLowongan struct {
+	ID              int       `json:"id"`
+	LowonganID      int       `json:"lowongan_id"`
+	Posisi          string    `json:"posisi"`
+	NamaPerusahaan  string    `json:"nama_perusahaan"`
+	AlumniID        int       `json:"alumni_id"`
+	NamaAlumni      string    `json:"nama_alumni"`
+	Jurusan         string    `json:"jurusan"`
+	TahunLulus      int       `json:"tahun_lulus"`
+	Email           string    `json:"email"`
+	NoTelepon       string    `json:"no_telepon"`
+	Status          string    `json:"status"`
+	CatatanPelamar  string    `json:"catatan_pelamar"`
+	CatatanPerekrut string    `json:"catatan_perekrut"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type LamaranRequest struct {
+	Catatan string `json:"catatan"`
+}
+
+// UpdateStatusLamaranRequest perekrut memindahkan lamaran ke tahap berikutnya
+type UpdateStatusLamaranRequest struct {
+	Status  string `json:"status"`
+	Catatan string `json:"catatan"`
+}
+
+// StatusLamaranFinal lamaran yang sudah diputuskan atau dibatalkan tidak dapat diubah lagi
+func StatusLamaranFinal(status string) bool {
+	return status == StatusLamaranDiterima || status == StatusLamaranDitolak || status == StatusLamaranDibatalkan
+}
+
+// ValidStatusLamaranPerekrut status yang boleh diberikan perekrut
+func ValidStatusLamaranPerekrut(status string) bool {
+	switch status {
+	case StatusLamaranDiproses, StatusLamaranWawancara, StatusLamaranDiterima, StatusLamaranDitolak:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package model
+
+import "time"
+
+// Status permintaan mentoring
+const (
+	StatusMentoringDiajukan   = "diajukan"
+	StatusMentoringDiterima   = "diterima"
+	StatusMentoringDitolak    = "ditolak"
+	StatusMentoringDibatalkan = "dibatalkan"
+	StatusMentoringSelesai    = "selesai"
+)
+
+// Peran alumni dalam permintaan mentoring
+const (
+	PeranMentor = "mentor"
+	PeranMentee = "mentee"
+)
+
+// Mentor profil alumni yang bersedia menjadi mentor. Ringkasan karier diisi dari pekerjaan_alumni yang tidak
+// dihapus: PengalamanBulan menghitung pekerjaan yang beririsan satu kali seperti timeline alumni.
+// Kontak mentor tidak pernah ada di sini; kontak hanya dibuka lewat permintaan yang diterima.
+type Mentor struct {
+	ID                int       `json:"id"`
+	AlumniID          int       `json:"alumni_id"`
+	Nama              string    `json:"nama"`
+	Jurusan           string    `json:"jurusan"`
+	Angkatan          int       `json:"angkatan"`
+	TahunLulus        int       `json:"tahun_lulus"`
+	Topik             []string  `json:"topik"`
+	Bio               string    `json:"bio"`
+	Kapasitas         int       `json:"kapasitas"`
+	JumlahMentee      int       `json:"jumlah_mentee"`
+	BagikanTelepon    bool      `json:"bagikan_telepon"`
+	Aktif             bool      `json:"aktif"`
+	PosisiSaatIni     string    `json:"posisi_saat_ini,omitempty"`
+	PerusahaanSaatIni string    `json:"perusahaan_saat_ini,omitempty"`
+	BidangIndustri    []string  `json:"bidang_industri"`
+	LokasiKerja       []string  `json:"lokasi_kerja"`
+	PengalamanBulan   int       `json:"pengalaman_bulan"`
+	PengalamanTahun   float64   `json:"pengalaman_tahun"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// MentorRequest mendaftar atau mengubah profil mentor; Aktif nil berarti tetap aktif
+type MentorRequest struct {
+	Topik          []string `json:"topik"`
+	Bio            string   `json:"bio"`
+	Kapasitas      int      `json:"kapasitas"`
+	BagikanTelepon bool     `json:"bagikan_telepon"`
+	Aktif          *bool    `json:"aktif"`
+}
+
+// SaranMentor mentor yang disarankan untuk mentee beserta skor kecocokan (0-100) dan alasannya
+type SaranMentor struct {
+	Mentor
+	Skor   int      `json:"skor"`
+	Alasan []string `json:"alasan"`
+}
+
+// SaranMentorFilter minat mentee di luar data alumninya; kosong berarti memakai riwayat pekerjaan mentee
+type SaranMentorFilter struct {
+	Topik          string
+	BidangIndustri string
+	Lokasi         string
+	Limit          int
+}
+
+// KontakMentoring kontak yang dibuka ke pasangan mentoring setelah permintaan diterima
+type KontakMentoring struct {
+	Email     string `json:"email"`
+	NoTelepon string `json:"no_telepon,omitempty"`
+}
+
+// PermintaanMentor permintaan mentee (AlumniID) ke mentor. KontakMentor dan KontakMentee hanya diisi untuk
+// permintaan yang diterima atau selesai; Peran diisi dari sudut pandang alumni yang sedang login.
+type PermintaanMentor struct {
+	ID               int              `json:"id"`
+	MentorID         int              `json:"mentor_id"`
+	MentorAlumniID   int              `json:"mentor_alumni_id"`
+	NamaMentor       string           `json:"nama_mentor"`
+	AlumniID         int              `json:"alumni_id"`
+	NamaMentee       string           `json:"nama_mentee"`
+	JurusanMentee    string           `json:"jurusan_mentee"`
+	TahunLulusMentee int              `json:"tahun_lulus_mentee"`
+	Peran            string           `json:"peran,omitempty"`
+	Status           string           `json:"status"`
+	Topik            string           `json:"topik"`
+	Pesan            string           `json:"pesan"`
+	Balasan          string           `json:"balasan"`
+	BagikanTelepon   bool             `json:"bagikan_telepon"`
+	KontakMentor     *KontakMentoring `json:"kontak_mentor,omitempty"`
+	KontakMentee     *KontakMentoring `json:"kontak_mentee,omitempty"`
+	DiresponsAt      *time.Time       `json:"direspons_at"`
+	SelesaiAt        *time.Time       `json:"selesai_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// PermintaanMentorRequest mentee meminta mentoring; BagikanTelepon membuka nomor telepon mentee jika diterima
+type PermintaanMentorRequest struct {
+	MentorID       int    `json:"mentor_id"`
+	Topik          string `json:"topik"`
+	Pesan          string `json:"pesan"`
+	BagikanTelepon bool   `json:"bagikan_telepon"`
+}
+
+// ResponsPermintaanMentorRequest jawaban mentor saat menerima atau menolak permintaan
+type ResponsPermintaanMentorRequest struct {
+	Balasan string `json:"balasan"`
+}
+
+// KontakDibuka kontak pasangan mentoring hanya dibuka setelah mentor menerima permintaan
+func KontakDibuka(status string) bool {
+	return status == StatusMentoringDiterima || status == StatusMentoringSelesai
+}
@@ -7,40 +7,70 @@ import (
 type PekerjaanAlumni struct {
     ID                 int       `json:"id"`
     AlumniID           int       `json:"alumni_id"`
+    PerusahaanID       int       `json:"perusahaan_id,omitempty"`
     NamaPerusahaan    string    `json:"nama_perusahaan"`
     PosisiJabatan     string    `json:"posisi_jabatan"`
     BidangIndustri    string    `json:"bidang_industri"`
     LokasiKerja       string    `json:"lokasi_kerja"`
     GajiRange         string    `json:"gaji_range"`
-    TanggalMulaiKerja string    `json:"tanggal_mulai_kerja"`
-    TanggalSelesaiKerja string    `json:"tanggal_selesai_kerja"`
+    Gaji              *GajiRange `json:"gaji"`
+    TanggalMulaiKerja Tanggal   `json:"tanggal_mulai_kerja"`
+    TanggalSelesaiKerja Tanggal   `json:"tanggal_selesai_kerja"`
     StatusPekerjaan   string    `json:"status_pekerjaan"`
     DeskripsiPekerjaan string    `json:"deskripsi_pekerjaan"`
+    IsDelete          string     `json:"is_delete"`
+    TrashedAt         *time.Time `json:"trashed_at,omitempty"`
+    TrashedBy         int        `json:"trashed_by,omitempty"`
     CreatedAt         time.Time `json:"created_at"`
     UpdatedAt         time.Time `json:"updated_at"`
 }
 
+// Scope visibilitas pekerjaan berdasarkan status trash
+const (
+    ScopeActive  = "active"
+    ScopeTrashed = "trashed"
+    ScopeAll     = "all"
+)
+
+// PekerjaanFilter pencarian dan filter rentang untuk daftar pekerjaan; nilai nol berarti tidak difilter.
+// Filter gaji mencari rentang gaji yang beririsan dengan [GajiMin, GajiMax].
+type PekerjaanFilter struct {
+    Scope       string
+    Search      string
+    MulaiDari   Tanggal
+    MulaiSampai Tanggal
+    GajiMin     int64
+    GajiMax     int64
+    MataUang    string
+}
+
+// CreatePekerjaanAlumniRequest gaji boleh dikirim terstruktur (gaji) atau sebagai teks gaji_range yang akan diurai.
+// perusahaan_id memilih perusahaan dari direktori; jika kosong perusahaan dicari atau dibuat dari nama_perusahaan.
 type CreatePekerjaanAlumniRequest struct {
     AlumniID          int    `json:"alumni_id"`
+    PerusahaanID      int    `json:"perusahaan_id"`
     NamaPerusahaan   string `json:"nama_perusahaan"`
     PosisiJabatan    string `json:"posisi_jabatan"`
     BidangIndustri   string `json:"bidang_industri"`
     LokasiKerja      string `json:"lokasi_kerja"`
     GajiRange        string `json:"gaji_range"`
-    TanggalMulaiKerja string `json:"tanggal_mulai_kerja"`
-    TanggalSelesaiKerja string `json:"tanggal_selesai_kerja"`
+    Gaji             *GajiRange `json:"gaji"`
+    TanggalMulaiKerja Tanggal `json:"tanggal_mulai_kerja"`
+    TanggalSelesaiKerja Tanggal `json:"tanggal_selesai_kerja"`
     StatusPekerjaan  string `json:"status_pekerjaan"`
     DeskripsiPekerjaan string `json:"deskripsi_pekerjaan"`
 }
 
 type UpdatePekerjaanAlumniRequest struct {
+    PerusahaanID      int    `json:"perusahaan_id"`
     NamaPerusahaan    string `json:"nama_perusahaan"`
     PosisiJabatan     string `json:"posisi_jabatan"`
     BidangIndustri    string `json:"bidang_industri"`
     LokasiKerja       string `json:"lokasi_kerja"`
     GajiRange         string `json:"gaji_range"`
-    TanggalMulaiKerja string `json:"tanggal_mulai_kerja"`
-    TanggalSelesaiKerja string `json:"tanggal_selesai_kerja"`
+    Gaji              *GajiRange `json:"gaji"`
+    TanggalMulaiKerja Tanggal `json:"tanggal_mulai_kerja"`
+    TanggalSelesaiKerja Tanggal `json:"tanggal_selesai_kerja"`
     StatusPekerjaan   string `json:"status_pekerjaan"`
     DeskripsiPekerjaan string `json:"deskripsi_pekerjaan"`
     UpdatedAt         time.Time `json:"updated_at"`
@@ -54,5 +84,43 @@ type Trash struct {
     ID          int    `json:"id"`
     AlumniID    int    `json:"alumni_id"`
     IsDelete    string `json:"is_delete"`
+    TrashedAt   *time.Time `json:"trashed_at,omitempty"`
+    TrashedBy   int        `json:"trashed_by,omitempty"`
     UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TrashPurgeCandidate pekerjaan di trash beserta jadwal penghapusan permanennya
+type TrashPurgeCandidate struct {
+    ID          int       `json:"id"`
+    AlumniID    int       `json:"alumni_id"`
+    TrashedAt   time.Time `json:"trashed_at"`
+    TrashedBy   int       `json:"trashed_by,omitempty"`
+    PurgeAt     time.Time `json:"purge_at"`
+}
+
+// TrashFilter memilih pekerjaan di trash berdasarkan alumni dan rentang tanggal masuk trash (YYYY-MM-DD)
+type TrashFilter struct {
+    AlumniID    int    `json:"alumni_id"`
+    TrashedFrom string `json:"trashed_from"`
+    TrashedTo   string `json:"trashed_to"`
+}
+
+// BulkTrashRequest untuk restore/purge banyak pekerjaan sekaligus, berdasarkan daftar id atau filter
+type BulkTrashRequest struct {
+    IDs []int `json:"ids"`
+    TrashFilter
+}
+
+type BulkTrashResult struct {
+    ID      int    `json:"id"`
+    Success bool   `json:"success"`
+    Message string `json:"message"`
+}
+
+type BulkTrashResponse struct {
+    Processed int               `json:"processed"`
+    Succeeded int               `json:"succeeded"`
+    Failed    int               `json:"failed"`
+    Skipped   int               `json:"skipped"`
+    Results   []BulkTrashResult `json:"results"`
 }
\ No newline at end of file
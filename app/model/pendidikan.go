@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// Jenjang pendidikan
+const (
+	JenjangD3      = "D3"
+	JenjangD4      = "D4"
+	JenjangS1      = "S1"
+	JenjangS2      = "S2"
+	JenjangS3      = "S3"
+	JenjangProfesi = "Profesi"
+)
+
+// JenjangDefault dipakai untuk alumni yang dibuat lewat API lama tanpa field jenjang
+const JenjangDefault = JenjangS1
+
+// PendidikanAlumni satu riwayat pendidikan alumni. Pendidikan utama dicerminkan ke kolom
+// jurusan/angkatan/tahun_lulus pada Alumni untuk client lama. TahunLulus nil berarti masih berjalan.
+type PendidikanAlumni struct {
+	ID              int       `json:"id"`
+	AlumniID        int       `json:"alumni_id"`
+	Jenjang         string    `json:"jenjang"`
+	ProgramStudi    string    `json:"program_studi"`
+	TahunMasuk      int       `json:"tahun_masuk"`
+	TahunLulus      *int      `json:"tahun_lulus"`
+	IPK             *float64  `json:"ipk"`
+	JudulTugasAkhir string    `json:"judul_tugas_akhir"`
+	IsUtama         bool      `json:"is_utama"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PendidikanAlumniRequest untuk menambah atau mengubah riwayat pendidikan.
+// IsUtama=true menjadikannya pendidikan utama menggantikan yang lama.
+type PendidikanAlumniRequest struct {
+	Jenjang         string   `json:"jenjang"`
+	ProgramStudi    string   `json:"program_studi"`
+	TahunMasuk      int      `json:"tahun_masuk"`
+	TahunLulus      *int     `json:"tahun_lulus"`
+	IPK             *float64 `json:"ipk"`
+	JudulTugasAkhir string   `json:"judul_tugas_akhir"`
+	IsUtama         bool     `json:"is_utama"`
+}
+
+func ValidJenjang(jenjang string) bool {
+	switch jenjang {
+	case JenjangD3, JenjangD4, JenjangS1, JenjangS2, JenjangS3, JenjangProfesi:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Jenis dan status pengajuan perubahan pekerjaan oleh alumni
+const (
+	JenisPengajuanCreate = "create"
+	JenisPengajuanUpdate = "update"
+
+	StatusPengajuanPending  = "pending"
+	StatusPengajuanApproved = "approved"
+	StatusPengajuanRejected = "rejected"
+)
+
+// PekerjaanData isi pekerjaan yang diajukan alumni
+type PekerjaanData struct {
+	PerusahaanID        int        `json:"perusahaan_id,omitempty"`
+	NamaPerusahaan      string     `json:"nama_perusahaan"`
+	PosisiJabatan       string     `json:"posisi_jabatan"`
+	BidangIndustri      string     `json:"bidang_industri"`
+	LokasiKerja         string     `json:"lokasi_kerja"`
+	GajiRange           string     `json:"gaji_range"`
+	Gaji                *GajiRange `json:"gaji"`
+	TanggalMulaiKerja   Tanggal    `json:"tanggal_mulai_kerja"`
+	TanggalSelesaiKerja Tanggal    `json:"tanggal_selesai_kerja"`
+	StatusPekerjaan     string     `json:"status_pekerjaan"`
+	DeskripsiPekerjaan  string     `json:"deskripsi_pekerjaan"`
+}
+
+type PengajuanPekerjaan struct {
+	ID           int           `json:"id"`
+	AlumniID     int           `json:"alumni_id"`
+	PekerjaanID  int           `json:"pekerjaan_id,omitempty"`
+	Jenis        string        `json:"jenis"`
+	Data         PekerjaanData `json:"data"`
+	Status       string        `json:"status"`
+	DiajukanOleh int           `json:"diajukan_oleh,omitempty"`
+	DitinjauOleh int           `json:"ditinjau_oleh,omitempty"`
+	Komentar     string        `json:"komentar"`
+	CreatedAt    time.Time     `json:"created_at"`
+	ReviewedAt   *time.Time    `json:"reviewed_at,omitempty"`
+	Diff         []FieldDiff   `json:"diff,omitempty"`
+}
+
+// FieldDiff perbedaan satu field antara data pekerjaan saat ini dan yang diajukan
+type FieldDiff struct {
+	Field string `json:"field"`
+	Lama  string `json:"lama"`
+	Baru  string `json:"baru"`
+}
+
+// CreatePengajuanPekerjaanRequest pekerjaan_id kosong berarti pengajuan pekerjaan baru
+type CreatePengajuanPekerjaanRequest struct {
+	PekerjaanID int           `json:"pekerjaan_id"`
+	Data        PekerjaanData `json:"data"`
+}
+
+type ReviewPengajuanPekerjaanRequest struct {
+	Komentar string `json:"komentar"`
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Perusahaan direktori pemberi kerja yang dirujuk pekerjaan_alumni.perusahaan_id
+type Perusahaan struct {
+	ID             int       `json:"id"`
+	Nama           string    `json:"nama"`
+	Alias          []string  `json:"alias"`
+	BidangIndustri string    `json:"bidang_industri"`
+	Kota           string    `json:"kota"`
+	Website        string    `json:"website"`
+	JumlahAlumni   int       `json:"jumlah_alumni"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type PerusahaanRequest struct {
+	Nama           string   `json:"nama"`
+	Alias          []string `json:"alias"`
+	BidangIndustri string   `json:"bidang_industri"`
+	Kota           string   `json:"kota"`
+	Website        string   `json:"website"`
+}
+
+// MitraPerusahaanRequest menghubungkan akun user sebagai mitra perusahaan
+type MitraPerusahaanRequest struct {
+	UserID int `json:"user_id"`
+}
+
+// PerusahaanMatch kandidat hasil pencocokan nama; CocokDengan berisi nama atau alias yang paling mirip
+type PerusahaanMatch struct {
+	Perusahaan
+	CocokDengan string  `json:"cocok_dengan"`
+	Skor        float64 `json:"skor"`
+}
+
+// DuplikatPerusahaan pasangan perusahaan yang namanya mirip dan kemungkinan perlu digabung
+type DuplikatPerusahaan struct {
+	PerusahaanID int     `json:"perusahaan_id"`
+	Nama         string  `json:"nama"`
+	DuplikatID   int     `json:"duplikat_id"`
+	NamaDuplikat string  `json:"nama_duplikat"`
+	Skor         float64 `json:"skor"`
+}
+
+// MergePerusahaanRequest menggabungkan perusahaan sumber ke perusahaan tujuan (:id)
+type MergePerusahaanRequest struct {
+	SumberIDs []int `json:"sumber_ids"`
+}
+
+type MergePerusahaanResult struct {
+	Perusahaan        Perusahaan `json:"perusahaan"`
+	Digabung          []int      `json:"digabung"`
+	PekerjaanDipindah int64      `json:"pekerjaan_dipindah"`
+}
+
+// TopPerusahaan perusahaan dengan jumlah alumni terbanyak
+type TopPerusahaan struct {
+	PerusahaanID   int    `json:"perusahaan_id"`
+	Nama           string `json:"nama"`
+	BidangIndustri string `json:"bidang_industri"`
+	Kota           string `json:"kota"`
+	JumlahAlumni   int    `json:"jumlah_alumni"`
+	AlumniAktif    int    `json:"alumni_aktif"`
+}
+
+// bentukUsaha kata bentuk badan usaha yang diabaikan saat membandingkan nama perusahaan
+var bentukUsaha = map[string]bool{
+	"pt": true, "cv": true, "ud": true, "tbk": true, "persero": true, "perseroan": true, "terbatas": true,
+	"inc": true, "ltd": true, "llc": true, "corp": true, "corporation": true, "co": true, "company": true,
+}
+
+// NormalizeNamaPerusahaan kunci pembanding nama perusahaan: huruf kecil, tanpa tanda baca
+// dan tanpa bentuk badan usaha, sehingga "PT. Telkom Indonesia (Persero) Tbk" menjadi "telkom indonesia"
+func NormalizeNamaPerusahaan(nama string) string {
+	words := strings.FieldsFunc(strings.ToLower(nama), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kept := words[:0]
+	for _, w := range words {
+		if !bentukUsaha[w] {
+			kept = append(kept, w)
+		}
+	}
+	return strings.Join(kept, " ")
+}
@@ -0,0 +1,61 @@
+package model
+
+import "time"
+
+// PermintaanPenghapusan permintaan alumni agar data pribadinya dihapus. Status memakai StatusPengajuan*.
+// AlumniID 0 berarti alumninya sudah dihapus permanen; id aslinya tetap ada di TandaTerima.
+type PermintaanPenghapusan struct {
+	ID           int                     `json:"id"`
+	AlumniID     int                     `json:"alumni_id"`
+	DiajukanOleh int                     `json:"diajukan_oleh,omitempty"`
+	Alasan       string                  `json:"alasan"`
+	Status       string                  `json:"status"`
+	DitinjauOleh int                     `json:"ditinjau_oleh,omitempty"`
+	Komentar     string                  `json:"komentar"`
+	TandaTerima  *TandaTerimaPenghapusan `json:"tanda_terima,omitempty"`
+	CreatedAt    time.Time               `json:"created_at"`
+	ReviewedAt   *time.Time              `json:"reviewed_at,omitempty"`
+}
+
+type CreatePermintaanPenghapusanRequest struct {
+	Alasan string `json:"alasan"`
+}
+
+// TandaTerimaPenghapusan bukti penghapusan yang disimpan setelah data dianonimkan. Hash adalah sha256
+// dari isi tanda terima tanpa field Hash, untuk membuktikan tanda terima tidak diubah.
+type TandaTerimaPenghapusan struct {
+	Nomor               string    `json:"nomor"`
+	PermintaanID        int       `json:"permintaan_id"`
+	AlumniID            int       `json:"alumni_id"`
+	AlumniDigabungIDs   []int     `json:"alumni_digabung_ids,omitempty"`
+	UserIDs             []int     `json:"user_ids"`
+	DiajukanAt          time.Time `json:"diajukan_at"`
+	DisetujuiOleh       int       `json:"disetujui_oleh"`
+	DilaksanakanAt      time.Time `json:"dilaksanakan_at"`
+	FieldDianonimkan    []string  `json:"field_dianonimkan"`
+	JumlahPekerjaan     int       `json:"jumlah_pekerjaan"`
+	JumlahPengajuan     int       `json:"jumlah_pengajuan"`
+	JumlahRevisiDihapus int       `json:"jumlah_revisi_dihapus"`
+	JumlahBerkasDihapus int       `json:"jumlah_berkas_dihapus,omitempty"`
+	DataDipertahankan   []string  `json:"data_dipertahankan"`
+	Catatan             string    `json:"catatan"`
+	Hash                string    `json:"hash"`
+}
+
+// DataExport seluruh data pribadi yang disimpan tentang user yang login
+type DataExport struct {
+	DibuatAt              time.Time               `json:"dibuat_at"`
+	User                  User                    `json:"user"`
+	Alumni                *Alumni                 `json:"alumni"`
+	Pendidikan            []PendidikanAlumni      `json:"pendidikan"`
+	Keahlian              []KeahlianAlumni        `json:"keahlian"`
+	Pekerjaan             []PekerjaanAlumni       `json:"pekerjaan"`
+	PengajuanPekerjaan    []PengajuanPekerjaan    `json:"pengajuan_pekerjaan"`
+	LamaranLowongan       []LamaranLowongan       `json:"lamaran_lowongan"`
+	Acara                 []PendaftaranAcara      `json:"acara"`
+	Mentor                *Mentor                 `json:"mentor"`
+	Mentoring             []PermintaanMentor      `json:"mentoring"`
+	PermintaanPenghapusan []PermintaanPenghapusan `json:"permintaan_penghapusan"`
+	Berkas                []Berkas                `json:"berkas"`
+	AuditLog              []AuditLog              `json:"audit_log"`
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// Jenis kosakata terkontrol yang dikelola admin di tabel referensi
+const (
+	ReferensiFakultas       = "fakultas"
+	ReferensiProgramStudi   = "program_studi"
+	ReferensiBidangIndustri = "bidang_industri"
+	ReferensiProvinsi       = "provinsi"
+	ReferensiKota           = "kota"
+	ReferensiSkill          = "skill"
+)
+
+// ReferensiIndukJenis jenis induk untuk setiap jenis referensi; string kosong berarti tidak punya induk.
+// Induk program_studi dan kota wajib, induk bidang_industri opsional (sub-bidang).
+var ReferensiIndukJenis = map[string]string{
+	ReferensiFakultas:       "",
+	ReferensiProgramStudi:   ReferensiFakultas,
+	ReferensiBidangIndustri: ReferensiBidangIndustri,
+	ReferensiProvinsi:       "",
+	ReferensiKota:           ReferensiProvinsi,
+	ReferensiSkill:          "",
+}
+
+// Referensi satu entri kosakata terkontrol; Induk berisi nama fakultas, provinsi, atau bidang induk
+type Referensi struct {
+	ID        int       `json:"id"`
+	Jenis     string    `json:"jenis"`
+	Kode      string    `json:"kode,omitempty"`
+	Nama      string    `json:"nama"`
+	IndukID   int       `json:"induk_id,omitempty"`
+	Induk     string    `json:"induk,omitempty"`
+	Aktif     bool      `json:"aktif"`
+	Alias     []string  `json:"alias"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ReferensiRequest struct {
+	Kode    string `json:"kode"`
+	Nama    string `json:"nama"`
+	IndukID int    `json:"induk_id"`
+	Aktif   *bool  `json:"aktif"`
+}
+
+// NilaiLegacy nilai lama di data alumni/pekerjaan yang belum cocok dengan kosakata,
+// beserta saran entri kanonis yang paling mirip
+type NilaiLegacy struct {
+	Nilai     string  `json:"nilai"`
+	Jumlah    int     `json:"jumlah"`
+	SaranID   int     `json:"saran_id,omitempty"`
+	SaranNama string  `json:"saran_nama,omitempty"`
+	Skor      float64 `json:"skor"`
+}
+
+// MappingLegacyRequest memetakan nilai lama ke entri kanonis; nilai lama disimpan sebagai alias
+type MappingLegacyRequest struct {
+	NilaiLama   []string `json:"nilai_lama"`
+	ReferensiID int      `json:"referensi_id"`
+}
+
+type MappingLegacyResult struct {
+	Referensi       Referensi `json:"referensi"`
+	AliasDitambah   []string  `json:"alias_ditambah"`
+	BarisDiperbarui int64     `json:"baris_diperbarui"`
+}
+
+// NormalizeReferensi menyamakan huruf besar dan spasi agar nilai bisa dibandingkan dengan nama atau alias
+func NormalizeReferensi(nilai string) string {
+	return strings.ToLower(strings.Join(strings.Fields(nilai), " "))
+}
@@ -8,6 +8,8 @@ type MetaInfo struct {
 	SortBy string `json:"sortBy"`
 	Order  string `json:"order"`
 	Search string `json:"search"`
+	Scope  string `json:"scope,omitempty"`
+	Skill  []string `json:"skill,omitempty"`
 }
 
 type AlumniResponse struct {
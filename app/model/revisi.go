@@ -0,0 +1,74 @@
+package model
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Entitas yang memiliki riwayat revisi
+const (
+	RevisiEntitasAlumni    = "alumni"
+	RevisiEntitasPekerjaan = "pekerjaan_alumni"
+)
+
+// Aksi yang menghasilkan revisi. RevisiAksiAwal adalah nilai record sebelum perubahan pertama yang tercatat.
+const (
+	RevisiAksiAwal   = "awal"
+	RevisiAksiBuat   = "buat"
+	RevisiAksiUbah   = "ubah"
+	RevisiAksiStatus = "status"
+	RevisiAksiRevert = "revert"
+)
+
+// Revisi salinan lengkap record pada satu versi. Data memakai nama field JSON record tanpa id dan kolom waktu/trash.
+type Revisi struct {
+	ID           int                    `json:"id"`
+	Entitas      string                 `json:"entitas"`
+	EntitasID    int                    `json:"entitas_id"`
+	Versi        int                    `json:"versi"`
+	Aksi         string                 `json:"aksi"`
+	Data         map[string]interface{} `json:"data"`
+	FieldBerubah []string               `json:"field_berubah"`
+	RevertDari   int                    `json:"revert_dari,omitempty"`
+	DibuatOleh   int                    `json:"dibuat_oleh,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+type PerubahanField struct {
+	Field string      `json:"field"`
+	Lama  interface{} `json:"lama"`
+	Baru  interface{} `json:"baru"`
+}
+
+type RevisiDiff struct {
+	Entitas   string           `json:"entitas"`
+	EntitasID int              `json:"entitas_id"`
+	Dari      int              `json:"dari"`
+	Ke        int              `json:"ke"`
+	Perubahan []PerubahanField `json:"perubahan"`
+}
+
+// DiffRevisiData membandingkan dua data revisi, diurutkan menurut nama field
+func DiffRevisiData(lama, baru map[string]interface{}) []PerubahanField {
+	fields := map[string]bool{}
+	for k := range lama {
+		fields[k] = true
+	}
+	for k := range baru {
+		fields[k] = true
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	perubahan := []PerubahanField{}
+	for _, k := range keys {
+		if !reflect.DeepEqual(lama[k], baru[k]) {
+			perubahan = append(perubahan, PerubahanField{Field: k, Lama: lama[k], Baru: baru[k]})
+		}
+	}
+	return perubahan
+}
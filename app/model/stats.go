@@ -0,0 +1,40 @@
+package model
+
+// StatsFilter untuk filter statistik tracer study
+type StatsFilter struct {
+	Angkatan   int    `json:"angkatan,omitempty"`
+	TahunLulus int    `json:"tahun_lulus,omitempty"`
+	Jurusan    string `json:"jurusan,omitempty"`
+	// Jenjang membatasi statistik ke alumni yang menempuh jenjang ini; filter lain lalu berlaku untuk
+	// pendidikan jenjang tersebut
+	Jenjang string `json:"jenjang,omitempty"`
+}
+
+// EmploymentRate tingkat keterserapan kerja untuk satu grup (angkatan, tahun lulus, jurusan, atau jenjang)
+type EmploymentRate struct {
+	Grup          string  `json:"grup"`
+	TotalAlumni   int     `json:"total_alumni"`
+	AlumniBekerja int     `json:"alumni_bekerja"`
+	Persentase    float64 `json:"persentase"`
+}
+
+// DistributionItem jumlah pekerjaan untuk satu nilai (bidang industri, lokasi kerja, gaji range)
+type DistributionItem struct {
+	Nilai      string  `json:"nilai"`
+	Jumlah     int     `json:"jumlah"`
+	Persentase float64 `json:"persentase"`
+}
+
+type StatsResponse struct {
+	Keseluruhan             EmploymentRate     `json:"keseluruhan"`
+	PerAngkatan             []EmploymentRate   `json:"per_angkatan"`
+	PerTahunLulus           []EmploymentRate   `json:"per_tahun_lulus"`
+	PerJurusan              []EmploymentRate   `json:"per_jurusan"`
+	PerJenjang              []EmploymentRate   `json:"per_jenjang"`
+	RataRataMasaTungguTahun float64            `json:"rata_rata_masa_tunggu_tahun"`
+	BidangIndustri          []DistributionItem `json:"bidang_industri"`
+	LokasiKerja             []DistributionItem `json:"lokasi_kerja"`
+	GajiRange               []DistributionItem `json:"gaji_range"`
+	TopPerusahaan           []TopPerusahaan    `json:"top_perusahaan"`
+	Filter                  StatsFilter        `json:"filter"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Status pekerjaan yang dikenali
+const (
+	StatusPekerjaanAktif           = "aktif"
+	StatusPekerjaanSelesai         = "selesai"
+	StatusPekerjaanCuti            = "cuti"
+	StatusPekerjaanKontrakBerakhir = "kontrak-berakhir"
+)
+
+// StatusPekerjaanTransitions daftar transisi yang diizinkan dari setiap status.
+// selesai adalah status akhir; kontrak-berakhir masih bisa kembali aktif jika kontrak diperpanjang.
+var StatusPekerjaanTransitions = map[string][]string{
+	StatusPekerjaanAktif:           {StatusPekerjaanSelesai, StatusPekerjaanCuti, StatusPekerjaanKontrakBerakhir},
+	StatusPekerjaanCuti:            {StatusPekerjaanAktif, StatusPekerjaanSelesai, StatusPekerjaanKontrakBerakhir},
+	StatusPekerjaanKontrakBerakhir: {StatusPekerjaanAktif},
+	StatusPekerjaanSelesai:         {},
+}
+
+type StatusPekerjaanHistory struct {
+	ID          int       `json:"id"`
+	PekerjaanID int       `json:"pekerjaan_id"`
+	StatusLama  string    `json:"status_lama"`
+	StatusBaru  string    `json:"status_baru"`
+	DiubahOleh  int       `json:"diubah_oleh,omitempty"`
+	Catatan     string    `json:"catatan"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type UpdateStatusPekerjaanRequest struct {
+	StatusPekerjaan     string  `json:"status_pekerjaan"`
+	TanggalSelesaiKerja Tanggal `json:"tanggal_selesai_kerja"`
+	Catatan             string  `json:"catatan"`
+}
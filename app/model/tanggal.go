@@ -0,0 +1,115 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TanggalLayout format kanonis tanggal di JSON dan database
+const TanggalLayout = "2006-01-02"
+
+// tanggalLayouts format yang diterima saat parsing, termasuk format lama yang tersimpan sebagai teks
+var tanggalLayouts = []string{
+	TanggalLayout,
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"02/01/2006",
+	"02-01-2006",
+	"2006/01/02",
+	"2006-01",
+	"01/2006",
+	"2006",
+}
+
+// Tanggal tanggal tanpa jam (kolom DATE). Nilai nol berarti kosong: NULL di database dan null di JSON.
+type Tanggal struct {
+	time.Time
+}
+
+// NewTanggal membuang komponen jam dari t
+func NewTanggal(t time.Time) Tanggal {
+	y, m, d := t.Date()
+	return Tanggal{time.Date(y, m, d, 0, 0, 0, 0, time.UTC)}
+}
+
+// Today tanggal hari ini menurut waktu lokal server
+func Today() Tanggal {
+	return NewTanggal(time.Now())
+}
+
+// ParseTanggal mengurai YYYY-MM-DD serta format lama (DD/MM/YYYY, YYYY-MM, YYYY, ...).
+// String kosong menghasilkan Tanggal nol tanpa error.
+func ParseTanggal(value string) (Tanggal, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Tanggal{}, nil
+	}
+	for _, layout := range tanggalLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return NewTanggal(t), nil
+		}
+	}
+	return Tanggal{}, fmt.Errorf("format tanggal tidak valid: %q (gunakan YYYY-MM-DD)", value)
+}
+
+func (t Tanggal) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(TanggalLayout)
+}
+
+func (t Tanggal) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
+
+func (t *Tanggal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Tanggal{}
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("tanggal harus berupa string YYYY-MM-DD")
+	}
+	parsed, err := ParseTanggal(value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Scan menerima DATE/TIMESTAMP maupun teks lama dari database
+func (t *Tanggal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*t = Tanggal{}
+		return nil
+	case time.Time:
+		*t = NewTanggal(v)
+		return nil
+	case []byte:
+		parsed, err := ParseTanggal(string(v))
+		*t = parsed
+		return err
+	case string:
+		parsed, err := ParseTanggal(v)
+		*t = parsed
+		return err
+	}
+	return fmt.Errorf("tidak dapat membaca %T sebagai tanggal", src)
+}
+
+func (t Tanggal) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.String(), nil
+}
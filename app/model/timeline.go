@@ -0,0 +1,58 @@
+package model
+
+// Jenis peristiwa pada timeline karir alumni
+const (
+	TimelineLulus        = "lulus"
+	TimelineMulaiKerja   = "mulai_kerja"
+	TimelineSelesaiKerja = "selesai_kerja"
+	TimelineStatus       = "status"
+	TimelineJeda         = "jeda"
+)
+
+// TimelineEvent satu peristiwa pada timeline, diurutkan menurut tanggal
+type TimelineEvent struct {
+	Tanggal     Tanggal `json:"tanggal"`
+	Jenis       string  `json:"jenis"`
+	Judul       string  `json:"judul"`
+	Keterangan  string  `json:"keterangan,omitempty"`
+	PekerjaanID int     `json:"pekerjaan_id,omitempty"`
+}
+
+// TimelinePekerjaan pekerjaan beserta durasi dan riwayat statusnya; pekerjaan berjalan dihitung sampai hari ini
+type TimelinePekerjaan struct {
+	PekerjaanAlumni
+	DurasiBulan   int                      `json:"durasi_bulan"`
+	Berjalan      bool                     `json:"berjalan"`
+	RiwayatStatus []StatusPekerjaanHistory `json:"riwayat_status"`
+}
+
+// JedaKarir rentang waktu tanpa pekerjaan di antara dua pekerjaan
+type JedaKarir struct {
+	Dari               Tanggal `json:"dari"`
+	Sampai             Tanggal `json:"sampai"`
+	DurasiBulan        int     `json:"durasi_bulan"`
+	SebelumPekerjaanID int     `json:"sebelum_pekerjaan_id"`
+	SesudahPekerjaanID int     `json:"sesudah_pekerjaan_id"`
+}
+
+// TimelineRingkasan nilai turunan untuk halaman profil. Tanggal lulus hanya diketahui tahunnya,
+// sehingga dianggap 1 Juli tahun lulus. Pengalaman menghitung irisan pekerjaan satu kali.
+// BulanKePekerjaanPertama nil jika tahun lulus atau pekerjaan belum ada, dan 0 jika sudah bekerja sebelum lulus.
+type TimelineRingkasan struct {
+	TanggalLulus            Tanggal `json:"tanggal_lulus"`
+	JumlahPekerjaan         int     `json:"jumlah_pekerjaan"`
+	TotalPengalamanBulan    int     `json:"total_pengalaman_bulan"`
+	TotalPengalamanTahun    float64 `json:"total_pengalaman_tahun"`
+	BulanKePekerjaanPertama *int    `json:"bulan_ke_pekerjaan_pertama"`
+	BekerjaSebelumLulus     bool    `json:"bekerja_sebelum_lulus"`
+	TotalJedaBulan          int     `json:"total_jeda_bulan"`
+}
+
+type AlumniTimeline struct {
+	Alumni           Alumni              `json:"alumni"`
+	Ringkasan        TimelineRingkasan   `json:"ringkasan"`
+	PekerjaanSaatIni []PekerjaanAlumni   `json:"pekerjaan_saat_ini"`
+	Pekerjaan        []TimelinePekerjaan `json:"pekerjaan"`
+	Jeda             []JedaKarir         `json:"jeda"`
+	Events           []TimelineEvent     `json:"events"`
+}
@@ -9,7 +9,10 @@ type User struct {
 	Password  string    `json:"password"`
 	AlumniID  int       `json:"alumni_id"`
 	RoleID    int       `json:"role_id"`
+	IsActive  bool      `json:"is_active"`
 	CreatedAt time.Time `json:"created_at"`
+	// PerusahaanID terisi untuk akun mitra industri yang boleh memasang lowongan perusahaan tersebut
+	PerusahaanID int `json:"perusahaan_id,omitempty"`
 }
 
 type Role struct {
@@ -0,0 +1,14 @@
+package model
+
+// FieldError detail hasil validasi untuk satu field request, dipakai untuk error maupun peringatan
+type FieldError struct {
+	Field string `json:"field"`
+	Kode  string `json:"kode"`
+	Pesan string `json:"pesan"`
+}
+
+// Penanganan pekerjaan aktif yang tumpang tindih (PEKERJAAN_OVERLAP_MODE)
+const (
+	OverlapModeWarn   = "warn"
+	OverlapModeReject = "reject"
+)
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// Tingkat visibilitas field kontak alumni
+const (
+	VisibilitasPublik = "publik"
+	VisibilitasAlumni = "alumni"
+	VisibilitasAdmin  = "admin"
+)
+
+// AlumniVisibilitas siapa saja yang boleh melihat email, nomor telepon, alamat, dan foto profil seorang alumni
+type AlumniVisibilitas struct {
+	AlumniID  int       `json:"alumni_id"`
+	Email     string    `json:"email"`
+	NoTelepon string    `json:"no_telepon"`
+	Alamat    string    `json:"alamat"`
+	Foto      string    `json:"foto"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UpdateAlumniVisibilitasRequest field yang kosong tidak diubah
+type UpdateAlumniVisibilitasRequest struct {
+	Email     string `json:"email"`
+	NoTelepon string `json:"no_telepon"`
+	Alamat    string `json:"alamat"`
+	Foto      string `json:"foto"`
+}
+
+// DefaultAlumniVisibilitas dipakai untuk alumni yang belum mengatur visibilitasnya
+func DefaultAlumniVisibilitas(alumniID int) AlumniVisibilitas {
+	return AlumniVisibilitas{
+		AlumniID:  alumniID,
+		Email:     VisibilitasAlumni,
+		NoTelepon: VisibilitasAdmin,
+		Alamat:    VisibilitasAdmin,
+		Foto:      VisibilitasAlumni,
+	}
+}
+
+func ValidVisibilitas(level string) bool {
+	return level == VisibilitasPublik || level == VisibilitasAlumni || level == VisibilitasAdmin
+}
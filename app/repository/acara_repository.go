@@ -0,0 +1,551 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"hello-fiber/app/model"
+	"log"
+	"strings"
+	"time"
+)
+
+type AcaraRepository interface {
+	GetAcaraWithPagination(filter model.AcaraFilter, sortBy, order string, limit, offset int) ([]model.Acara, error)
+	CountAcara(filter model.AcaraFilter) (int, error)
+	GetByID(id, alumniID int) (model.Acara, error)
+	Create(req model.AcaraRequest, actorID int) (model.Acara, error)
+	Update(id int, req model.AcaraRequest) (model.Acara, error)
+	UpdateStatus(id int, status string) (model.Acara, error)
+	Delete(id int) error
+	CountPendaftaran(id int) (int, error)
+	Daftar(id, alumniID int) (model.PendaftaranAcara, error)
+	BatalDaftar(id, alumniID int) (model.PendaftaranAcara, error)
+	GetPendaftaranByAcara(id int, status string) ([]model.PendaftaranAcara, error)
+	GetPendaftaranByAlumni(alumniID int) ([]model.PendaftaranAcara, error)
+	GetPendaftaran(id, alumniID int) (model.PendaftaranAcara, error)
+	GetPendaftaranByID(id int) (model.PendaftaranAcara, error)
+	CheckIn(id, pendaftaranID, actorID int) (model.PendaftaranAcara, error)
+	GetPetugas(id int) ([]model.User, error)
+	TambahPetugas(id, userID int) error
+	HapusPetugas(id, userID int) error
+}
+
+var (
+	// ErrPendaftaranAcaraDitutup acara belum dipublikasikan, dibatalkan, atau batas pendaftaran sudah lewat
+	ErrPendaftaranAcaraDitutup = errors.New("pendaftaran acara sudah ditutup")
+	// ErrSudahTerdaftar alumni sudah terdaftar atau masuk daftar tunggu acara yang sama
+	ErrSudahTerdaftar = errors.New("alumni sudah terdaftar di acara ini")
+	// ErrKapasitasKurang kapasitas baru lebih kecil dari jumlah peserta terdaftar
+	ErrKapasitasKurang = errors.New("kapasitas lebih kecil dari jumlah peserta terdaftar")
+	// ErrSudahCheckIn peserta sudah check-in sehingga tiket tidak dapat dipakai atau dibatalkan lagi
+	ErrSudahCheckIn = errors.New("peserta sudah check-in")
+	// ErrTiketTidakBerlaku pendaftaran dibatalkan, masih di daftar tunggu, atau acaranya dibatalkan
+	ErrTiketTidakBerlaku = errors.New("tiket tidak berlaku")
+)
+
+type acaraRepository struct {
+	db *sql.DB
+}
+
+func NewAcaraRepository(db *sql.DB) AcaraRepository {
+	return &acaraRepository{db: db}
+}
+
+// acaraColumns kolom acara (alias a) beserta rekap pendaftaran; pemirsa placeholder alumni_id user yang
+// melihat (0 jika bukan alumni) untuk mengisi StatusPendaftaran
+func acaraColumns(pemirsa string) string {
+	return `a.id, a.judul, a.deskripsi, a.jenis, a.lokasi, a.mulai_at, a.selesai_at, a.batas_daftar, a.kapasitas,
+	a.angkatan, a.jurusan, a.status, COALESCE(a.dibuat_oleh, 0), a.created_at, a.updated_at,
+	(SELECT COUNT(*) FROM pendaftaran_acara p WHERE p.acara_id = a.id AND p.status = 'terdaftar'),
+	(SELECT COUNT(*) FROM pendaftaran_acara p WHERE p.acara_id = a.id AND p.status = 'daftar_tunggu'),
+	(SELECT COUNT(*) FROM pendaftaran_acara p WHERE p.acara_id = a.id AND p.status = 'terdaftar' AND p.check_in_at IS NOT NULL),
+	COALESCE((SELECT p.status FROM pendaftaran_acara p WHERE p.acara_id = a.id AND p.alumni_id = ` + pemirsa + `), '')`
+}
+
+func scanAcara(scanner interface{ Scan(...interface{}) error }) (model.Acara, error) {
+	var a model.Acara
+	var batasDaftar sql.NullTime
+	var kapasitas, angkatan sql.NullInt64
+	var jurusan sql.NullString
+
+	err := scanner.Scan(
+		&a.ID, &a.Judul, &a.Deskripsi, &a.Jenis, &a.Lokasi, &a.MulaiAt, &a.SelesaiAt, &batasDaftar, &kapasitas,
+		&angkatan, &jurusan, &a.Status, &a.DibuatOleh, &a.CreatedAt, &a.UpdatedAt,
+		&a.JumlahTerdaftar, &a.JumlahDaftarTunggu, &a.JumlahHadir, &a.StatusPendaftaran,
+	)
+	if batasDaftar.Valid {
+		a.BatasDaftar = &batasDaftar.Time
+	}
+	a.Kapasitas = int(kapasitas.Int64)
+	a.Angkatan = int(angkatan.Int64)
+	a.Jurusan = jurusan.String
+	return a, err
+}
+
+// acaraFilterCondition menyusun kondisi WHERE dari AcaraFilter
+func acaraFilterCondition(filter model.AcaraFilter) (string, []interface{}) {
+	args := []interface{}{"%" + filter.Search + "%"}
+	conditions := []string{"(a.judul ILIKE $1 OR a.deskripsi ILIKE $1 OR a.lokasi ILIKE $1)"}
+
+	if filter.Sasaran != nil {
+		args = append(args, filter.Sasaran.Angkatan, filter.Sasaran.Jurusan)
+		conditions = append(conditions, fmt.Sprintf(
+			"a.status = 'dipublikasikan' AND (a.angkatan IS NULL OR a.angkatan = $%d) AND (a.jurusan IS NULL OR a.jurusan ILIKE $%d)",
+			len(args)-1, len(args)))
+	} else if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("a.status = $%d", len(args)))
+	}
+	if filter.Jenis != "" {
+		args = append(args, filter.Jenis)
+		conditions = append(conditions, fmt.Sprintf("a.jenis = $%d", len(args)))
+	}
+	if filter.Mendatang {
+		conditions = append(conditions, "a.selesai_at >= NOW()")
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *acaraRepository) GetAcaraWithPagination(filter model.AcaraFilter, sortBy, order string, limit, offset int) ([]model.Acara, error) {
+	validSortColumns := map[string]string{
+		"id": "a.id", "judul": "a.judul", "mulai_at": "a.mulai_at", "created_at": "a.created_at", "updated_at": "a.updated_at",
+	}
+	sortExpr, ok := validSortColumns[sortBy]
+	if !ok {
+		sortExpr = "a.id"
+	}
+
+	where, args := acaraFilterCondition(filter)
+	args = append(args, filter.AlumniID, limit, offset)
+	query := fmt.Sprintf(`SELECT %s
+		FROM acara a
+		WHERE %s
+		ORDER BY %s %s, a.id
+		LIMIT $%d OFFSET $%d
+	`, acaraColumns(fmt.Sprintf("$%d", len(args)-2)), where, sortExpr, order, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Println("Error men-query acara:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.Acara{}
+	for rows.Next() {
+		a, err := scanAcara(rows)
+		if err != nil {
+			log.Println("Error men-scan acara:", err)
+			return nil, err
+		}
+		list = append(list, a)
+	}
+	return list, rows.Err()
+}
+
+func (r *acaraRepository) CountAcara(filter model.AcaraFilter) (int, error) {
+	var total int
+	where, args := acaraFilterCondition(filter)
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM acara a WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung acara:", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetByID mengambil acara apa pun statusnya; alumniID mengisi StatusPendaftaran
+func (r *acaraRepository) GetByID(id, alumniID int) (model.Acara, error) {
+	a, err := scanAcara(r.db.QueryRow(`SELECT `+acaraColumns("$1")+` FROM acara a WHERE a.id = $2`, alumniID, id))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil acara:", err)
+	}
+	return a, err
+}
+
+// Create membuat acara baru berstatus draft
+func (r *acaraRepository) Create(req model.AcaraRequest, actorID int) (model.Acara, error) {
+	now := time.Now()
+	var id int
+	err := r.db.QueryRow(`INSERT INTO acara (judul, deskripsi, jenis, lokasi, mulai_at, selesai_at, batas_daftar, kapasitas,
+			angkatan, jurusan, status, dibuat_oleh, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13) RETURNING id`,
+		req.Judul, req.Deskripsi, req.Jenis, req.Lokasi, req.MulaiAt, req.SelesaiAt, req.BatasDaftar, nullableInt(req.Kapasitas),
+		nullableInt(req.Angkatan), nullableString(req.Jurusan), model.StatusAcaraDraft, nullableInt(actorID), now,
+	).Scan(&id)
+	if err != nil {
+		log.Println("Error menyimpan acara:", err)
+		return model.Acara{}, err
+	}
+	return r.GetByID(id, 0)
+}
+
+// lockAcara mengunci baris acara selama transaksi agar kuota kursi tidak terlampaui oleh pendaftaran bersamaan.
+// lewatBatas true jika batas pendaftaran (atau waktu mulai acara) sudah lewat.
+func lockAcara(tx *sql.Tx, id int) (status string, kapasitas sql.NullInt64, lewatBatas bool, err error) {
+	err = tx.QueryRow(`SELECT status, kapasitas, COALESCE(batas_daftar, mulai_at) < NOW() FROM acara WHERE id = $1 FOR UPDATE`, id).
+		Scan(&status, &kapasitas, &lewatBatas)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengunci acara:", err)
+	}
+	return status, kapasitas, lewatBatas, err
+}
+
+func countTerdaftar(tx *sql.Tx, id int) (int, error) {
+	var total int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pendaftaran_acara WHERE acara_id = $1 AND status = 'terdaftar'`, id).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung peserta acara:", err)
+	}
+	return total, err
+}
+
+// promosikanDaftarTunggu mengisi kursi kosong dengan daftar tunggu paling awal; acara yang dibatalkan dilewati
+func promosikanDaftarTunggu(tx *sql.Tx, id int) error {
+	_, err := tx.Exec(`UPDATE pendaftaran_acara SET status = 'terdaftar', updated_at = $2
+		WHERE id IN (
+			SELECT p.id FROM pendaftaran_acara p
+			WHERE p.acara_id = $1 AND p.status = 'daftar_tunggu'
+			  AND EXISTS (SELECT 1 FROM acara a WHERE a.id = $1 AND a.status <> 'dibatalkan')
+			ORDER BY p.didaftar_at, p.id
+			LIMIT GREATEST(
+				COALESCE((SELECT kapasitas FROM acara WHERE id = $1), 2147483647)
+				- (SELECT COUNT(*) FROM pendaftaran_acara WHERE acara_id = $1 AND status = 'terdaftar'), 0)
+		)`, id, time.Now())
+	if err != nil {
+		log.Println("Error mempromosikan daftar tunggu acara:", err)
+	}
+	return err
+}
+
+// Update mengubah acara. Kapasitas tidak boleh di bawah jumlah peserta terdaftar; kursi tambahan langsung
+// diisi dari daftar tunggu.
+func (r *acaraRepository) Update(id int, req model.AcaraRequest) (model.Acara, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi acara:", err)
+		return model.Acara{}, err
+	}
+	defer tx.Rollback()
+
+	if _, _, _, err := lockAcara(tx, id); err != nil {
+		return model.Acara{}, err
+	}
+	if req.Kapasitas > 0 {
+		terdaftar, err := countTerdaftar(tx, id)
+		if err != nil {
+			return model.Acara{}, err
+		}
+		if terdaftar > req.Kapasitas {
+			return model.Acara{}, ErrKapasitasKurang
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE acara
+		SET judul = $1, deskripsi = $2, jenis = $3, lokasi = $4, mulai_at = $5, selesai_at = $6, batas_daftar = $7,
+		    kapasitas = $8, angkatan = $9, jurusan = $10, updated_at = $11
+		WHERE id = $12`,
+		req.Judul, req.Deskripsi, req.Jenis, req.Lokasi, req.MulaiAt, req.SelesaiAt, req.BatasDaftar,
+		nullableInt(req.Kapasitas), nullableInt(req.Angkatan), nullableString(req.Jurusan), time.Now(), id,
+	)
+	if err != nil {
+		log.Println("Error mengupdate acara:", err)
+		return model.Acara{}, err
+	}
+	if err := promosikanDaftarTunggu(tx, id); err != nil {
+		return model.Acara{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Acara{}, err
+	}
+	return r.GetByID(id, 0)
+}
+
+func (r *acaraRepository) UpdateStatus(id int, status string) (model.Acara, error) {
+	result, err := r.db.Exec(`UPDATE acara SET status = $1, updated_at = $2 WHERE id = $3`, status, time.Now(), id)
+	if err != nil {
+		log.Println("Error mengubah status acara:", err)
+		return model.Acara{}, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return model.Acara{}, sql.ErrNoRows
+	}
+	return r.GetByID(id, 0)
+}
+
+func (r *acaraRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM acara WHERE id = $1`, id)
+	if err != nil {
+		log.Println("Error menghapus acara:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountPendaftaran menghitung seluruh pendaftaran acara, termasuk yang dibatalkan
+func (r *acaraRepository) CountPendaftaran(id int) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM pendaftaran_acara WHERE acara_id = $1`, id).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung pendaftaran acara:", err)
+	}
+	return total, err
+}
+
+// Daftar mendaftarkan alumni ke acara yang dipublikasikan; jika kursi penuh alumni masuk daftar tunggu.
+// Pendaftaran yang pernah dibatalkan boleh diulang dan masuk antrean paling belakang.
+func (r *acaraRepository) Daftar(id, alumniID int) (model.PendaftaranAcara, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi pendaftaran acara:", err)
+		return model.PendaftaranAcara{}, err
+	}
+	defer tx.Rollback()
+
+	status, kapasitas, lewatBatas, err := lockAcara(tx, id)
+	if err != nil {
+		return model.PendaftaranAcara{}, err
+	}
+	if status != model.StatusAcaraDipublikasikan || lewatBatas {
+		return model.PendaftaranAcara{}, ErrPendaftaranAcaraDitutup
+	}
+
+	statusBaru := model.StatusPendaftaranTerdaftar
+	if kapasitas.Valid {
+		terdaftar, err := countTerdaftar(tx, id)
+		if err != nil {
+			return model.PendaftaranAcara{}, err
+		}
+		if int64(terdaftar) >= kapasitas.Int64 {
+			statusBaru = model.StatusPendaftaranDaftarTunggu
+		}
+	}
+
+	now := time.Now()
+	var pendaftaranID int
+	err = tx.QueryRow(`INSERT INTO pendaftaran_acara (acara_id, alumni_id, status, didaftar_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (acara_id, alumni_id) DO UPDATE
+		SET status = EXCLUDED.status, didaftar_at = EXCLUDED.didaftar_at, check_in_at = NULL, check_in_oleh = NULL,
+		    updated_at = EXCLUDED.updated_at
+		WHERE pendaftaran_acara.status = 'dibatalkan'
+		RETURNING id`, id, alumniID, statusBaru, now).Scan(&pendaftaranID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.PendaftaranAcara{}, ErrSudahTerdaftar
+		}
+		log.Println("Error menyimpan pendaftaran acara:", err)
+		return model.PendaftaranAcara{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.PendaftaranAcara{}, err
+	}
+	return r.GetPendaftaranByID(pendaftaranID)
+}
+
+// BatalDaftar membatalkan pendaftaran atau keluar dari daftar tunggu. Kursi yang kosong langsung diisi
+// daftar tunggu berikutnya. Peserta yang sudah check-in tidak dapat membatalkan.
+func (r *acaraRepository) BatalDaftar(id, alumniID int) (model.PendaftaranAcara, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi pembatalan acara:", err)
+		return model.PendaftaranAcara{}, err
+	}
+	defer tx.Rollback()
+
+	if _, _, _, err := lockAcara(tx, id); err != nil {
+		return model.PendaftaranAcara{}, err
+	}
+
+	var pendaftaranID int
+	var status string
+	var checkInAt sql.NullTime
+	err = tx.QueryRow(`SELECT id, status, check_in_at FROM pendaftaran_acara
+		WHERE acara_id = $1 AND alumni_id = $2 AND status <> 'dibatalkan'`, id, alumniID).
+		Scan(&pendaftaranID, &status, &checkInAt)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error mengambil pendaftaran acara:", err)
+		}
+		return model.PendaftaranAcara{}, err
+	}
+	if checkInAt.Valid {
+		return model.PendaftaranAcara{}, ErrSudahCheckIn
+	}
+
+	if _, err := tx.Exec(`UPDATE pendaftaran_acara SET status = 'dibatalkan', updated_at = $1 WHERE id = $2`,
+		time.Now(), pendaftaranID); err != nil {
+		log.Println("Error membatalkan pendaftaran acara:", err)
+		return model.PendaftaranAcara{}, err
+	}
+	if status == model.StatusPendaftaranTerdaftar {
+		if err := promosikanDaftarTunggu(tx, id); err != nil {
+			return model.PendaftaranAcara{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.PendaftaranAcara{}, err
+	}
+	return r.GetPendaftaranByID(pendaftaranID)
+}
+
+// pendaftaranColumns kolom pendaftaran (alias p) beserta acara (a) dan alumni (al); email alumni masih terenkripsi
+const pendaftaranColumns = `p.id, p.acara_id, a.judul, a.mulai_at, p.alumni_id, al.nim, al.nama, al.angkatan, al.jurusan,
+	COALESCE(al.email, ''), p.status,
+	CASE WHEN p.status = 'daftar_tunggu' THEN (
+		SELECT COUNT(*) FROM pendaftaran_acara q
+		WHERE q.acara_id = p.acara_id AND q.status = 'daftar_tunggu' AND (q.didaftar_at, q.id) <= (p.didaftar_at, p.id)
+	) ELSE 0 END,
+	p.didaftar_at, p.check_in_at, COALESCE(p.check_in_oleh, 0), p.updated_at`
+
+const pendaftaranFrom = `FROM pendaftaran_acara p
+	JOIN acara a ON a.id = p.acara_id
+	JOIN alumni al ON al.id = p.alumni_id`
+
+func (r *acaraRepository) scanPendaftaran(scanner interface{ Scan(...interface{}) error }) (model.PendaftaranAcara, error) {
+	var p model.PendaftaranAcara
+	var checkInAt sql.NullTime
+	err := scanner.Scan(
+		&p.ID, &p.AcaraID, &p.JudulAcara, &p.MulaiAt, &p.AlumniID, &p.NIM, &p.NamaAlumni, &p.Angkatan, &p.Jurusan,
+		&p.Email, &p.Status, &p.UrutanTunggu, &p.DidaftarAt, &checkInAt, &p.CheckInOleh, &p.UpdatedAt,
+	)
+	if err != nil {
+		return p, err
+	}
+	if checkInAt.Valid {
+		p.CheckInAt = &checkInAt.Time
+	}
+	p.Email, err = decryptPII(r.db, kolomPIIEmail, p.Email)
+	return p, err
+}
+
+func (r *acaraRepository) queryPendaftaran(query string, args ...interface{}) ([]model.PendaftaranAcara, error) {
+	rows, err := r.db.Query(`SELECT `+pendaftaranColumns+` `+pendaftaranFrom+` `+query, args...)
+	if err != nil {
+		log.Println("Error men-query pendaftaran acara:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.PendaftaranAcara{}
+	for rows.Next() {
+		p, err := r.scanPendaftaran(rows)
+		if err != nil {
+			log.Println("Error men-scan pendaftaran acara:", err)
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// GetPendaftaranByAcara peserta acara; status kosong berarti semua kecuali yang dibatalkan.
+// Peserta terdaftar lebih dulu, lalu daftar tunggu sesuai urutan antrean.
+func (r *acaraRepository) GetPendaftaranByAcara(id int, status string) ([]model.PendaftaranAcara, error) {
+	if status == "" {
+		return r.queryPendaftaran(`WHERE p.acara_id = $1 AND p.status <> 'dibatalkan'
+			ORDER BY p.status DESC, p.didaftar_at, p.id`, id)
+	}
+	return r.queryPendaftaran(`WHERE p.acara_id = $1 AND p.status = $2 ORDER BY p.didaftar_at, p.id`, id, status)
+}
+
+// GetPendaftaranByAlumni riwayat pendaftaran dan kehadiran alumni, acara terbaru lebih dulu
+func (r *acaraRepository) GetPendaftaranByAlumni(alumniID int) ([]model.PendaftaranAcara, error) {
+	return r.queryPendaftaran(`WHERE p.alumni_id = $1 ORDER BY a.mulai_at DESC, p.id DESC`, alumniID)
+}
+
+func (r *acaraRepository) GetPendaftaran(id, alumniID int) (model.PendaftaranAcara, error) {
+	p, err := r.scanPendaftaran(r.db.QueryRow(`SELECT `+pendaftaranColumns+` `+pendaftaranFrom+`
+		WHERE p.acara_id = $1 AND p.alumni_id = $2`, id, alumniID))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil pendaftaran acara:", err)
+	}
+	return p, err
+}
+
+func (r *acaraRepository) GetPendaftaranByID(id int) (model.PendaftaranAcara, error) {
+	p, err := r.scanPendaftaran(r.db.QueryRow(`SELECT `+pendaftaranColumns+` `+pendaftaranFrom+` WHERE p.id = $1`, id))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil pendaftaran acara:", err)
+	}
+	return p, err
+}
+
+// CheckIn mencatat kehadiran peserta terdaftar satu kali. Mengembalikan ErrSudahCheckIn beserta data
+// pendaftaran jika tiket sudah pernah dipakai.
+func (r *acaraRepository) CheckIn(id, pendaftaranID, actorID int) (model.PendaftaranAcara, error) {
+	result, err := r.db.Exec(`UPDATE pendaftaran_acara SET check_in_at = $1, check_in_oleh = $2, updated_at = $1
+		WHERE id = $3 AND acara_id = $4 AND status = 'terdaftar' AND check_in_at IS NULL
+		  AND EXISTS (SELECT 1 FROM acara WHERE id = $4 AND status = 'dipublikasikan')`,
+		time.Now(), nullableInt(actorID), pendaftaranID, id)
+	if err != nil {
+		log.Println("Error mencatat check-in acara:", err)
+		return model.PendaftaranAcara{}, err
+	}
+
+	p, err := r.GetPendaftaranByID(pendaftaranID)
+	if err != nil {
+		return p, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		if p.AcaraID != id {
+			return model.PendaftaranAcara{}, sql.ErrNoRows
+		}
+		if p.CheckInAt != nil && p.Status == model.StatusPendaftaranTerdaftar {
+			return p, ErrSudahCheckIn
+		}
+		return p, ErrTiketTidakBerlaku
+	}
+	return p, nil
+}
+
+// GetPetugas akun panitia check-in acara
+func (r *acaraRepository) GetPetugas(id int) ([]model.User, error) {
+	rows, err := r.db.Query(`SELECT u.id, u.username, u.email, u.alumni_id, u.role_id, u.is_active, u.created_at, u.perusahaan_id
+		FROM acara_petugas ap JOIN users u ON u.id = ap.user_id
+		WHERE ap.acara_id = $1 ORDER BY u.id`, id)
+	if err != nil {
+		log.Println("Error men-query petugas acara:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []model.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			log.Println("Error men-scan petugas acara:", err)
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *acaraRepository) TambahPetugas(id, userID int) error {
+	_, err := r.db.Exec(`INSERT INTO acara_petugas (acara_id, user_id, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`, id, userID, time.Now())
+	if err != nil {
+		log.Println("Error menambah petugas acara:", err)
+	}
+	return err
+}
+
+func (r *acaraRepository) HapusPetugas(id, userID int) error {
+	result, err := r.db.Exec(`DELETE FROM acara_petugas WHERE acara_id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		log.Println("Error menghapus petugas acara:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,617 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hello-fiber/app/model"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrMergeDianonimkan salah satu alumni pada penggabungan sudah dianonimkan sehingga snapshot-nya tidak lagi
+// memuat data asli dan penggabungan tidak bisa dibatalkan
+var ErrMergeDianonimkan = errors.New("alumni pada penggabungan ini sudah dianonimkan")
+
+type AlumniMergeRepository interface {
+	FindDuplicates(minSkor float64, limit int) ([]model.DuplikatAlumni, error)
+	Abaikan(alumniIDA, alumniIDB, actorID int) error
+	Merge(survivorID, duplikatID int, catatan string, actorID int) (model.AlumniMerge, error)
+	GetAll(limit, offset int) ([]model.AlumniMerge, error)
+	GetByID(id int) (model.AlumniMerge, error)
+	Undo(id, actorID int) (model.AlumniMerge, error)
+}
+
+type alumniMergeRepository struct {
+	db *sql.DB
+}
+
+func NewAlumniMergeRepository(db *sql.DB) AlumniMergeRepository {
+	return &alumniMergeRepository{db: db}
+}
+
+// Bobot kriteria duplikat; skor total dibatasi 1
+const (
+	bobotNIMSama     = 0.6
+	bobotEmailSama   = 0.5
+	bobotTeleponSama = 0.4
+	bobotNamaMirip   = 0.5 // dikali similarity nama
+	minNamaMirip     = 0.3
+)
+
+// skorDuplikat menghitung skor pasangan alumni dari kriteria yang cocok
+func skorDuplikat(nimSama, emailSama, teleponSama bool, namaSkor float64) (float64, []string) {
+	skor := 0.0
+	alasan := []string{}
+	if nimSama {
+		skor += bobotNIMSama
+		alasan = append(alasan, "nim_sama")
+	}
+	if emailSama {
+		skor += bobotEmailSama
+		alasan = append(alasan, "email_sama")
+	}
+	if teleponSama {
+		skor += bobotTeleponSama
+		alasan = append(alasan, "telepon_sama")
+	}
+	if namaSkor >= minNamaMirip {
+		skor += bobotNamaMirip * namaSkor
+		alasan = append(alasan, fmt.Sprintf("nama_mirip (%.2f)", namaSkor))
+	}
+	if skor > 1 {
+		skor = 1
+	}
+	return roundPercent(skor), alasan
+}
+
+// alumniNormal kolom alumni aktif yang sudah dinormalisasi untuk dibandingkan. Email dan telepon terenkripsi,
+// jadi dibandingkan lewat blind index (lihat normalisasiEmail dan normalisasiTelepon).
+const alumniNormal = `SELECT id, lower(trim(nim)) AS nim_n, COALESCE(email_bidx, '') AS email_n,
+		COALESCE(telepon_bidx, '') AS telp_n, lower(nama) AS nama_n
+	FROM alumni WHERE is_delete IS DISTINCT FROM 'hapus'`
+
+// kriteriaDuplikat kolom kriteria untuk pasangan x, y dari alumniNormal
+const kriteriaDuplikat = `x.nim_n <> '' AND x.nim_n = y.nim_n,
+		x.email_n <> '' AND x.email_n = y.email_n,
+		x.telp_n <> '' AND x.telp_n = y.telp_n,
+		similarity(x.nama_n, y.nama_n)`
+
+func (r *alumniMergeRepository) FindDuplicates(minSkor float64, limit int) ([]model.DuplikatAlumni, error) {
+	rows, err := r.db.Query(`WITH a AS (` + alumniNormal + `)
+		SELECT x.id, y.id, ` + kriteriaDuplikat + `
+		FROM a x JOIN a y ON x.id < y.id
+		 AND ((x.nim_n <> '' AND x.nim_n = y.nim_n)
+		   OR (x.email_n <> '' AND x.email_n = y.email_n)
+		   OR (x.telp_n <> '' AND x.telp_n = y.telp_n)
+		   OR x.nama_n % y.nama_n)
+		WHERE NOT EXISTS (
+			SELECT 1 FROM alumni_duplikat_diabaikan d WHERE d.alumni_id_a = x.id AND d.alumni_id_b = y.id)`)
+	if err != nil {
+		log.Println("Error mencari alumni duplikat:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	type pasangan struct {
+		a, b   int
+		skor   float64
+		alasan []string
+	}
+	list := []pasangan{}
+	for rows.Next() {
+		var p pasangan
+		var nimSama, emailSama, teleponSama bool
+		var namaSkor float64
+		if err := rows.Scan(&p.a, &p.b, &nimSama, &emailSama, &teleponSama, &namaSkor); err != nil {
+			log.Println("Error men-scan alumni duplikat:", err)
+			return nil, err
+		}
+		p.skor, p.alasan = skorDuplikat(nimSama, emailSama, teleponSama, namaSkor)
+		if p.skor >= minSkor {
+			list = append(list, p)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].skor != list[j].skor {
+			return list[i].skor > list[j].skor
+		}
+		return list[i].a < list[j].a
+	})
+	if len(list) > limit {
+		list = list[:limit]
+	}
+
+	ids := []int{}
+	for _, p := range list {
+		ids = append(ids, p.a, p.b)
+	}
+	alumniByID, err := r.getAlumniByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []model.DuplikatAlumni{}
+	for _, p := range list {
+		result = append(result, model.DuplikatAlumni{
+			Alumni:   alumniByID[p.a],
+			Duplikat: alumniByID[p.b],
+			Skor:     p.skor,
+			Alasan:   p.alasan,
+		})
+	}
+	return result, nil
+}
+
+func (r *alumniMergeRepository) getAlumniByIDs(ids []int) (map[int]model.Alumni, error) {
+	result := map[int]model.Alumni{}
+	if len(ids) == 0 {
+		return result, nil
+	}
+	rows, err := r.db.Query(`SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at
+		FROM alumni WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		log.Println("Error mengambil alumni duplikat:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a model.Alumni
+		if err := rows.Scan(
+			&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus,
+			&a.Email, &a.NoTelepon, &a.Alamat, &a.CreatedAt, &a.UpdatedAt,
+		); err != nil {
+			log.Println("Error men-scan alumni duplikat:", err)
+			return nil, err
+		}
+		if err := decryptAlumniPII(r.db, &a.Email, &a.NoTelepon, &a.Alamat); err != nil {
+			log.Println("Error membuka data alumni duplikat:", err)
+			return nil, err
+		}
+		result[a.ID] = a
+	}
+	return result, rows.Err()
+}
+
+func (r *alumniMergeRepository) Abaikan(alumniIDA, alumniIDB, actorID int) error {
+	if alumniIDA > alumniIDB {
+		alumniIDA, alumniIDB = alumniIDB, alumniIDA
+	}
+	_, err := r.db.Exec(`INSERT INTO alumni_duplikat_diabaikan (alumni_id_a, alumni_id_b, diabaikan_oleh)
+		VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, alumniIDA, alumniIDB, nullableInt(actorID))
+	if err != nil {
+		log.Println("Error mengabaikan pasangan duplikat:", err)
+	}
+	return err
+}
+
+// collectIDs menjalankan query yang mengembalikan satu kolom id
+func collectIDs(db DBTX, query string, args ...interface{}) ([]int, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// lockAlumni mengunci alumni aktif untuk penggabungan
+func lockAlumni(tx *sql.Tx, id int) (model.Alumni, error) {
+	var a model.Alumni
+	err := tx.QueryRow(`SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at
+		FROM alumni WHERE id = $1 AND is_delete IS DISTINCT FROM 'hapus' FOR UPDATE`, id).Scan(
+		&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus,
+		&a.Email, &a.NoTelepon, &a.Alamat, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if err == nil {
+		err = decryptAlumniPII(tx, &a.Email, &a.NoTelepon, &a.Alamat)
+	}
+	return a, err
+}
+
+// isiFieldKosong field survivor yang kosong dan diisi dari duplikat saat penggabungan
+func isiFieldKosong(survivor, duplikat model.Alumni) map[string]interface{} {
+	diisi := map[string]interface{}{}
+	if strings.TrimSpace(survivor.Jurusan) == "" && duplikat.Jurusan != "" {
+		diisi["jurusan"] = duplikat.Jurusan
+	}
+	if survivor.Angkatan == 0 && duplikat.Angkatan != 0 {
+		diisi["angkatan"] = duplikat.Angkatan
+	}
+	if survivor.TahunLulus == 0 && duplikat.TahunLulus != 0 {
+		diisi["tahun_lulus"] = duplikat.TahunLulus
+	}
+	if strings.TrimSpace(survivor.NoTelepon) == "" && duplikat.NoTelepon != "" {
+		diisi["no_telepon"] = duplikat.NoTelepon
+	}
+	if strings.TrimSpace(survivor.Alamat) == "" && duplikat.Alamat != "" {
+		diisi["alamat"] = duplikat.Alamat
+	}
+	return diisi
+}
+
+// nilaiFieldAlumni nilai kolom alumni untuk field yang bisa diisi saat penggabungan
+func nilaiFieldAlumni(a model.Alumni, field string) interface{} {
+	switch field {
+	case "jurusan":
+		return a.Jurusan
+	case "angkatan":
+		return a.Angkatan
+	case "tahun_lulus":
+		return a.TahunLulus
+	case "email":
+		return a.Email
+	case "no_telepon":
+		return a.NoTelepon
+	case "alamat":
+		return a.Alamat
+	}
+	return nil
+}
+
+// mergeTarget tabel yang baris alumni_id-nya dipindahkan dari duplikat ke survivor saat merge. pilih
+// mengambil nilai kolom kunci baris duplikat ($1) yang boleh dipindah; $2 berisi survivor untuk menyaring
+// baris yang bentrok dengan milik survivor sehingga tetap milik duplikat. Kunci bersama alumni_id harus
+// unik per baris. ids menunjuk field snapshot untuk undo.
+type mergeTarget struct {
+	tabel string
+	kunci string
+	pilih string
+	ids   func(s *model.AlumniMergeSnapshot) *[]int
+}
+
+var mergeTargets = []mergeTarget{
+	{"pekerjaan_alumni", "id", `SELECT id FROM pekerjaan_alumni WHERE alumni_id = $1`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.PekerjaanIDs }},
+	{"users", "id", `SELECT id FROM users WHERE alumni_id = $1`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.UserIDs }},
+	{"pengajuan_pekerjaan", "id", `SELECT id FROM pengajuan_pekerjaan WHERE alumni_id = $1`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.PengajuanIDs }},
+	{"kuesioner_respon", "id", `SELECT r.id FROM kuesioner_respon r WHERE r.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM kuesioner_respon s WHERE s.alumni_id = $2 AND s.kuesioner_id = r.kuesioner_id
+			  AND s.periode = r.periode AND s.versi = r.versi)`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.ResponIDs }},
+	{"pendidikan_alumni", "id", `SELECT d.id FROM pendidikan_alumni d WHERE d.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM pendidikan_alumni s WHERE s.alumni_id = $2 AND s.jenjang = d.jenjang
+			  AND lower(s.program_studi) = lower(d.program_studi))`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.PendidikanIDs }},
+	{"keahlian_alumni", "id", `SELECT d.id FROM keahlian_alumni d WHERE d.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM keahlian_alumni s WHERE s.alumni_id = $2 AND s.jenis = d.jenis
+			  AND lower(s.nama) = lower(d.nama) AND lower(s.penerbit) = lower(d.penerbit))`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.KeahlianIDs }},
+	{"alumni_tag", "id", `SELECT d.id FROM alumni_tag d WHERE d.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM alumni_tag s WHERE s.alumni_id = $2 AND s.referensi_id = d.referensi_id)`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.TagIDs }},
+	{"lamaran_lowongan", "id", `SELECT d.id FROM lamaran_lowongan d WHERE d.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM lamaran_lowongan s WHERE s.alumni_id = $2 AND s.lowongan_id = d.lowongan_id)`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.LamaranIDs }},
+	{"pendaftaran_acara", "id", `SELECT d.id FROM pendaftaran_acara d WHERE d.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM pendaftaran_acara s WHERE s.alumni_id = $2 AND s.acara_id = d.acara_id)`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.PendaftaranIDs }},
+	// profil mentor hanya dipindah jika survivor belum menjadi mentor dan tidak menjadi mentee profil itu
+	{"mentor", "id", `SELECT d.id FROM mentor d WHERE d.alumni_id = $1
+			AND NOT EXISTS (SELECT 1 FROM mentor s WHERE s.alumni_id = $2)
+			AND NOT EXISTS (SELECT 1 FROM permintaan_mentor p WHERE p.mentor_id = d.id AND p.alumni_id = $2)`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.MentorIDs }},
+	{"permintaan_mentor", "id", `SELECT d.id FROM permintaan_mentor d WHERE d.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM permintaan_mentor s WHERE s.alumni_id = $2 AND s.mentor_id = d.mentor_id)
+			AND d.mentor_id NOT IN (SELECT id FROM mentor WHERE alumni_id = $2)`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.PermintaanIDs }},
+	// foto profil duplikat tetap milik duplikat jika survivor sudah punya foto
+	{"berkas", "id", `SELECT d.id FROM berkas d WHERE d.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM berkas s WHERE s.alumni_id = $2 AND s.jenis = d.jenis)`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.BerkasIDs }},
+	// bookmark lowongan tidak punya id; dikunci lowongan_id karena primary key-nya (lowongan_id, alumni_id)
+	{"lowongan_simpan", "lowongan_id", `SELECT d.lowongan_id FROM lowongan_simpan d WHERE d.alumni_id = $1 AND NOT EXISTS (
+			SELECT 1 FROM lowongan_simpan s WHERE s.alumni_id = $2 AND s.lowongan_id = d.lowongan_id)`,
+		func(s *model.AlumniMergeSnapshot) *[]int { return &s.SimpanIDs }},
+}
+
+// Merge memindahkan semua baris yang tercantum di mergeTargets dari duplikat ke survivor, mengisi field
+// survivor yang kosong, lalu menandai duplikat digabung_ke survivor. Semua dalam satu transaksi
+// dan dicatat di alumni_merge untuk undo.
+func (r *alumniMergeRepository) Merge(survivorID, duplikatID int, catatan string, actorID int) (model.AlumniMerge, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi merge alumni:", err)
+		return model.AlumniMerge{}, err
+	}
+	defer tx.Rollback()
+
+	// kunci dengan urutan id agar dua merge bersamaan tidak deadlock
+	pertama, kedua := survivorID, duplikatID
+	if pertama > kedua {
+		pertama, kedua = kedua, pertama
+	}
+	terkunci := map[int]model.Alumni{}
+	for _, id := range []int{pertama, kedua} {
+		a, err := lockAlumni(tx, id)
+		if err != nil {
+			if err != sql.ErrNoRows {
+				log.Println("Error mengunci alumni untuk merge:", err)
+			}
+			return model.AlumniMerge{}, err
+		}
+		terkunci[id] = a
+	}
+	survivor, duplikat := terkunci[survivorID], terkunci[duplikatID]
+
+	var nimSama, emailSama, teleponSama bool
+	var namaSkor float64
+	err = tx.QueryRow(`WITH a AS (`+alumniNormal+`)
+		SELECT `+kriteriaDuplikat+` FROM a x, a y WHERE x.id = $1 AND y.id = $2`,
+		survivorID, duplikatID,
+	).Scan(&nimSama, &emailSama, &teleponSama, &namaSkor)
+	if err != nil {
+		log.Println("Error menghitung skor merge alumni:", err)
+		return model.AlumniMerge{}, err
+	}
+	skor, _ := skorDuplikat(nimSama, emailSama, teleponSama, namaSkor)
+
+	snapshot := model.AlumniMergeSnapshot{SurvivorSebelum: survivor, Duplikat: duplikat, FieldDiisi: []string{}}
+	for _, t := range mergeTargets {
+		args := []interface{}{duplikatID}
+		if strings.Contains(t.pilih, "$2") {
+			args = append(args, survivorID)
+		}
+		ids := t.ids(&snapshot)
+		if *ids, err = collectIDs(tx, t.pilih, args...); err != nil {
+			log.Println("Error mengambil data milik alumni duplikat:", err)
+			return model.AlumniMerge{}, err
+		}
+		if len(*ids) == 0 {
+			continue
+		}
+		// survivor sudah punya pendidikan utama sendiri
+		set := "alumni_id = $1"
+		if t.tabel == "pendidikan_alumni" {
+			set += ", is_utama = FALSE"
+		}
+		_, err = tx.Exec(`UPDATE `+t.tabel+` SET `+set+` WHERE `+t.kunci+` = ANY($2) AND alumni_id = $3`,
+			survivorID, pq.Array(*ids), duplikatID)
+		if err != nil {
+			log.Println("Error memindahkan", t.tabel, "ke survivor:", err)
+			return model.AlumniMerge{}, err
+		}
+	}
+
+	// duplikat dinonaktifkan sebelum field survivor diisi karena email_bidx unik di antara alumni aktif
+	now := time.Now()
+	_, err = tx.Exec(`UPDATE alumni SET is_delete = 'hapus', digabung_ke = $1, updated_at = $2 WHERE id = $3`, survivorID, now, duplikatID)
+	if err != nil {
+		log.Println("Error menandai alumni duplikat:", err)
+		return model.AlumniMerge{}, err
+	}
+	for field, nilai := range isiFieldKosong(survivor, duplikat) {
+		if err := setAlumniField(tx, survivorID, field, nilai, now); err != nil {
+			log.Println("Error mengisi field survivor:", err)
+			return model.AlumniMerge{}, err
+		}
+		snapshot.FieldDiisi = append(snapshot.FieldDiisi, field)
+	}
+	sort.Strings(snapshot.FieldDiisi)
+	if err := sinkronPendidikanUtama(tx, survivorID, ""); err != nil {
+		log.Println("Error menyinkronkan pendidikan utama survivor:", err)
+		return model.AlumniMerge{}, err
+	}
+
+	data, err := marshalSnapshotMerge(tx, snapshot)
+	if err != nil {
+		return model.AlumniMerge{}, err
+	}
+	var id int
+	err = tx.QueryRow(`INSERT INTO alumni_merge (survivor_id, duplikat_id, skor, catatan, snapshot, digabung_oleh, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		survivorID, duplikatID, skor, catatan, data, nullableInt(actorID), now,
+	).Scan(&id)
+	if err != nil {
+		log.Println("Error menyimpan riwayat merge alumni:", err)
+		return model.AlumniMerge{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit merge alumni:", err)
+		return model.AlumniMerge{}, err
+	}
+	return r.GetByID(id)
+}
+
+// marshalSnapshotMerge menyimpan snapshot dengan PII alumni terenkripsi seperti di tabel alumni
+func marshalSnapshotMerge(db DBTX, s model.AlumniMergeSnapshot) ([]byte, error) {
+	for _, a := range []*model.Alumni{&s.SurvivorSebelum, &s.Duplikat} {
+		p, err := encryptAlumniPII(db, a.Email, a.NoTelepon, a.Alamat)
+		if err != nil {
+			return nil, err
+		}
+		a.Email, a.NoTelepon, a.Alamat = p.email, p.noTelepon, p.alamat
+	}
+	return json.Marshal(s)
+}
+
+// bukaSnapshotMerge membuka PII alumni di snapshot hasil scan
+func bukaSnapshotMerge(db DBTX, m *model.AlumniMerge) error {
+	for _, a := range []*model.Alumni{&m.Snapshot.SurvivorSebelum, &m.Snapshot.Duplikat} {
+		if err := decryptAlumniPII(db, &a.Email, &a.NoTelepon, &a.Alamat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const alumniMergeColumns = `id, survivor_id, duplikat_id, skor, catatan, snapshot, COALESCE(digabung_oleh, 0),
+		created_at, dibatalkan_at, COALESCE(dibatalkan_oleh, 0)`
+
+func scanAlumniMerge(scanner interface{ Scan(...interface{}) error }) (model.AlumniMerge, error) {
+	var m model.AlumniMerge
+	var data []byte
+	var dibatalkanAt sql.NullTime
+	err := scanner.Scan(
+		&m.ID, &m.SurvivorID, &m.DuplikatID, &m.Skor, &m.Catatan, &data, &m.DigabungOleh,
+		&m.CreatedAt, &dibatalkanAt, &m.DibatalkanOleh,
+	)
+	if err != nil {
+		return m, err
+	}
+	if dibatalkanAt.Valid {
+		m.DibatalkanAt = &dibatalkanAt.Time
+	}
+	err = json.Unmarshal(data, &m.Snapshot)
+	return m, err
+}
+
+func (r *alumniMergeRepository) GetAll(limit, offset int) ([]model.AlumniMerge, error) {
+	rows, err := r.db.Query(`SELECT `+alumniMergeColumns+` FROM alumni_merge
+		ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		log.Println("Error men-query riwayat merge alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.AlumniMerge{}
+	for rows.Next() {
+		m, err := scanAlumniMerge(rows)
+		if err == nil {
+			err = bukaSnapshotMerge(r.db, &m)
+		}
+		if err != nil {
+			log.Println("Error men-scan riwayat merge alumni:", err)
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, nil
+}
+
+func (r *alumniMergeRepository) GetByID(id int) (model.AlumniMerge, error) {
+	m, err := scanAlumniMerge(r.db.QueryRow(`SELECT `+alumniMergeColumns+` FROM alumni_merge WHERE id = $1`, id))
+	if err == nil {
+		err = bukaSnapshotMerge(r.db, &m)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil riwayat merge alumni:", err)
+	}
+	return m, err
+}
+
+// Undo mengembalikan duplikat menjadi alumni aktif, memindahkan kembali baris yang tercatat di snapshot,
+// dan mengosongkan lagi field survivor yang diisi saat merge selama nilainya belum diubah.
+// sql.ErrNoRows jika merge tidak ada atau sudah dibatalkan, ErrMergeDianonimkan jika salah satu alumninya
+// sudah dianonimkan.
+func (r *alumniMergeRepository) Undo(id, actorID int) (model.AlumniMerge, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi undo merge alumni:", err)
+		return model.AlumniMerge{}, err
+	}
+	defer tx.Rollback()
+
+	m, err := scanAlumniMerge(tx.QueryRow(`SELECT `+alumniMergeColumns+` FROM alumni_merge
+		WHERE id = $1 AND dibatalkan_at IS NULL FOR UPDATE`, id))
+	if err == nil {
+		err = bukaSnapshotMerge(tx, &m)
+	}
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error mengambil merge alumni untuk undo:", err)
+		}
+		return model.AlumniMerge{}, err
+	}
+
+	var dianonimkan bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM alumni WHERE id IN ($1, $2) AND dianonimkan_at IS NOT NULL)`,
+		m.SurvivorID, m.DuplikatID).Scan(&dianonimkan)
+	if err != nil {
+		log.Println("Error memeriksa anonimisasi alumni untuk undo merge:", err)
+		return model.AlumniMerge{}, err
+	}
+	if dianonimkan {
+		return model.AlumniMerge{}, ErrMergeDianonimkan
+	}
+
+	now := time.Now()
+	for _, t := range mergeTargets {
+		ids := t.ids(&m.Snapshot)
+		if len(*ids) == 0 {
+			continue
+		}
+		_, err := tx.Exec(`UPDATE `+t.tabel+` SET alumni_id = $1 WHERE `+t.kunci+` = ANY($2) AND alumni_id = $3`,
+			m.DuplikatID, pq.Array(*ids), m.SurvivorID)
+		if err != nil {
+			log.Println("Error mengembalikan", t.tabel, "ke alumni duplikat:", err)
+			return model.AlumniMerge{}, err
+		}
+	}
+
+	// nilai dibandingkan setelah dibuka karena kolom PII terenkripsi dengan nonce acak
+	survivor, err := lockAlumni(tx, m.SurvivorID)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengunci survivor untuk undo merge:", err)
+		return model.AlumniMerge{}, err
+	}
+	for _, field := range m.Snapshot.FieldDiisi {
+		if err == sql.ErrNoRows {
+			break
+		}
+		if _, ok := isiFieldKosong(model.Alumni{}, m.Snapshot.Duplikat)[field]; !ok {
+			continue
+		}
+		if nilaiFieldAlumni(survivor, field) != nilaiFieldAlumni(m.Snapshot.Duplikat, field) {
+			continue
+		}
+		if err := setAlumniField(tx, m.SurvivorID, field, nilaiFieldAlumni(m.Snapshot.SurvivorSebelum, field), now); err != nil {
+			log.Println("Error mengembalikan field survivor:", err)
+			return model.AlumniMerge{}, err
+		}
+	}
+
+	// duplikat diaktifkan setelah field survivor dikembalikan karena email_bidx unik di antara alumni aktif
+	result, err := tx.Exec(`UPDATE alumni SET is_delete = 'tidak', digabung_ke = NULL, updated_at = $1
+		WHERE id = $2 AND digabung_ke = $3`, now, m.DuplikatID, m.SurvivorID)
+	if err != nil {
+		log.Println("Error mengaktifkan kembali alumni duplikat:", err)
+		return model.AlumniMerge{}, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return model.AlumniMerge{}, sql.ErrNoRows
+	}
+
+	// pendidikan utama duplikat yang ikut dipindahkan kehilangan tanda utamanya
+	for _, alumniID := range []int{m.SurvivorID, m.DuplikatID} {
+		if err := sinkronPendidikanUtama(tx, alumniID, ""); err != nil {
+			log.Println("Error menyinkronkan pendidikan utama setelah undo merge:", err)
+			return model.AlumniMerge{}, err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE alumni_merge SET dibatalkan_at = $1, dibatalkan_oleh = $2 WHERE id = $3`,
+		now, nullableInt(actorID), id); err != nil {
+		log.Println("Error menandai merge alumni dibatalkan:", err)
+		return model.AlumniMerge{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit undo merge alumni:", err)
+		return model.AlumniMerge{}, err
+	}
+	return r.GetByID(id)
+}
@@ -6,6 +6,8 @@ import (
 	"log"
 	"time"
 	"fmt"
+
+	"github.com/lib/pq"
 )
 
 type AlumniRepository interface {
@@ -13,21 +15,49 @@ type AlumniRepository interface {
 	GetByID(id int) (model.Alumni, error)
 	Create(alumni model.CreateAlumniRequest) (model.Alumni, error)
 	Update(id int, alumni model.UpdateAlumniRequest) (model.Alumni, error)
-	Delete(id int) error
+	Delete(id int, actorID int) error
+	GetTrashed() ([]model.Alumni, error)
+	RestoreIfTrashed(id int) (model.Alumni, error)
+	HardDeleteIfTrashed(id int) ([]model.Berkas, error)
+	FindByEmail(email string, kecualiID int) (model.Alumni, error)
+}
+
+// AlumniRepositoryTx bagian AlumniRepository yang dapat berjalan di dalam transaksi milik pemanggil
+type AlumniRepositoryTx interface {
+	GetByID(id int) (model.Alumni, error)
+	Create(alumni model.CreateAlumniRequest) (model.Alumni, error)
+	Update(id int, alumni model.UpdateAlumniRequest) (model.Alumni, error)
+	FindByEmail(email string, kecualiID int) (model.Alumni, error)
 }
 
 type alumniRepository struct {
 	db *sql.DB
+	tx *sql.Tx
 }
 
 func NewAlumniRepository(db *sql.DB) AlumniRepository {
 	return &alumniRepository{db: db}
 }
 
+// NewAlumniRepositoryTx membuat repository di dalam transaksi tx. Method lain AlumniRepository membuka
+// transaksinya sendiri sehingga tidak tersedia di sini.
+func NewAlumniRepositoryTx(tx *sql.Tx) AlumniRepositoryTx {
+	return &alumniRepository{tx: tx}
+}
+
+// conn koneksi untuk query tunggal: transaksi jika ada, selain itu db
+func (r *alumniRepository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
 func (r *alumniRepository) GetAll() ([]model.Alumni, error) {
 	sqlStatement := `
 		SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at 
 		FROM alumni 
+		WHERE is_delete IS DISTINCT FROM 'hapus'
 		ORDER BY created_at DESC`
 	
 	rows, err := r.db.Query(sqlStatement)
@@ -45,6 +75,9 @@ func (r *alumniRepository) GetAll() ([]model.Alumni, error) {
 			&alumni.Angkatan, &alumni.TahunLulus, &alumni.Email, &alumni.NoTelepon,
 			&alumni.Alamat, &alumni.CreatedAt, &alumni.UpdatedAt,
 		)
+		if err == nil {
+			err = decryptAlumniPII(r.db, &alumni.Email, &alumni.NoTelepon, &alumni.Alamat)
+		}
 		if err != nil {
 			log.Println("Error scanning alumni:", err)
 			return nil, err
@@ -58,14 +91,17 @@ func (r *alumniRepository) GetByID(id int) (model.Alumni, error) {
 	sqlStatement := `
 		SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at 
 		FROM alumni 
-		WHERE id = $1`
+		WHERE id = $1 AND is_delete IS DISTINCT FROM 'hapus'`
 	
 	var alumni model.Alumni
-	err := r.db.QueryRow(sqlStatement, id).Scan(
+	err := r.conn().QueryRow(sqlStatement, id).Scan(
 		&alumni.ID, &alumni.NIM, &alumni.Nama, &alumni.Jurusan,
 		&alumni.Angkatan, &alumni.TahunLulus, &alumni.Email, &alumni.NoTelepon,
 		&alumni.Alamat, &alumni.CreatedAt, &alumni.UpdatedAt,
 	)
+	if err == nil {
+		err = decryptAlumniPII(r.conn(), &alumni.Email, &alumni.NoTelepon, &alumni.Alamat)
+	}
 	if err != nil {
 		log.Println("Error finding alumni by ID:", err)
 		return model.Alumni{}, err
@@ -75,21 +111,30 @@ func (r *alumniRepository) GetByID(id int) (model.Alumni, error) {
 
 func (r *alumniRepository) Create(req model.CreateAlumniRequest) (model.Alumni, error) {
 	sqlStatement := `
-		INSERT INTO alumni (nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
+		INSERT INTO alumni (nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, email_bidx, telepon_bidx, created_at, updated_at) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) 
 		RETURNING id, created_at, updated_at`
 	
 	var alumni model.Alumni
+	pii, err := encryptAlumniPII(r.conn(), req.Email, req.NoTelepon, req.Alamat)
+	if err != nil {
+		log.Println("Error mengenkripsi data alumni:", err)
+		return model.Alumni{}, err
+	}
 	now := time.Now()
-	err := r.db.QueryRow(
+	err = r.conn().QueryRow(
 		sqlStatement, req.NIM, req.Nama, req.Jurusan, req.Angkatan, 
-		req.TahunLulus, req.Email, req.NoTelepon, req.Alamat, now, now,
+		req.TahunLulus, pii.email, pii.noTelepon, pii.alamat, pii.emailBidx, pii.teleponBidx, now, now,
 	).Scan(&alumni.ID, &alumni.CreatedAt, &alumni.UpdatedAt)
 	
 	if err != nil {
 		log.Println("Error inserting alumni:", err)
 		return model.Alumni{}, err
 	}
+	if err := sinkronPendidikanUtama(r.conn(), alumni.ID, req.Jenjang); err != nil {
+		log.Println("Error menyimpan pendidikan utama alumni:", err)
+		return model.Alumni{}, err
+	}
 
 	alumni.NIM = req.NIM
 	alumni.Nama = req.Nama
@@ -106,13 +151,19 @@ func (r *alumniRepository) Create(req model.CreateAlumniRequest) (model.Alumni,
 func (r *alumniRepository) Update(id int, req model.UpdateAlumniRequest) (model.Alumni, error) {
 	sqlStatement := `
 		UPDATE alumni 
-		SET nama = $1, jurusan = $2, angkatan = $3, tahun_lulus = $4, email = $5, no_telepon = $6, alamat = $7, updated_at = $8 
-		WHERE id = $9`
+		SET nama = $1, jurusan = $2, angkatan = $3, tahun_lulus = $4, email = $5, no_telepon = $6, alamat = $7, updated_at = $8,
+		    email_bidx = $10, telepon_bidx = $11 
+		WHERE id = $9 AND is_delete IS DISTINCT FROM 'hapus'`
 	
+	pii, err := encryptAlumniPII(r.conn(), req.Email, req.NoTelepon, req.Alamat)
+	if err != nil {
+		log.Println("Error mengenkripsi data alumni:", err)
+		return model.Alumni{}, err
+	}
 	now := time.Now()
-	result, err := r.db.Exec(
+	result, err := r.conn().Exec(
 		sqlStatement, req.Nama, req.Jurusan, req.Angkatan, req.TahunLulus,
-		req.Email, req.NoTelepon, req.Alamat, now, id,
+		pii.email, pii.noTelepon, pii.alamat, now, id, pii.emailBidx, pii.teleponBidx,
 	)
 	if err != nil {
 		log.Println("Error updating alumni:", err)
@@ -123,13 +174,27 @@ func (r *alumniRepository) Update(id int, req model.UpdateAlumniRequest) (model.
 	if rowsAffected == 0 {
 		return model.Alumni{}, sql.ErrNoRows
 	}
+	if err := sinkronPendidikanUtama(r.conn(), id, ""); err != nil {
+		log.Println("Error menyinkronkan pendidikan utama alumni:", err)
+		return model.Alumni{}, err
+	}
 
 	return r.GetByID(id)
 }
 
-func (r *alumniRepository) Delete(id int) error {
-	sqlStatement := `DELETE FROM alumni WHERE id = $1`
-	result, err := r.db.Exec(sqlStatement, id)
+// Delete memindahkan alumni ke trash (is_delete = 'hapus'), ikut men-trash pekerjaannya
+// dan menonaktifkan user yang terhubung
+func (r *alumniRepository) Delete(id int, actorID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi hapus alumni:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`UPDATE alumni SET is_delete = 'hapus', updated_at = $2
+		WHERE id = $1 AND is_delete IS DISTINCT FROM 'hapus'`, id, now)
 	if err != nil {
 		log.Println("Error deleting alumni:", err)
 		return err
@@ -139,14 +204,150 @@ func (r *alumniRepository) Delete(id int) error {
 	if rowsAffected == 0 {
 		return sql.ErrNoRows
 	}
+
+	_, err = tx.Exec(`UPDATE pekerjaan_alumni
+		SET is_delete = 'hapus', dihapus_via_alumni = TRUE, updated_at = $2, trashed_at = $2, trashed_by = $3
+		WHERE alumni_id = $1 AND is_delete IS DISTINCT FROM 'hapus'`, id, now, nullableInt(actorID))
+	if err != nil {
+		log.Println("Error menghapus pekerjaan milik alumni:", err)
+		return err
+	}
+
+	if _, err = tx.Exec(`UPDATE users SET is_active = FALSE WHERE alumni_id = $1`, id); err != nil {
+		log.Println("Error menonaktifkan user milik alumni:", err)
+		return err
+	}
 	
-	return nil
+	return tx.Commit()
 }
 
-func GetAlumniWithPagination(db *sql.DB, search, sortBy, order string, limit, offset int) ([]model.Alumni, error) {
+func (r *alumniRepository) GetTrashed() ([]model.Alumni, error) {
+	sqlStatement := `
+		SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at 
+		FROM alumni 
+		WHERE is_delete = 'hapus' AND digabung_ke IS NULL
+		ORDER BY updated_at DESC`
+
+	rows, err := r.db.Query(sqlStatement)
+	if err != nil {
+		log.Println("Error memanggil trash alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	alumniList := []model.Alumni{}
+	for rows.Next() {
+		var alumni model.Alumni
+		err := rows.Scan(
+			&alumni.ID, &alumni.NIM, &alumni.Nama, &alumni.Jurusan,
+			&alumni.Angkatan, &alumni.TahunLulus, &alumni.Email, &alumni.NoTelepon,
+			&alumni.Alamat, &alumni.CreatedAt, &alumni.UpdatedAt,
+		)
+		if err == nil {
+			err = decryptAlumniPII(r.db, &alumni.Email, &alumni.NoTelepon, &alumni.Alamat)
+		}
+		if err != nil {
+			log.Println("Error men-scan trash alumni:", err)
+			return nil, err
+		}
+		alumniList = append(alumniList, alumni)
+	}
+	return alumniList, nil
+}
+
+// RestoreIfTrashed mengembalikan alumni dari trash beserta pekerjaan yang ikut terhapus
+// dan mengaktifkan kembali user yang terhubung
+func (r *alumniRepository) RestoreIfTrashed(id int) (model.Alumni, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi restore alumni:", err)
+		return model.Alumni{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`UPDATE alumni SET is_delete = 'tidak', updated_at = $2
+		WHERE id = $1 AND is_delete = 'hapus' AND digabung_ke IS NULL`, id, now)
+	if err != nil {
+		log.Println("Error untuk mengembalikan alumni:", err)
+		return model.Alumni{}, err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return model.Alumni{}, sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`UPDATE pekerjaan_alumni
+		SET is_delete = 'tidak', dihapus_via_alumni = FALSE, updated_at = $2, trashed_at = NULL, trashed_by = NULL
+		WHERE alumni_id = $1 AND dihapus_via_alumni`, id, now)
+	if err != nil {
+		log.Println("Error mengembalikan pekerjaan milik alumni:", err)
+		return model.Alumni{}, err
+	}
+
+	if _, err = tx.Exec(`UPDATE users SET is_active = TRUE WHERE alumni_id = $1`, id); err != nil {
+		log.Println("Error mengaktifkan user milik alumni:", err)
+		return model.Alumni{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit restore alumni:", err)
+		return model.Alumni{}, err
+	}
+	return r.GetByID(id)
+}
+
+// HardDeleteIfTrashed menghapus permanen alumni yang sudah ada di trash beserta seluruh pekerjaannya.
+// User yang terhubung tetap disimpan dalam keadaan nonaktif tanpa alumni_id. Berkas yang ikut terhapus
+// dikembalikan agar object-nya bisa dihapus dari storage setelah commit.
+func (r *alumniRepository) HardDeleteIfTrashed(id int) ([]model.Berkas, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi hard delete alumni:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var trashedID int
+	err = tx.QueryRow(`SELECT id FROM alumni WHERE id = $1 AND is_delete = 'hapus' AND digabung_ke IS NULL FOR UPDATE`, id).Scan(&trashedID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error menemukan trash alumni:", err)
+		}
+		return nil, err
+	}
+
+	berkas, err := berkasMilikAlumni(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = tx.Exec(`DELETE FROM pekerjaan_alumni WHERE alumni_id = $1`, id); err != nil {
+		log.Println("Error hard deleting pekerjaan milik alumni:", err)
+		return nil, err
+	}
+	if _, err = tx.Exec(`UPDATE users SET alumni_id = NULL, is_active = FALSE WHERE alumni_id = $1`, id); err != nil {
+		log.Println("Error melepas user dari alumni:", err)
+		return nil, err
+	}
+	if _, err = tx.Exec(`DELETE FROM alumni WHERE id = $1`, id); err != nil {
+		log.Println("Error hard deleting alumni:", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit hard delete alumni:", err)
+		return nil, err
+	}
+	return berkas, nil
+}
+
+// GetAlumniWithPagination daftar alumni aktif; skillIDs (boleh kosong) membatasi ke alumni yang memiliki
+// semua skill tersebut
+func GetAlumniWithPagination(db *sql.DB, search string, skillIDs []int, sortBy, order string, limit, offset int) ([]model.Alumni, error) {
+	// email terenkripsi sehingga tidak bisa dipakai untuk mengurutkan
 	validSortColumns := map[string]bool{
 		"id": true, "nim": true, "nama": true, "jurusan": true, 
-		"angkatan": true, "tahun_lulus": true, "email": true, "created_at": true,
+		"angkatan": true, "tahun_lulus": true, "created_at": true,
 	}
 	if !validSortColumns[sortBy] {
 		sortBy = "id"
@@ -155,12 +356,22 @@ func GetAlumniWithPagination(db *sql.DB, search, sortBy, order string, limit, of
 	query := fmt.Sprintf(`
 		SELECT id, nim, nama, jurusan, angkatan, tahun_lulus, email, no_telepon, alamat, created_at, updated_at
 		FROM alumni
-		WHERE nama ILIKE $1 OR nim ILIKE $1 OR jurusan ILIKE $1 OR email ILIKE $1
+		WHERE is_delete IS DISTINCT FROM 'hapus'
+		  AND (nama ILIKE $1 OR nim ILIKE $1 OR jurusan ILIKE $1 OR email_bidx = $4)
+		  AND %s
 		ORDER BY %s %s
 		LIMIT $2 OFFSET $3
-	`, sortBy, order)
+	`, kondisiSkillAlumni("$5"), sortBy, order)
 
-	rows, err := db.Query(query, "%"+search+"%", limit, offset)
+	// email hanya bisa dicari dengan nilai lengkap lewat blind index
+	emailBidx, err := blindIndexPII(db, kolomPIIEmail, normalisasiEmail(search))
+	if err != nil {
+		return nil, err
+	}
+	if skillIDs == nil {
+		skillIDs = []int{}
+	}
+	rows, err := db.Query(query, "%"+search+"%", limit, offset, emailBidx, pq.Array(skillIDs))
 	if err != nil {
 		log.Println("Query error:", err)
 		return nil, err
@@ -174,6 +385,9 @@ func GetAlumniWithPagination(db *sql.DB, search, sortBy, order string, limit, of
 			&a.ID, &a.NIM, &a.Nama, &a.Jurusan, &a.Angkatan, &a.TahunLulus,
 			&a.Email, &a.NoTelepon, &a.Alamat, &a.CreatedAt, &a.UpdatedAt,
 		)
+		if err == nil {
+			err = decryptAlumniPII(db, &a.Email, &a.NoTelepon, &a.Alamat)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -183,12 +397,42 @@ func GetAlumniWithPagination(db *sql.DB, search, sortBy, order string, limit, of
 	return alumni, nil
 }
 
-func CountAlumni(db *sql.DB, search string) (int, error) {
+func CountAlumni(db *sql.DB, search string, skillIDs []int) (int, error) {
 	var total int
-	countQuery := `SELECT COUNT(*) FROM alumni WHERE nama ILIKE $1 OR nim ILIKE $1 OR jurusan ILIKE $1 OR email ILIKE $1`
-	err := db.QueryRow(countQuery, "%"+search+"%").Scan(&total)
+	countQuery := `SELECT COUNT(*) FROM alumni WHERE is_delete IS DISTINCT FROM 'hapus' AND (nama ILIKE $1 OR nim ILIKE $1 OR jurusan ILIKE $1 OR email_bidx = $2) AND ` + kondisiSkillAlumni("$3")
+	emailBidx, err := blindIndexPII(db, kolomPIIEmail, normalisasiEmail(search))
+	if err != nil {
+		return 0, err
+	}
+	if skillIDs == nil {
+		skillIDs = []int{}
+	}
+	err = db.QueryRow(countQuery, "%"+search+"%", emailBidx, pq.Array(skillIDs)).Scan(&total)
 	if err != nil && err != sql.ErrNoRows {
 		return 0, err
 	}
 	return total, nil
 }
+
+// FindByEmail mencari alumni aktif lain dengan email yang sama lewat blind index (tidak peka huruf besar)
+func (r *alumniRepository) FindByEmail(email string, kecualiID int) (model.Alumni, error) {
+	emailBidx, err := blindIndexPII(r.conn(), kolomPIIEmail, normalisasiEmail(email))
+	if err != nil {
+		return model.Alumni{}, err
+	}
+	if emailBidx == nil {
+		return model.Alumni{}, sql.ErrNoRows
+	}
+
+	var id int
+	err = r.conn().QueryRow(`SELECT id FROM alumni
+		WHERE email_bidx = $1 AND id <> $2 AND is_delete IS DISTINCT FROM 'hapus'
+		ORDER BY id LIMIT 1`, emailBidx, kecualiID).Scan(&id)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error mencari alumni by email:", err)
+		}
+		return model.Alumni{}, err
+	}
+	return r.GetByID(id)
+}
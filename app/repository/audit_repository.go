@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hello-fiber/app/model"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type AuditRepository interface {
+	Append(entry model.AuditLog) (model.AuditLog, error)
+	GetAll(filter model.AuditFilter, limit, offset int) ([]model.AuditLog, error)
+	Count(filter model.AuditFilter) (int, error)
+	GetBySubjek(userID, alumniID int) ([]model.AuditLog, error)
+	Verify() (model.AuditVerifikasi, error)
+}
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// auditGenesisHash hash_sebelumnya untuk entri pertama
+var auditGenesisHash = strings.Repeat("0", 64)
+
+// auditWaktuLayout presisi mikrodetik sesuai kolom TIMESTAMP, tanpa zona waktu
+const auditWaktuLayout = "2006-01-02T15:04:05.000000"
+
+// canonicalAuditJSON menormalkan JSON (urutan key, spasi) agar hash sama sebelum dan sesudah disimpan sebagai JSONB
+func canonicalAuditJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// hashAuditLog sha256 dari hash sebelumnya dan seluruh isi entri kecuali id dan hash
+func hashAuditLog(hashSebelumnya string, e model.AuditLog) (string, error) {
+	sebelum, err := canonicalAuditJSON(e.Sebelum)
+	if err != nil {
+		return "", err
+	}
+	sesudah, err := canonicalAuditJSON(e.Sesudah)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal([]interface{}{
+		hashSebelumnya, e.Waktu.Format(auditWaktuLayout), e.UserID, e.RoleID, e.ImpersonatorID,
+		e.Aksi, e.ResourceTipe, e.ResourceID, e.Method, e.Path, e.Status, e.IP, e.RequestID,
+		sebelum, sesudah,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// nullableJSON NULL untuk snapshot kosong
+func nullableJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	return []byte(raw)
+}
+
+// Append menambah entri di ujung rantai. Advisory lock memastikan entri dari request yang bersamaan
+// dirantai berurutan sesuai id.
+func (r *auditRepository) Append(e model.AuditLog) (model.AuditLog, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi audit log:", err)
+		return model.AuditLog{}, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('audit_log'))`); err != nil {
+		log.Println("Error mengunci audit log:", err)
+		return model.AuditLog{}, err
+	}
+	err = tx.QueryRow(`SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1`).Scan(&e.HashSebelumnya)
+	if err == sql.ErrNoRows {
+		e.HashSebelumnya = auditGenesisHash
+	} else if err != nil {
+		log.Println("Error mengambil hash audit terakhir:", err)
+		return model.AuditLog{}, err
+	}
+
+	e.Waktu = time.Now().Truncate(time.Microsecond)
+	if e.Sebelum, err = canonicalAuditJSON(e.Sebelum); err != nil {
+		return model.AuditLog{}, err
+	}
+	if e.Sesudah, err = canonicalAuditJSON(e.Sesudah); err != nil {
+		return model.AuditLog{}, err
+	}
+	if e.Hash, err = hashAuditLog(e.HashSebelumnya, e); err != nil {
+		return model.AuditLog{}, err
+	}
+
+	err = tx.QueryRow(`INSERT INTO audit_log (waktu, user_id, role_id, impersonator_id, aksi, resource_tipe, resource_id,
+			method, path, status, ip, request_id, sebelum, sesudah, hash_sebelumnya, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16) RETURNING id`,
+		e.Waktu, nullableInt(e.UserID), nullableInt(e.RoleID), nullableInt(e.ImpersonatorID), e.Aksi, e.ResourceTipe, e.ResourceID,
+		e.Method, e.Path, e.Status, e.IP, e.RequestID, nullableJSON(e.Sebelum), nullableJSON(e.Sesudah), e.HashSebelumnya, e.Hash,
+	).Scan(&e.ID)
+	if err != nil {
+		log.Println("Error menyimpan audit log:", err)
+		return model.AuditLog{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit audit log:", err)
+		return model.AuditLog{}, err
+	}
+	return e, nil
+}
+
+const auditColumns = `id, waktu, COALESCE(user_id, 0), COALESCE(role_id, 0), COALESCE(impersonator_id, 0), aksi,
+		resource_tipe, resource_id, method, path, status, ip, request_id, sebelum, sesudah, hash_sebelumnya, hash`
+
+func scanAuditLog(scanner interface{ Scan(...interface{}) error }) (model.AuditLog, error) {
+	var e model.AuditLog
+	var sebelum, sesudah []byte
+	err := scanner.Scan(
+		&e.ID, &e.Waktu, &e.UserID, &e.RoleID, &e.ImpersonatorID, &e.Aksi,
+		&e.ResourceTipe, &e.ResourceID, &e.Method, &e.Path, &e.Status, &e.IP, &e.RequestID,
+		&sebelum, &sesudah, &e.HashSebelumnya, &e.Hash,
+	)
+	if err != nil {
+		return e, err
+	}
+	if sebelum != nil {
+		e.Sebelum = json.RawMessage(sebelum)
+	}
+	if sesudah != nil {
+		e.Sesudah = json.RawMessage(sesudah)
+	}
+	return e, nil
+}
+
+func auditFilterCondition(filter model.AuditFilter) (string, []interface{}) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	add := func(cond string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.UserID > 0 {
+		add("user_id = $%d", filter.UserID)
+	}
+	if filter.Aksi != "" {
+		add("aksi = $%d", filter.Aksi)
+	}
+	if filter.ResourceTipe != "" {
+		add("resource_tipe = $%d", filter.ResourceTipe)
+	}
+	if filter.ResourceID != "" {
+		add("resource_id = $%d", filter.ResourceID)
+	}
+	if !filter.Dari.IsZero() {
+		add("waktu >= $%d", filter.Dari)
+	}
+	if !filter.Sampai.IsZero() {
+		add("waktu <= $%d", filter.Sampai)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *auditRepository) GetAll(filter model.AuditFilter, limit, offset int) ([]model.AuditLog, error) {
+	where, args := auditFilterCondition(filter)
+	args = append(args, limit, offset)
+	rows, err := r.db.Query(fmt.Sprintf(`SELECT %s FROM audit_log WHERE %s ORDER BY id DESC LIMIT $%d OFFSET $%d`,
+		auditColumns, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		log.Println("Error men-query audit log:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.AuditLog{}
+	for rows.Next() {
+		e, err := scanAuditLog(rows)
+		if err != nil {
+			log.Println("Error men-scan audit log:", err)
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
+
+// GetBySubjek entri yang dilakukan user tersebut atau yang mengubah data alumninya, untuk export data pribadi
+func (r *auditRepository) GetBySubjek(userID, alumniID int) ([]model.AuditLog, error) {
+	rows, err := r.db.Query(`SELECT `+auditColumns+` FROM audit_log
+		WHERE user_id = $1 OR (resource_tipe = 'alumni' AND resource_id = $2)
+		ORDER BY id ASC`, userID, strconv.Itoa(alumniID))
+	if err != nil {
+		log.Println("Error men-query audit log subjek:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.AuditLog{}
+	for rows.Next() {
+		e, err := scanAuditLog(rows)
+		if err != nil {
+			log.Println("Error men-scan audit log:", err)
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
+
+func (r *auditRepository) Count(filter model.AuditFilter) (int, error) {
+	where, args := auditFilterCondition(filter)
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM audit_log WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung audit log:", err)
+	}
+	return total, err
+}
+
+// Verify menghitung ulang rantai hash dari entri pertama dan berhenti di entri pertama yang tidak cocok
+func (r *auditRepository) Verify() (model.AuditVerifikasi, error) {
+	rows, err := r.db.Query(`SELECT ` + auditColumns + ` FROM audit_log ORDER BY id`)
+	if err != nil {
+		log.Println("Error men-query audit log untuk verifikasi:", err)
+		return model.AuditVerifikasi{}, err
+	}
+	defer rows.Close()
+
+	result := model.AuditVerifikasi{Valid: true, HashTerakhir: auditGenesisHash}
+	for rows.Next() {
+		e, err := scanAuditLog(rows)
+		if err != nil {
+			log.Println("Error men-scan audit log untuk verifikasi:", err)
+			return model.AuditVerifikasi{}, err
+		}
+		result.JumlahEntri++
+
+		if e.HashSebelumnya != result.HashTerakhir {
+			result.Valid, result.RusakDiID = false, e.ID
+			result.Alasan = "hash_sebelumnya tidak sama dengan hash entri sebelumnya (entri dihapus atau disisipkan)"
+			return result, nil
+		}
+		hash, err := hashAuditLog(e.HashSebelumnya, e)
+		if err != nil {
+			return model.AuditVerifikasi{}, err
+		}
+		if hash != e.Hash {
+			result.Valid, result.RusakDiID = false, e.ID
+			result.Alasan = "isi entri tidak sesuai dengan hash-nya (entri diubah)"
+			return result, nil
+		}
+		result.HashTerakhir = e.Hash
+	}
+	return result, rows.Err()
+}
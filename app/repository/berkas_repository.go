@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"hello-fiber/app/model"
+	"log"
+)
+
+type BerkasRepository interface {
+	Create(berkas model.Berkas) (model.Berkas, error)
+	// ReplaceFotoAlumni menyimpan foto baru dan menghapus baris foto lama dalam satu transaksi;
+	// foto lama dikembalikan agar object-nya bisa dihapus dari storage
+	ReplaceFotoAlumni(berkas model.Berkas) (model.Berkas, []model.Berkas, error)
+	GetByID(id int) (model.Berkas, error)
+	GetFotoAlumni(alumniID int) (model.Berkas, error)
+	GetByAlumni(alumniID int) ([]model.Berkas, error)
+	GetByPekerjaan(pekerjaanID int) ([]model.Berkas, error)
+	Delete(id int) error
+	// CatatHapusGagal mencatat object yang gagal dihapus dari storage agar dicoba ulang
+	CatatHapusGagal(key, pesan string) error
+	GetHapusGagal(limit int) ([]string, error)
+	HapusCatatanGagal(key string) error
+}
+
+type berkasRepository struct {
+	db *sql.DB
+}
+
+func NewBerkasRepository(db *sql.DB) BerkasRepository {
+	return &berkasRepository{db: db}
+}
+
+const berkasColumns = `id, alumni_id, pekerjaan_id, jenis, kategori, nama_asli, content_type, ukuran, sha256,
+	storage_key, thumbnail_key, diunggah_oleh, created_at`
+
+func scanBerkas(scanner interface{ Scan(...interface{}) error }) (model.Berkas, error) {
+	var b model.Berkas
+	var alumniID, pekerjaanID, diunggahOleh sql.NullInt64
+	var thumbnailKey sql.NullString
+
+	err := scanner.Scan(
+		&b.ID, &alumniID, &pekerjaanID, &b.Jenis, &b.Kategori, &b.NamaAsli, &b.ContentType, &b.Ukuran, &b.SHA256,
+		&b.StorageKey, &thumbnailKey, &diunggahOleh, &b.CreatedAt,
+	)
+	if err != nil {
+		return b, err
+	}
+	if alumniID.Valid {
+		id := int(alumniID.Int64)
+		b.AlumniID = &id
+	}
+	if pekerjaanID.Valid {
+		id := int(pekerjaanID.Int64)
+		b.PekerjaanID = &id
+	}
+	b.ThumbnailKey = thumbnailKey.String
+	b.DiunggahOleh = int(diunggahOleh.Int64)
+	return b, nil
+}
+
+func insertBerkas(db DBTX, b model.Berkas) (model.Berkas, error) {
+	var alumniID, pekerjaanID int
+	if b.AlumniID != nil {
+		alumniID = *b.AlumniID
+	}
+	if b.PekerjaanID != nil {
+		pekerjaanID = *b.PekerjaanID
+	}
+
+	row := db.QueryRow(`INSERT INTO berkas (alumni_id, pekerjaan_id, jenis, kategori, nama_asli, content_type, ukuran,
+			sha256, storage_key, thumbnail_key, diunggah_oleh)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING `+berkasColumns,
+		nullableInt(alumniID), nullableInt(pekerjaanID), b.Jenis, b.Kategori, b.NamaAsli, b.ContentType, b.Ukuran,
+		b.SHA256, b.StorageKey, nullableString(b.ThumbnailKey), nullableInt(b.DiunggahOleh),
+	)
+	return scanBerkas(row)
+}
+
+func (r *berkasRepository) Create(berkas model.Berkas) (model.Berkas, error) {
+	created, err := insertBerkas(r.db, berkas)
+	if err != nil {
+		log.Println("Error menyimpan berkas:", err)
+	}
+	return created, err
+}
+
+func (r *berkasRepository) ReplaceFotoAlumni(berkas model.Berkas) (model.Berkas, []model.Berkas, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Berkas{}, nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`DELETE FROM berkas WHERE alumni_id = $1 AND jenis = $2 RETURNING `+berkasColumns,
+		*berkas.AlumniID, model.JenisBerkasFoto)
+	if err != nil {
+		log.Println("Error menghapus foto lama alumni:", err)
+		return model.Berkas{}, nil, err
+	}
+	lama := []model.Berkas{}
+	for rows.Next() {
+		b, err := scanBerkas(rows)
+		if err != nil {
+			rows.Close()
+			log.Println("Error men-scan foto lama alumni:", err)
+			return model.Berkas{}, nil, err
+		}
+		lama = append(lama, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return model.Berkas{}, nil, err
+	}
+
+	created, err := insertBerkas(tx, berkas)
+	if err != nil {
+		log.Println("Error menyimpan foto alumni:", err)
+		return model.Berkas{}, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Berkas{}, nil, err
+	}
+	return created, lama, nil
+}
+
+func (r *berkasRepository) GetByID(id int) (model.Berkas, error) {
+	b, err := scanBerkas(r.db.QueryRow(`SELECT `+berkasColumns+` FROM berkas WHERE id = $1`, id))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil berkas:", err)
+	}
+	return b, err
+}
+
+func (r *berkasRepository) GetFotoAlumni(alumniID int) (model.Berkas, error) {
+	b, err := scanBerkas(r.db.QueryRow(`SELECT `+berkasColumns+` FROM berkas
+		WHERE alumni_id = $1 AND jenis = $2 ORDER BY created_at DESC LIMIT 1`, alumniID, model.JenisBerkasFoto))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil foto alumni:", err)
+	}
+	return b, err
+}
+
+// GetByAlumni seluruh berkas milik alumni, termasuk dokumen pada pekerjaannya
+func (r *berkasRepository) GetByAlumni(alumniID int) ([]model.Berkas, error) {
+	return berkasMilikAlumni(r.db, alumniID)
+}
+
+func berkasMilikAlumni(db DBTX, alumniID int) ([]model.Berkas, error) {
+	return listBerkas(db, `SELECT `+berkasColumns+` FROM berkas
+		WHERE alumni_id = $1 OR pekerjaan_id IN (SELECT id FROM pekerjaan_alumni WHERE alumni_id = $1)
+		ORDER BY created_at, id`, alumniID)
+}
+
+func (r *berkasRepository) GetByPekerjaan(pekerjaanID int) ([]model.Berkas, error) {
+	return r.list(`SELECT `+berkasColumns+` FROM berkas WHERE pekerjaan_id = $1 ORDER BY created_at, id`, pekerjaanID)
+}
+
+func (r *berkasRepository) list(query string, args ...interface{}) ([]model.Berkas, error) {
+	return listBerkas(r.db, query, args...)
+}
+
+func listBerkas(db DBTX, query string, args ...interface{}) ([]model.Berkas, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		log.Println("Error men-query berkas:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.Berkas{}
+	for rows.Next() {
+		b, err := scanBerkas(rows)
+		if err != nil {
+			log.Println("Error men-scan berkas:", err)
+			return nil, err
+		}
+		list = append(list, b)
+	}
+	return list, rows.Err()
+}
+
+func (r *berkasRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM berkas WHERE id = $1`, id)
+	if err != nil {
+		log.Println("Error menghapus berkas:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *berkasRepository) CatatHapusGagal(key, pesan string) error {
+	_, err := r.db.Exec(`INSERT INTO berkas_hapus_gagal (storage_key, error) VALUES ($1, $2)
+		ON CONFLICT (storage_key) DO UPDATE
+		SET error = EXCLUDED.error, percobaan = berkas_hapus_gagal.percobaan + 1, updated_at = NOW()`, key, pesan)
+	if err != nil {
+		log.Println("Error mencatat berkas gagal dihapus:", err)
+	}
+	return err
+}
+
+// GetHapusGagal key object yang gagal dihapus, yang paling lama tidak dicoba lebih dulu
+func (r *berkasRepository) GetHapusGagal(limit int) ([]string, error) {
+	rows, err := r.db.Query(`SELECT storage_key FROM berkas_hapus_gagal ORDER BY updated_at LIMIT $1`, limit)
+	if err != nil {
+		log.Println("Error men-query berkas gagal dihapus:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *berkasRepository) HapusCatatanGagal(key string) error {
+	_, err := r.db.Exec(`DELETE FROM berkas_hapus_gagal WHERE storage_key = $1`, key)
+	if err != nil {
+		log.Println("Error menghapus catatan berkas gagal dihapus:", err)
+	}
+	return err
+}
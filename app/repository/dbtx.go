@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"database/sql"
+)
+
+// DBTX dipenuhi oleh *sql.DB maupun *sql.Tx, sehingga repository yang sama
+// bisa dipakai di dalam maupun di luar transaksi
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"database/sql"
+	"hello-fiber/app/model"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type KeahlianAlumniRepository interface {
+	GetByAlumni(alumniID int) ([]model.KeahlianAlumni, error)
+	GetByID(alumniID, id int) (model.KeahlianAlumni, error)
+	Create(alumniID int, req model.KeahlianAlumniRequest, skillID int, tagIDs []int) (model.KeahlianAlumni, error)
+	Update(alumniID, id int, req model.KeahlianAlumniRequest, skillID int, tagIDs []int) (model.KeahlianAlumni, error)
+	Delete(alumniID, id int) error
+	GetTagAlumni(alumniID int) ([]model.TagAlumni, error)
+	SetTagAlumni(alumniID int, referensiIDs []int, actorID int) ([]model.TagAlumni, error)
+	GetSkillAktif(alumniID int) ([]string, error)
+}
+
+type keahlianAlumniRepository struct {
+	db *sql.DB
+}
+
+func NewKeahlianAlumniRepository(db *sql.DB) KeahlianAlumniRepository {
+	return &keahlianAlumniRepository{db: db}
+}
+
+// skillAktifAlumni pasangan (alumni_id, referensi_id) untuk setiap skill yang dimiliki alumni: tag admin,
+// keahlian jenis skill, dan tag keahlian. Sertifikasi yang sudah kedaluwarsa tidak dihitung.
+const skillAktifAlumni = `SELECT alumni_id, referensi_id FROM alumni_tag
+	UNION SELECT k.alumni_id, k.referensi_id FROM keahlian_alumni k
+	    WHERE k.referensi_id IS NOT NULL AND (k.berlaku_sampai IS NULL OR k.berlaku_sampai >= CURRENT_DATE)
+	UNION SELECT k.alumni_id, t.referensi_id FROM keahlian_alumni k JOIN keahlian_alumni_tag t ON t.keahlian_id = k.id
+	    WHERE k.berlaku_sampai IS NULL OR k.berlaku_sampai >= CURRENT_DATE`
+
+// kondisiSkillAlumni kondisi WHERE untuk tabel alumni: alumni harus memiliki semua skill di parameter
+// placeholder (int[]). Array kosong selalu terpenuhi.
+func kondisiSkillAlumni(placeholder string) string {
+	return `(SELECT COUNT(DISTINCT s.referensi_id) FROM (` + skillAktifAlumni + `) s
+		WHERE s.alumni_id = alumni.id AND s.referensi_id = ANY(` + placeholder + `::int[])) = cardinality(` + placeholder + `::int[])`
+}
+
+// keahlianColumns kolom keahlian (alias k); nama skill mengikuti nama kanonis referensi (alias rs) terbaru
+const keahlianColumns = `k.id, k.alumni_id, k.jenis, COALESCE(rs.nama, k.nama), k.penerbit, k.tahun, k.berlaku_sampai,
+	COALESCE(k.berlaku_sampai < CURRENT_DATE, FALSE), k.created_at, k.updated_at,
+	ARRAY(SELECT r.nama FROM referensi r
+	      WHERE r.id = k.referensi_id OR r.id IN (SELECT t.referensi_id FROM keahlian_alumni_tag t WHERE t.keahlian_id = k.id)
+	      ORDER BY COALESCE(r.id = k.referensi_id, FALSE) DESC, r.nama)`
+
+const keahlianFrom = `FROM keahlian_alumni k LEFT JOIN referensi rs ON rs.id = k.referensi_id`
+
+func scanKeahlian(scanner interface{ Scan(...interface{}) error }) (model.KeahlianAlumni, error) {
+	var k model.KeahlianAlumni
+	var tahun sql.NullInt64
+
+	err := scanner.Scan(
+		&k.ID, &k.AlumniID, &k.Jenis, &k.Nama, &k.Penerbit, &tahun, &k.BerlakuSampai,
+		&k.Kedaluwarsa, &k.CreatedAt, &k.UpdatedAt, pq.Array(&k.Tags),
+	)
+	if err != nil {
+		return k, err
+	}
+	if tahun.Valid {
+		t := int(tahun.Int64)
+		k.Tahun = &t
+	}
+	if k.Tags == nil {
+		k.Tags = []string{}
+	}
+	return k, nil
+}
+
+// GetByAlumni skill lebih dulu, lalu sertifikasi dari yang terbaru
+func (r *keahlianAlumniRepository) GetByAlumni(alumniID int) ([]model.KeahlianAlumni, error) {
+	rows, err := r.db.Query(`SELECT `+keahlianColumns+` `+keahlianFrom+`
+		WHERE k.alumni_id = $1
+		ORDER BY k.jenis = 'skill' DESC, k.tahun DESC NULLS LAST, k.id`, alumniID)
+	if err != nil {
+		log.Println("Error men-query keahlian alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.KeahlianAlumni{}
+	for rows.Next() {
+		k, err := scanKeahlian(rows)
+		if err != nil {
+			log.Println("Error men-scan keahlian alumni:", err)
+			return nil, err
+		}
+		list = append(list, k)
+	}
+	return list, rows.Err()
+}
+
+func (r *keahlianAlumniRepository) GetByID(alumniID, id int) (model.KeahlianAlumni, error) {
+	k, err := scanKeahlian(r.db.QueryRow(`SELECT `+keahlianColumns+` `+keahlianFrom+`
+		WHERE k.id = $1 AND k.alumni_id = $2`, id, alumniID))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil keahlian alumni:", err)
+	}
+	return k, err
+}
+
+// simpanTagKeahlian mengganti tag tambahan keahlian; skill keahlian itu sendiri tidak disimpan ulang sebagai tag
+func simpanTagKeahlian(tx *sql.Tx, keahlianID, skillID int, tagIDs []int) error {
+	if _, err := tx.Exec(`DELETE FROM keahlian_alumni_tag WHERE keahlian_id = $1`, keahlianID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(`INSERT INTO keahlian_alumni_tag (keahlian_id, referensi_id)
+		SELECT $1, id FROM unnest($2::int[]) AS id WHERE id <> $3
+		ON CONFLICT DO NOTHING`, keahlianID, pq.Array(tagIDs), skillID)
+	return err
+}
+
+// Create menyimpan keahlian; skillID diisi id referensi skill untuk jenis skill, 0 untuk sertifikasi
+func (r *keahlianAlumniRepository) Create(alumniID int, req model.KeahlianAlumniRequest, skillID int, tagIDs []int) (model.KeahlianAlumni, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi keahlian alumni:", err)
+		return model.KeahlianAlumni{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var id int
+	err = tx.QueryRow(`INSERT INTO keahlian_alumni
+			(alumni_id, jenis, referensi_id, nama, penerbit, tahun, berlaku_sampai, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8) RETURNING id`,
+		alumniID, req.Jenis, nullableInt(skillID), req.Nama, req.Penerbit, nullableIntPtr(req.Tahun), req.BerlakuSampai, now,
+	).Scan(&id)
+	if err != nil {
+		log.Println("Error menyimpan keahlian alumni:", err)
+		return model.KeahlianAlumni{}, err
+	}
+	if err := simpanTagKeahlian(tx, id, skillID, tagIDs); err != nil {
+		log.Println("Error menyimpan tag keahlian alumni:", err)
+		return model.KeahlianAlumni{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.KeahlianAlumni{}, err
+	}
+	return r.GetByID(alumniID, id)
+}
+
+func (r *keahlianAlumniRepository) Update(alumniID, id int, req model.KeahlianAlumniRequest, skillID int, tagIDs []int) (model.KeahlianAlumni, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi keahlian alumni:", err)
+		return model.KeahlianAlumni{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE keahlian_alumni
+		SET jenis = $1, referensi_id = $2, nama = $3, penerbit = $4, tahun = $5, berlaku_sampai = $6, updated_at = $7
+		WHERE id = $8 AND alumni_id = $9`,
+		req.Jenis, nullableInt(skillID), req.Nama, req.Penerbit, nullableIntPtr(req.Tahun), req.BerlakuSampai, time.Now(),
+		id, alumniID,
+	)
+	if err != nil {
+		log.Println("Error mengupdate keahlian alumni:", err)
+		return model.KeahlianAlumni{}, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return model.KeahlianAlumni{}, sql.ErrNoRows
+	}
+	if err := simpanTagKeahlian(tx, id, skillID, tagIDs); err != nil {
+		log.Println("Error menyimpan tag keahlian alumni:", err)
+		return model.KeahlianAlumni{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.KeahlianAlumni{}, err
+	}
+	return r.GetByID(alumniID, id)
+}
+
+func (r *keahlianAlumniRepository) Delete(alumniID, id int) error {
+	result, err := r.db.Exec(`DELETE FROM keahlian_alumni WHERE id = $1 AND alumni_id = $2`, id, alumniID)
+	if err != nil {
+		log.Println("Error menghapus keahlian alumni:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *keahlianAlumniRepository) GetTagAlumni(alumniID int) ([]model.TagAlumni, error) {
+	return getTagAlumni(r.db, alumniID)
+}
+
+func getTagAlumni(db DBTX, alumniID int) ([]model.TagAlumni, error) {
+	rows, err := db.Query(`SELECT t.referensi_id, r.nama, COALESCE(t.ditandai_oleh, 0), t.created_at
+		FROM alumni_tag t JOIN referensi r ON r.id = t.referensi_id
+		WHERE t.alumni_id = $1
+		ORDER BY r.nama`, alumniID)
+	if err != nil {
+		log.Println("Error men-query tag alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.TagAlumni{}
+	for rows.Next() {
+		var t model.TagAlumni
+		if err := rows.Scan(&t.ReferensiID, &t.Nama, &t.DitandaiOleh, &t.CreatedAt); err != nil {
+			log.Println("Error men-scan tag alumni:", err)
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// SetTagAlumni mengganti seluruh tag admin alumni. Tag yang sudah ada tetap menyimpan penanda dan waktu awalnya.
+func (r *keahlianAlumniRepository) SetTagAlumni(alumniID int, referensiIDs []int, actorID int) ([]model.TagAlumni, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi tag alumni:", err)
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if referensiIDs == nil {
+		referensiIDs = []int{}
+	}
+	if _, err := tx.Exec(`DELETE FROM alumni_tag WHERE alumni_id = $1 AND NOT (referensi_id = ANY($2::int[]))`,
+		alumniID, pq.Array(referensiIDs)); err != nil {
+		log.Println("Error menghapus tag alumni:", err)
+		return nil, err
+	}
+	if _, err := tx.Exec(`INSERT INTO alumni_tag (alumni_id, referensi_id, ditandai_oleh, created_at)
+		SELECT $1, id, $3, $4 FROM unnest($2::int[]) AS id
+		ON CONFLICT (alumni_id, referensi_id) DO NOTHING`,
+		alumniID, pq.Array(referensiIDs), nullableInt(actorID), time.Now()); err != nil {
+		log.Println("Error menyimpan tag alumni:", err)
+		return nil, err
+	}
+
+	tags, err := getTagAlumni(tx, alumniID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetSkillAktif nama seluruh skill alumni yang dihitung saat pencarian berdasarkan skill
+func (r *keahlianAlumniRepository) GetSkillAktif(alumniID int) ([]string, error) {
+	var skills []string
+	err := r.db.QueryRow(`SELECT COALESCE(array_agg(r.nama ORDER BY r.nama), '{}')
+		FROM referensi r
+		WHERE r.id IN (SELECT s.referensi_id FROM (`+skillAktifAlumni+`) s WHERE s.alumni_id = $1)`,
+		alumniID).Scan(pq.Array(&skills))
+	if err != nil {
+		log.Println("Error mengambil skill alumni:", err)
+		return nil, err
+	}
+	if skills == nil {
+		skills = []string{}
+	}
+	return skills, nil
+}
@@ -0,0 +1,395 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"hello-fiber/app/model"
+	"log"
+	"time"
+)
+
+type KuesionerRepository interface {
+	Create(req model.CreateKuesionerRequest) (model.Kuesioner, error)
+	GetAll() ([]model.Kuesioner, error)
+	GetByID(id int) (model.Kuesioner, error)
+	UpdateStatus(id int, req model.UpdateKuesionerStatusRequest) (model.Kuesioner, error)
+	Delete(id int) error
+	GetAvailableForAlumni(alumni model.Alumni) ([]model.Kuesioner, error)
+	CountSasaran(kuesioner model.Kuesioner) (int, error)
+	SubmitRespon(kuesioner model.Kuesioner, alumniID int, jawaban []model.JawabanItem) (model.KuesionerRespon, error)
+	GetLatestRespon(kuesionerID, alumniID, periode int) (model.KuesionerRespon, error)
+	GetLatestJawaban(kuesionerID, periode int) ([]model.JawabanItem, int, error)
+}
+
+type kuesionerRepository struct {
+	db *sql.DB
+}
+
+func NewKuesionerRepository(db *sql.DB) KuesionerRepository {
+	return &kuesionerRepository{db: db}
+}
+
+const kuesionerColumns = `id, judul, deskripsi, periode, angkatan, jurusan, status, created_at, updated_at`
+
+func scanKuesioner(scanner interface{ Scan(...interface{}) error }) (model.Kuesioner, error) {
+	var k model.Kuesioner
+	var angkatan sql.NullInt64
+	var jurusan sql.NullString
+	err := scanner.Scan(
+		&k.ID, &k.Judul, &k.Deskripsi, &k.Periode, &angkatan, &jurusan,
+		&k.Status, &k.CreatedAt, &k.UpdatedAt,
+	)
+	if angkatan.Valid {
+		k.Angkatan = int(angkatan.Int64)
+	}
+	if jurusan.Valid {
+		k.Jurusan = jurusan.String
+	}
+	return k, err
+}
+
+func nullableInt(value int) interface{} {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+func nullableString(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func (r *kuesionerRepository) Create(req model.CreateKuesionerRequest) (model.Kuesioner, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi kuesioner:", err)
+		return model.Kuesioner{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var id int
+	err = tx.QueryRow(`INSERT INTO kuesioner (judul, deskripsi, periode, angkatan, jurusan, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		req.Judul, req.Deskripsi, req.Periode, nullableInt(req.Angkatan), nullableString(req.Jurusan),
+		model.StatusKuesionerDraft, now, now,
+	).Scan(&id)
+	if err != nil {
+		log.Println("Error inserting kuesioner:", err)
+		return model.Kuesioner{}, err
+	}
+
+	for i, bagian := range req.Bagian {
+		var bagianID int
+		err = tx.QueryRow(`INSERT INTO kuesioner_bagian (kuesioner_id, judul, urutan) VALUES ($1, $2, $3) RETURNING id`,
+			id, bagian.Judul, i+1,
+		).Scan(&bagianID)
+		if err != nil {
+			log.Println("Error inserting bagian kuesioner:", err)
+			return model.Kuesioner{}, err
+		}
+
+		for j, p := range bagian.Pertanyaan {
+			opsi := p.Opsi
+			if opsi == nil {
+				opsi = []string{}
+			}
+			opsiJSON, _ := json.Marshal(opsi)
+			_, err = tx.Exec(`INSERT INTO kuesioner_pertanyaan (bagian_id, pertanyaan, tipe, opsi, skala_min, skala_max, wajib, urutan)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				bagianID, p.Pertanyaan, p.Tipe, string(opsiJSON), p.SkalaMin, p.SkalaMax, p.Wajib, j+1,
+			)
+			if err != nil {
+				log.Println("Error inserting pertanyaan kuesioner:", err)
+				return model.Kuesioner{}, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit kuesioner:", err)
+		return model.Kuesioner{}, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *kuesionerRepository) GetAll() ([]model.Kuesioner, error) {
+	rows, err := r.db.Query(`SELECT ` + kuesionerColumns + ` FROM kuesioner ORDER BY periode DESC, created_at DESC`)
+	if err != nil {
+		log.Println("Error men-query kuesioner:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	kuesionerList := []model.Kuesioner{}
+	for rows.Next() {
+		k, err := scanKuesioner(rows)
+		if err != nil {
+			log.Println("Error men-scan kuesioner:", err)
+			return nil, err
+		}
+		kuesionerList = append(kuesionerList, k)
+	}
+	return kuesionerList, nil
+}
+
+// GetByID mengambil kuesioner lengkap dengan bagian dan pertanyaannya
+func (r *kuesionerRepository) GetByID(id int) (model.Kuesioner, error) {
+	k, err := scanKuesioner(r.db.QueryRow(`SELECT `+kuesionerColumns+` FROM kuesioner WHERE id = $1`, id))
+	if err != nil {
+		log.Println("Error menemukan kuesioner by ID:", err)
+		return model.Kuesioner{}, err
+	}
+
+	rows, err := r.db.Query(`SELECT b.id, b.judul, b.urutan, p.id, p.pertanyaan, p.tipe, p.opsi,
+		       p.skala_min, p.skala_max, p.wajib, p.urutan
+		FROM kuesioner_bagian b
+		LEFT JOIN kuesioner_pertanyaan p ON p.bagian_id = b.id
+		WHERE b.kuesioner_id = $1
+		ORDER BY b.urutan, p.urutan`, id)
+	if err != nil {
+		log.Println("Error men-query pertanyaan kuesioner:", err)
+		return model.Kuesioner{}, err
+	}
+	defer rows.Close()
+
+	k.Bagian = []model.KuesionerBagian{}
+	for rows.Next() {
+		var bagian model.KuesionerBagian
+		var pID, skalaMin, skalaMax, pUrutan sql.NullInt64
+		var pertanyaan, tipe sql.NullString
+		var opsi []byte
+		var wajib sql.NullBool
+		if err := rows.Scan(
+			&bagian.ID, &bagian.Judul, &bagian.Urutan, &pID, &pertanyaan, &tipe, &opsi,
+			&skalaMin, &skalaMax, &wajib, &pUrutan,
+		); err != nil {
+			log.Println("Error men-scan pertanyaan kuesioner:", err)
+			return model.Kuesioner{}, err
+		}
+
+		if len(k.Bagian) == 0 || k.Bagian[len(k.Bagian)-1].ID != bagian.ID {
+			bagian.KuesionerID = id
+			bagian.Pertanyaan = []model.KuesionerPertanyaan{}
+			k.Bagian = append(k.Bagian, bagian)
+		}
+		if !pID.Valid {
+			continue
+		}
+
+		p := model.KuesionerPertanyaan{
+			ID:         int(pID.Int64),
+			BagianID:   bagian.ID,
+			Pertanyaan: pertanyaan.String,
+			Tipe:       tipe.String,
+			SkalaMin:   int(skalaMin.Int64),
+			SkalaMax:   int(skalaMax.Int64),
+			Wajib:      wajib.Bool,
+			Urutan:     int(pUrutan.Int64),
+		}
+		if err := json.Unmarshal(opsi, &p.Opsi); err != nil || p.Opsi == nil {
+			p.Opsi = []string{}
+		}
+		last := &k.Bagian[len(k.Bagian)-1]
+		last.Pertanyaan = append(last.Pertanyaan, p)
+	}
+	return k, nil
+}
+
+func (r *kuesionerRepository) UpdateStatus(id int, req model.UpdateKuesionerStatusRequest) (model.Kuesioner, error) {
+	result, err := r.db.Exec(`UPDATE kuesioner
+		SET status = COALESCE(NULLIF($1, ''), status), periode = COALESCE($2::int, periode), updated_at = $3
+		WHERE id = $4`,
+		req.Status, nullableInt(req.Periode), time.Now(), id,
+	)
+	if err != nil {
+		log.Println("Error updating status kuesioner:", err)
+		return model.Kuesioner{}, err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return model.Kuesioner{}, sql.ErrNoRows
+	}
+	return r.GetByID(id)
+}
+
+func (r *kuesionerRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM kuesioner WHERE id = $1`, id)
+	if err != nil {
+		log.Println("Error deleting kuesioner:", err)
+		return err
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetAvailableForAlumni mengambil kuesioner aktif yang sasarannya mencakup angkatan/jurusan alumni
+func (r *kuesionerRepository) GetAvailableForAlumni(alumni model.Alumni) ([]model.Kuesioner, error) {
+	rows, err := r.db.Query(`SELECT `+kuesionerColumns+` FROM kuesioner
+		WHERE status = $1
+		  AND (angkatan IS NULL OR angkatan = $2)
+		  AND (jurusan IS NULL OR jurusan ILIKE $3)
+		ORDER BY periode DESC, created_at DESC`,
+		model.StatusKuesionerAktif, alumni.Angkatan, alumni.Jurusan,
+	)
+	if err != nil {
+		log.Println("Error men-query kuesioner untuk alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	kuesionerList := []model.Kuesioner{}
+	for rows.Next() {
+		k, err := scanKuesioner(rows)
+		if err != nil {
+			log.Println("Error men-scan kuesioner:", err)
+			return nil, err
+		}
+		kuesionerList = append(kuesionerList, k)
+	}
+	return kuesionerList, nil
+}
+
+// CountSasaran menghitung jumlah alumni yang termasuk sasaran kuesioner
+func (r *kuesionerRepository) CountSasaran(kuesioner model.Kuesioner) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM alumni
+		WHERE is_delete IS DISTINCT FROM 'hapus'
+		  AND ($1::int IS NULL OR angkatan = $1) AND ($2::text IS NULL OR jurusan ILIKE $2)`,
+		nullableInt(kuesioner.Angkatan), nullableString(kuesioner.Jurusan),
+	).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung sasaran kuesioner:", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// SubmitRespon menyimpan jawaban sebagai versi baru untuk periode kuesioner yang sedang berjalan
+func (r *kuesionerRepository) SubmitRespon(kuesioner model.Kuesioner, alumniID int, jawaban []model.JawabanItem) (model.KuesionerRespon, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi respon kuesioner:", err)
+		return model.KuesionerRespon{}, err
+	}
+	defer tx.Rollback()
+
+	respon := model.KuesionerRespon{
+		KuesionerID: kuesioner.ID,
+		AlumniID:    alumniID,
+		Periode:     kuesioner.Periode,
+		SubmittedAt: time.Now(),
+		Jawaban:     jawaban,
+	}
+
+	// submit bersamaan dari alumni yang sama diantrekan agar nomor versi tidak bentrok
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock($1, $2)`, respon.KuesionerID, respon.AlumniID); err != nil {
+		log.Println("Error mengunci respon kuesioner:", err)
+		return model.KuesionerRespon{}, err
+	}
+
+	err = tx.QueryRow(`INSERT INTO kuesioner_respon (kuesioner_id, alumni_id, periode, versi, submitted_at)
+		SELECT $1, $2, $3, COALESCE(MAX(versi), 0) + 1, $4
+		FROM kuesioner_respon
+		WHERE kuesioner_id = $1 AND alumni_id = $2 AND periode = $3
+		RETURNING id, versi`,
+		respon.KuesionerID, respon.AlumniID, respon.Periode, respon.SubmittedAt,
+	).Scan(&respon.ID, &respon.Versi)
+	if err != nil {
+		log.Println("Error inserting respon kuesioner:", err)
+		return model.KuesionerRespon{}, err
+	}
+
+	for _, item := range jawaban {
+		_, err = tx.Exec(`INSERT INTO kuesioner_jawaban (respon_id, pertanyaan_id, jawaban) VALUES ($1, $2, $3)`,
+			respon.ID, item.PertanyaanID, string(item.Jawaban),
+		)
+		if err != nil {
+			log.Println("Error inserting jawaban kuesioner:", err)
+			return model.KuesionerRespon{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit respon kuesioner:", err)
+		return model.KuesionerRespon{}, err
+	}
+	return respon, nil
+}
+
+func (r *kuesionerRepository) GetLatestRespon(kuesionerID, alumniID, periode int) (model.KuesionerRespon, error) {
+	var respon model.KuesionerRespon
+	err := r.db.QueryRow(`SELECT id, kuesioner_id, alumni_id, periode, versi, submitted_at
+		FROM kuesioner_respon
+		WHERE kuesioner_id = $1 AND alumni_id = $2 AND periode = $3
+		ORDER BY versi DESC
+		LIMIT 1`, kuesionerID, alumniID, periode,
+	).Scan(&respon.ID, &respon.KuesionerID, &respon.AlumniID, &respon.Periode, &respon.Versi, &respon.SubmittedAt)
+	if err != nil {
+		log.Println("Error menemukan respon kuesioner:", err)
+		return model.KuesionerRespon{}, err
+	}
+
+	rows, err := r.db.Query(`SELECT pertanyaan_id, jawaban FROM kuesioner_jawaban WHERE respon_id = $1 ORDER BY pertanyaan_id`, respon.ID)
+	if err != nil {
+		log.Println("Error men-query jawaban kuesioner:", err)
+		return model.KuesionerRespon{}, err
+	}
+	defer rows.Close()
+
+	respon.Jawaban = []model.JawabanItem{}
+	for rows.Next() {
+		var item model.JawabanItem
+		var raw []byte
+		if err := rows.Scan(&item.PertanyaanID, &raw); err != nil {
+			log.Println("Error men-scan jawaban kuesioner:", err)
+			return model.KuesionerRespon{}, err
+		}
+		item.Jawaban = raw
+		respon.Jawaban = append(respon.Jawaban, item)
+	}
+	return respon, nil
+}
+
+// GetLatestJawaban mengambil jawaban dari versi terakhir setiap alumni pada periode tertentu,
+// beserta jumlah responden
+func (r *kuesionerRepository) GetLatestJawaban(kuesionerID, periode int) ([]model.JawabanItem, int, error) {
+	latest := `SELECT DISTINCT ON (alumni_id) id
+		FROM kuesioner_respon
+		WHERE kuesioner_id = $1 AND periode = $2
+		ORDER BY alumni_id, versi DESC`
+
+	var responden int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM (`+latest+`) t`, kuesionerID, periode).Scan(&responden); err != nil {
+		log.Println("Error menghitung responden kuesioner:", err)
+		return nil, 0, err
+	}
+
+	rows, err := r.db.Query(`SELECT j.pertanyaan_id, j.jawaban
+		FROM kuesioner_jawaban j
+		WHERE j.respon_id IN (`+latest+`)`, kuesionerID, periode)
+	if err != nil {
+		log.Println("Error men-query jawaban kuesioner:", err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	jawaban := []model.JawabanItem{}
+	for rows.Next() {
+		var item model.JawabanItem
+		var raw []byte
+		if err := rows.Scan(&item.PertanyaanID, &raw); err != nil {
+			log.Println("Error men-scan jawaban kuesioner:", err)
+			return nil, 0, err
+		}
+		item.Jawaban = raw
+		jawaban = append(jawaban, item)
+	}
+	return jawaban, responden, nil
+}
@@ -0,0 +1,712 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hello-fiber/utils"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Enkripsi PII memakai envelope encryption: nilai kolom dienkripsi AES-GCM dengan kunci data, kunci data
+// disimpan di kunci_pii dalam keadaan terbungkus (AES-GCM) oleh kunci master dari env/file. Blind index
+// (HMAC) memakai kunci jenis indeks yang tidak pernah diganti agar pencarian tetap stabil saat rotasi.
+
+// Jenis kunci di tabel kunci_pii
+const (
+	kunciJenisData   = "data"
+	kunciJenisIndeks = "indeks"
+)
+
+// prefixPII penanda nilai terenkripsi: pii:<id kunci data>:<base64 nonce+ciphertext>.
+// Nilai tanpa prefix dianggap plaintext lama (belum dienkripsi atau hasil anonimisasi).
+const prefixPII = "pii:"
+
+// Konteks AAD dan blind index per kolom
+const (
+	kolomPIIEmail   = "alumni.email"
+	kolomPIITelepon = "alumni.no_telepon"
+	kolomPIIAlamat  = "alumni.alamat"
+)
+
+// piiKeyringTTL keyring dimuat ulang berkala agar proses yang berjalan ikut memakai kunci data hasil rotasi
+const piiKeyringTTL = 5 * time.Minute
+
+type piiKeyring struct {
+	dataKeys  map[int][]byte
+	aktifData int
+	indexKey  []byte
+	dimuatAt  time.Time
+}
+
+var (
+	piiMu   sync.Mutex
+	piiKeys *piiKeyring
+)
+
+func aadKunciPII(jenis string) []byte {
+	return []byte("kunci_pii:" + jenis)
+}
+
+// bungkusKunci membungkus kunci dengan kunci master aktif
+func bungkusKunci(master utils.MasterKeys, jenis string, key []byte) ([]byte, error) {
+	return utils.Seal(master.Kunci[master.AktifID], key, aadKunciPII(jenis))
+}
+
+// loadPIIKeyring membuka seluruh kunci di kunci_pii dengan kunci master yang tersedia
+func loadPIIKeyring(db DBTX) (*piiKeyring, error) {
+	master, err := utils.LoadMasterKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT id, jenis, master_key_id, kunci_terbungkus, aktif FROM kunci_pii ORDER BY id`)
+	if err != nil {
+		log.Println("Error membaca kunci_pii:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	k := &piiKeyring{dataKeys: map[int][]byte{}, dimuatAt: time.Now()}
+	for rows.Next() {
+		var id int
+		var jenis, masterID string
+		var wrapped []byte
+		var aktif bool
+		if err := rows.Scan(&id, &jenis, &masterID, &wrapped, &aktif); err != nil {
+			return nil, err
+		}
+		mk, ok := master.Kunci[masterID]
+		if !ok {
+			return nil, fmt.Errorf("kunci master %q untuk kunci_pii %d tidak tersedia", masterID, id)
+		}
+		key, err := utils.Open(mk, wrapped, aadKunciPII(jenis))
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuka kunci_pii %d dengan kunci master %q: %w", id, masterID, err)
+		}
+		switch jenis {
+		case kunciJenisData:
+			k.dataKeys[id] = key
+			if aktif {
+				k.aktifData = id
+			}
+		case kunciJenisIndeks:
+			if aktif {
+				k.indexKey = key
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if k.aktifData == 0 || k.indexKey == nil {
+		return nil, errors.New("kunci PII belum dibuat, jalankan aplikasi atau cmd/enkripsi-pii sekali")
+	}
+	return k, nil
+}
+
+// getPIIKeyring keyring yang tersimpan di memori, dimuat ulang jika kedaluwarsa atau reload bernilai true
+func getPIIKeyring(db DBTX, reload bool) (*piiKeyring, error) {
+	piiMu.Lock()
+	defer piiMu.Unlock()
+	if piiKeys != nil && !reload && time.Since(piiKeys.dimuatAt) < piiKeyringTTL {
+		return piiKeys, nil
+	}
+	k, err := loadPIIKeyring(db)
+	if err != nil {
+		return nil, err
+	}
+	piiKeys = k
+	return k, nil
+}
+
+// InitPIIKeyring membuat kunci data dan kunci indeks pertama jika belum ada lalu memuat keyring.
+// Dipanggil saat aplikasi start agar kunci tidak pernah dibuat di dalam transaksi yang bisa di-rollback.
+func InitPIIKeyring(db *sql.DB) error {
+	master, err := utils.LoadMasterKeys()
+	if err != nil {
+		return err
+	}
+	for _, jenis := range []string{kunciJenisData, kunciJenisIndeks} {
+		key, err := utils.KunciAcak()
+		if err != nil {
+			return err
+		}
+		wrapped, err := bungkusKunci(master, jenis, key)
+		if err != nil {
+			return err
+		}
+		_, err = db.Exec(`INSERT INTO kunci_pii (jenis, master_key_id, kunci_terbungkus)
+			SELECT $1, $2, $3 WHERE NOT EXISTS (SELECT 1 FROM kunci_pii WHERE jenis = $1 AND aktif)
+			ON CONFLICT DO NOTHING`, jenis, master.AktifID, wrapped)
+		if err != nil {
+			log.Println("Error membuat kunci_pii "+jenis+":", err)
+			return err
+		}
+	}
+	_, err = getPIIKeyring(db, true)
+	return err
+}
+
+// encryptPII mengenkripsi nilai kolom dengan kunci data aktif; string kosong tetap kosong
+func encryptPII(db DBTX, kolom, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	k, err := getPIIKeyring(db, false)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := utils.Seal(k.dataKeys[k.aktifData], []byte(plaintext), []byte(kolom))
+	if err != nil {
+		return "", err
+	}
+	return prefixPII + strconv.Itoa(k.aktifData) + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// kunciIDPII id kunci data yang dipakai nilai terenkripsi; 0 untuk plaintext
+func kunciIDPII(value string) int {
+	if !strings.HasPrefix(value, prefixPII) {
+		return 0
+	}
+	idPart, _, _ := strings.Cut(strings.TrimPrefix(value, prefixPII), ":")
+	id, _ := strconv.Atoi(idPart)
+	return id
+}
+
+// decryptPII membuka nilai terenkripsi; nilai tanpa prefix dikembalikan apa adanya
+func decryptPII(db DBTX, kolom, value string) (string, error) {
+	if !strings.HasPrefix(value, prefixPII) {
+		return value, nil
+	}
+	idPart, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefixPII), ":")
+	id, err := strconv.Atoi(idPart)
+	if !ok || err != nil {
+		return "", fmt.Errorf("format nilai terenkripsi %s tidak valid", kolom)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("format nilai terenkripsi %s tidak valid", kolom)
+	}
+
+	k, err := getPIIKeyring(db, false)
+	if err != nil {
+		return "", err
+	}
+	key, ok := k.dataKeys[id]
+	if !ok {
+		// kunci dibuat proses lain (rotasi) setelah keyring dimuat
+		if k, err = getPIIKeyring(db, true); err != nil {
+			return "", err
+		}
+		if key, ok = k.dataKeys[id]; !ok {
+			return "", fmt.Errorf("kunci data PII %d tidak ditemukan", id)
+		}
+	}
+	plaintext, err := utils.Open(key, sealed, []byte(kolom))
+	if err != nil {
+		return "", fmt.Errorf("gagal membuka %s: %w", kolom, err)
+	}
+	return string(plaintext), nil
+}
+
+// normalisasiEmail bentuk email yang dibandingkan untuk blind index
+func normalisasiEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalisasiTelepon hanya digit dengan awalan 62 menjadi 0; kosong jika kurang dari 8 digit
+func normalisasiTelepon(telepon string) string {
+	var b strings.Builder
+	for _, r := range telepon {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	digits := b.String()
+	if strings.HasPrefix(digits, "62") {
+		digits = "0" + digits[2:]
+	}
+	if len(digits) < 8 {
+		return ""
+	}
+	return digits
+}
+
+// blindIndexPII HMAC nilai yang sudah dinormalisasi; nil (NULL) untuk nilai kosong
+func blindIndexPII(db DBTX, kolom, normal string) (interface{}, error) {
+	if normal == "" {
+		return nil, nil
+	}
+	k, err := getPIIKeyring(db, false)
+	if err != nil {
+		return nil, err
+	}
+	return utils.BlindIndex(k.indexKey, kolom, normal), nil
+}
+
+// BlindIndexEmail blind index email (sama dengan alumni.email_bidx) untuk mencatat email tanpa menyimpan
+// plaintext-nya, misalnya di audit log percobaan login
+func BlindIndexEmail(db DBTX, email string) (string, error) {
+	bidx, err := blindIndexPII(db, kolomPIIEmail, normalisasiEmail(email))
+	if err != nil || bidx == nil {
+		return "", err
+	}
+	return bidx.(string), nil
+}
+
+// alumniPII nilai kolom PII alumni yang siap disimpan
+type alumniPII struct {
+	email, noTelepon, alamat string
+	emailBidx, teleponBidx   interface{}
+}
+
+func encryptAlumniPII(db DBTX, email, noTelepon, alamat string) (alumniPII, error) {
+	var p alumniPII
+	var err error
+	if p.email, err = encryptPII(db, kolomPIIEmail, email); err != nil {
+		return p, err
+	}
+	if p.noTelepon, err = encryptPII(db, kolomPIITelepon, noTelepon); err != nil {
+		return p, err
+	}
+	if p.alamat, err = encryptPII(db, kolomPIIAlamat, alamat); err != nil {
+		return p, err
+	}
+	if p.emailBidx, err = blindIndexPII(db, kolomPIIEmail, normalisasiEmail(email)); err != nil {
+		return p, err
+	}
+	p.teleponBidx, err = blindIndexPII(db, kolomPIITelepon, normalisasiTelepon(noTelepon))
+	return p, err
+}
+
+// decryptAlumniPII membuka email, nomor telepon, dan alamat hasil scan
+func decryptAlumniPII(db DBTX, email, noTelepon, alamat *string) error {
+	var err error
+	if *email, err = decryptPII(db, kolomPIIEmail, *email); err != nil {
+		return err
+	}
+	if *noTelepon, err = decryptPII(db, kolomPIITelepon, *noTelepon); err != nil {
+		return err
+	}
+	*alamat, err = decryptPII(db, kolomPIIAlamat, *alamat)
+	return err
+}
+
+// piiDataKolom kolom PII alumni berdasarkan key JSON-nya, untuk salinan data alumni di revisi dan
+// snapshot merge alumni
+var piiDataKolom = map[string]string{"email": kolomPIIEmail, "no_telepon": kolomPIITelepon, "alamat": kolomPIIAlamat}
+
+// encryptPIIData mengenkripsi nilai PII di data JSON alumni (di tempat)
+func encryptPIIData(db DBTX, data map[string]interface{}) error {
+	for key, kolom := range piiDataKolom {
+		if v, ok := data[key].(string); ok {
+			enc, err := encryptPII(db, kolom, v)
+			if err != nil {
+				return err
+			}
+			data[key] = enc
+		}
+	}
+	return nil
+}
+
+// decryptPIIData membuka nilai PII di data JSON alumni (di tempat)
+func decryptPIIData(db DBTX, data map[string]interface{}) error {
+	for key, kolom := range piiDataKolom {
+		if v, ok := data[key].(string); ok {
+			dec, err := decryptPII(db, kolom, v)
+			if err != nil {
+				return err
+			}
+			data[key] = dec
+		}
+	}
+	return nil
+}
+
+// setAlumniField mengubah satu kolom alumni; kolom PII dienkripsi dan blind index-nya ikut diperbarui
+func setAlumniField(db DBTX, id int, field string, nilai interface{}, now time.Time) error {
+	var err error
+	switch field {
+	case "no_telepon":
+		telepon, _ := nilai.(string)
+		var enc string
+		var bidx interface{}
+		if enc, err = encryptPII(db, kolomPIITelepon, telepon); err == nil {
+			if bidx, err = blindIndexPII(db, kolomPIITelepon, normalisasiTelepon(telepon)); err == nil {
+				_, err = db.Exec(`UPDATE alumni SET no_telepon = $1, telepon_bidx = $2, updated_at = $3 WHERE id = $4`, enc, bidx, now, id)
+			}
+		}
+	case "alamat":
+		alamat, _ := nilai.(string)
+		var enc string
+		if enc, err = encryptPII(db, kolomPIIAlamat, alamat); err == nil {
+			_, err = db.Exec(`UPDATE alumni SET alamat = $1, updated_at = $2 WHERE id = $3`, enc, now, id)
+		}
+	case "email":
+		email, _ := nilai.(string)
+		var enc string
+		var bidx interface{}
+		if enc, err = encryptPII(db, kolomPIIEmail, email); err == nil {
+			if bidx, err = blindIndexPII(db, kolomPIIEmail, normalisasiEmail(email)); err == nil {
+				_, err = db.Exec(`UPDATE alumni SET email = $1, email_bidx = $2, updated_at = $3 WHERE id = $4`, enc, bidx, now, id)
+			}
+		}
+	case "jurusan", "angkatan", "tahun_lulus":
+		_, err = db.Exec(`UPDATE alumni SET `+field+` = $1, updated_at = $2 WHERE id = $3`, nilai, now, id)
+	default:
+		return fmt.Errorf("field alumni %q tidak bisa diubah lewat setAlumniField", field)
+	}
+	return err
+}
+
+// KunciPIIRepository operasi rotasi kunci untuk cmd/enkripsi-pii
+type KunciPIIRepository interface {
+	RewrapKunci() (int, error)
+	RotasiKunciData() (int, error)
+	EnkripsiUlangAlumni(batchSize int, dryRun bool) (int, error)
+	EnkripsiUlangSalinan(batchSize int, dryRun bool) (int, error)
+	HapusKunciDataLama() (int, error)
+}
+
+type kunciPIIRepository struct {
+	db *sql.DB
+}
+
+func NewKunciPIIRepository(db *sql.DB) KunciPIIRepository {
+	return &kunciPIIRepository{db: db}
+}
+
+// RewrapKunci membungkus ulang kunci yang masih memakai kunci master lama dengan kunci master aktif
+func (r *kunciPIIRepository) RewrapKunci() (int, error) {
+	master, err := utils.LoadMasterKeys()
+	if err != nil {
+		return 0, err
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id, jenis, master_key_id, kunci_terbungkus FROM kunci_pii
+		WHERE master_key_id <> $1 FOR UPDATE`, master.AktifID)
+	if err != nil {
+		log.Println("Error membaca kunci_pii untuk rewrap:", err)
+		return 0, err
+	}
+	type kunci struct {
+		id      int
+		jenis   string
+		wrapped []byte
+	}
+	var list []kunci
+	for rows.Next() {
+		var k kunci
+		var masterID string
+		var wrapped []byte
+		if err := rows.Scan(&k.id, &k.jenis, &masterID, &wrapped); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		mk, ok := master.Kunci[masterID]
+		if !ok {
+			rows.Close()
+			return 0, fmt.Errorf("kunci master lama %q untuk kunci_pii %d tidak ada di PII_MASTER_KEY_LAMA", masterID, k.id)
+		}
+		key, err := utils.Open(mk, wrapped, aadKunciPII(k.jenis))
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("gagal membuka kunci_pii %d: %w", k.id, err)
+		}
+		if k.wrapped, err = bungkusKunci(master, k.jenis, key); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		list = append(list, k)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, k := range list {
+		_, err := tx.Exec(`UPDATE kunci_pii SET master_key_id = $1, kunci_terbungkus = $2, rewrapped_at = NOW() WHERE id = $3`,
+			master.AktifID, k.wrapped, k.id)
+		if err != nil {
+			log.Println("Error menyimpan kunci_pii hasil rewrap:", err)
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	_, err = getPIIKeyring(r.db, true)
+	return len(list), err
+}
+
+// RotasiKunciData membuat kunci data aktif baru; kunci lama tetap disimpan untuk membuka data lama
+func (r *kunciPIIRepository) RotasiKunciData() (int, error) {
+	master, err := utils.LoadMasterKeys()
+	if err != nil {
+		return 0, err
+	}
+	key, err := utils.KunciAcak()
+	if err != nil {
+		return 0, err
+	}
+	wrapped, err := bungkusKunci(master, kunciJenisData, key)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`UPDATE kunci_pii SET aktif = FALSE WHERE jenis = $1 AND aktif`, kunciJenisData); err != nil {
+		log.Println("Error menonaktifkan kunci data lama:", err)
+		return 0, err
+	}
+	var id int
+	err = tx.QueryRow(`INSERT INTO kunci_pii (jenis, master_key_id, kunci_terbungkus) VALUES ($1, $2, $3) RETURNING id`,
+		kunciJenisData, master.AktifID, wrapped).Scan(&id)
+	if err != nil {
+		log.Println("Error membuat kunci data baru:", err)
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	_, err = getPIIKeyring(r.db, true)
+	return id, err
+}
+
+// EnkripsiUlangAlumni mengenkripsi plaintext lama dan nilai yang memakai kunci data non-aktif dengan kunci
+// data aktif, serta mengisi blind index. Diproses per batch dalam transaksi masing-masing; mengembalikan
+// jumlah alumni yang diubah (atau yang perlu diubah jika dryRun).
+func (r *kunciPIIRepository) EnkripsiUlangAlumni(batchSize int, dryRun bool) (int, error) {
+	k, err := getPIIKeyring(r.db, true)
+	if err != nil {
+		return 0, err
+	}
+	perluUlang := func(v string) bool {
+		return v != "" && kunciIDPII(v) != k.aktifData
+	}
+
+	total, lastID := 0, 0
+	for {
+		tx, err := r.db.Begin()
+		if err != nil {
+			return total, err
+		}
+		rows, err := tx.Query(`SELECT id, COALESCE(email, ''), COALESCE(no_telepon, ''), COALESCE(alamat, ''),
+			email_bidx IS NULL AND COALESCE(email, '') <> ''
+			FROM alumni WHERE id > $1 ORDER BY id LIMIT $2 FOR UPDATE`, lastID, batchSize)
+		if err != nil {
+			tx.Rollback()
+			log.Println("Error membaca alumni untuk enkripsi ulang:", err)
+			return total, err
+		}
+		type baris struct {
+			id                       int
+			email, telepon, alamat   string
+			tanpaIndeks, perluDiubah bool
+		}
+		var batch []baris
+		for rows.Next() {
+			var b baris
+			if err := rows.Scan(&b.id, &b.email, &b.telepon, &b.alamat, &b.tanpaIndeks); err != nil {
+				rows.Close()
+				tx.Rollback()
+				return total, err
+			}
+			b.perluDiubah = b.tanpaIndeks || perluUlang(b.email) || perluUlang(b.telepon) || perluUlang(b.alamat)
+			batch = append(batch, b)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			tx.Rollback()
+			return total, err
+		}
+		if len(batch) == 0 {
+			tx.Rollback()
+			return total, nil
+		}
+
+		for _, b := range batch {
+			lastID = b.id
+			if !b.perluDiubah {
+				continue
+			}
+			total++
+			if dryRun {
+				continue
+			}
+			if err := decryptAlumniPII(tx, &b.email, &b.telepon, &b.alamat); err != nil {
+				tx.Rollback()
+				return total, fmt.Errorf("alumni %d: %w", b.id, err)
+			}
+			p, err := encryptAlumniPII(tx, b.email, b.telepon, b.alamat)
+			if err != nil {
+				tx.Rollback()
+				return total, err
+			}
+			_, err = tx.Exec(`UPDATE alumni SET email = $1, no_telepon = $2, alamat = $3, email_bidx = $4, telepon_bidx = $5
+				WHERE id = $6`, p.email, p.noTelepon, p.alamat, p.emailBidx, p.teleponBidx, b.id)
+			if err != nil {
+				tx.Rollback()
+				log.Println("Error menyimpan alumni hasil enkripsi ulang:", err)
+				// email_bidx unik di antara alumni aktif: email ganda harus digabung dulu lewat merge alumni
+				return total, fmt.Errorf("alumni %d: %w", b.id, err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// EnkripsiUlangSalinan mengenkripsi (ulang) salinan PII alumni di revisi dan alumni_merge.snapshot yang masih
+// plaintext atau memakai kunci data non-aktif. Mengembalikan jumlah baris yang diubah (atau perlu diubah).
+func (r *kunciPIIRepository) EnkripsiUlangSalinan(batchSize int, dryRun bool) (int, error) {
+	revisi, err := r.enkripsiUlangJSON(
+		`SELECT id, data FROM revisi WHERE entitas = 'alumni' AND id > $1 ORDER BY id LIMIT $2 FOR UPDATE`,
+		`UPDATE revisi SET data = $1 WHERE id = $2`,
+		func(doc map[string]interface{}) []map[string]interface{} { return []map[string]interface{}{doc} },
+		batchSize, dryRun)
+	if err != nil {
+		return revisi, err
+	}
+	merge, err := r.enkripsiUlangJSON(
+		`SELECT id, snapshot FROM alumni_merge WHERE id > $1 ORDER BY id LIMIT $2 FOR UPDATE`,
+		`UPDATE alumni_merge SET snapshot = $1 WHERE id = $2`,
+		func(doc map[string]interface{}) []map[string]interface{} {
+			var bagian []map[string]interface{}
+			for _, key := range []string{"survivor_sebelum", "duplikat"} {
+				if m, ok := doc[key].(map[string]interface{}); ok {
+					bagian = append(bagian, m)
+				}
+			}
+			return bagian
+		},
+		batchSize, dryRun)
+	return revisi + merge, err
+}
+
+// enkripsiUlangJSON memproses kolom JSON per batch; bagian memilih objek alumni di dalam dokumen
+func (r *kunciPIIRepository) enkripsiUlangJSON(query, update string, bagian func(map[string]interface{}) []map[string]interface{}, batchSize int, dryRun bool) (int, error) {
+	k, err := getPIIKeyring(r.db, true)
+	if err != nil {
+		return 0, err
+	}
+
+	total, lastID := 0, 0
+	for {
+		tx, err := r.db.Begin()
+		if err != nil {
+			return total, err
+		}
+		rows, err := tx.Query(query, lastID, batchSize)
+		if err != nil {
+			tx.Rollback()
+			log.Println("Error membaca salinan PII untuk enkripsi ulang:", err)
+			return total, err
+		}
+		type baris struct {
+			id  int
+			doc map[string]interface{}
+		}
+		var batch []baris
+		for rows.Next() {
+			var b baris
+			var raw []byte
+			if err := rows.Scan(&b.id, &raw); err != nil {
+				rows.Close()
+				tx.Rollback()
+				return total, err
+			}
+			if err := json.Unmarshal(raw, &b.doc); err != nil {
+				rows.Close()
+				tx.Rollback()
+				return total, fmt.Errorf("baris %d: %w", b.id, err)
+			}
+			batch = append(batch, b)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			tx.Rollback()
+			return total, err
+		}
+		if len(batch) == 0 {
+			tx.Rollback()
+			return total, nil
+		}
+
+		for _, b := range batch {
+			lastID = b.id
+			perlu := false
+			for _, data := range bagian(b.doc) {
+				for key := range piiDataKolom {
+					if v, ok := data[key].(string); ok && v != "" && kunciIDPII(v) != k.aktifData {
+						perlu = true
+					}
+				}
+			}
+			if !perlu {
+				continue
+			}
+			total++
+			if dryRun {
+				continue
+			}
+			for _, data := range bagian(b.doc) {
+				if err := decryptPIIData(tx, data); err != nil {
+					tx.Rollback()
+					return total, fmt.Errorf("baris %d: %w", b.id, err)
+				}
+				if err := encryptPIIData(tx, data); err != nil {
+					tx.Rollback()
+					return total, err
+				}
+			}
+			raw, err := json.Marshal(b.doc)
+			if err == nil {
+				_, err = tx.Exec(update, raw, b.id)
+			}
+			if err != nil {
+				tx.Rollback()
+				log.Println("Error menyimpan salinan PII hasil enkripsi ulang:", err)
+				return total, err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// HapusKunciDataLama menghapus kunci data non-aktif yang sudah tidak dipakai nilai mana pun
+func (r *kunciPIIRepository) HapusKunciDataLama() (int, error) {
+	result, err := r.db.Exec(`DELETE FROM kunci_pii k
+		WHERE k.jenis = $1 AND NOT k.aktif AND NOT EXISTS (
+			SELECT 1 FROM alumni a
+			WHERE a.email LIKE 'pii:' || k.id || ':%' OR a.no_telepon LIKE 'pii:' || k.id || ':%'
+			   OR a.alamat LIKE 'pii:' || k.id || ':%')
+		  AND NOT EXISTS (SELECT 1 FROM revisi v WHERE v.data::text LIKE '%"pii:' || k.id || ':%')
+		  AND NOT EXISTS (SELECT 1 FROM alumni_merge m WHERE m.snapshot::text LIKE '%"pii:' || k.id || ':%')`, kunciJenisData)
+	if err != nil {
+		log.Println("Error menghapus kunci data lama:", err)
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"hello-fiber/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pasangKeyringTest memasang keyring di memori agar helper PII bisa diuji tanpa database
+func pasangKeyringTest(t *testing.T, aktif int, ids ...int) *piiKeyring {
+	t.Helper()
+	// reload keyring (kunci data tidak dikenal) gagal lebih dulu di kunci master, sebelum menyentuh database
+	t.Setenv("PII_MASTER_KEY", "")
+	t.Setenv("PII_MASTER_KEY_FILE", "")
+
+	k := &piiKeyring{dataKeys: map[int][]byte{}, aktifData: aktif, dimuatAt: time.Now()}
+	for _, id := range ids {
+		key, err := utils.KunciAcak()
+		if err != nil {
+			t.Fatal(err)
+		}
+		k.dataKeys[id] = key
+	}
+	var err error
+	if k.indexKey, err = utils.KunciAcak(); err != nil {
+		t.Fatal(err)
+	}
+
+	piiMu.Lock()
+	lama := piiKeys
+	piiKeys = k
+	piiMu.Unlock()
+	t.Cleanup(func() {
+		piiMu.Lock()
+		piiKeys = lama
+		piiMu.Unlock()
+	})
+	return k
+}
+
+func TestEncryptDecryptPII(t *testing.T) {
+	pasangKeyringTest(t, 1, 1)
+
+	enc, err := encryptPII(nil, kolomPIIEmail, "budi@example.com")
+	if err != nil {
+		t.Fatalf("encryptPII: %v", err)
+	}
+	if !strings.HasPrefix(enc, "pii:1:") || strings.Contains(enc, "budi") {
+		t.Fatalf("nilai terenkripsi %q", enc)
+	}
+	dec, err := decryptPII(nil, kolomPIIEmail, enc)
+	if err != nil || dec != "budi@example.com" {
+		t.Fatalf("decryptPII = %q, %v", dec, err)
+	}
+
+	if _, err := decryptPII(nil, kolomPIIAlamat, enc); err == nil {
+		t.Error("nilai kolom email tidak boleh bisa dibuka sebagai alamat")
+	}
+	if enc, _ := encryptPII(nil, kolomPIIEmail, ""); enc != "" {
+		t.Errorf("string kosong harus tetap kosong, dapat %q", enc)
+	}
+	// data lama dan hasil anonimisasi lama masih plaintext
+	if dec, err := decryptPII(nil, kolomPIIEmail, "lama@example.com"); err != nil || dec != "lama@example.com" {
+		t.Errorf("plaintext lama = %q, %v", dec, err)
+	}
+	for _, rusak := range []string{"pii:x:abc", "pii:1", "pii:1:%%%"} {
+		if _, err := decryptPII(nil, kolomPIIEmail, rusak); err == nil {
+			t.Errorf("decryptPII(%q) harus gagal", rusak)
+		}
+	}
+}
+
+func TestRotasiKunciDataPII(t *testing.T) {
+	k := pasangKeyringTest(t, 1, 1)
+	lama, err := encryptPII(nil, kolomPIITelepon, "081234567890")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// kunci data baru menjadi aktif; nilai lama tetap terbuka dengan kunci lama
+	baru, err := utils.KunciAcak()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.dataKeys[2], k.aktifData = baru, 2
+	enc, err := encryptPII(nil, kolomPIITelepon, "081234567890")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kunciIDPII(enc) != 2 || kunciIDPII(lama) != 1 || kunciIDPII("081234567890") != 0 {
+		t.Fatalf("kunciIDPII baru=%d lama=%d", kunciIDPII(enc), kunciIDPII(lama))
+	}
+	for _, v := range []string{lama, enc} {
+		if dec, err := decryptPII(nil, kolomPIITelepon, v); err != nil || dec != "081234567890" {
+			t.Errorf("decryptPII(%q) = %q, %v", v, dec, err)
+		}
+	}
+
+	// kunci lama yang sudah dihapus tidak bisa dipakai lagi
+	delete(k.dataKeys, 1)
+	if _, err := decryptPII(nil, kolomPIITelepon, lama); err == nil {
+		t.Error("nilai dengan kunci data yang tidak ada harus gagal dibuka")
+	}
+}
+
+func TestBlindIndexPII(t *testing.T) {
+	pasangKeyringTest(t, 1, 1)
+
+	a, err := blindIndexPII(nil, kolomPIIEmail, normalisasiEmail(" Budi@Example.COM "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := blindIndexPII(nil, kolomPIIEmail, normalisasiEmail("budi@example.com"))
+	if a == nil || a != b {
+		t.Fatalf("email yang sama setelah normalisasi harus punya blind index sama: %v vs %v", a, b)
+	}
+	if c, _ := blindIndexPII(nil, kolomPIITelepon, normalisasiEmail("budi@example.com")); c == a {
+		t.Error("blind index kolom berbeda tidak boleh sama")
+	}
+	if kosong, _ := blindIndexPII(nil, kolomPIIEmail, ""); kosong != nil {
+		t.Errorf("nilai kosong harus NULL, dapat %v", kosong)
+	}
+
+	ekspor, err := BlindIndexEmail(nil, "BUDI@example.com")
+	if err != nil || ekspor != a {
+		t.Errorf("BlindIndexEmail = %q, %v; ingin sama dengan email_bidx %v", ekspor, err, a)
+	}
+}
+
+func TestNormalisasiTelepon(t *testing.T) {
+	kasus := map[string]string{
+		"0812-3456-7890":   "081234567890",
+		"+62 812 3456 789": "08123456789",
+		"(021) 555 1234":   "0215551234",
+		"12345":            "",
+		"":                 "",
+	}
+	for input, ingin := range kasus {
+		if got := normalisasiTelepon(input); got != ingin {
+			t.Errorf("normalisasiTelepon(%q) = %q, ingin %q", input, got, ingin)
+		}
+	}
+}
+
+func TestAlumniPIIRoundTrip(t *testing.T) {
+	pasangKeyringTest(t, 1, 1)
+
+	p, err := encryptAlumniPII(nil, "Budi@Example.com", "+62 812 3456 7890", "Jl. Merdeka 1")
+	if err != nil {
+		t.Fatalf("encryptAlumniPII: %v", err)
+	}
+	if p.emailBidx == nil || p.teleponBidx == nil {
+		t.Fatal("blind index email dan telepon harus terisi")
+	}
+	email, telepon, alamat := p.email, p.noTelepon, p.alamat
+	if err := decryptAlumniPII(nil, &email, &telepon, &alamat); err != nil {
+		t.Fatalf("decryptAlumniPII: %v", err)
+	}
+	if email != "Budi@Example.com" || telepon != "+62 812 3456 7890" || alamat != "Jl. Merdeka 1" {
+		t.Errorf("hasil dekripsi %q %q %q", email, telepon, alamat)
+	}
+}
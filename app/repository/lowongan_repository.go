@@ -0,0 +1,485 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"hello-fiber/app/model"
+	"log"
+	"strings"
+	"time"
+)
+
+type LowonganRepository interface {
+	GetLowonganWithPagination(filter model.LowonganFilter, sortBy, order string, limit, offset int) ([]model.Lowongan, error)
+	CountLowongan(filter model.LowonganFilter) (int, error)
+	GetByID(id, alumniID int) (model.Lowongan, error)
+	Create(req model.LowonganRequest, actorID int) (model.Lowongan, error)
+	Update(id int, req model.LowonganRequest) (model.Lowongan, error)
+	UpdateStatus(id int, status string) (model.Lowongan, error)
+	Delete(id int) error
+	CountLamaran(id int) (int, error)
+	Simpan(id, alumniID int) error
+	HapusSimpan(id, alumniID int) error
+	ExpireLewatBatas() (int64, error)
+	Lamar(id, alumniID int, catatan string) (model.LamaranLowongan, error)
+	GetLamaranByLowongan(id int) ([]model.LamaranLowongan, error)
+	GetLamaranByAlumni(alumniID int) ([]model.LamaranLowongan, error)
+	GetLamaranByID(id int) (model.LamaranLowongan, error)
+	UpdateStatusLamaran(lowonganID, id int, status, catatan string) (model.LamaranLowongan, error)
+	BatalkanLamaran(alumniID, id int) (model.LamaranLowongan, error)
+}
+
+var (
+	// ErrLowonganDitutup lowongan sudah ditutup atau melewati batas lamaran
+	ErrLowonganDitutup = errors.New("lowongan sudah tidak menerima lamaran")
+	// ErrSudahMelamar alumni sudah memiliki lamaran aktif di lowongan yang sama
+	ErrSudahMelamar = errors.New("alumni sudah melamar lowongan ini")
+	// ErrLamaranFinal lamaran yang sudah diterima, ditolak, atau dibatalkan tidak dapat diubah
+	ErrLamaranFinal = errors.New("lamaran sudah final")
+)
+
+type lowonganRepository struct {
+	db *sql.DB
+}
+
+func NewLowonganRepository(db *sql.DB) LowonganRepository {
+	return &lowonganRepository{db: db}
+}
+
+// statusLowonganSQL status efektif lowongan: lowongan dibuka yang sudah lewat batas_lamaran dianggap
+// kedaluwarsa walaupun scheduler belum sempat mengubah kolom status
+const statusLowonganSQL = `CASE WHEN l.status = 'dibuka' AND l.batas_lamaran < CURRENT_DATE THEN 'kedaluwarsa' ELSE l.status END`
+
+// lowonganColumns kolom lowongan (alias l) dan perusahaan (alias p); pemirsa placeholder alumni_id user yang
+// melihat (0 jika bukan alumni) untuk mengisi Disimpan dan StatusLamaran
+func lowonganColumns(pemirsa string) string {
+	return `l.id, l.perusahaan_id, p.nama, l.posisi, l.deskripsi, l.bidang_industri, l.lokasi,
+	l.gaji_min, l.gaji_max, l.gaji_mata_uang, l.gaji_periode, l.batas_lamaran, ` + statusLowonganSQL + `,
+	COALESCE(l.dibuat_oleh, 0), l.ditutup_at, l.created_at, l.updated_at,
+	(SELECT COUNT(*) FROM lamaran_lowongan m WHERE m.lowongan_id = l.id AND m.status <> 'dibatalkan'),
+	EXISTS (SELECT 1 FROM lowongan_simpan s WHERE s.lowongan_id = l.id AND s.alumni_id = ` + pemirsa + `),
+	COALESCE((SELECT m.status FROM lamaran_lowongan m WHERE m.lowongan_id = l.id AND m.alumni_id = ` + pemirsa + `), '')`
+}
+
+const lowonganFrom = `FROM lowongan l JOIN perusahaan p ON p.id = l.perusahaan_id`
+
+func scanLowongan(scanner interface{ Scan(...interface{}) error }) (model.Lowongan, error) {
+	var l model.Lowongan
+	var gajiMataUang, gajiPeriode sql.NullString
+	var gajiMin, gajiMax sql.NullInt64
+	var ditutupAt sql.NullTime
+
+	err := scanner.Scan(
+		&l.ID, &l.PerusahaanID, &l.NamaPerusahaan, &l.Posisi, &l.Deskripsi, &l.BidangIndustri, &l.Lokasi,
+		&gajiMin, &gajiMax, &gajiMataUang, &gajiPeriode, &l.BatasLamaran, &l.Status,
+		&l.DibuatOleh, &ditutupAt, &l.CreatedAt, &l.UpdatedAt,
+		&l.JumlahPelamar, &l.Disimpan, &l.StatusLamaran,
+	)
+	if gajiMin.Valid || gajiMax.Valid {
+		l.Gaji = &model.GajiRange{
+			Min:      gajiMin.Int64,
+			Max:      gajiMax.Int64,
+			MataUang: gajiMataUang.String,
+			Periode:  gajiPeriode.String,
+		}
+	}
+	if ditutupAt.Valid {
+		l.DitutupAt = &ditutupAt.Time
+	}
+	return l, err
+}
+
+// lowonganFilterCondition menyusun kondisi WHERE dari LowonganFilter
+func lowonganFilterCondition(filter model.LowonganFilter) (string, []interface{}) {
+	args := []interface{}{"%" + filter.Search + "%"}
+	conditions := []string{"(l.posisi ILIKE $1 OR l.deskripsi ILIKE $1 OR p.nama ILIKE $1)"}
+
+	switch filter.Status {
+	case "":
+		conditions = append(conditions, statusLowonganSQL+" = 'dibuka'")
+	case "semua":
+	default:
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", statusLowonganSQL, len(args)))
+	}
+	if filter.BidangIndustri != "" {
+		args = append(args, filter.BidangIndustri)
+		conditions = append(conditions, fmt.Sprintf("l.bidang_industri ILIKE $%d", len(args)))
+	}
+	if filter.Lokasi != "" {
+		args = append(args, "%"+filter.Lokasi+"%")
+		conditions = append(conditions, fmt.Sprintf("l.lokasi ILIKE $%d", len(args)))
+	}
+	if filter.PerusahaanID != 0 {
+		args = append(args, filter.PerusahaanID)
+		conditions = append(conditions, fmt.Sprintf("l.perusahaan_id = $%d", len(args)))
+	}
+	if filter.GajiMin != 0 {
+		args = append(args, filter.GajiMin)
+		conditions = append(conditions, fmt.Sprintf("l.gaji_min IS NOT NULL AND (l.gaji_max IS NULL OR l.gaji_max >= $%d)", len(args)))
+	}
+	if filter.GajiMax != 0 {
+		args = append(args, filter.GajiMax)
+		conditions = append(conditions, fmt.Sprintf("l.gaji_min <= $%d", len(args)))
+	}
+	if filter.MataUang != "" {
+		args = append(args, filter.MataUang)
+		conditions = append(conditions, fmt.Sprintf("l.gaji_mata_uang = $%d", len(args)))
+	}
+	if filter.DisimpanSaja {
+		args = append(args, filter.AlumniID)
+		conditions = append(conditions, fmt.Sprintf("EXISTS (SELECT 1 FROM lowongan_simpan s WHERE s.lowongan_id = l.id AND s.alumni_id = $%d)", len(args)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *lowonganRepository) GetLowonganWithPagination(filter model.LowonganFilter, sortBy, order string, limit, offset int) ([]model.Lowongan, error) {
+	validSortColumns := map[string]string{
+		"id": "l.id", "posisi": "l.posisi", "nama_perusahaan": "p.nama", "batas_lamaran": "l.batas_lamaran",
+		"gaji_min": "l.gaji_min", "created_at": "l.created_at", "updated_at": "l.updated_at",
+	}
+	sortExpr, ok := validSortColumns[sortBy]
+	if !ok {
+		sortExpr = "l.id"
+	}
+
+	where, args := lowonganFilterCondition(filter)
+	args = append(args, filter.AlumniID, limit, offset)
+	query := fmt.Sprintf(`SELECT %s
+		%s
+		WHERE %s
+		ORDER BY %s %s NULLS LAST, l.id
+		LIMIT $%d OFFSET $%d
+	`, lowonganColumns(fmt.Sprintf("$%d", len(args)-2)), lowonganFrom, where, sortExpr, order, len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Println("Error men-query lowongan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.Lowongan{}
+	for rows.Next() {
+		l, err := scanLowongan(rows)
+		if err != nil {
+			log.Println("Error men-scan lowongan:", err)
+			return nil, err
+		}
+		list = append(list, l)
+	}
+	return list, rows.Err()
+}
+
+func (r *lowonganRepository) CountLowongan(filter model.LowonganFilter) (int, error) {
+	var total int
+	where, args := lowonganFilterCondition(filter)
+	err := r.db.QueryRow(`SELECT COUNT(*) `+lowonganFrom+` WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung lowongan:", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetByID mengambil lowongan apa pun statusnya; alumniID mengisi Disimpan dan StatusLamaran
+func (r *lowonganRepository) GetByID(id, alumniID int) (model.Lowongan, error) {
+	l, err := scanLowongan(r.db.QueryRow(`SELECT `+lowonganColumns("$1")+` `+lowonganFrom+` WHERE l.id = $2`, alumniID, id))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil lowongan:", err)
+	}
+	return l, err
+}
+
+// Create memasang lowongan baru dengan status dibuka. Perusahaan dipilih dari perusahaan_id atau dicari/dibuat
+// dari nama_perusahaan seperti pada pekerjaan alumni.
+func (r *lowonganRepository) Create(req model.LowonganRequest, actorID int) (model.Lowongan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi lowongan:", err)
+		return model.Lowongan{}, err
+	}
+	defer tx.Rollback()
+
+	perusahaanID, _, err := resolvePerusahaan(tx, req.PerusahaanID, req.NamaPerusahaan, req.BidangIndustri, req.Lokasi)
+	if err != nil {
+		return model.Lowongan{}, err
+	}
+
+	now := time.Now()
+	gajiMin, gajiMax, gajiMataUang, gajiPeriode := gajiArgs(req.Gaji)
+	var id int
+	err = tx.QueryRow(`INSERT INTO lowongan (perusahaan_id, posisi, deskripsi, bidang_industri, lokasi,
+			gaji_min, gaji_max, gaji_mata_uang, gaji_periode, batas_lamaran, status, dibuat_oleh, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13) RETURNING id`,
+		perusahaanID, req.Posisi, req.Deskripsi, req.BidangIndustri, req.Lokasi,
+		gajiMin, gajiMax, gajiMataUang, gajiPeriode, req.BatasLamaran, model.StatusLowonganDibuka, nullableInt(actorID), now,
+	).Scan(&id)
+	if err != nil {
+		log.Println("Error menyimpan lowongan:", err)
+		return model.Lowongan{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Lowongan{}, err
+	}
+	return r.GetByID(id, 0)
+}
+
+// Update mengubah isi lowongan. Lowongan kedaluwarsa yang batas lamarannya diperpanjang dibuka kembali.
+func (r *lowonganRepository) Update(id int, req model.LowonganRequest) (model.Lowongan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi lowongan:", err)
+		return model.Lowongan{}, err
+	}
+	defer tx.Rollback()
+
+	perusahaanID, _, err := resolvePerusahaan(tx, req.PerusahaanID, req.NamaPerusahaan, req.BidangIndustri, req.Lokasi)
+	if err != nil {
+		return model.Lowongan{}, err
+	}
+
+	gajiMin, gajiMax, gajiMataUang, gajiPeriode := gajiArgs(req.Gaji)
+	result, err := tx.Exec(`UPDATE lowongan
+		SET perusahaan_id = $1, posisi = $2, deskripsi = $3, bidang_industri = $4, lokasi = $5,
+		    gaji_min = $6, gaji_max = $7, gaji_mata_uang = $8, gaji_periode = $9, batas_lamaran = $10,
+		    status = CASE WHEN status = 'kedaluwarsa' AND $10::date >= CURRENT_DATE THEN 'dibuka' ELSE status END,
+		    ditutup_at = CASE WHEN status = 'kedaluwarsa' AND $10::date >= CURRENT_DATE THEN NULL ELSE ditutup_at END,
+		    updated_at = $11
+		WHERE id = $12`,
+		perusahaanID, req.Posisi, req.Deskripsi, req.BidangIndustri, req.Lokasi,
+		gajiMin, gajiMax, gajiMataUang, gajiPeriode, req.BatasLamaran, time.Now(), id,
+	)
+	if err != nil {
+		log.Println("Error mengupdate lowongan:", err)
+		return model.Lowongan{}, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return model.Lowongan{}, sql.ErrNoRows
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Lowongan{}, err
+	}
+	return r.GetByID(id, 0)
+}
+
+// UpdateStatus menutup atau membuka kembali lowongan; ditutup_at dicatat saat lowongan ditutup
+func (r *lowonganRepository) UpdateStatus(id int, status string) (model.Lowongan, error) {
+	now := time.Now()
+	var ditutupAt interface{}
+	if status != model.StatusLowonganDibuka {
+		ditutupAt = now
+	}
+	result, err := r.db.Exec(`UPDATE lowongan SET status = $1, ditutup_at = $2, updated_at = $3 WHERE id = $4`,
+		status, ditutupAt, now, id)
+	if err != nil {
+		log.Println("Error mengubah status lowongan:", err)
+		return model.Lowongan{}, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return model.Lowongan{}, sql.ErrNoRows
+	}
+	return r.GetByID(id, 0)
+}
+
+func (r *lowonganRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM lowongan WHERE id = $1`, id)
+	if err != nil {
+		log.Println("Error menghapus lowongan:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountLamaran menghitung seluruh lamaran lowongan, termasuk yang dibatalkan
+func (r *lowonganRepository) CountLamaran(id int) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM lamaran_lowongan WHERE lowongan_id = $1`, id).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung lamaran lowongan:", err)
+	}
+	return total, err
+}
+
+func (r *lowonganRepository) Simpan(id, alumniID int) error {
+	_, err := r.db.Exec(`INSERT INTO lowongan_simpan (lowongan_id, alumni_id, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`, id, alumniID, time.Now())
+	if err != nil {
+		log.Println("Error menyimpan bookmark lowongan:", err)
+	}
+	return err
+}
+
+func (r *lowonganRepository) HapusSimpan(id, alumniID int) error {
+	result, err := r.db.Exec(`DELETE FROM lowongan_simpan WHERE lowongan_id = $1 AND alumni_id = $2`, id, alumniID)
+	if err != nil {
+		log.Println("Error menghapus bookmark lowongan:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ExpireLewatBatas menandai kedaluwarsa lowongan dibuka yang batas lamarannya sudah lewat
+func (r *lowonganRepository) ExpireLewatBatas() (int64, error) {
+	now := time.Now()
+	result, err := r.db.Exec(`UPDATE lowongan SET status = 'kedaluwarsa', ditutup_at = $1, updated_at = $1
+		WHERE status = 'dibuka' AND batas_lamaran < CURRENT_DATE`, now)
+	if err != nil {
+		log.Println("Error menandai lowongan kedaluwarsa:", err)
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// lamaranColumns kolom lamaran (alias m) beserta lowongan (l), perusahaan (p), dan alumni (a);
+// email dan no_telepon alumni masih terenkripsi
+const lamaranColumns = `m.id, m.lowongan_id, l.posisi, p.nama, m.alumni_id, a.nama, a.jurusan, a.tahun_lulus,
+	COALESCE(a.email, ''), COALESCE(a.no_telepon, ''), m.status, m.catatan_pelamar, m.catatan_perekrut,
+	m.created_at, m.updated_at`
+
+const lamaranFrom = `FROM lamaran_lowongan m
+	JOIN lowongan l ON l.id = m.lowongan_id
+	JOIN perusahaan p ON p.id = l.perusahaan_id
+	JOIN alumni a ON a.id = m.alumni_id`
+
+func (r *lowonganRepository) scanLamaran(scanner interface{ Scan(...interface{}) error }) (model.LamaranLowongan, error) {
+	var m model.LamaranLowongan
+	err := scanner.Scan(
+		&m.ID, &m.LowonganID, &m.Posisi, &m.NamaPerusahaan, &m.AlumniID, &m.NamaAlumni, &m.Jurusan, &m.TahunLulus,
+		&m.Email, &m.NoTelepon, &m.Status, &m.CatatanPelamar, &m.CatatanPerekrut,
+		&m.CreatedAt, &m.UpdatedAt,
+	)
+	if err != nil {
+		return m, err
+	}
+	if m.Email, err = decryptPII(r.db, kolomPIIEmail, m.Email); err != nil {
+		return m, err
+	}
+	m.NoTelepon, err = decryptPII(r.db, kolomPIITelepon, m.NoTelepon)
+	return m, err
+}
+
+func (r *lowonganRepository) queryLamaran(query string, args ...interface{}) ([]model.LamaranLowongan, error) {
+	rows, err := r.db.Query(`SELECT `+lamaranColumns+` `+lamaranFrom+` `+query, args...)
+	if err != nil {
+		log.Println("Error men-query lamaran lowongan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.LamaranLowongan{}
+	for rows.Next() {
+		m, err := r.scanLamaran(rows)
+		if err != nil {
+			log.Println("Error men-scan lamaran lowongan:", err)
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// Lamar mengirim lamaran alumni. Lamaran yang pernah dibatalkan boleh dikirim ulang.
+func (r *lowonganRepository) Lamar(id, alumniID int, catatan string) (model.LamaranLowongan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi lamaran lowongan:", err)
+		return model.LamaranLowongan{}, err
+	}
+	defer tx.Rollback()
+
+	// FOR SHARE agar lowongan tidak ditutup di tengah pengiriman lamaran
+	var status string
+	err = tx.QueryRow(`SELECT `+statusLowonganSQL+` FROM lowongan l WHERE l.id = $1 FOR SHARE`, id).Scan(&status)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error mengambil status lowongan:", err)
+		}
+		return model.LamaranLowongan{}, err
+	}
+	if status != model.StatusLowonganDibuka {
+		return model.LamaranLowongan{}, ErrLowonganDitutup
+	}
+
+	now := time.Now()
+	var lamaranID int
+	err = tx.QueryRow(`INSERT INTO lamaran_lowongan (lowongan_id, alumni_id, status, catatan_pelamar, created_at, updated_at)
+		VALUES ($1, $2, 'dikirim', $3, $4, $4)
+		ON CONFLICT (lowongan_id, alumni_id) DO UPDATE
+		SET status = 'dikirim', catatan_pelamar = EXCLUDED.catatan_pelamar, catatan_perekrut = '', updated_at = EXCLUDED.updated_at
+		WHERE lamaran_lowongan.status = 'dibatalkan'
+		RETURNING id`, id, alumniID, catatan, now).Scan(&lamaranID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.LamaranLowongan{}, ErrSudahMelamar
+		}
+		log.Println("Error menyimpan lamaran lowongan:", err)
+		return model.LamaranLowongan{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.LamaranLowongan{}, err
+	}
+	return r.GetLamaranByID(lamaranID)
+}
+
+// GetLamaranByLowongan pelamar satu lowongan, yang terbaru lebih dulu
+func (r *lowonganRepository) GetLamaranByLowongan(id int) ([]model.LamaranLowongan, error) {
+	return r.queryLamaran(`WHERE m.lowongan_id = $1 ORDER BY m.created_at DESC, m.id DESC`, id)
+}
+
+// GetLamaranByAlumni riwayat lamaran alumni, yang terbaru lebih dulu
+func (r *lowonganRepository) GetLamaranByAlumni(alumniID int) ([]model.LamaranLowongan, error) {
+	return r.queryLamaran(`WHERE m.alumni_id = $1 ORDER BY m.created_at DESC, m.id DESC`, alumniID)
+}
+
+func (r *lowonganRepository) GetLamaranByID(id int) (model.LamaranLowongan, error) {
+	m, err := r.scanLamaran(r.db.QueryRow(`SELECT `+lamaranColumns+` `+lamaranFrom+` WHERE m.id = $1`, id))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil lamaran lowongan:", err)
+	}
+	return m, err
+}
+
+// ubahStatusLamaran mengubah lamaran yang belum final; lamaran juga harus cocok dengan kolom pemilik
+// (lowongan_id untuk perekrut, alumni_id untuk pelamar)
+func (r *lowonganRepository) ubahStatusLamaran(id int, status, catatan, kolom string, pemilikID int) (model.LamaranLowongan, error) {
+	result, err := r.db.Exec(`UPDATE lamaran_lowongan
+		SET status = $1, catatan_perekrut = COALESCE(NULLIF($2, ''), catatan_perekrut), updated_at = $3
+		WHERE `+kolom+` = $4 AND id = $5 AND status NOT IN ('diterima', 'ditolak', 'dibatalkan')`,
+		status, catatan, time.Now(), pemilikID, id)
+	if err != nil {
+		log.Println("Error mengubah status lamaran lowongan:", err)
+		return model.LamaranLowongan{}, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		var ada bool
+		err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM lamaran_lowongan WHERE `+kolom+` = $1 AND id = $2)`, pemilikID, id).Scan(&ada)
+		if err != nil {
+			log.Println("Error memeriksa lamaran lowongan:", err)
+			return model.LamaranLowongan{}, err
+		}
+		if ada {
+			return model.LamaranLowongan{}, ErrLamaranFinal
+		}
+		return model.LamaranLowongan{}, sql.ErrNoRows
+	}
+	return r.GetLamaranByID(id)
+}
+
+func (r *lowonganRepository) UpdateStatusLamaran(lowonganID, id int, status, catatan string) (model.LamaranLowongan, error) {
+	return r.ubahStatusLamaran(id, status, catatan, "lowongan_id", lowonganID)
+}
+
+func (r *lowonganRepository) BatalkanLamaran(alumniID, id int) (model.LamaranLowongan, error) {
+	return r.ubahStatusLamaran(id, model.StatusLamaranDibatalkan, "", "alumni_id", alumniID)
+}
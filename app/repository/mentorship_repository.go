@@ -0,0 +1,418 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"hello-fiber/app/model"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type MentorshipRepository interface {
+	GetMentorByAlumni(alumniID int) (model.Mentor, error)
+	SimpanMentor(alumniID int, req model.MentorRequest) (model.Mentor, error)
+	GetKandidatMentor(mentee model.Alumni) ([]model.Mentor, error)
+	GetPekerjaanByAlumniIDs(alumniIDs []int) (map[int][]model.PekerjaanAlumni, error)
+	Ajukan(menteeID int, req model.PermintaanMentorRequest) (model.PermintaanMentor, error)
+	GetPermintaanByID(id int) (model.PermintaanMentor, error)
+	GetPermintaanByAlumni(alumniID int, peran, status string) ([]model.PermintaanMentor, error)
+	Terima(mentorAlumniID, id int, balasan string) (model.PermintaanMentor, error)
+	Tolak(mentorAlumniID, id int, balasan string) (model.PermintaanMentor, error)
+	Batalkan(menteeID, id int) (model.PermintaanMentor, error)
+	Selesaikan(alumniID, id int) (model.PermintaanMentor, error)
+}
+
+var (
+	// ErrMentorTidakAktif mentor sedang tidak menerima permintaan baru
+	ErrMentorTidakAktif = errors.New("mentor tidak menerima permintaan baru")
+	// ErrMentorPenuh jumlah mentee yang diterima sudah mencapai kapasitas mentor
+	ErrMentorPenuh = errors.New("kapasitas mentor penuh")
+	// ErrMentorDiriSendiri alumni meminta mentoring ke profil mentornya sendiri
+	ErrMentorDiriSendiri = errors.New("tidak dapat meminta mentoring ke diri sendiri")
+	// ErrSudahMemintaMentor mentee masih memiliki permintaan aktif ke mentor yang sama
+	ErrSudahMemintaMentor = errors.New("permintaan ke mentor ini masih aktif")
+	// ErrStatusPermintaanMentor status permintaan tidak memungkinkan aksi yang diminta
+	ErrStatusPermintaanMentor = errors.New("status permintaan mentoring tidak sesuai")
+)
+
+type mentorshipRepository struct {
+	db *sql.DB
+}
+
+func NewMentorshipRepository(db *sql.DB) MentorshipRepository {
+	return &mentorshipRepository{db: db}
+}
+
+// mentorColumns kolom mentor (alias m) dan alumninya (alias a); jumlah mentee dihitung dari permintaan diterima
+const mentorColumns = `m.id, m.alumni_id, a.nama, a.jurusan, a.angkatan, a.tahun_lulus, m.topik, m.bio, m.kapasitas,
+	(SELECT COUNT(*) FROM permintaan_mentor p WHERE p.mentor_id = m.id AND p.status = 'diterima'),
+	m.bagikan_telepon, m.aktif, m.created_at, m.updated_at`
+
+const mentorFrom = `FROM mentor m JOIN alumni a ON a.id = m.alumni_id`
+
+func scanMentor(scanner interface{ Scan(...interface{}) error }) (model.Mentor, error) {
+	var m model.Mentor
+	err := scanner.Scan(
+		&m.ID, &m.AlumniID, &m.Nama, &m.Jurusan, &m.Angkatan, &m.TahunLulus, pq.Array(&m.Topik), &m.Bio, &m.Kapasitas,
+		&m.JumlahMentee, &m.BagikanTelepon, &m.Aktif, &m.CreatedAt, &m.UpdatedAt,
+	)
+	if m.Topik == nil {
+		m.Topik = []string{}
+	}
+	m.BidangIndustri, m.LokasiKerja = []string{}, []string{}
+	return m, err
+}
+
+func (r *mentorshipRepository) GetMentorByAlumni(alumniID int) (model.Mentor, error) {
+	m, err := scanMentor(r.db.QueryRow(`SELECT `+mentorColumns+` `+mentorFrom+` WHERE m.alumni_id = $1`, alumniID))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil profil mentor:", err)
+	}
+	return m, err
+}
+
+// SimpanMentor mendaftarkan alumni sebagai mentor atau mengubah profilnya. Kapasitas yang diturunkan di bawah
+// jumlah mentee saat ini tidak memutus mentoring yang berjalan, hanya menahan penerimaan berikutnya.
+func (r *mentorshipRepository) SimpanMentor(alumniID int, req model.MentorRequest) (model.Mentor, error) {
+	aktif := req.Aktif == nil || *req.Aktif
+	_, err := r.db.Exec(`INSERT INTO mentor (alumni_id, topik, bio, kapasitas, bagikan_telepon, aktif, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+		ON CONFLICT (alumni_id) DO UPDATE
+		SET topik = EXCLUDED.topik, bio = EXCLUDED.bio, kapasitas = EXCLUDED.kapasitas,
+		    bagikan_telepon = EXCLUDED.bagikan_telepon, aktif = EXCLUDED.aktif, updated_at = EXCLUDED.updated_at`,
+		alumniID, pq.Array(req.Topik), req.Bio, req.Kapasitas, req.BagikanTelepon, aktif, time.Now())
+	if err != nil {
+		log.Println("Error menyimpan profil mentor:", err)
+		return model.Mentor{}, err
+	}
+	return r.GetMentorByAlumni(alumniID)
+}
+
+// GetKandidatMentor mentor aktif yang lulus lebih dulu dari mentee, masih punya kapasitas, dan belum memiliki
+// permintaan aktif dari mentee tersebut
+func (r *mentorshipRepository) GetKandidatMentor(mentee model.Alumni) ([]model.Mentor, error) {
+	rows, err := r.db.Query(`SELECT `+mentorColumns+` `+mentorFrom+`
+		WHERE m.aktif AND a.is_delete IS DISTINCT FROM 'hapus' AND m.alumni_id <> $1
+		  AND ($2 = 0 OR a.tahun_lulus < $2)
+		  AND (SELECT COUNT(*) FROM permintaan_mentor p WHERE p.mentor_id = m.id AND p.status = 'diterima') < m.kapasitas
+		  AND NOT EXISTS (SELECT 1 FROM permintaan_mentor p
+			WHERE p.mentor_id = m.id AND p.alumni_id = $1 AND p.status IN ('diajukan', 'diterima'))
+		ORDER BY a.tahun_lulus, m.id`, mentee.ID, mentee.TahunLulus)
+	if err != nil {
+		log.Println("Error men-query kandidat mentor:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.Mentor{}
+	for rows.Next() {
+		m, err := scanMentor(rows)
+		if err != nil {
+			log.Println("Error men-scan kandidat mentor:", err)
+			return nil, err
+		}
+		list = append(list, m)
+	}
+	return list, rows.Err()
+}
+
+// GetPekerjaanByAlumniIDs pekerjaan yang tidak dihapus milik beberapa alumni sekaligus, untuk meringkas karier
+func (r *mentorshipRepository) GetPekerjaanByAlumniIDs(alumniIDs []int) (map[int][]model.PekerjaanAlumni, error) {
+	result := map[int][]model.PekerjaanAlumni{}
+	if len(alumniIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(`SELECT `+pekerjaanColumns+` FROM pekerjaan_alumni
+		WHERE alumni_id = ANY($1) AND `+scopeCondition(model.ScopeActive)+`
+		ORDER BY alumni_id, tanggal_mulai_kerja, id`, pq.Array(alumniIDs))
+	if err != nil {
+		log.Println("Error men-query pekerjaan mentor:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanPekerjaan(rows)
+		if err != nil {
+			log.Println("Error men-scan pekerjaan mentor:", err)
+			return nil, err
+		}
+		result[p.AlumniID] = append(result[p.AlumniID], p)
+	}
+	return result, rows.Err()
+}
+
+// permintaanMentorColumns kolom permintaan (alias p), mentor (m), alumni mentor (am), dan alumni mentee (ae);
+// email dan no_telepon masih terenkripsi
+const permintaanMentorColumns = `p.id, p.mentor_id, m.alumni_id, am.nama, p.alumni_id, ae.nama, ae.jurusan, ae.tahun_lulus,
+	p.status, p.topik, p.pesan, p.balasan, p.bagikan_telepon, m.bagikan_telepon,
+	COALESCE(am.email, ''), COALESCE(am.no_telepon, ''), COALESCE(ae.email, ''), COALESCE(ae.no_telepon, ''),
+	p.direspons_at, p.selesai_at, p.created_at, p.updated_at`
+
+const permintaanMentorFrom = `FROM permintaan_mentor p
+	JOIN mentor m ON m.id = p.mentor_id
+	JOIN alumni am ON am.id = m.alumni_id
+	JOIN alumni ae ON ae.id = p.alumni_id`
+
+// kontakMentoring membuka email dan, jika dibagikan, nomor telepon terenkripsi
+func (r *mentorshipRepository) kontakMentoring(email, telepon string, bagikanTelepon bool) (*model.KontakMentoring, error) {
+	var kontak model.KontakMentoring
+	var err error
+	if kontak.Email, err = decryptPII(r.db, kolomPIIEmail, email); err != nil {
+		return nil, err
+	}
+	if bagikanTelepon {
+		if kontak.NoTelepon, err = decryptPII(r.db, kolomPIITelepon, telepon); err != nil {
+			return nil, err
+		}
+	}
+	return &kontak, nil
+}
+
+// scanPermintaanMentor kontak kedua pihak hanya dibuka untuk permintaan yang sudah diterima
+func (r *mentorshipRepository) scanPermintaanMentor(scanner interface{ Scan(...interface{}) error }) (model.PermintaanMentor, error) {
+	var p model.PermintaanMentor
+	var mentorBagikanTelepon bool
+	var emailMentor, teleponMentor, emailMentee, teleponMentee string
+	var diresponsAt, selesaiAt sql.NullTime
+
+	err := scanner.Scan(
+		&p.ID, &p.MentorID, &p.MentorAlumniID, &p.NamaMentor, &p.AlumniID, &p.NamaMentee, &p.JurusanMentee, &p.TahunLulusMentee,
+		&p.Status, &p.Topik, &p.Pesan, &p.Balasan, &p.BagikanTelepon, &mentorBagikanTelepon,
+		&emailMentor, &teleponMentor, &emailMentee, &teleponMentee,
+		&diresponsAt, &selesaiAt, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return p, err
+	}
+	if diresponsAt.Valid {
+		p.DiresponsAt = &diresponsAt.Time
+	}
+	if selesaiAt.Valid {
+		p.SelesaiAt = &selesaiAt.Time
+	}
+	if !model.KontakDibuka(p.Status) {
+		return p, nil
+	}
+	if p.KontakMentor, err = r.kontakMentoring(emailMentor, teleponMentor, mentorBagikanTelepon); err != nil {
+		return p, err
+	}
+	p.KontakMentee, err = r.kontakMentoring(emailMentee, teleponMentee, p.BagikanTelepon)
+	return p, err
+}
+
+func (r *mentorshipRepository) GetPermintaanByID(id int) (model.PermintaanMentor, error) {
+	p, err := r.scanPermintaanMentor(r.db.QueryRow(`SELECT `+permintaanMentorColumns+` `+permintaanMentorFrom+` WHERE p.id = $1`, id))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil permintaan mentoring:", err)
+	}
+	return p, err
+}
+
+// GetPermintaanByAlumni permintaan yang melibatkan alumni sebagai mentor, mentee, atau keduanya (peran kosong),
+// yang terbaru lebih dulu
+func (r *mentorshipRepository) GetPermintaanByAlumni(alumniID int, peran, status string) ([]model.PermintaanMentor, error) {
+	where := `(m.alumni_id = $1 OR p.alumni_id = $1)`
+	switch peran {
+	case model.PeranMentor:
+		where = `m.alumni_id = $1`
+	case model.PeranMentee:
+		where = `p.alumni_id = $1`
+	}
+	args := []interface{}{alumniID}
+	if status != "" {
+		args = append(args, status)
+		where += ` AND p.status = $2`
+	}
+
+	rows, err := r.db.Query(`SELECT `+permintaanMentorColumns+` `+permintaanMentorFrom+`
+		WHERE `+where+` ORDER BY p.created_at DESC, p.id DESC`, args...)
+	if err != nil {
+		log.Println("Error men-query permintaan mentoring:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.PermintaanMentor{}
+	for rows.Next() {
+		p, err := r.scanPermintaanMentor(rows)
+		if err != nil {
+			log.Println("Error men-scan permintaan mentoring:", err)
+			return nil, err
+		}
+		p.Peran = model.PeranMentee
+		if p.MentorAlumniID == alumniID {
+			p.Peran = model.PeranMentor
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// lockMentor mengunci baris mentor selama transaksi agar kapasitas tidak terlampaui oleh penerimaan bersamaan.
+// penuh true jika jumlah mentee yang diterima sudah mencapai kapasitas.
+func lockMentor(tx *sql.Tx, id int) (alumniID int, aktif, penuh bool, err error) {
+	err = tx.QueryRow(`SELECT m.alumni_id, m.aktif AND a.is_delete IS DISTINCT FROM 'hapus',
+			(SELECT COUNT(*) FROM permintaan_mentor p WHERE p.mentor_id = m.id AND p.status = 'diterima') >= m.kapasitas
+		`+mentorFrom+` WHERE m.id = $1 FOR UPDATE OF m`, id).Scan(&alumniID, &aktif, &penuh)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengunci mentor:", err)
+	}
+	return alumniID, aktif, penuh, err
+}
+
+// Ajukan mengirim permintaan mentoring; mentor yang tidak aktif atau sudah penuh tidak dapat diminta
+func (r *mentorshipRepository) Ajukan(menteeID int, req model.PermintaanMentorRequest) (model.PermintaanMentor, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi permintaan mentoring:", err)
+		return model.PermintaanMentor{}, err
+	}
+	defer tx.Rollback()
+
+	mentorAlumniID, aktif, penuh, err := lockMentor(tx, req.MentorID)
+	if err != nil {
+		return model.PermintaanMentor{}, err
+	}
+	switch {
+	case mentorAlumniID == menteeID:
+		return model.PermintaanMentor{}, ErrMentorDiriSendiri
+	case !aktif:
+		return model.PermintaanMentor{}, ErrMentorTidakAktif
+	case penuh:
+		return model.PermintaanMentor{}, ErrMentorPenuh
+	}
+
+	var ada bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM permintaan_mentor
+		WHERE mentor_id = $1 AND alumni_id = $2 AND status IN ('diajukan', 'diterima'))`, req.MentorID, menteeID).Scan(&ada)
+	if err != nil {
+		log.Println("Error memeriksa permintaan mentoring:", err)
+		return model.PermintaanMentor{}, err
+	}
+	if ada {
+		return model.PermintaanMentor{}, ErrSudahMemintaMentor
+	}
+
+	var id int
+	err = tx.QueryRow(`INSERT INTO permintaan_mentor (mentor_id, alumni_id, status, topik, pesan, bagikan_telepon, created_at, updated_at)
+		VALUES ($1, $2, 'diajukan', $3, $4, $5, $6, $6) RETURNING id`,
+		req.MentorID, menteeID, req.Topik, req.Pesan, req.BagikanTelepon, time.Now()).Scan(&id)
+	if err != nil {
+		log.Println("Error menyimpan permintaan mentoring:", err)
+		return model.PermintaanMentor{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.PermintaanMentor{}, err
+	}
+	return r.GetPermintaanByID(id)
+}
+
+// Terima menerima permintaan yang masih diajukan selama kapasitas mentor belum penuh
+func (r *mentorshipRepository) Terima(mentorAlumniID, id int, balasan string) (model.PermintaanMentor, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi penerimaan mentoring:", err)
+		return model.PermintaanMentor{}, err
+	}
+	defer tx.Rollback()
+
+	var mentorID int
+	var status string
+	err = tx.QueryRow(`SELECT p.mentor_id, p.status FROM permintaan_mentor p JOIN mentor m ON m.id = p.mentor_id
+		WHERE p.id = $1 AND m.alumni_id = $2`, id, mentorAlumniID).Scan(&mentorID, &status)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error mengambil permintaan mentoring:", err)
+		}
+		return model.PermintaanMentor{}, err
+	}
+	_, _, penuh, err := lockMentor(tx, mentorID)
+	if err != nil {
+		return model.PermintaanMentor{}, err
+	}
+	if status != model.StatusMentoringDiajukan {
+		return model.PermintaanMentor{}, ErrStatusPermintaanMentor
+	}
+	if penuh {
+		return model.PermintaanMentor{}, ErrMentorPenuh
+	}
+
+	now := time.Now()
+	result, err := tx.Exec(`UPDATE permintaan_mentor SET status = 'diterima', balasan = $1, direspons_at = $2, updated_at = $2
+		WHERE id = $3 AND status = 'diajukan'`, balasan, now, id)
+	if err != nil {
+		log.Println("Error menerima permintaan mentoring:", err)
+		return model.PermintaanMentor{}, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return model.PermintaanMentor{}, ErrStatusPermintaanMentor
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.PermintaanMentor{}, err
+	}
+	return r.GetPermintaanByID(id)
+}
+
+// ubahStatusPermintaan mengubah permintaan berstatus dari milik pemilik; kondisi pemilik memakai placeholder $1
+func (r *mentorshipRepository) ubahStatusPermintaan(id int, dari, ke, balasan, pemilik string, pemilikID int) (model.PermintaanMentor, error) {
+	now := time.Now()
+	var diresponsAt, selesaiAt interface{}
+	switch ke {
+	case model.StatusMentoringDitolak:
+		diresponsAt = now
+	case model.StatusMentoringSelesai:
+		selesaiAt = now
+	}
+
+	result, err := r.db.Exec(`UPDATE permintaan_mentor p
+		SET status = $4, balasan = COALESCE(NULLIF($5, ''), balasan), direspons_at = COALESCE($6, direspons_at),
+		    selesai_at = COALESCE($7, selesai_at), updated_at = $8
+		WHERE `+pemilik+` AND p.id = $2 AND p.status = $3`,
+		pemilikID, id, dari, ke, balasan, diresponsAt, selesaiAt, now)
+	if err != nil {
+		log.Println("Error mengubah status permintaan mentoring:", err)
+		return model.PermintaanMentor{}, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		var ada bool
+		err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM permintaan_mentor p WHERE `+pemilik+` AND p.id = $2)`,
+			pemilikID, id).Scan(&ada)
+		if err != nil {
+			log.Println("Error memeriksa permintaan mentoring:", err)
+			return model.PermintaanMentor{}, err
+		}
+		if ada {
+			return model.PermintaanMentor{}, ErrStatusPermintaanMentor
+		}
+		return model.PermintaanMentor{}, sql.ErrNoRows
+	}
+	return r.GetPermintaanByID(id)
+}
+
+const (
+	permintaanMilikMentor = `p.mentor_id IN (SELECT id FROM mentor WHERE alumni_id = $1)`
+	permintaanMilikMentee = `p.alumni_id = $1`
+)
+
+func (r *mentorshipRepository) Tolak(mentorAlumniID, id int, balasan string) (model.PermintaanMentor, error) {
+	return r.ubahStatusPermintaan(id, model.StatusMentoringDiajukan, model.StatusMentoringDitolak, balasan,
+		permintaanMilikMentor, mentorAlumniID)
+}
+
+// Batalkan menarik permintaan yang belum dijawab mentor
+func (r *mentorshipRepository) Batalkan(menteeID, id int) (model.PermintaanMentor, error) {
+	return r.ubahStatusPermintaan(id, model.StatusMentoringDiajukan, model.StatusMentoringDibatalkan, "",
+		permintaanMilikMentee, menteeID)
+}
+
+// Selesaikan mengakhiri mentoring yang berjalan oleh mentor atau mentee; kapasitas mentor kembali tersedia
+func (r *mentorshipRepository) Selesaikan(alumniID, id int) (model.PermintaanMentor, error) {
+	return r.ubahStatusPermintaan(id, model.StatusMentoringDiterima, model.StatusMentoringSelesai, "",
+		`(`+permintaanMilikMentee+` OR `+permintaanMilikMentor+`)`, alumniID)
+}
@@ -3,114 +3,204 @@ package repository
 import (
 	"hello-fiber/app/model"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 )
 
 type PekerjaanAlumniRepository interface {
-	GetAll() ([]model.PekerjaanAlumni, error)
-	GetByID(id int) (model.PekerjaanAlumni, error)
+	GetAll(scope string) ([]model.PekerjaanAlumni, error)
+	GetByID(id int, scope string) (model.PekerjaanAlumni, error)
 	GetTrashByID(id int) (model.Trash, error)
-	GetByAlumniID(alumniID int) ([]model.PekerjaanAlumni, error)
-	GetPekerjaanAlumniWithPagination(search, sortBy, order string, limit, offset int) ([]model.PekerjaanAlumni, error)
+	GetByAlumniID(alumniID int, scope string) ([]model.PekerjaanAlumni, error)
+	GetPekerjaanAlumniWithPagination(filter model.PekerjaanFilter, sortBy, order string, limit, offset int) ([]model.PekerjaanAlumni, error)
 	Create(pekerjaan model.CreatePekerjaanAlumniRequest) (model.PekerjaanAlumni, error)
 	Update(id int, pekerjaan model.UpdatePekerjaanAlumniRequest) (model.PekerjaanAlumni, error)
-	UpdateUser(id int, pekerjaan model.UpdatePekerjaanAlumniSoftDelete) (model.Trash, error)
-	UpdateAdmin(id int, pekerjaan model.UpdatePekerjaanAlumniSoftDelete) (model.Trash, error)
-	Delete(id int) error
-	CountPekerjaanAlumni(search string) (int, error)
-	GetTrashed() ([]model.Trash, error)
-	HardDeleteIfTrashed(id int) error
+	UpdateUser(id int, pekerjaan model.UpdatePekerjaanAlumniSoftDelete, actorID int) (model.Trash, error)
+	UpdateAdmin(id int, pekerjaan model.UpdatePekerjaanAlumniSoftDelete, actorID int) (model.Trash, error)
+	Delete(id int, actorID int) error
+	CountPekerjaanAlumni(filter model.PekerjaanFilter) (int, error)
+	HardDeleteIfTrashed(id int) ([]model.Berkas, error)
 	RestoreIfTrashed(id int) (model.Trash, error)
+	GetPurgeCandidates(retention time.Duration, limit int) ([]model.TrashPurgeCandidate, error)
+	PurgeExpiredTrash(retention time.Duration, batchSize int) (int64, []model.Berkas, error)
+	GetTrashedIDs(filter model.TrashFilter) ([]int, error)
+	UpdateStatus(id int, status string, tanggalSelesai model.Tanggal) (model.PekerjaanAlumni, error)
+	AddStatusHistory(history model.StatusPekerjaanHistory) error
+	GetStatusHistory(pekerjaanID int) ([]model.StatusPekerjaanHistory, error)
+	GetStatusHistoryByAlumniID(alumniID int) ([]model.StatusPekerjaanHistory, error)
 }
 
+// ErrPekerjaanViaAlumni pekerjaan masuk trash karena alumninya dihapus; hanya dipulihkan atau dihapus lewat alumni
+var ErrPekerjaanViaAlumni = errors.New("pekerjaan dihapus bersama alumni, pulihkan atau hapus lewat alumni")
+
 type pekerjaanAlumniRepository struct {
-	db *sql.DB
+	db DBTX
 }
 
 func NewPekerjaanAlumniRepository(db *sql.DB) PekerjaanAlumniRepository {
 	return &pekerjaanAlumniRepository{db: db}
 }
 
-func (r *pekerjaanAlumniRepository) GetAll() ([]model.PekerjaanAlumni, error) {
-	sqlStatement := `SELECT id, alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, 
-		       gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, 
-		       deskripsi_pekerjaan, created_at, updated_at
-		FROM pekerjaan_alumni 
-		ORDER BY created_at DESC`
-	
-	rows, err := r.db.Query(sqlStatement)
-	if err != nil {
-		log.Println("Error men-query pekerjaan alumni:", err)
-		return nil, err
+// NewPekerjaanAlumniRepositoryTx membuat repository yang seluruh query-nya berjalan di dalam transaksi tx
+func NewPekerjaanAlumniRepositoryTx(tx *sql.Tx) PekerjaanAlumniRepository {
+	return &pekerjaanAlumniRepository{db: tx}
+}
+
+// pekerjaanColumns kolom standar untuk dibaca dengan scanPekerjaan
+const pekerjaanColumns = `id, alumni_id, perusahaan_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, 
+		       gaji_range, gaji_min, gaji_max, gaji_mata_uang, gaji_periode,
+		       tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, 
+		       deskripsi_pekerjaan, is_delete, trashed_at, trashed_by, created_at, updated_at`
+
+func scanPekerjaan(scanner interface{ Scan(...interface{}) error }) (model.PekerjaanAlumni, error) {
+	var pekerjaan model.PekerjaanAlumni
+	var gajiRange, gajiMataUang, gajiPeriode, isDelete sql.NullString
+	var perusahaanID, gajiMin, gajiMax sql.NullInt64
+	var trashedAt sql.NullTime
+	var trashedBy sql.NullInt64
+
+	err := scanner.Scan(
+		&pekerjaan.ID, &pekerjaan.AlumniID, &perusahaanID, &pekerjaan.NamaPerusahaan, &pekerjaan.PosisiJabatan,
+		&pekerjaan.BidangIndustri, &pekerjaan.LokasiKerja,
+		&gajiRange, &gajiMin, &gajiMax, &gajiMataUang, &gajiPeriode,
+		&pekerjaan.TanggalMulaiKerja, &pekerjaan.TanggalSelesaiKerja, &pekerjaan.StatusPekerjaan,
+		&pekerjaan.DeskripsiPekerjaan, &isDelete, &trashedAt, &trashedBy,
+		&pekerjaan.CreatedAt, &pekerjaan.UpdatedAt,
+	)
+	pekerjaan.PerusahaanID = int(perusahaanID.Int64)
+	pekerjaan.GajiRange = gajiRange.String
+	if gajiMin.Valid || gajiMax.Valid {
+		pekerjaan.Gaji = &model.GajiRange{
+			Min:      gajiMin.Int64,
+			Max:      gajiMax.Int64,
+			MataUang: gajiMataUang.String,
+			Periode:  gajiPeriode.String,
+		}
+	}
+	pekerjaan.IsDelete = "tidak"
+	if isDelete.Valid {
+		pekerjaan.IsDelete = isDelete.String
+	}
+	if trashedAt.Valid {
+		pekerjaan.TrashedAt = &trashedAt.Time
+	}
+	if trashedBy.Valid {
+		pekerjaan.TrashedBy = int(trashedBy.Int64)
+	}
+	return pekerjaan, err
+}
+
+// scopeCondition menerjemahkan scope visibilitas (active, trashed, all) menjadi kondisi WHERE
+func scopeCondition(scope string) string {
+	switch scope {
+	case model.ScopeTrashed:
+		return "is_delete = 'hapus'"
+	case model.ScopeAll:
+		return "TRUE"
+	default:
+		return "is_delete IS DISTINCT FROM 'hapus'"
+	}
+}
+
+// gajiArgs nilai kolom gaji_min, gaji_max, gaji_mata_uang, gaji_periode; Max 0 disimpan NULL (tanpa batas atas)
+func gajiArgs(gaji *model.GajiRange) (interface{}, interface{}, interface{}, interface{}) {
+	if gaji == nil {
+		return nil, nil, nil, nil
+	}
+	return gaji.Min, nullableInt64(gaji.Max), gaji.MataUang, gaji.Periode
+}
+
+func nullableInt64(value int64) interface{} {
+	if value == 0 {
+		return nil
 	}
+	return value
+}
+
+// pekerjaanFilterCondition menyusun kondisi WHERE dari PekerjaanFilter
+func pekerjaanFilterCondition(filter model.PekerjaanFilter) (string, []interface{}) {
+	args := []interface{}{"%" + filter.Search + "%"}
+	conditions := []string{
+		scopeCondition(filter.Scope),
+		"(nama_perusahaan ILIKE $1 OR posisi_jabatan ILIKE $1 OR bidang_industri ILIKE $1 OR lokasi_kerja ILIKE $1)",
+	}
+
+	if !filter.MulaiDari.IsZero() {
+		args = append(args, filter.MulaiDari)
+		conditions = append(conditions, fmt.Sprintf("tanggal_mulai_kerja >= $%d", len(args)))
+	}
+	if !filter.MulaiSampai.IsZero() {
+		args = append(args, filter.MulaiSampai)
+		conditions = append(conditions, fmt.Sprintf("tanggal_mulai_kerja <= $%d", len(args)))
+	}
+	if filter.GajiMin != 0 {
+		args = append(args, filter.GajiMin)
+		conditions = append(conditions, fmt.Sprintf("gaji_min IS NOT NULL AND (gaji_max IS NULL OR gaji_max >= $%d)", len(args)))
+	}
+	if filter.GajiMax != 0 {
+		args = append(args, filter.GajiMax)
+		conditions = append(conditions, fmt.Sprintf("gaji_min <= $%d", len(args)))
+	}
+	if filter.MataUang != "" {
+		args = append(args, filter.MataUang)
+		conditions = append(conditions, fmt.Sprintf("gaji_mata_uang = $%d", len(args)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+func (r *pekerjaanAlumniRepository) scanPekerjaanRows(rows *sql.Rows) ([]model.PekerjaanAlumni, error) {
 	defer rows.Close()
 
-	var pekerjaanList []model.PekerjaanAlumni
+	pekerjaanList := []model.PekerjaanAlumni{}
 	for rows.Next() {
-		var pekerjaan model.PekerjaanAlumni
-		var tanggalSelesai sql.NullString
-		
-		err := rows.Scan(
-			&pekerjaan.ID, &pekerjaan.AlumniID, &pekerjaan.NamaPerusahaan, &pekerjaan.PosisiJabatan,
-			&pekerjaan.BidangIndustri, &pekerjaan.LokasiKerja, &pekerjaan.GajiRange, 
-			&pekerjaan.TanggalMulaiKerja, &tanggalSelesai, &pekerjaan.StatusPekerjaan,
-			&pekerjaan.DeskripsiPekerjaan, &pekerjaan.CreatedAt, &pekerjaan.UpdatedAt,
-		)
+		pekerjaan, err := scanPekerjaan(rows)
 		if err != nil {
 			log.Println("Error men-scan pekerjaan alumni:", err)
 			return nil, err
 		}
-		
-		if tanggalSelesai.Valid {
-			pekerjaan.TanggalSelesaiKerja = tanggalSelesai.String
-		}
-		
 		pekerjaanList = append(pekerjaanList, pekerjaan)
 	}
 	return pekerjaanList, nil
 }
 
-func (r *pekerjaanAlumniRepository) GetByID(id int) (model.PekerjaanAlumni, error) {
-	sqlStatement := `SELECT id, alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, 
-		       gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, 
-		       deskripsi_pekerjaan, created_at, updated_at
+func (r *pekerjaanAlumniRepository) GetAll(scope string) ([]model.PekerjaanAlumni, error) {
+	sqlStatement := `SELECT ` + pekerjaanColumns + `
 		FROM pekerjaan_alumni 
-		WHERE id = $1`
+		WHERE ` + scopeCondition(scope) + `
+		ORDER BY created_at DESC`
 	
-	var pekerjaan model.PekerjaanAlumni
-	var tanggalSelesai sql.NullString
+	rows, err := r.db.Query(sqlStatement)
+	if err != nil {
+		log.Println("Error men-query pekerjaan alumni:", err)
+		return nil, err
+	}
+	return r.scanPekerjaanRows(rows)
+}
+
+func (r *pekerjaanAlumniRepository) GetByID(id int, scope string) (model.PekerjaanAlumni, error) {
+	sqlStatement := `SELECT ` + pekerjaanColumns + `
+		FROM pekerjaan_alumni 
+		WHERE id = $1 AND ` + scopeCondition(scope)
 	
-	err := r.db.QueryRow(sqlStatement, id).Scan(
-		&pekerjaan.ID, &pekerjaan.AlumniID, &pekerjaan.NamaPerusahaan, &pekerjaan.PosisiJabatan,
-		&pekerjaan.BidangIndustri, &pekerjaan.LokasiKerja, &pekerjaan.GajiRange, 
-		&pekerjaan.TanggalMulaiKerja, &tanggalSelesai, &pekerjaan.StatusPekerjaan,
-		&pekerjaan.DeskripsiPekerjaan, &pekerjaan.CreatedAt, &pekerjaan.UpdatedAt,
-	)
+	pekerjaan, err := scanPekerjaan(r.db.QueryRow(sqlStatement, id))
 	if err != nil {
 		log.Println("Error menemukan pekerjaan alumni by ID:", err)
 		return model.PekerjaanAlumni{}, err
 	}
 	
-	if tanggalSelesai.Valid {
-		pekerjaan.TanggalSelesaiKerja = tanggalSelesai.String
-	}
-	
 	return pekerjaan, nil
 }
 
 
 func (r *pekerjaanAlumniRepository) GetTrashByID(id int) (model.Trash, error) {
-	sqlStatement := `SELECT id, alumni_id, is_delete 
+	sqlStatement := `SELECT id, alumni_id, is_delete, trashed_at, trashed_by, updated_at 
 		FROM pekerjaan_alumni 
 		WHERE id = $1 AND is_delete = 'hapus'`
 	
-	var trash model.Trash
-	
-	err := r.db.QueryRow(sqlStatement, id).Scan(
-		&trash.ID, &trash.AlumniID, &trash.IsDelete,
-	)
+	trash, err := scanTrash(r.db.QueryRow(sqlStatement, id))
 	if err != nil {
 		log.Println("Error menemukan pekerjaan alumni by ID:", err)
 		return model.Trash{}, err
@@ -119,12 +209,10 @@ func (r *pekerjaanAlumniRepository) GetTrashByID(id int) (model.Trash, error) {
 	return trash, nil
 }
 
-func (r *pekerjaanAlumniRepository) GetByAlumniID(alumniID int) ([]model.PekerjaanAlumni, error) {
-	sqlStatement := `SELECT id, alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, 
-		       gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, 
-		       deskripsi_pekerjaan, created_at, updated_at
+func (r *pekerjaanAlumniRepository) GetByAlumniID(alumniID int, scope string) ([]model.PekerjaanAlumni, error) {
+	sqlStatement := `SELECT ` + pekerjaanColumns + `
 		FROM pekerjaan_alumni 
-		WHERE alumni_id = $1 
+		WHERE alumni_id = $1 AND ` + scopeCondition(scope) + `
 		ORDER BY tanggal_mulai_kerja DESC`
 	
 	rows, err := r.db.Query(sqlStatement, alumniID)
@@ -132,131 +220,59 @@ func (r *pekerjaanAlumniRepository) GetByAlumniID(alumniID int) ([]model.Pekerja
 		log.Println("Error men-query pekerjaan alumni by alumni ID:", err)
 		return nil, err
 	}
-	defer rows.Close()
-
-	var pekerjaanList []model.PekerjaanAlumni
-	for rows.Next() {
-		var pekerjaan model.PekerjaanAlumni
-		var tanggalSelesai sql.NullString
-		
-		err := rows.Scan(
-			&pekerjaan.ID, &pekerjaan.AlumniID, &pekerjaan.NamaPerusahaan, &pekerjaan.PosisiJabatan,
-			&pekerjaan.BidangIndustri, &pekerjaan.LokasiKerja, &pekerjaan.GajiRange, 
-			&pekerjaan.TanggalMulaiKerja, &tanggalSelesai, &pekerjaan.StatusPekerjaan,
-			&pekerjaan.DeskripsiPekerjaan, &pekerjaan.CreatedAt, &pekerjaan.UpdatedAt,
-		)
-		if err != nil {
-			log.Println("Error men-scan pekerjaan alumni:", err)
-			return nil, err
-		}
-		
-		if tanggalSelesai.Valid {
-			pekerjaan.TanggalSelesaiKerja = tanggalSelesai.String
-		}
-		
-		pekerjaanList = append(pekerjaanList, pekerjaan)
-	}
-	return pekerjaanList, nil
+	return r.scanPekerjaanRows(rows)
 }
 
 
-func (r *pekerjaanAlumniRepository) GetPekerjaanAlumniWithPagination(search, sortBy, order string, limit, offset int) ([]model.PekerjaanAlumni, error) {
+func (r *pekerjaanAlumniRepository) GetPekerjaanAlumniWithPagination(filter model.PekerjaanFilter, sortBy, order string, limit, offset int) ([]model.PekerjaanAlumni, error) {
 	validSortColumns := map[string]bool{
 		"id": true, "nama_perusahaan": true, "posisi_jabatan": true, "bidang_industri": true,
 		"lokasi_kerja": true, "tanggal_mulai_kerja": true, "status_pekerjaan": true, "created_at": true,
+		"is_delete": true, "trashed_at": true, "updated_at": true, "gaji_min": true, "tanggal_selesai_kerja": true,
 	}
 	if !validSortColumns[sortBy] {
 		sortBy = "id"
 	}
 
-	query := fmt.Sprintf(`SELECT id, alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, lokasi_kerja, 
-		       gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, status_pekerjaan, 
-		       deskripsi_pekerjaan, created_at, updated_at
+	where, args := pekerjaanFilterCondition(filter)
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`SELECT %s
 		FROM pekerjaan_alumni
-		WHERE nama_perusahaan ILIKE $1 OR posisi_jabatan ILIKE $1 OR bidang_industri ILIKE $1 OR lokasi_kerja ILIKE $1
-		ORDER BY %s %s
-		LIMIT $2 OFFSET $3
-	`, sortBy, order)
+		WHERE %s
+		ORDER BY %s %s NULLS LAST
+		LIMIT $%d OFFSET $%d
+	`, pekerjaanColumns, where, sortBy, order, len(args)-1, len(args))
 
-	rows, err := r.db.Query(query, "%"+search+"%", limit, offset)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		log.Println("Query error:", err)
 		return nil, err
 	}
-	defer rows.Close()
-
-	var pekerjaanList []model.PekerjaanAlumni
-	for rows.Next() {
-		var pekerjaan model.PekerjaanAlumni
-		var tanggalSelesai sql.NullString
-		
-		err := rows.Scan(
-			&pekerjaan.ID, &pekerjaan.AlumniID, &pekerjaan.NamaPerusahaan, &pekerjaan.PosisiJabatan,
-			&pekerjaan.BidangIndustri, &pekerjaan.LokasiKerja, &pekerjaan.GajiRange, 
-			&pekerjaan.TanggalMulaiKerja, &tanggalSelesai, &pekerjaan.StatusPekerjaan,
-			&pekerjaan.DeskripsiPekerjaan, &pekerjaan.CreatedAt, &pekerjaan.UpdatedAt,
-		)
-		if err != nil {
-			return nil, err
-		}
-		
-		if tanggalSelesai.Valid {
-			pekerjaan.TanggalSelesaiKerja = tanggalSelesai.String
-		}
-		
-		pekerjaanList = append(pekerjaanList, pekerjaan)
-	}
-
-	return pekerjaanList, nil
-}
-
-func (r *pekerjaanAlumniRepository) GetTrashed() ([]model.Trash, error) {
-	sqlStatement := `SELECT id, alumni_id, is_delete, updated_at 
-		FROM pekerjaan_alumni 
-		WHERE is_delete = 'hapus'
-		ORDER BY updated_at DESC`
-
-	rows, err := r.db.Query(sqlStatement)
-	if err != nil {
-		log.Println("Error memanggil trash pekerjaan_alumni:", err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var pekerjaanList []model.Trash
-	for rows.Next() {
-		var trash model.Trash
-		if err := rows.Scan(
-			&trash.ID, &trash.AlumniID, &trash.IsDelete, &trash.UpdatedAt,
-		); err != nil {
-			log.Println("Error men-scan trash pekerjaan_alumni:", err)
-			return nil, err
-		}
-		pekerjaanList = append(pekerjaanList, trash)
-	}
-	return pekerjaanList, nil
+	return r.scanPekerjaanRows(rows)
 }
 
 func (r *pekerjaanAlumniRepository) Create(req model.CreatePekerjaanAlumniRequest) (model.PekerjaanAlumni, error) {
-	sqlStatement := `INSERT INTO pekerjaan_alumni (alumni_id, nama_perusahaan, posisi_jabatan, bidang_industri, 
-		                             lokasi_kerja, gaji_range, tanggal_mulai_kerja, tanggal_selesai_kerja, 
+	sqlStatement := `INSERT INTO pekerjaan_alumni (alumni_id, perusahaan_id, nama_perusahaan, posisi_jabatan, bidang_industri, 
+		                             lokasi_kerja, gaji_range, gaji_min, gaji_max, gaji_mata_uang, gaji_periode,
+		                             tanggal_mulai_kerja, tanggal_selesai_kerja, 
 		                             status_pekerjaan, deskripsi_pekerjaan, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) 
 		RETURNING id, created_at, updated_at`
 	
 	var pekerjaan model.PekerjaanAlumni
 	now := time.Now()
-	
-	var tanggalSelesai interface{}
-	if req.TanggalSelesaiKerja != "" {
-		tanggalSelesai = req.TanggalSelesaiKerja
-	} else {
-		tanggalSelesai = nil
+	gajiMin, gajiMax, gajiMataUang, gajiPeriode := gajiArgs(req.Gaji)
+
+	var err error
+	req.PerusahaanID, req.NamaPerusahaan, err = resolvePerusahaan(r.db, req.PerusahaanID, req.NamaPerusahaan, req.BidangIndustri, req.LokasiKerja)
+	if err != nil {
+		return model.PekerjaanAlumni{}, err
 	}
 	
-	err := r.db.QueryRow(
-		sqlStatement, req.AlumniID, req.NamaPerusahaan, req.PosisiJabatan, req.BidangIndustri,
-		req.LokasiKerja, req.GajiRange, req.TanggalMulaiKerja, tanggalSelesai,
+	err = r.db.QueryRow(
+		sqlStatement, req.AlumniID, nullableInt(req.PerusahaanID), req.NamaPerusahaan, req.PosisiJabatan, req.BidangIndustri,
+		req.LokasiKerja, req.GajiRange, gajiMin, gajiMax, gajiMataUang, gajiPeriode,
+		req.TanggalMulaiKerja, req.TanggalSelesaiKerja,
 		req.StatusPekerjaan, req.DeskripsiPekerjaan, now, now,
 	).Scan(&pekerjaan.ID, &pekerjaan.CreatedAt, &pekerjaan.UpdatedAt)
 	
@@ -266,16 +282,18 @@ func (r *pekerjaanAlumniRepository) Create(req model.CreatePekerjaanAlumniReques
 	}
 
 	pekerjaan.AlumniID = req.AlumniID
+	pekerjaan.PerusahaanID = req.PerusahaanID
 	pekerjaan.NamaPerusahaan = req.NamaPerusahaan
 	pekerjaan.PosisiJabatan = req.PosisiJabatan
 	pekerjaan.BidangIndustri = req.BidangIndustri
 	pekerjaan.LokasiKerja = req.LokasiKerja
 	pekerjaan.GajiRange = req.GajiRange
+	pekerjaan.Gaji = req.Gaji
 	pekerjaan.TanggalMulaiKerja = req.TanggalMulaiKerja
 	pekerjaan.TanggalSelesaiKerja = req.TanggalSelesaiKerja
 	pekerjaan.StatusPekerjaan = req.StatusPekerjaan
 	pekerjaan.DeskripsiPekerjaan = req.DeskripsiPekerjaan
-	// pekerjaan.IsDelete = req.IsDelete
+	pekerjaan.IsDelete = "tidak"
 	
 	return pekerjaan, nil
 }
@@ -283,23 +301,25 @@ func (r *pekerjaanAlumniRepository) Create(req model.CreatePekerjaanAlumniReques
 func (r *pekerjaanAlumniRepository) Update(id int, req model.UpdatePekerjaanAlumniRequest) (model.PekerjaanAlumni, error) {
 	sqlStatement := `UPDATE pekerjaan_alumni 
 		SET nama_perusahaan = $1, posisi_jabatan = $2, bidang_industri = $3, lokasi_kerja = $4, 
-		    gaji_range = $5, tanggal_mulai_kerja = $6, tanggal_selesai_kerja = $7, 
-		    status_pekerjaan = $8, deskripsi_pekerjaan = $9, updated_at = $10
-		WHERE id = $11`
+		    gaji_range = $5, gaji_min = $6, gaji_max = $7, gaji_mata_uang = $8, gaji_periode = $9,
+		    tanggal_mulai_kerja = $10, tanggal_selesai_kerja = $11, 
+		    status_pekerjaan = $12, deskripsi_pekerjaan = $13, updated_at = $14, perusahaan_id = $16
+		WHERE id = $15 AND is_delete IS DISTINCT FROM 'hapus'`
 	
 	now := time.Now()
-	
-	var tanggalSelesai interface{}
-	if req.TanggalSelesaiKerja != "" {
-		tanggalSelesai = req.TanggalSelesaiKerja
-	} else {
-		tanggalSelesai = nil
+	gajiMin, gajiMax, gajiMataUang, gajiPeriode := gajiArgs(req.Gaji)
+
+	var err error
+	req.PerusahaanID, req.NamaPerusahaan, err = resolvePerusahaan(r.db, req.PerusahaanID, req.NamaPerusahaan, req.BidangIndustri, req.LokasiKerja)
+	if err != nil {
+		return model.PekerjaanAlumni{}, err
 	}
 	
 	result, err := r.db.Exec(
 		sqlStatement, req.NamaPerusahaan, req.PosisiJabatan, req.BidangIndustri, req.LokasiKerja,
-		req.GajiRange, req.TanggalMulaiKerja, tanggalSelesai, req.StatusPekerjaan,
-		req.DeskripsiPekerjaan, now, id,
+		req.GajiRange, gajiMin, gajiMax, gajiMataUang, gajiPeriode,
+		req.TanggalMulaiKerja, req.TanggalSelesaiKerja, req.StatusPekerjaan,
+		req.DeskripsiPekerjaan, now, id, nullableInt(req.PerusahaanID),
 	)
 	if err != nil {
 		log.Println("Error updating pekerjaan alumni:", err)
@@ -311,18 +331,20 @@ func (r *pekerjaanAlumniRepository) Update(id int, req model.UpdatePekerjaanAlum
 		return model.PekerjaanAlumni{}, sql.ErrNoRows
 	}
 	
-	return r.GetByID(id)
+	return r.GetByID(id, model.ScopeActive)
 }
 
-func (r *pekerjaanAlumniRepository) UpdateUser(id int, req model.UpdatePekerjaanAlumniSoftDelete) (model.Trash, error) {
+// UpdateUser mengubah is_delete pekerjaan; pekerjaan yang ikut terhapus bersama alumninya ditolak
+// dengan ErrPekerjaanViaAlumni
+func (r *pekerjaanAlumniRepository) UpdateUser(id int, req model.UpdatePekerjaanAlumniSoftDelete, actorID int) (model.Trash, error) {
 	sqlStatement := `UPDATE pekerjaan_alumni 
-		SET is_delete = $1, updated_at = $2
-		WHERE id = $3`
+		SET is_delete = $1, updated_at = $2, ` + trashedColumnsUpdate + `
+		WHERE id = $3 AND NOT dihapus_via_alumni`
 	
 	now := time.Now()
 	
 	result, err := r.db.Exec(
-		sqlStatement, req.IsDelete, now, id,
+		sqlStatement, req.IsDelete, now, id, nullableInt(actorID),
 	)
 	if err != nil {
 		log.Println("Error memperbarui pekerjaan alumni user to user:", err)
@@ -331,21 +353,22 @@ func (r *pekerjaanAlumniRepository) UpdateUser(id int, req model.UpdatePekerjaan
 	
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return model.Trash{}, sql.ErrNoRows
+		return model.Trash{}, r.trashMissReason(id)
 	}
 	
 	return r.GetTrashByID(id)
 }
 
-func (r *pekerjaanAlumniRepository) UpdateAdmin(id int, req model.UpdatePekerjaanAlumniSoftDelete) (model.Trash, error) {
+// UpdateAdmin seperti UpdateUser untuk admin
+func (r *pekerjaanAlumniRepository) UpdateAdmin(id int, req model.UpdatePekerjaanAlumniSoftDelete, actorID int) (model.Trash, error) {
 	sqlStatement := `UPDATE pekerjaan_alumni 
-		SET is_delete = $1, updated_at = $2
-		WHERE id = $3`
+		SET is_delete = $1, updated_at = $2, ` + trashedColumnsUpdate + `
+		WHERE id = $3 AND NOT dihapus_via_alumni`
 	
 	now := time.Now()
 	
 	result, err := r.db.Exec(
-		sqlStatement, req.IsDelete, now, id,
+		sqlStatement, req.IsDelete, now, id, nullableInt(actorID),
 	)
 	if err != nil {
 		log.Println("Error memperbarui pekerjaan alumni admin to user:", err)
@@ -354,15 +377,17 @@ func (r *pekerjaanAlumniRepository) UpdateAdmin(id int, req model.UpdatePekerjaa
 	
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return model.Trash{}, sql.ErrNoRows
+		return model.Trash{}, r.trashMissReason(id)
 	}
 	
 	return r.GetTrashByID(id)
 }
 
-func (r *pekerjaanAlumniRepository) Delete(id int) error {
-	sqlStatement := `UPDATE pekerjaan_alumni SET is_delete = 'hapus' WHERE id = $1`
-	result, err := r.db.Exec(sqlStatement, id)
+func (r *pekerjaanAlumniRepository) Delete(id int, actorID int) error {
+	sqlStatement := `UPDATE pekerjaan_alumni
+		SET is_delete = 'hapus', trashed_at = COALESCE(trashed_at, $2), trashed_by = COALESCE(trashed_by, $3)
+		WHERE id = $1`
+	result, err := r.db.Exec(sqlStatement, id, time.Now(), nullableInt(actorID))
 	if err != nil {
 		log.Println("Error menghapus pekerjaan alumni:", err)
 		return err
@@ -376,34 +401,79 @@ func (r *pekerjaanAlumniRepository) Delete(id int) error {
 	return nil
 }
 
-func (r *pekerjaanAlumniRepository) CountPekerjaanAlumni(search string) (int, error) {
+func (r *pekerjaanAlumniRepository) CountPekerjaanAlumni(filter model.PekerjaanFilter) (int, error) {
 	var total int
-	countQuery := `SELECT COUNT(*) FROM pekerjaan_alumni WHERE nama_perusahaan ILIKE $1 OR posisi_jabatan ILIKE $1 OR bidang_industri ILIKE $1 OR lokasi_kerja ILIKE $1`
-	err := r.db.QueryRow(countQuery, "%"+search+"%").Scan(&total)
+	where, args := pekerjaanFilterCondition(filter)
+	countQuery := `SELECT COUNT(*) FROM pekerjaan_alumni WHERE ` + where
+	err := r.db.QueryRow(countQuery, args...).Scan(&total)
 	if err != nil && err != sql.ErrNoRows {
 		return 0, err
 	}
 	return total, nil
 }
 
-func (r *pekerjaanAlumniRepository) HardDeleteIfTrashed(id int) error {
-	sqlStatement := `DELETE FROM pekerjaan_alumni WHERE id = $1 AND is_delete = 'hapus'`
-	result, err := r.db.Exec(sqlStatement, id)
+// HardDeleteIfTrashed menghapus permanen pekerjaan di trash dan mengembalikan berkas yang ikut terhapus
+// agar object-nya bisa dihapus dari storage setelah commit
+func (r *pekerjaanAlumniRepository) HardDeleteIfTrashed(id int) ([]model.Berkas, error) {
+	deleted, berkas, err := hapusPekerjaanDenganBerkas(r.db,
+		`DELETE FROM pekerjaan_alumni WHERE id = $1 AND is_delete = 'hapus' AND NOT dihapus_via_alumni RETURNING id`, id)
 	if err != nil {
 		log.Println("Error hard deleting pekerjaan_alumni:", err)
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, r.trashMissReason(id)
+	}
+	return berkas, nil
+}
+
+// hapusPekerjaanDenganBerkas menjalankan deleteQuery (DELETE pekerjaan_alumni ... RETURNING id) dan mengembalikan
+// jumlah pekerjaan yang terhapus beserta key berkas yang ikut terhapus lewat ON DELETE CASCADE. SELECT utama
+// masih melihat baris berkas karena memakai snapshot sebelum statement dijalankan.
+func hapusPekerjaanDenganBerkas(db DBTX, deleteQuery string, args ...interface{}) (int64, []model.Berkas, error) {
+	rows, err := db.Query(`WITH hapus AS (`+deleteQuery+`)
+		SELECT h.id, b.storage_key, b.thumbnail_key
+		FROM hapus h LEFT JOIN berkas b ON b.pekerjaan_id = h.id`, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	deleted := map[int]bool{}
+	berkas := []model.Berkas{}
+	for rows.Next() {
+		var pekerjaanID int
+		var storageKey, thumbnailKey sql.NullString
+		if err := rows.Scan(&pekerjaanID, &storageKey, &thumbnailKey); err != nil {
+			return 0, nil, err
+		}
+		deleted[pekerjaanID] = true
+		if storageKey.Valid {
+			id := pekerjaanID
+			berkas = append(berkas, model.Berkas{PekerjaanID: &id, StorageKey: storageKey.String, ThumbnailKey: thumbnailKey.String})
+		}
+	}
+	return int64(len(deleted)), berkas, rows.Err()
+}
+
+// trashMissReason membedakan pekerjaan yang tidak ada di trash dari pekerjaan yang ikut terhapus bersama alumni
+func (r *pekerjaanAlumniRepository) trashMissReason(id int) error {
+	var viaAlumni bool
+	err := r.db.QueryRow(`SELECT dihapus_via_alumni FROM pekerjaan_alumni WHERE id = $1 AND is_delete = 'hapus'`, id).Scan(&viaAlumni)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error memeriksa trash pekerjaan_alumni:", err)
 		return err
 	}
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return sql.ErrNoRows
+	if viaAlumni {
+		return ErrPekerjaanViaAlumni
 	}
-	return nil
+	return sql.ErrNoRows
 }
 
 func (r *pekerjaanAlumniRepository) RestoreIfTrashed(id int) (model.Trash, error) {
 	sqlStatement := `UPDATE pekerjaan_alumni
-		SET is_delete = 'tidak', updated_at = $2
-		WHERE id = $1 AND is_delete = 'hapus'`
+		SET is_delete = 'tidak', updated_at = $2, trashed_at = NULL, trashed_by = NULL
+		WHERE id = $1 AND is_delete = 'hapus' AND NOT dihapus_via_alumni`
 	now := time.Now()
 
 	result, err := r.db.Exec(sqlStatement, id, now)
@@ -413,7 +483,7 @@ func (r *pekerjaanAlumniRepository) RestoreIfTrashed(id int) (model.Trash, error
 	}
 	rowsAffected, _ := result.RowsAffected()
 	if rowsAffected == 0 {
-		return model.Trash{}, sql.ErrNoRows
+		return model.Trash{}, r.trashMissReason(id)
 	}
 	
 	sqlSelect := `SELECT id, alumni_id, is_delete, updated_at 
@@ -431,3 +501,201 @@ func (r *pekerjaanAlumniRepository) RestoreIfTrashed(id int) (model.Trash, error
 	
 	return trash, nil
 }
+
+// trashedColumnsUpdate mengisi trashed_at/trashed_by saat is_delete ($1) menjadi 'hapus'
+// (mempertahankan nilai lama jika sudah di trash) dan mengosongkannya saat dikembalikan.
+// Membutuhkan $2 = waktu sekarang dan $4 = user pelaku.
+const trashedColumnsUpdate = `trashed_at = CASE WHEN $1 = 'hapus' THEN COALESCE(trashed_at, $2) ELSE NULL END,
+		    trashed_by = CASE WHEN $1 = 'hapus' AND trashed_at IS NULL THEN $4
+		                      WHEN $1 = 'hapus' THEN trashed_by ELSE NULL END`
+
+func scanTrash(scanner interface{ Scan(...interface{}) error }) (model.Trash, error) {
+	var trash model.Trash
+	var trashedAt sql.NullTime
+	var trashedBy sql.NullInt64
+	err := scanner.Scan(
+		&trash.ID, &trash.AlumniID, &trash.IsDelete, &trashedAt, &trashedBy, &trash.UpdatedAt,
+	)
+	if trashedAt.Valid {
+		trash.TrashedAt = &trashedAt.Time
+	}
+	if trashedBy.Valid {
+		trash.TrashedBy = int(trashedBy.Int64)
+	}
+	return trash, err
+}
+
+// GetPurgeCandidates mengambil pekerjaan di trash yang akan dihapus permanen paling awal.
+// Pekerjaan yang ikut terhapus bersama alumninya tidak dihapus otomatis karena mengikuti trash alumni.
+func (r *pekerjaanAlumniRepository) GetPurgeCandidates(retention time.Duration, limit int) ([]model.TrashPurgeCandidate, error) {
+	sqlStatement := `SELECT id, alumni_id, trashed_at, trashed_by
+		FROM pekerjaan_alumni
+		WHERE is_delete = 'hapus' AND trashed_at IS NOT NULL AND NOT dihapus_via_alumni
+		ORDER BY trashed_at ASC
+		LIMIT $1`
+
+	rows, err := r.db.Query(sqlStatement, limit)
+	if err != nil {
+		log.Println("Error memanggil kandidat purge pekerjaan_alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []model.TrashPurgeCandidate{}
+	for rows.Next() {
+		var candidate model.TrashPurgeCandidate
+		var trashedBy sql.NullInt64
+		if err := rows.Scan(&candidate.ID, &candidate.AlumniID, &candidate.TrashedAt, &trashedBy); err != nil {
+			log.Println("Error men-scan kandidat purge pekerjaan_alumni:", err)
+			return nil, err
+		}
+		if trashedBy.Valid {
+			candidate.TrashedBy = int(trashedBy.Int64)
+		}
+		candidate.PurgeAt = candidate.TrashedAt.Add(retention)
+		candidates = append(candidates, candidate)
+	}
+	return candidates, nil
+}
+
+// PurgeExpiredTrash menghapus permanen maksimal batchSize pekerjaan yang sudah di trash lebih lama dari retention.
+// Berkas yang ikut terhapus dikembalikan agar object-nya bisa dihapus dari storage.
+func (r *pekerjaanAlumniRepository) PurgeExpiredTrash(retention time.Duration, batchSize int) (int64, []model.Berkas, error) {
+	sqlStatement := `DELETE FROM pekerjaan_alumni
+		WHERE id IN (
+		    SELECT id FROM pekerjaan_alumni
+		    WHERE is_delete = 'hapus' AND trashed_at <= $1 AND NOT dihapus_via_alumni
+		    ORDER BY trashed_at ASC
+		    LIMIT $2
+		)
+		RETURNING id`
+
+	purged, berkas, err := hapusPekerjaanDenganBerkas(r.db, sqlStatement, time.Now().Add(-retention), batchSize)
+	if err != nil {
+		log.Println("Error purge trash pekerjaan_alumni:", err)
+		return 0, nil, err
+	}
+	return purged, berkas, nil
+}
+
+// GetTrashedIDs mengambil id pekerjaan di trash sesuai filter alumni dan rentang tanggal masuk trash.
+// Pekerjaan yang ikut terhapus bersama alumninya tidak disertakan.
+func (r *pekerjaanAlumniRepository) GetTrashedIDs(filter model.TrashFilter) ([]int, error) {
+	conditions := []string{"is_delete = 'hapus'", "NOT dihapus_via_alumni"}
+	args := []interface{}{}
+
+	if filter.AlumniID != 0 {
+		args = append(args, filter.AlumniID)
+		conditions = append(conditions, fmt.Sprintf("alumni_id = $%d", len(args)))
+	}
+	if filter.TrashedFrom != "" {
+		args = append(args, filter.TrashedFrom)
+		conditions = append(conditions, fmt.Sprintf("trashed_at >= $%d::date", len(args)))
+	}
+	if filter.TrashedTo != "" {
+		args = append(args, filter.TrashedTo)
+		conditions = append(conditions, fmt.Sprintf("trashed_at < $%d::date + 1", len(args)))
+	}
+
+	sqlStatement := `SELECT id FROM pekerjaan_alumni WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY id`
+	rows, err := r.db.Query(sqlStatement, args...)
+	if err != nil {
+		log.Println("Error memanggil id trash pekerjaan_alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			log.Println("Error men-scan id trash pekerjaan_alumni:", err)
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (r *pekerjaanAlumniRepository) UpdateStatus(id int, status string, tanggalSelesai model.Tanggal) (model.PekerjaanAlumni, error) {
+	sqlStatement := `UPDATE pekerjaan_alumni 
+		SET status_pekerjaan = $1, tanggal_selesai_kerja = $2, updated_at = $3
+		WHERE id = $4 AND is_delete IS DISTINCT FROM 'hapus'`
+
+	result, err := r.db.Exec(sqlStatement, status, tanggalSelesai, time.Now(), id)
+	if err != nil {
+		log.Println("Error updating status pekerjaan alumni:", err)
+		return model.PekerjaanAlumni{}, err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return model.PekerjaanAlumni{}, sql.ErrNoRows
+	}
+
+	return r.GetByID(id, model.ScopeActive)
+}
+
+func (r *pekerjaanAlumniRepository) AddStatusHistory(history model.StatusPekerjaanHistory) error {
+	sqlStatement := `INSERT INTO status_pekerjaan_history (pekerjaan_id, status_lama, status_baru, diubah_oleh, catatan, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.db.Exec(sqlStatement,
+		history.PekerjaanID, history.StatusLama, history.StatusBaru,
+		nullableInt(history.DiubahOleh), history.Catatan, time.Now(),
+	)
+	if err != nil {
+		log.Println("Error inserting riwayat status pekerjaan:", err)
+	}
+	return err
+}
+
+func (r *pekerjaanAlumniRepository) GetStatusHistory(pekerjaanID int) ([]model.StatusPekerjaanHistory, error) {
+	sqlStatement := `SELECT id, pekerjaan_id, status_lama, status_baru, diubah_oleh, catatan, created_at
+		FROM status_pekerjaan_history
+		WHERE pekerjaan_id = $1
+		ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.Query(sqlStatement, pekerjaanID)
+	if err != nil {
+		log.Println("Error men-query riwayat status pekerjaan:", err)
+		return nil, err
+	}
+	return scanStatusHistoryRows(rows)
+}
+
+// GetStatusHistoryByAlumniID mengambil riwayat status seluruh pekerjaan aktif (bukan trash) milik alumni
+func (r *pekerjaanAlumniRepository) GetStatusHistoryByAlumniID(alumniID int) ([]model.StatusPekerjaanHistory, error) {
+	sqlStatement := `SELECT h.id, h.pekerjaan_id, h.status_lama, h.status_baru, h.diubah_oleh, h.catatan, h.created_at
+		FROM status_pekerjaan_history h
+		JOIN pekerjaan_alumni p ON p.id = h.pekerjaan_id
+		WHERE p.alumni_id = $1 AND ` + scopeCondition(model.ScopeActive) + `
+		ORDER BY h.created_at ASC, h.id ASC`
+
+	rows, err := r.db.Query(sqlStatement, alumniID)
+	if err != nil {
+		log.Println("Error men-query riwayat status pekerjaan alumni:", err)
+		return nil, err
+	}
+	return scanStatusHistoryRows(rows)
+}
+
+func scanStatusHistoryRows(rows *sql.Rows) ([]model.StatusPekerjaanHistory, error) {
+	defer rows.Close()
+
+	historyList := []model.StatusPekerjaanHistory{}
+	for rows.Next() {
+		var history model.StatusPekerjaanHistory
+		var diubahOleh sql.NullInt64
+		if err := rows.Scan(
+			&history.ID, &history.PekerjaanID, &history.StatusLama, &history.StatusBaru,
+			&diubahOleh, &history.Catatan, &history.CreatedAt,
+		); err != nil {
+			log.Println("Error men-scan riwayat status pekerjaan:", err)
+			return nil, err
+		}
+		history.DiubahOleh = int(diubahOleh.Int64)
+		historyList = append(historyList, history)
+	}
+	return historyList, nil
+}
@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"hello-fiber/app/model"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PendidikanAlumniRepository interface {
+	GetByAlumni(alumniID int) ([]model.PendidikanAlumni, error)
+	GetByAlumniIDs(alumniIDs []int) (map[int][]model.PendidikanAlumni, error)
+	GetByID(alumniID, id int) (model.PendidikanAlumni, error)
+	Create(alumniID int, req model.PendidikanAlumniRequest) (model.PendidikanAlumni, error)
+	Update(alumniID, id int, req model.PendidikanAlumniRequest) (model.PendidikanAlumni, error)
+	Delete(alumniID, id int) error
+}
+
+// ErrPendidikanTerakhir pendidikan satu-satunya tidak boleh dihapus karena menjadi sumber kolom jurusan alumni
+var ErrPendidikanTerakhir = errors.New("pendidikan terakhir alumni tidak dapat dihapus")
+
+type pendidikanAlumniRepository struct {
+	db *sql.DB
+}
+
+func NewPendidikanAlumniRepository(db *sql.DB) PendidikanAlumniRepository {
+	return &pendidikanAlumniRepository{db: db}
+}
+
+const pendidikanColumns = `id, alumni_id, jenjang, program_studi, tahun_masuk, tahun_lulus, ipk, judul_tugas_akhir,
+	is_utama, created_at, updated_at`
+
+func scanPendidikan(scanner interface{ Scan(...interface{}) error }) (model.PendidikanAlumni, error) {
+	var p model.PendidikanAlumni
+	var tahunLulus sql.NullInt64
+	var ipk sql.NullFloat64
+
+	err := scanner.Scan(
+		&p.ID, &p.AlumniID, &p.Jenjang, &p.ProgramStudi, &p.TahunMasuk, &tahunLulus, &ipk, &p.JudulTugasAkhir,
+		&p.IsUtama, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return p, err
+	}
+	if tahunLulus.Valid {
+		tahun := int(tahunLulus.Int64)
+		p.TahunLulus = &tahun
+	}
+	if ipk.Valid {
+		p.IPK = &ipk.Float64
+	}
+	return p, nil
+}
+
+func nullableIntPtr(value *int) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+func nullableFloatPtr(value *float64) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
+
+// sinkronPendidikanUtama menyalin jurusan/angkatan/tahun_lulus alumni ke pendidikan utamanya, dipanggil
+// setiap kali kolom itu diubah lewat API alumni. Jika alumni belum punya pendidikan utama, baris dengan
+// program studi yang sama dijadikan utama; jika tidak ada, pendidikan baru dibuat dengan jenjang (default S1).
+func sinkronPendidikanUtama(db DBTX, alumniID int, jenjang string) error {
+	now := time.Now()
+	result, err := db.Exec(`UPDATE pendidikan_alumni p
+		SET program_studi = a.jurusan, tahun_masuk = a.angkatan,
+		    tahun_lulus = CASE WHEN a.tahun_lulus >= a.angkatan THEN a.tahun_lulus END,
+		    jenjang = COALESCE(NULLIF($2, ''), p.jenjang), updated_at = $3
+		FROM alumni a
+		WHERE a.id = p.alumni_id AND p.alumni_id = $1 AND p.is_utama`, alumniID, jenjang, now)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	result, err = db.Exec(`UPDATE pendidikan_alumni SET is_utama = TRUE, updated_at = $2
+		WHERE id = (
+		    SELECT p.id FROM pendidikan_alumni p JOIN alumni a ON a.id = p.alumni_id
+		    WHERE p.alumni_id = $1 AND lower(p.program_studi) = lower(a.jurusan)
+		    ORDER BY p.tahun_masuk, p.id LIMIT 1
+		)`, alumniID, now)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return nil
+	}
+
+	if jenjang == "" {
+		jenjang = model.JenjangDefault
+	}
+	_, err = db.Exec(`INSERT INTO pendidikan_alumni (alumni_id, jenjang, program_studi, tahun_masuk, tahun_lulus, is_utama, created_at, updated_at)
+		SELECT id, $2, jurusan, angkatan, CASE WHEN tahun_lulus >= angkatan THEN tahun_lulus END, TRUE, $3, $3
+		FROM alumni WHERE id = $1`, alumniID, jenjang, now)
+	return err
+}
+
+// sinkronAlumniDariPendidikan kebalikan sinkronPendidikanUtama: kolom jurusan/angkatan/tahun_lulus alumni
+// diisi dari pendidikan utamanya setelah riwayat pendidikan diubah
+func sinkronAlumniDariPendidikan(db DBTX, alumniID int) error {
+	_, err := db.Exec(`UPDATE alumni a
+		SET jurusan = p.program_studi, angkatan = p.tahun_masuk, tahun_lulus = COALESCE(p.tahun_lulus, 0), updated_at = $2
+		FROM pendidikan_alumni p
+		WHERE p.alumni_id = a.id AND a.id = $1 AND p.is_utama`, alumniID, time.Now())
+	return err
+}
+
+func (r *pendidikanAlumniRepository) GetByAlumni(alumniID int) ([]model.PendidikanAlumni, error) {
+	list, err := r.GetByAlumniIDs([]int{alumniID})
+	if err != nil {
+		return nil, err
+	}
+	if list[alumniID] == nil {
+		return []model.PendidikanAlumni{}, nil
+	}
+	return list[alumniID], nil
+}
+
+// GetByAlumniIDs riwayat pendidikan beberapa alumni sekaligus, diurutkan dari yang paling awal
+func (r *pendidikanAlumniRepository) GetByAlumniIDs(alumniIDs []int) (map[int][]model.PendidikanAlumni, error) {
+	result := map[int][]model.PendidikanAlumni{}
+	if len(alumniIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(`SELECT `+pendidikanColumns+` FROM pendidikan_alumni
+		WHERE alumni_id = ANY($1) ORDER BY alumni_id, tahun_masuk, id`, pq.Array(alumniIDs))
+	if err != nil {
+		log.Println("Error men-query pendidikan alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanPendidikan(rows)
+		if err != nil {
+			log.Println("Error men-scan pendidikan alumni:", err)
+			return nil, err
+		}
+		result[p.AlumniID] = append(result[p.AlumniID], p)
+	}
+	return result, rows.Err()
+}
+
+func (r *pendidikanAlumniRepository) GetByID(alumniID, id int) (model.PendidikanAlumni, error) {
+	p, err := scanPendidikan(r.db.QueryRow(`SELECT `+pendidikanColumns+` FROM pendidikan_alumni
+		WHERE id = $1 AND alumni_id = $2`, id, alumniID))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil pendidikan alumni:", err)
+	}
+	return p, err
+}
+
+// Create menambah riwayat pendidikan; pendidikan pertama alumni selalu menjadi utama
+func (r *pendidikanAlumniRepository) Create(alumniID int, req model.PendidikanAlumniRequest) (model.PendidikanAlumni, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi pendidikan alumni:", err)
+		return model.PendidikanAlumni{}, err
+	}
+	defer tx.Rollback()
+
+	var adaUtama bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM pendidikan_alumni WHERE alumni_id = $1 AND is_utama)`, alumniID).Scan(&adaUtama); err != nil {
+		log.Println("Error memeriksa pendidikan utama alumni:", err)
+		return model.PendidikanAlumni{}, err
+	}
+	utama := req.IsUtama || !adaUtama
+	now := time.Now()
+	if utama {
+		if _, err := tx.Exec(`UPDATE pendidikan_alumni SET is_utama = FALSE, updated_at = $2 WHERE alumni_id = $1 AND is_utama`, alumniID, now); err != nil {
+			log.Println("Error melepas pendidikan utama alumni:", err)
+			return model.PendidikanAlumni{}, err
+		}
+	}
+
+	p, err := scanPendidikan(tx.QueryRow(`INSERT INTO pendidikan_alumni
+			(alumni_id, jenjang, program_studi, tahun_masuk, tahun_lulus, ipk, judul_tugas_akhir, is_utama, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		RETURNING `+pendidikanColumns,
+		alumniID, req.Jenjang, req.ProgramStudi, req.TahunMasuk, nullableIntPtr(req.TahunLulus), nullableFloatPtr(req.IPK),
+		req.JudulTugasAkhir, utama, now,
+	))
+	if err != nil {
+		log.Println("Error menyimpan pendidikan alumni:", err)
+		return model.PendidikanAlumni{}, err
+	}
+	if utama {
+		if err := sinkronAlumniDariPendidikan(tx, alumniID); err != nil {
+			log.Println("Error menyinkronkan jurusan alumni:", err)
+			return model.PendidikanAlumni{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.PendidikanAlumni{}, err
+	}
+	return p, nil
+}
+
+// Update mengubah riwayat pendidikan. Pendidikan utama hanya dapat diganti dengan menjadikan pendidikan
+// lain utama, sehingga IsUtama=false pada pendidikan utama diabaikan.
+func (r *pendidikanAlumniRepository) Update(alumniID, id int, req model.PendidikanAlumniRequest) (model.PendidikanAlumni, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi pendidikan alumni:", err)
+		return model.PendidikanAlumni{}, err
+	}
+	defer tx.Rollback()
+
+	current, err := scanPendidikan(tx.QueryRow(`SELECT `+pendidikanColumns+` FROM pendidikan_alumni
+		WHERE id = $1 AND alumni_id = $2 FOR UPDATE`, id, alumniID))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error mengambil pendidikan alumni:", err)
+		}
+		return model.PendidikanAlumni{}, err
+	}
+
+	utama := current.IsUtama || req.IsUtama
+	now := time.Now()
+	if utama && !current.IsUtama {
+		if _, err := tx.Exec(`UPDATE pendidikan_alumni SET is_utama = FALSE, updated_at = $2 WHERE alumni_id = $1 AND is_utama`, alumniID, now); err != nil {
+			log.Println("Error melepas pendidikan utama alumni:", err)
+			return model.PendidikanAlumni{}, err
+		}
+	}
+
+	p, err := scanPendidikan(tx.QueryRow(`UPDATE pendidikan_alumni
+		SET jenjang = $1, program_studi = $2, tahun_masuk = $3, tahun_lulus = $4, ipk = $5, judul_tugas_akhir = $6,
+		    is_utama = $7, updated_at = $8
+		WHERE id = $9
+		RETURNING `+pendidikanColumns,
+		req.Jenjang, req.ProgramStudi, req.TahunMasuk, nullableIntPtr(req.TahunLulus), nullableFloatPtr(req.IPK),
+		req.JudulTugasAkhir, utama, now, id,
+	))
+	if err != nil {
+		log.Println("Error mengupdate pendidikan alumni:", err)
+		return model.PendidikanAlumni{}, err
+	}
+	if utama {
+		if err := sinkronAlumniDariPendidikan(tx, alumniID); err != nil {
+			log.Println("Error menyinkronkan jurusan alumni:", err)
+			return model.PendidikanAlumni{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.PendidikanAlumni{}, err
+	}
+	return p, nil
+}
+
+// Delete menghapus riwayat pendidikan. Jika yang dihapus pendidikan utama, pendidikan paling awal yang
+// tersisa menjadi utama. Pendidikan terakhir tidak dapat dihapus (ErrPendidikanTerakhir).
+func (r *pendidikanAlumniRepository) Delete(alumniID, id int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi pendidikan alumni:", err)
+		return err
+	}
+	defer tx.Rollback()
+
+	var utama bool
+	var jumlah int
+	err = tx.QueryRow(`SELECT p.is_utama, (SELECT COUNT(*) FROM pendidikan_alumni WHERE alumni_id = $2)
+		FROM pendidikan_alumni p WHERE p.id = $1 AND p.alumni_id = $2 FOR UPDATE`, id, alumniID).Scan(&utama, &jumlah)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error mengambil pendidikan alumni:", err)
+		}
+		return err
+	}
+	if jumlah <= 1 {
+		return ErrPendidikanTerakhir
+	}
+
+	if _, err := tx.Exec(`DELETE FROM pendidikan_alumni WHERE id = $1`, id); err != nil {
+		log.Println("Error menghapus pendidikan alumni:", err)
+		return err
+	}
+	if utama {
+		now := time.Now()
+		_, err := tx.Exec(`UPDATE pendidikan_alumni SET is_utama = TRUE, updated_at = $2
+			WHERE id = (SELECT id FROM pendidikan_alumni WHERE alumni_id = $1 ORDER BY tahun_masuk, id LIMIT 1)`, alumniID, now)
+		if err == nil {
+			err = sinkronAlumniDariPendidikan(tx, alumniID)
+		}
+		if err != nil {
+			log.Println("Error memindahkan pendidikan utama alumni:", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hello-fiber/app/model"
+	"log"
+	"strings"
+	"time"
+)
+
+type PengajuanPekerjaanRepository interface {
+	Create(pengajuan model.PengajuanPekerjaan) (model.PengajuanPekerjaan, error)
+	GetAll(status string, alumniID int) ([]model.PengajuanPekerjaan, error)
+	GetByID(id int) (model.PengajuanPekerjaan, error)
+	Approve(id, reviewerID int, komentar string) (model.PengajuanPekerjaan, error)
+	Reject(id, reviewerID int, komentar string) (model.PengajuanPekerjaan, error)
+}
+
+type pengajuanPekerjaanRepository struct {
+	db *sql.DB
+}
+
+func NewPengajuanPekerjaanRepository(db *sql.DB) PengajuanPekerjaanRepository {
+	return &pengajuanPekerjaanRepository{db: db}
+}
+
+const pengajuanColumns = `id, alumni_id, pekerjaan_id, jenis, data, status, diajukan_oleh, ditinjau_oleh, komentar, created_at, reviewed_at`
+
+func scanPengajuan(scanner interface{ Scan(...interface{}) error }) (model.PengajuanPekerjaan, error) {
+	var p model.PengajuanPekerjaan
+	var pekerjaanID, diajukanOleh, ditinjauOleh sql.NullInt64
+	var data []byte
+	var reviewedAt sql.NullTime
+
+	err := scanner.Scan(
+		&p.ID, &p.AlumniID, &pekerjaanID, &p.Jenis, &data, &p.Status,
+		&diajukanOleh, &ditinjauOleh, &p.Komentar, &p.CreatedAt, &reviewedAt,
+	)
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(data, &p.Data); err != nil {
+		return p, err
+	}
+	p.PekerjaanID = int(pekerjaanID.Int64)
+	p.DiajukanOleh = int(diajukanOleh.Int64)
+	p.DitinjauOleh = int(ditinjauOleh.Int64)
+	if reviewedAt.Valid {
+		p.ReviewedAt = &reviewedAt.Time
+	}
+	return p, nil
+}
+
+func (r *pengajuanPekerjaanRepository) Create(p model.PengajuanPekerjaan) (model.PengajuanPekerjaan, error) {
+	data, err := json.Marshal(p.Data)
+	if err != nil {
+		return model.PengajuanPekerjaan{}, err
+	}
+
+	p.Status = model.StatusPengajuanPending
+	p.CreatedAt = time.Now()
+	err = r.db.QueryRow(`INSERT INTO pengajuan_pekerjaan (alumni_id, pekerjaan_id, jenis, data, status, diajukan_oleh, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		p.AlumniID, nullableInt(p.PekerjaanID), p.Jenis, string(data), p.Status, nullableInt(p.DiajukanOleh), p.CreatedAt,
+	).Scan(&p.ID)
+	if err != nil {
+		log.Println("Error inserting pengajuan pekerjaan:", err)
+		return model.PengajuanPekerjaan{}, err
+	}
+	return p, nil
+}
+
+// GetAll mengambil pengajuan, opsional difilter status dan alumni; yang terlama di depan agar antrean diproses berurutan
+func (r *pengajuanPekerjaanRepository) GetAll(status string, alumniID int) ([]model.PengajuanPekerjaan, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if alumniID != 0 {
+		args = append(args, alumniID)
+		conditions = append(conditions, fmt.Sprintf("alumni_id = $%d", len(args)))
+	}
+
+	rows, err := r.db.Query(`SELECT `+pengajuanColumns+` FROM pengajuan_pekerjaan
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY created_at ASC`, args...)
+	if err != nil {
+		log.Println("Error men-query pengajuan pekerjaan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.PengajuanPekerjaan{}
+	for rows.Next() {
+		p, err := scanPengajuan(rows)
+		if err != nil {
+			log.Println("Error men-scan pengajuan pekerjaan:", err)
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+func (r *pengajuanPekerjaanRepository) GetByID(id int) (model.PengajuanPekerjaan, error) {
+	p, err := scanPengajuan(r.db.QueryRow(`SELECT `+pengajuanColumns+` FROM pengajuan_pekerjaan WHERE id = $1`, id))
+	if err != nil {
+		log.Println("Error menemukan pengajuan pekerjaan by ID:", err)
+		return model.PengajuanPekerjaan{}, err
+	}
+	return p, nil
+}
+
+// Approve menerapkan pengajuan ke pekerjaan_alumni dan menandainya approved dalam satu transaksi.
+// Mengembalikan sql.ErrNoRows jika pengajuan tidak pending atau pekerjaan yang diubah sudah tidak aktif.
+func (r *pengajuanPekerjaanRepository) Approve(id, reviewerID int, komentar string) (model.PengajuanPekerjaan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi approve pengajuan:", err)
+		return model.PengajuanPekerjaan{}, err
+	}
+	defer tx.Rollback()
+
+	p, err := scanPengajuan(tx.QueryRow(`SELECT `+pengajuanColumns+` FROM pengajuan_pekerjaan
+		WHERE id = $1 AND status = $2 FOR UPDATE`, id, model.StatusPengajuanPending))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error menemukan pengajuan pekerjaan pending:", err)
+		}
+		return model.PengajuanPekerjaan{}, err
+	}
+
+	pekerjaanRepo := NewPekerjaanAlumniRepositoryTx(tx)
+	revisiRepo := NewRevisiRepositoryTx(tx)
+	switch p.Jenis {
+	case model.JenisPengajuanCreate:
+		pekerjaan, err := pekerjaanRepo.Create(model.CreatePekerjaanAlumniRequest{
+			AlumniID:            p.AlumniID,
+			PerusahaanID:        p.Data.PerusahaanID,
+			NamaPerusahaan:      p.Data.NamaPerusahaan,
+			PosisiJabatan:       p.Data.PosisiJabatan,
+			BidangIndustri:      p.Data.BidangIndustri,
+			LokasiKerja:         p.Data.LokasiKerja,
+			GajiRange:           p.Data.GajiRange,
+			Gaji:                p.Data.Gaji,
+			TanggalMulaiKerja:   p.Data.TanggalMulaiKerja,
+			TanggalSelesaiKerja: p.Data.TanggalSelesaiKerja,
+			StatusPekerjaan:     p.Data.StatusPekerjaan,
+			DeskripsiPekerjaan:  p.Data.DeskripsiPekerjaan,
+		})
+		if err != nil {
+			return model.PengajuanPekerjaan{}, err
+		}
+		p.PekerjaanID = pekerjaan.ID
+		err = pekerjaanRepo.AddStatusHistory(model.StatusPekerjaanHistory{
+			PekerjaanID: pekerjaan.ID,
+			StatusBaru:  pekerjaan.StatusPekerjaan,
+			DiubahOleh:  reviewerID,
+			Catatan:     fmt.Sprintf("Pengajuan #%d", p.ID),
+		})
+		if err != nil {
+			return model.PengajuanPekerjaan{}, err
+		}
+		_, err = revisiRepo.Record(model.RevisiEntitasPekerjaan, pekerjaan.ID, nil, pekerjaan, model.RevisiAksiBuat, 0, reviewerID)
+		if err != nil {
+			return model.PengajuanPekerjaan{}, err
+		}
+	case model.JenisPengajuanUpdate:
+		current, err := pekerjaanRepo.GetByID(p.PekerjaanID, model.ScopeActive)
+		if err != nil {
+			return model.PengajuanPekerjaan{}, err
+		}
+		updated, err := pekerjaanRepo.Update(p.PekerjaanID, model.UpdatePekerjaanAlumniRequest{
+			PerusahaanID:        p.Data.PerusahaanID,
+			NamaPerusahaan:      p.Data.NamaPerusahaan,
+			PosisiJabatan:       p.Data.PosisiJabatan,
+			BidangIndustri:      p.Data.BidangIndustri,
+			LokasiKerja:         p.Data.LokasiKerja,
+			GajiRange:           p.Data.GajiRange,
+			Gaji:                p.Data.Gaji,
+			TanggalMulaiKerja:   p.Data.TanggalMulaiKerja,
+			TanggalSelesaiKerja: p.Data.TanggalSelesaiKerja,
+			StatusPekerjaan:     p.Data.StatusPekerjaan,
+			DeskripsiPekerjaan:  p.Data.DeskripsiPekerjaan,
+		})
+		if err != nil {
+			return model.PengajuanPekerjaan{}, err
+		}
+		if updated.StatusPekerjaan != current.StatusPekerjaan {
+			err = pekerjaanRepo.AddStatusHistory(model.StatusPekerjaanHistory{
+				PekerjaanID: p.PekerjaanID,
+				StatusLama:  current.StatusPekerjaan,
+				StatusBaru:  updated.StatusPekerjaan,
+				DiubahOleh:  reviewerID,
+				Catatan:     fmt.Sprintf("Pengajuan #%d", p.ID),
+			})
+			if err != nil {
+				return model.PengajuanPekerjaan{}, err
+			}
+		}
+		_, err = revisiRepo.Record(model.RevisiEntitasPekerjaan, p.PekerjaanID, current, updated, model.RevisiAksiUbah, 0, reviewerID)
+		if err != nil {
+			return model.PengajuanPekerjaan{}, err
+		}
+	default:
+		return model.PengajuanPekerjaan{}, fmt.Errorf("jenis pengajuan tidak dikenal: %s", p.Jenis)
+	}
+
+	if err := r.markReviewed(tx, &p, model.StatusPengajuanApproved, reviewerID, komentar); err != nil {
+		return model.PengajuanPekerjaan{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit approve pengajuan:", err)
+		return model.PengajuanPekerjaan{}, err
+	}
+	return p, nil
+}
+
+func (r *pengajuanPekerjaanRepository) Reject(id, reviewerID int, komentar string) (model.PengajuanPekerjaan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi reject pengajuan:", err)
+		return model.PengajuanPekerjaan{}, err
+	}
+	defer tx.Rollback()
+
+	p, err := scanPengajuan(tx.QueryRow(`SELECT `+pengajuanColumns+` FROM pengajuan_pekerjaan
+		WHERE id = $1 AND status = $2 FOR UPDATE`, id, model.StatusPengajuanPending))
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error menemukan pengajuan pekerjaan pending:", err)
+		}
+		return model.PengajuanPekerjaan{}, err
+	}
+
+	if err := r.markReviewed(tx, &p, model.StatusPengajuanRejected, reviewerID, komentar); err != nil {
+		return model.PengajuanPekerjaan{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit reject pengajuan:", err)
+		return model.PengajuanPekerjaan{}, err
+	}
+	return p, nil
+}
+
+func (r *pengajuanPekerjaanRepository) markReviewed(tx *sql.Tx, p *model.PengajuanPekerjaan, status string, reviewerID int, komentar string) error {
+	now := time.Now()
+	_, err := tx.Exec(`UPDATE pengajuan_pekerjaan
+		SET status = $1, pekerjaan_id = $2, ditinjau_oleh = $3, komentar = $4, reviewed_at = $5
+		WHERE id = $6`,
+		status, nullableInt(p.PekerjaanID), nullableInt(reviewerID), komentar, now, p.ID,
+	)
+	if err != nil {
+		log.Println("Error memperbarui status pengajuan pekerjaan:", err)
+		return err
+	}
+	p.Status = status
+	p.DitinjauOleh = reviewerID
+	p.Komentar = komentar
+	p.ReviewedAt = &now
+	return nil
+}
@@ -0,0 +1,501 @@
+package repository
+
+import (
+	"database/sql"
+	"hello-fiber/app/model"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PerusahaanRepository interface {
+	GetAll(search string, limit, offset int) ([]model.Perusahaan, error)
+	Count(search string) (int, error)
+	GetByID(id int) (model.Perusahaan, error)
+	FindByNama(nama string) (model.Perusahaan, error)
+	Create(req model.PerusahaanRequest) (model.Perusahaan, error)
+	Update(id int, req model.PerusahaanRequest) (model.Perusahaan, error)
+	Delete(id int) error
+	CountPekerjaan(id int) (int, error)
+	CountLowongan(id int) (int, error)
+	FindMatches(nama string, limit int) ([]model.PerusahaanMatch, error)
+	FindDuplicates(minSkor float64, limit int) ([]model.DuplikatPerusahaan, error)
+	Merge(targetID int, sumberIDs []int) (model.MergePerusahaanResult, error)
+	BackfillPekerjaan() (int, error)
+}
+
+type perusahaanRepository struct {
+	db *sql.DB
+}
+
+func NewPerusahaanRepository(db *sql.DB) PerusahaanRepository {
+	return &perusahaanRepository{db: db}
+}
+
+// perusahaanColumns kolom perusahaan (alias p) beserta alias dan jumlah alumni yang bekerja di sana
+const perusahaanColumns = `p.id, p.nama, p.bidang_industri, p.kota, p.website, p.created_at, p.updated_at,
+		COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM perusahaan_alias a WHERE a.perusahaan_id = p.id), '{}'),
+		(SELECT COUNT(DISTINCT pa.alumni_id) FROM pekerjaan_alumni pa
+		 WHERE pa.perusahaan_id = p.id AND pa.is_delete IS DISTINCT FROM 'hapus')`
+
+func scanPerusahaan(scanner interface{ Scan(...interface{}) error }, extra ...interface{}) (model.Perusahaan, error) {
+	var p model.Perusahaan
+	dest := []interface{}{
+		&p.ID, &p.Nama, &p.BidangIndustri, &p.Kota, &p.Website, &p.CreatedAt, &p.UpdatedAt,
+		pq.Array(&p.Alias), &p.JumlahAlumni,
+	}
+	err := scanner.Scan(append(dest, extra...)...)
+	if p.Alias == nil {
+		p.Alias = []string{}
+	}
+	return p, err
+}
+
+func (r *perusahaanRepository) GetAll(search string, limit, offset int) ([]model.Perusahaan, error) {
+	rows, err := r.db.Query(`SELECT `+perusahaanColumns+`
+		FROM perusahaan p
+		WHERE p.nama ILIKE $1
+		   OR EXISTS (SELECT 1 FROM perusahaan_alias a WHERE a.perusahaan_id = p.id AND a.alias ILIKE $1)
+		ORDER BY p.nama ASC
+		LIMIT $2 OFFSET $3`, "%"+search+"%", limit, offset)
+	if err != nil {
+		log.Println("Error men-query perusahaan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.Perusahaan{}
+	for rows.Next() {
+		p, err := scanPerusahaan(rows)
+		if err != nil {
+			log.Println("Error men-scan perusahaan:", err)
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+func (r *perusahaanRepository) Count(search string) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM perusahaan p
+		WHERE p.nama ILIKE $1
+		   OR EXISTS (SELECT 1 FROM perusahaan_alias a WHERE a.perusahaan_id = p.id AND a.alias ILIKE $1)`,
+		"%"+search+"%",
+	).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung perusahaan:", err)
+	}
+	return total, err
+}
+
+func (r *perusahaanRepository) GetByID(id int) (model.Perusahaan, error) {
+	p, err := scanPerusahaan(r.db.QueryRow(`SELECT `+perusahaanColumns+` FROM perusahaan p WHERE p.id = $1`, id))
+	if err != nil {
+		log.Println("Error menemukan perusahaan by ID:", err)
+		return model.Perusahaan{}, err
+	}
+	return p, nil
+}
+
+// FindByNama mencari perusahaan yang nama atau aliasnya sama setelah dinormalisasi
+func (r *perusahaanRepository) FindByNama(nama string) (model.Perusahaan, error) {
+	id, err := findPerusahaanIDByNama(r.db, model.NormalizeNamaPerusahaan(nama))
+	if err != nil {
+		return model.Perusahaan{}, err
+	}
+	return r.GetByID(id)
+}
+
+func findPerusahaanIDByNama(db DBTX, normal string) (int, error) {
+	var id int
+	err := db.QueryRow(`SELECT id FROM perusahaan WHERE nama_normal = $1
+		UNION ALL
+		SELECT perusahaan_id FROM perusahaan_alias WHERE alias_normal = $1
+		LIMIT 1`, normal,
+	).Scan(&id)
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mencari perusahaan by nama:", err)
+	}
+	return id, err
+}
+
+func (r *perusahaanRepository) Create(req model.PerusahaanRequest) (model.Perusahaan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi perusahaan:", err)
+		return model.Perusahaan{}, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	var id int
+	err = tx.QueryRow(`INSERT INTO perusahaan (nama, nama_normal, bidang_industri, kota, website, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		req.Nama, model.NormalizeNamaPerusahaan(req.Nama), req.BidangIndustri, req.Kota, req.Website, now, now,
+	).Scan(&id)
+	if err != nil {
+		log.Println("Error inserting perusahaan:", err)
+		return model.Perusahaan{}, err
+	}
+	if err := insertPerusahaanAlias(tx, id, req.Alias); err != nil {
+		return model.Perusahaan{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit perusahaan:", err)
+		return model.Perusahaan{}, err
+	}
+	return r.GetByID(id)
+}
+
+func insertPerusahaanAlias(db DBTX, perusahaanID int, aliases []string) error {
+	for _, alias := range aliases {
+		normal := model.NormalizeNamaPerusahaan(alias)
+		if normal == "" {
+			continue
+		}
+		_, err := db.Exec(`INSERT INTO perusahaan_alias (perusahaan_id, alias, alias_normal)
+			VALUES ($1, $2, $3) ON CONFLICT (alias_normal) DO NOTHING`,
+			perusahaanID, strings.TrimSpace(alias), normal,
+		)
+		if err != nil {
+			log.Println("Error inserting alias perusahaan:", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// Update mengganti data perusahaan beserta seluruh aliasnya; nama pada pekerjaan terkait ikut diperbarui
+func (r *perusahaanRepository) Update(id int, req model.PerusahaanRequest) (model.Perusahaan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi perusahaan:", err)
+		return model.Perusahaan{}, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE perusahaan
+		SET nama = $1, nama_normal = $2, bidang_industri = $3, kota = $4, website = $5, updated_at = $6
+		WHERE id = $7`,
+		req.Nama, model.NormalizeNamaPerusahaan(req.Nama), req.BidangIndustri, req.Kota, req.Website, time.Now(), id,
+	)
+	if err != nil {
+		log.Println("Error updating perusahaan:", err)
+		return model.Perusahaan{}, err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return model.Perusahaan{}, sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(`DELETE FROM perusahaan_alias WHERE perusahaan_id = $1`, id); err != nil {
+		log.Println("Error menghapus alias perusahaan:", err)
+		return model.Perusahaan{}, err
+	}
+	if err := insertPerusahaanAlias(tx, id, req.Alias); err != nil {
+		return model.Perusahaan{}, err
+	}
+	if _, err := tx.Exec(`UPDATE pekerjaan_alumni SET nama_perusahaan = $1 WHERE perusahaan_id = $2`, req.Nama, id); err != nil {
+		log.Println("Error memperbarui nama perusahaan pada pekerjaan:", err)
+		return model.Perusahaan{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit perusahaan:", err)
+		return model.Perusahaan{}, err
+	}
+	return r.GetByID(id)
+}
+
+func (r *perusahaanRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM perusahaan WHERE id = $1`, id)
+	if err != nil {
+		log.Println("Error deleting perusahaan:", err)
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountPekerjaan menghitung seluruh pekerjaan (termasuk di trash) yang merujuk perusahaan
+func (r *perusahaanRepository) CountPekerjaan(id int) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM pekerjaan_alumni WHERE perusahaan_id = $1`, id).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung pekerjaan perusahaan:", err)
+	}
+	return total, err
+}
+
+// CountLowongan menghitung seluruh lowongan (apa pun statusnya) milik perusahaan
+func (r *perusahaanRepository) CountLowongan(id int) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM lowongan WHERE perusahaan_id = $1`, id).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung lowongan perusahaan:", err)
+	}
+	return total, err
+}
+
+// FindMatches mencari perusahaan yang nama atau aliasnya mirip (trigram), termasuk nama yang
+// memuat kata yang dicari, mis. "telkom" cocok dengan "Telkom Indonesia"
+func (r *perusahaanRepository) FindMatches(nama string, limit int) ([]model.PerusahaanMatch, error) {
+	normal := model.NormalizeNamaPerusahaan(nama)
+	if normal == "" {
+		return []model.PerusahaanMatch{}, nil
+	}
+
+	rows, err := r.db.Query(`WITH kandidat AS (
+			SELECT id AS perusahaan_id, nama AS cocok,
+			       GREATEST(similarity(nama_normal, $1), word_similarity($1, nama_normal)) AS skor
+			FROM perusahaan
+			WHERE nama_normal % $1 OR $1 <% nama_normal
+			UNION ALL
+			SELECT perusahaan_id, alias,
+			       GREATEST(similarity(alias_normal, $1), word_similarity($1, alias_normal))
+			FROM perusahaan_alias
+			WHERE alias_normal % $1 OR $1 <% alias_normal
+		), terbaik AS (
+			SELECT DISTINCT ON (perusahaan_id) perusahaan_id, cocok, skor
+			FROM kandidat
+			ORDER BY perusahaan_id, skor DESC
+		)
+		SELECT `+perusahaanColumns+`, t.cocok, t.skor
+		FROM terbaik t
+		JOIN perusahaan p ON p.id = t.perusahaan_id
+		ORDER BY t.skor DESC, p.nama ASC
+		LIMIT $2`, normal, limit)
+	if err != nil {
+		log.Println("Error mencocokkan nama perusahaan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []model.PerusahaanMatch{}
+	for rows.Next() {
+		var m model.PerusahaanMatch
+		m.Perusahaan, err = scanPerusahaan(rows, &m.CocokDengan, &m.Skor)
+		if err != nil {
+			log.Println("Error men-scan kandidat perusahaan:", err)
+			return nil, err
+		}
+		m.Skor = roundPercent(m.Skor)
+		matches = append(matches, m)
+	}
+	return matches, nil
+}
+
+// FindDuplicates mencari pasangan perusahaan dengan nama mirip sebagai saran untuk digabung
+func (r *perusahaanRepository) FindDuplicates(minSkor float64, limit int) ([]model.DuplikatPerusahaan, error) {
+	rows, err := r.db.Query(`SELECT * FROM (
+			SELECT a.id, a.nama, b.id, b.nama,
+			       GREATEST(similarity(a.nama_normal, b.nama_normal),
+			                word_similarity(a.nama_normal, b.nama_normal),
+			                word_similarity(b.nama_normal, a.nama_normal)) AS skor
+			FROM perusahaan a
+			JOIN perusahaan b ON a.id < b.id
+			 AND (a.nama_normal % b.nama_normal OR a.nama_normal <% b.nama_normal OR b.nama_normal <% a.nama_normal)
+		) d
+		WHERE skor >= $1
+		ORDER BY skor DESC
+		LIMIT $2`, minSkor, limit)
+	if err != nil {
+		log.Println("Error mencari duplikat perusahaan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.DuplikatPerusahaan{}
+	for rows.Next() {
+		var d model.DuplikatPerusahaan
+		if err := rows.Scan(&d.PerusahaanID, &d.Nama, &d.DuplikatID, &d.NamaDuplikat, &d.Skor); err != nil {
+			log.Println("Error men-scan duplikat perusahaan:", err)
+			return nil, err
+		}
+		d.Skor = roundPercent(d.Skor)
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// Merge menggabungkan perusahaan sumber ke target dalam satu transaksi: nama dan alias sumber menjadi
+// alias target, pekerjaan dipindahkan ke target, field target yang kosong diisi dari sumber,
+// lalu sumber dihapus. Mengembalikan sql.ErrNoRows jika salah satu perusahaan tidak ditemukan.
+func (r *perusahaanRepository) Merge(targetID int, sumberIDs []int) (model.MergePerusahaanResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi merge perusahaan:", err)
+		return model.MergePerusahaanResult{}, err
+	}
+	defer tx.Rollback()
+
+	ids := append([]int{targetID}, sumberIDs...)
+	rows, err := tx.Query(`SELECT id, nama, bidang_industri, kota, website FROM perusahaan
+		WHERE id = ANY($1) ORDER BY id FOR UPDATE`, pq.Array(ids))
+	if err != nil {
+		log.Println("Error mengunci perusahaan untuk merge:", err)
+		return model.MergePerusahaanResult{}, err
+	}
+	found := map[int]model.Perusahaan{}
+	for rows.Next() {
+		var p model.Perusahaan
+		if err := rows.Scan(&p.ID, &p.Nama, &p.BidangIndustri, &p.Kota, &p.Website); err != nil {
+			rows.Close()
+			log.Println("Error men-scan perusahaan untuk merge:", err)
+			return model.MergePerusahaanResult{}, err
+		}
+		found[p.ID] = p
+	}
+	rows.Close()
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			return model.MergePerusahaanResult{}, sql.ErrNoRows
+		}
+	}
+
+	target := found[targetID]
+	for _, id := range sumberIDs {
+		sumber := found[id]
+		if _, err := tx.Exec(`UPDATE perusahaan_alias SET perusahaan_id = $1 WHERE perusahaan_id = $2`, targetID, id); err != nil {
+			log.Println("Error memindahkan alias perusahaan:", err)
+			return model.MergePerusahaanResult{}, err
+		}
+		if err := insertPerusahaanAlias(tx, targetID, []string{sumber.Nama}); err != nil {
+			return model.MergePerusahaanResult{}, err
+		}
+		if target.BidangIndustri == "" {
+			target.BidangIndustri = sumber.BidangIndustri
+		}
+		if target.Kota == "" {
+			target.Kota = sumber.Kota
+		}
+		if target.Website == "" {
+			target.Website = sumber.Website
+		}
+	}
+
+	result, err := tx.Exec(`UPDATE pekerjaan_alumni SET perusahaan_id = $1, nama_perusahaan = $2
+		WHERE perusahaan_id = ANY($3)`, targetID, target.Nama, pq.Array(sumberIDs))
+	if err != nil {
+		log.Println("Error memindahkan pekerjaan ke perusahaan target:", err)
+		return model.MergePerusahaanResult{}, err
+	}
+	dipindah, _ := result.RowsAffected()
+
+	if _, err := tx.Exec(`UPDATE lowongan SET perusahaan_id = $1 WHERE perusahaan_id = ANY($2)`, targetID, pq.Array(sumberIDs)); err != nil {
+		log.Println("Error memindahkan lowongan ke perusahaan target:", err)
+		return model.MergePerusahaanResult{}, err
+	}
+	if _, err := tx.Exec(`UPDATE users SET perusahaan_id = $1 WHERE perusahaan_id = ANY($2)`, targetID, pq.Array(sumberIDs)); err != nil {
+		log.Println("Error memindahkan mitra ke perusahaan target:", err)
+		return model.MergePerusahaanResult{}, err
+	}
+
+	_, err = tx.Exec(`UPDATE perusahaan SET bidang_industri = $1, kota = $2, website = $3, updated_at = $4 WHERE id = $5`,
+		target.BidangIndustri, target.Kota, target.Website, time.Now(), targetID)
+	if err != nil {
+		log.Println("Error memperbarui perusahaan target:", err)
+		return model.MergePerusahaanResult{}, err
+	}
+	if _, err := tx.Exec(`DELETE FROM perusahaan WHERE id = ANY($1)`, pq.Array(sumberIDs)); err != nil {
+		log.Println("Error menghapus perusahaan sumber:", err)
+		return model.MergePerusahaanResult{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit merge perusahaan:", err)
+		return model.MergePerusahaanResult{}, err
+	}
+
+	perusahaan, err := r.GetByID(targetID)
+	if err != nil {
+		return model.MergePerusahaanResult{}, err
+	}
+	return model.MergePerusahaanResult{Perusahaan: perusahaan, Digabung: sumberIDs, PekerjaanDipindah: dipindah}, nil
+}
+
+// resolvePerusahaan menentukan perusahaan untuk sebuah pekerjaan: berdasarkan id jika diisi, jika tidak
+// berdasarkan nama yang sama setelah dinormalisasi, dan membuat entri baru bila belum ada.
+// Mengembalikan id dan nama kanonis; id 0 jika nama kosong setelah dinormalisasi.
+func resolvePerusahaan(db DBTX, id int, nama, bidangIndustri, kota string) (int, string, error) {
+	if id != 0 {
+		err := db.QueryRow(`SELECT nama FROM perusahaan WHERE id = $1`, id).Scan(&nama)
+		if err != nil {
+			log.Println("Error menemukan perusahaan by ID:", err)
+		}
+		return id, nama, err
+	}
+
+	normal := model.NormalizeNamaPerusahaan(nama)
+	if normal == "" {
+		return 0, nama, nil
+	}
+	id, err := findPerusahaanIDByNama(db, normal)
+	if err == nil {
+		err = db.QueryRow(`SELECT nama FROM perusahaan WHERE id = $1`, id).Scan(&nama)
+		return id, nama, err
+	}
+	if err != sql.ErrNoRows {
+		return 0, "", err
+	}
+
+	nama = strings.TrimSpace(nama)
+	now := time.Now()
+	err = db.QueryRow(`INSERT INTO perusahaan (nama, nama_normal, bidang_industri, kota, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (nama_normal) DO UPDATE SET nama_normal = EXCLUDED.nama_normal
+		RETURNING id, nama`,
+		nama, normal, bidangIndustri, kota, now, now,
+	).Scan(&id, &nama)
+	if err != nil {
+		log.Println("Error inserting perusahaan dari pekerjaan:", err)
+	}
+	return id, nama, err
+}
+
+// BackfillPekerjaan menghubungkan pekerjaan yang belum punya perusahaan_id berdasarkan nama_perusahaan.
+// Mengembalikan jumlah pekerjaan yang berhasil dihubungkan.
+func (r *perusahaanRepository) BackfillPekerjaan() (int, error) {
+	rows, err := r.db.Query(`SELECT id, nama_perusahaan, bidang_industri, lokasi_kerja
+		FROM pekerjaan_alumni WHERE perusahaan_id IS NULL ORDER BY id`)
+	if err != nil {
+		log.Println("Error men-query pekerjaan tanpa perusahaan:", err)
+		return 0, err
+	}
+	type pekerjaanLama struct {
+		id                           int
+		nama, bidangIndustri, lokasi string
+	}
+	var list []pekerjaanLama
+	for rows.Next() {
+		var p pekerjaanLama
+		if err := rows.Scan(&p.id, &p.nama, &p.bidangIndustri, &p.lokasi); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		list = append(list, p)
+	}
+	rows.Close()
+
+	linked := 0
+	for _, p := range list {
+		perusahaanID, _, err := resolvePerusahaan(r.db, 0, p.nama, p.bidangIndustri, p.lokasi)
+		if err != nil {
+			return linked, err
+		}
+		if perusahaanID == 0 {
+			continue
+		}
+		if _, err := r.db.Exec(`UPDATE pekerjaan_alumni SET perusahaan_id = $1 WHERE id = $2`, perusahaanID, p.id); err != nil {
+			log.Println("Error menghubungkan pekerjaan ke perusahaan:", err)
+			return linked, err
+		}
+		linked++
+	}
+	return linked, nil
+}
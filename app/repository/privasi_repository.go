@@ -0,0 +1,379 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hello-fiber/app/model"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PenghapusanDataRepository interface {
+	Create(permintaan model.PermintaanPenghapusan) (model.PermintaanPenghapusan, error)
+	GetAll(status string, alumniID int) ([]model.PermintaanPenghapusan, error)
+	GetByID(id int) (model.PermintaanPenghapusan, error)
+	// Approve mengembalikan berkas yang ikut dihapus agar object-nya dihapus dari storage setelah commit
+	Approve(id, reviewerID int, komentar string) (model.PermintaanPenghapusan, []model.Berkas, error)
+	Reject(id, reviewerID int, komentar string) (model.PermintaanPenghapusan, error)
+}
+
+type penghapusanDataRepository struct {
+	db *sql.DB
+}
+
+func NewPenghapusanDataRepository(db *sql.DB) PenghapusanDataRepository {
+	return &penghapusanDataRepository{db: db}
+}
+
+// fieldDianonimkan field alumni dan user yang diganti nilai anonim saat penghapusan disetujui
+var fieldDianonimkan = []string{
+	"alumni.nim", "alumni.nama", "alumni.email", "alumni.no_telepon", "alumni.alamat",
+	"alumni_merge.snapshot (nim, nama, email, no_telepon, alamat)",
+	"users.username", "users.email", "users.password", "berkas (foto dan dokumen pekerjaan)",
+	"pekerjaan_alumni.deskripsi_pekerjaan", "pengajuan_pekerjaan.data.deskripsi_pekerjaan",
+	"pendidikan_alumni.judul_tugas_akhir", "lamaran_lowongan.catatan_pelamar", "lamaran_lowongan.catatan_perekrut",
+	"mentor.topik", "mentor.bio", "permintaan_mentor.pesan", "permintaan_mentor.balasan",
+}
+
+// dataDipertahankan data yang tetap disimpan karena tidak mengidentifikasi alumni dan dipakai untuk statistik
+var dataDipertahankan = []string{
+	"alumni.jurusan", "alumni.angkatan", "alumni.tahun_lulus",
+	"pendidikan_alumni (jenjang, program studi, tahun masuk, tahun lulus, IPK)",
+	"keahlian_alumni dan alumni_tag (skill, sertifikasi, dan tag)",
+	"pekerjaan_alumni (perusahaan, posisi, bidang industri, lokasi, gaji, tanggal dan status kerja)",
+	"status_pekerjaan_history", "kuesioner_respon dan kuesioner_jawaban",
+	"lamaran_lowongan (lowongan yang dilamar dan status lamaran)",
+	"pendaftaran_acara (status pendaftaran dan kehadiran acara)",
+	"permintaan_mentor (pasangan dan status mentoring; permintaan yang masih berjalan diakhiri)",
+}
+
+const catatanPenghapusan = "Riwayat revisi alumni dan pekerjaannya dihapus. Audit log tidak diubah karena bersifat " +
+	"append-only dan disimpan untuk kewajiban hukum; entri lama masih dapat memuat data sebelum penghapusan."
+
+const penghapusanColumns = `id, alumni_id, diajukan_oleh, alasan, status, ditinjau_oleh, komentar, tanda_terima, created_at, reviewed_at`
+
+func scanPenghapusan(scanner interface{ Scan(...interface{}) error }) (model.PermintaanPenghapusan, error) {
+	var p model.PermintaanPenghapusan
+	var alumniID, diajukanOleh, ditinjauOleh sql.NullInt64
+	var tandaTerima []byte
+	var reviewedAt sql.NullTime
+
+	err := scanner.Scan(
+		&p.ID, &alumniID, &diajukanOleh, &p.Alasan, &p.Status, &ditinjauOleh,
+		&p.Komentar, &tandaTerima, &p.CreatedAt, &reviewedAt,
+	)
+	if err != nil {
+		return p, err
+	}
+	if tandaTerima != nil {
+		p.TandaTerima = &model.TandaTerimaPenghapusan{}
+		if err := json.Unmarshal(tandaTerima, p.TandaTerima); err != nil {
+			return p, err
+		}
+	}
+	p.AlumniID = int(alumniID.Int64)
+	p.DiajukanOleh = int(diajukanOleh.Int64)
+	p.DitinjauOleh = int(ditinjauOleh.Int64)
+	if reviewedAt.Valid {
+		p.ReviewedAt = &reviewedAt.Time
+	}
+	return p, nil
+}
+
+func (r *penghapusanDataRepository) Create(p model.PermintaanPenghapusan) (model.PermintaanPenghapusan, error) {
+	p.Status = model.StatusPengajuanPending
+	p.CreatedAt = time.Now()
+	err := r.db.QueryRow(`INSERT INTO permintaan_penghapusan (alumni_id, diajukan_oleh, alasan, status, created_at)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		p.AlumniID, nullableInt(p.DiajukanOleh), p.Alasan, p.Status, p.CreatedAt,
+	).Scan(&p.ID)
+	if err != nil {
+		log.Println("Error inserting permintaan penghapusan:", err)
+		return model.PermintaanPenghapusan{}, err
+	}
+	return p, nil
+}
+
+// GetAll mengambil permintaan penghapusan, opsional difilter status dan alumni; yang terlama di depan
+func (r *penghapusanDataRepository) GetAll(status string, alumniID int) ([]model.PermintaanPenghapusan, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if alumniID != 0 {
+		args = append(args, alumniID)
+		conditions = append(conditions, fmt.Sprintf("alumni_id = $%d", len(args)))
+	}
+
+	rows, err := r.db.Query(`SELECT `+penghapusanColumns+` FROM permintaan_penghapusan
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY created_at ASC`, args...)
+	if err != nil {
+		log.Println("Error men-query permintaan penghapusan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.PermintaanPenghapusan{}
+	for rows.Next() {
+		p, err := scanPenghapusan(rows)
+		if err != nil {
+			log.Println("Error men-scan permintaan penghapusan:", err)
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+func (r *penghapusanDataRepository) GetByID(id int) (model.PermintaanPenghapusan, error) {
+	p, err := scanPenghapusan(r.db.QueryRow(`SELECT `+penghapusanColumns+` FROM permintaan_penghapusan WHERE id = $1`, id))
+	if err != nil {
+		log.Println("Error menemukan permintaan penghapusan by ID:", err)
+		return model.PermintaanPenghapusan{}, err
+	}
+	return p, nil
+}
+
+func (r *penghapusanDataRepository) lockPending(tx *sql.Tx, id int) (model.PermintaanPenghapusan, error) {
+	p, err := scanPenghapusan(tx.QueryRow(`SELECT `+penghapusanColumns+` FROM permintaan_penghapusan
+		WHERE id = $1 AND status = $2 FOR UPDATE`, id, model.StatusPengajuanPending))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error menemukan permintaan penghapusan pending:", err)
+	}
+	return p, err
+}
+
+// Approve menganonimkan data pribadi alumni, alumni duplikat yang pernah digabung ke dalamnya, dan akun
+// user-nya, menghapus riwayat revisi dan berkasnya, lalu menyimpan tanda terima dalam satu transaksi.
+// Mengembalikan sql.ErrNoRows jika permintaan tidak pending atau alumninya sudah dihapus permanen.
+func (r *penghapusanDataRepository) Approve(id, reviewerID int, komentar string) (model.PermintaanPenghapusan, []model.Berkas, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi approve penghapusan:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+	defer tx.Rollback()
+
+	p, err := r.lockPending(tx, id)
+	if err != nil {
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+	if p.AlumniID == 0 {
+		return model.PermintaanPenghapusan{}, nil, sql.ErrNoRows
+	}
+
+	// duplikat yang digabung (termasuk penggabungan berantai) masih menyimpan data pribadi yang sama
+	alumniIDs, err := collectIDs(tx, `WITH RECURSIVE gabung AS (
+		    SELECT id FROM alumni WHERE id = $1
+		    UNION SELECT a.id FROM alumni a JOIN gabung g ON a.digabung_ke = g.id
+		)
+		SELECT id FROM alumni WHERE id IN (SELECT id FROM gabung) ORDER BY id FOR UPDATE`, p.AlumniID)
+	if err != nil {
+		log.Println("Error mengambil alumni yang digabung:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	now := time.Now()
+	for _, alumniID := range alumniIDs {
+		email := fmt.Sprintf("anon-%d@anonim.invalid", alumniID)
+		pii, err := encryptAlumniPII(tx, email, "", "")
+		if err != nil {
+			return model.PermintaanPenghapusan{}, nil, err
+		}
+		_, err = tx.Exec(`UPDATE alumni
+			SET nim = 'ANON-' || id, nama = 'Alumni Anonim #' || id, email = $2, email_bidx = $3,
+			    no_telepon = '', alamat = '', telepon_bidx = NULL, dianonimkan_at = $4, updated_at = $4
+			WHERE id = $1`, alumniID, pii.email, pii.emailBidx, now)
+		if err != nil {
+			log.Println("Error menganonimkan alumni:", err)
+			return model.PermintaanPenghapusan{}, nil, err
+		}
+	}
+
+	// snapshot merge menyimpan salinan data alumni sebelum digabung; merge tersebut tidak bisa di-undo lagi
+	_, err = tx.Exec(`UPDATE alumni_merge SET snapshot = snapshot
+		    || CASE WHEN survivor_id = ANY($1) THEN jsonb_build_object('survivor_sebelum',
+		           snapshot->'survivor_sebelum' || `+snapshotAnonim("survivor_id")+`) ELSE '{}'::jsonb END
+		    || CASE WHEN duplikat_id = ANY($1) THEN jsonb_build_object('duplikat',
+		           snapshot->'duplikat' || `+snapshotAnonim("duplikat_id")+`) ELSE '{}'::jsonb END
+		WHERE survivor_id = ANY($1) OR duplikat_id = ANY($1)`, pq.Array(alumniIDs))
+	if err != nil {
+		log.Println("Error menganonimkan snapshot merge alumni:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	berkas, err := listBerkas(tx, `DELETE FROM berkas
+		WHERE alumni_id = ANY($1) OR pekerjaan_id IN (SELECT id FROM pekerjaan_alumni WHERE alumni_id = ANY($1))
+		RETURNING `+berkasColumns, pq.Array(alumniIDs))
+	if err != nil {
+		log.Println("Error menghapus berkas alumni:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	userIDs, err := collectIDs(tx, `UPDATE users
+		SET username = 'anon-user-' || id, email = 'anon-user-' || id || '@anonim.invalid', password = '', is_active = FALSE
+		WHERE alumni_id = ANY($1) RETURNING id`, pq.Array(alumniIDs))
+	if err != nil {
+		log.Println("Error menganonimkan user alumni:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	pekerjaanIDs, err := collectIDs(tx, `UPDATE pekerjaan_alumni SET deskripsi_pekerjaan = ''
+		WHERE alumni_id = ANY($1) RETURNING id`, pq.Array(alumniIDs))
+	if err != nil {
+		log.Println("Error menganonimkan pekerjaan alumni:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	pengajuanIDs, err := collectIDs(tx, `UPDATE pengajuan_pekerjaan SET data = data - 'deskripsi_pekerjaan'
+		WHERE alumni_id = ANY($1) RETURNING id`, pq.Array(alumniIDs))
+	if err != nil {
+		log.Println("Error menganonimkan pengajuan pekerjaan:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	// judul tugas akhir dapat dicari di repositori kampus sehingga mengidentifikasi alumni
+	if _, err := tx.Exec(`UPDATE pendidikan_alumni SET judul_tugas_akhir = '' WHERE alumni_id = ANY($1)`, pq.Array(alumniIDs)); err != nil {
+		log.Println("Error menganonimkan pendidikan alumni:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	// catatan lamaran ditulis bebas oleh alumni dan perekrut sehingga dapat memuat data pribadi
+	if _, err := tx.Exec(`UPDATE lamaran_lowongan SET catatan_pelamar = '', catatan_perekrut = '' WHERE alumni_id = ANY($1)`, pq.Array(alumniIDs)); err != nil {
+		log.Println("Error menganonimkan lamaran lowongan:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	// alumni yang dihapus tidak lagi menjadi mentor; permintaan yang berjalan diakhiri dan pesannya dikosongkan
+	if _, err := tx.Exec(`UPDATE mentor SET topik = '{}', bio = '', aktif = FALSE, updated_at = $2 WHERE alumni_id = ANY($1)`,
+		pq.Array(alumniIDs), now); err != nil {
+		log.Println("Error menganonimkan profil mentor:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+	if _, err := tx.Exec(`UPDATE permintaan_mentor
+		SET pesan = '', balasan = '', updated_at = $2,
+		    status = CASE status WHEN 'diajukan' THEN 'dibatalkan' WHEN 'diterima' THEN 'selesai' ELSE status END,
+		    selesai_at = CASE WHEN status = 'diterima' THEN $2 ELSE selesai_at END
+		WHERE alumni_id = ANY($1) OR mentor_id IN (SELECT id FROM mentor WHERE alumni_id = ANY($1))`, pq.Array(alumniIDs), now); err != nil {
+		log.Println("Error menganonimkan permintaan mentoring:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+
+	result, err := tx.Exec(`DELETE FROM revisi
+		WHERE (entitas = $1 AND entitas_id = ANY($2)) OR (entitas = $3 AND entitas_id = ANY($4))`,
+		model.RevisiEntitasAlumni, pq.Array(alumniIDs), model.RevisiEntitasPekerjaan, pq.Array(pekerjaanIDs))
+	if err != nil {
+		log.Println("Error menghapus revisi alumni:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+	revisiDihapus, _ := result.RowsAffected()
+
+	tandaTerima := model.TandaTerimaPenghapusan{
+		Nomor:               fmt.Sprintf("PDP-%s-%06d", now.Format("20060102"), p.ID),
+		PermintaanID:        p.ID,
+		AlumniID:            p.AlumniID,
+		UserIDs:             userIDs,
+		DiajukanAt:          p.CreatedAt,
+		DisetujuiOleh:       reviewerID,
+		DilaksanakanAt:      now,
+		FieldDianonimkan:    fieldDianonimkan,
+		JumlahPekerjaan:     len(pekerjaanIDs),
+		JumlahPengajuan:     len(pengajuanIDs),
+		JumlahRevisiDihapus: int(revisiDihapus),
+		JumlahBerkasDihapus: len(berkas),
+		DataDipertahankan:   dataDipertahankan,
+		Catatan:             catatanPenghapusan,
+	}
+	for _, alumniID := range alumniIDs {
+		if alumniID != p.AlumniID {
+			tandaTerima.AlumniDigabungIDs = append(tandaTerima.AlumniDigabungIDs, alumniID)
+		}
+	}
+	if tandaTerima.Hash, err = hashTandaTerima(tandaTerima); err != nil {
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+	if err := r.markReviewed(tx, &p, model.StatusPengajuanApproved, reviewerID, komentar, &tandaTerima); err != nil {
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit approve penghapusan:", err)
+		return model.PermintaanPenghapusan{}, nil, err
+	}
+	return p, berkas, nil
+}
+
+func (r *penghapusanDataRepository) Reject(id, reviewerID int, komentar string) (model.PermintaanPenghapusan, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi reject penghapusan:", err)
+		return model.PermintaanPenghapusan{}, err
+	}
+	defer tx.Rollback()
+
+	p, err := r.lockPending(tx, id)
+	if err != nil {
+		return model.PermintaanPenghapusan{}, err
+	}
+	if err := r.markReviewed(tx, &p, model.StatusPengajuanRejected, reviewerID, komentar, nil); err != nil {
+		return model.PermintaanPenghapusan{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit reject penghapusan:", err)
+		return model.PermintaanPenghapusan{}, err
+	}
+	return p, nil
+}
+
+func (r *penghapusanDataRepository) markReviewed(tx *sql.Tx, p *model.PermintaanPenghapusan, status string, reviewerID int, komentar string, tandaTerima *model.TandaTerimaPenghapusan) error {
+	var data interface{}
+	if tandaTerima != nil {
+		raw, err := json.Marshal(tandaTerima)
+		if err != nil {
+			return err
+		}
+		data = raw
+	}
+
+	now := time.Now()
+	_, err := tx.Exec(`UPDATE permintaan_penghapusan
+		SET status = $1, ditinjau_oleh = $2, komentar = $3, tanda_terima = $4, reviewed_at = $5
+		WHERE id = $6`,
+		status, nullableInt(reviewerID), komentar, data, now, p.ID,
+	)
+	if err != nil {
+		log.Println("Error memperbarui status permintaan penghapusan:", err)
+		return err
+	}
+	p.Status = status
+	p.DitinjauOleh = reviewerID
+	p.Komentar = komentar
+	p.TandaTerima = tandaTerima
+	p.ReviewedAt = &now
+	return nil
+}
+
+// snapshotAnonim objek jsonb pengganti field pribadi alumni di snapshot merge; kolomID kolom id alumni tersebut
+func snapshotAnonim(kolomID string) string {
+	return `jsonb_build_object('nim', 'ANON-' || ` + kolomID + `, 'nama', 'Alumni Anonim #' || ` + kolomID + `,
+		'email', '', 'no_telepon', '', 'alamat', '')`
+}
+
+// hashTandaTerima sha256 dari isi tanda terima dengan field Hash dikosongkan
+func hashTandaTerima(t model.TandaTerimaPenghapusan) (string, error) {
+	t.Hash = ""
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"database/sql"
+	"hello-fiber/app/model"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ReferensiRepository interface {
+	Search(jenis, q string, indukID, limit int, semua bool) ([]model.Referensi, error)
+	GetByID(id int) (model.Referensi, error)
+	FindByNama(jenis string, indukID int, nama string) (model.Referensi, error)
+	Resolve(jenis, nilai string) (model.Referensi, error)
+	CountAktif(jenis string) (int, error)
+	CountAnak(id int) (int, error)
+	Create(jenis string, req model.ReferensiRequest) (model.Referensi, error)
+	Update(id int, req model.ReferensiRequest) (model.Referensi, error)
+	Delete(id int) error
+	FindLegacy(jenis string, limit int) ([]model.NilaiLegacy, error)
+	MapLegacy(referensiID int, nilaiLama []string) (model.MappingLegacyResult, error)
+	CanonicalizeLegacy(jenis string) (int64, error)
+}
+
+type referensiRepository struct {
+	db *sql.DB
+}
+
+func NewReferensiRepository(db *sql.DB) ReferensiRepository {
+	return &referensiRepository{db: db}
+}
+
+// referensiTarget kolom teks yang isinya diambil dari kosakata suatu jenis
+type referensiTarget struct {
+	tabel string
+	kolom string
+}
+
+// referensiTargets kolom yang ikut dirapikan saat nilai lama dipetakan atau nama kanonis diubah
+var referensiTargets = map[string][]referensiTarget{
+	model.ReferensiProgramStudi: {
+		{"alumni", "jurusan"},
+	},
+	model.ReferensiBidangIndustri: {
+		{"pekerjaan_alumni", "bidang_industri"},
+		{"perusahaan", "bidang_industri"},
+	},
+	model.ReferensiKota: {
+		{"pekerjaan_alumni", "lokasi_kerja"},
+		{"perusahaan", "kota"},
+	},
+}
+
+// normalSQL padanan model.NormalizeReferensi di sisi database
+func normalSQL(kolom string) string {
+	return `lower(regexp_replace(trim(` + kolom + `), '\s+', ' ', 'g'))`
+}
+
+// referensiColumns kolom referensi (alias r) beserta nama induk (alias i) dan daftar aliasnya
+const referensiColumns = `r.id, r.jenis, COALESCE(r.kode, ''), r.nama, COALESCE(r.induk_id, 0), COALESCE(i.nama, ''),
+		r.aktif, r.created_at, r.updated_at,
+		COALESCE((SELECT array_agg(a.alias ORDER BY a.alias) FROM referensi_alias a WHERE a.referensi_id = r.id), '{}')`
+
+const referensiFrom = `FROM referensi r LEFT JOIN referensi i ON i.id = r.induk_id`
+
+func scanReferensi(scanner interface{ Scan(...interface{}) error }) (model.Referensi, error) {
+	var ref model.Referensi
+	err := scanner.Scan(
+		&ref.ID, &ref.Jenis, &ref.Kode, &ref.Nama, &ref.IndukID, &ref.Induk,
+		&ref.Aktif, &ref.CreatedAt, &ref.UpdatedAt, pq.Array(&ref.Alias),
+	)
+	if ref.Alias == nil {
+		ref.Alias = []string{}
+	}
+	return ref, err
+}
+
+func (r *referensiRepository) queryReferensi(query string, args ...interface{}) ([]model.Referensi, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Println("Error men-query referensi:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.Referensi{}
+	for rows.Next() {
+		ref, err := scanReferensi(rows)
+		if err != nil {
+			log.Println("Error men-scan referensi:", err)
+			return nil, err
+		}
+		list = append(list, ref)
+	}
+	return list, nil
+}
+
+// Search untuk autocomplete: nama yang diawali q didahulukan, lalu nama/alias yang memuat q.
+// semua=false hanya mengembalikan entri aktif.
+func (r *referensiRepository) Search(jenis, q string, indukID, limit int, semua bool) ([]model.Referensi, error) {
+	q = strings.TrimSpace(q)
+	return r.queryReferensi(`SELECT `+referensiColumns+` `+referensiFrom+`
+		WHERE r.jenis = $1
+		  AND ($2 OR r.aktif)
+		  AND ($3 = 0 OR r.induk_id = $3)
+		  AND (r.nama ILIKE $4 OR EXISTS (
+		       SELECT 1 FROM referensi_alias a WHERE a.referensi_id = r.id AND a.alias ILIKE $4))
+		ORDER BY (r.nama ILIKE $5) DESC, r.nama ASC
+		LIMIT $6`,
+		jenis, semua, indukID, "%"+q+"%", q+"%", limit,
+	)
+}
+
+func (r *referensiRepository) GetByID(id int) (model.Referensi, error) {
+	ref, err := scanReferensi(r.db.QueryRow(`SELECT `+referensiColumns+` `+referensiFrom+` WHERE r.id = $1`, id))
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil referensi:", err)
+	}
+	return ref, err
+}
+
+// FindByNama mencari entri dengan nama yang sama (tanpa beda huruf besar) di bawah induk yang sama
+func (r *referensiRepository) FindByNama(jenis string, indukID int, nama string) (model.Referensi, error) {
+	return scanReferensi(r.db.QueryRow(`SELECT `+referensiColumns+` `+referensiFrom+`
+		WHERE r.jenis = $1 AND COALESCE(r.induk_id, 0) = $2 AND lower(r.nama) = $3`,
+		jenis, indukID, model.NormalizeReferensi(nama),
+	))
+}
+
+// Resolve mencari entri aktif yang nama atau aliasnya sama dengan nilai; kecocokan nama didahulukan
+func (r *referensiRepository) Resolve(jenis, nilai string) (model.Referensi, error) {
+	return scanReferensi(r.db.QueryRow(`SELECT `+referensiColumns+` `+referensiFrom+`
+		WHERE r.jenis = $1 AND r.aktif
+		  AND (lower(r.nama) = $2 OR EXISTS (
+		       SELECT 1 FROM referensi_alias a WHERE a.referensi_id = r.id AND a.alias_normal = $2))
+		ORDER BY (lower(r.nama) = $2) DESC, r.id ASC
+		LIMIT 1`,
+		jenis, model.NormalizeReferensi(nilai),
+	))
+}
+
+func (r *referensiRepository) CountAktif(jenis string) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM referensi WHERE jenis = $1 AND aktif`, jenis).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung referensi:", err)
+	}
+	return total, err
+}
+
+func (r *referensiRepository) CountAnak(id int) (int, error) {
+	var total int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM referensi WHERE induk_id = $1`, id).Scan(&total)
+	if err != nil {
+		log.Println("Error menghitung anak referensi:", err)
+	}
+	return total, err
+}
+
+func nullableKode(kode string) interface{} {
+	kode = strings.TrimSpace(kode)
+	if kode == "" {
+		return nil
+	}
+	return kode
+}
+
+func (r *referensiRepository) Create(jenis string, req model.ReferensiRequest) (model.Referensi, error) {
+	aktif := true
+	if req.Aktif != nil {
+		aktif = *req.Aktif
+	}
+
+	now := time.Now()
+	var id int
+	err := r.db.QueryRow(`INSERT INTO referensi (jenis, kode, nama, induk_id, aktif, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		jenis, nullableKode(req.Kode), req.Nama, nullableInt(req.IndukID), aktif, now, now,
+	).Scan(&id)
+	if err != nil {
+		log.Println("Error inserting referensi:", err)
+		return model.Referensi{}, err
+	}
+	return r.GetByID(id)
+}
+
+// Update mengubah entri; jika nama berubah, nilai di kolom data ikut diganti dan nama lama disimpan sebagai alias
+func (r *referensiRepository) Update(id int, req model.ReferensiRequest) (model.Referensi, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi referensi:", err)
+		return model.Referensi{}, err
+	}
+	defer tx.Rollback()
+
+	var jenis, namaLama string
+	var aktif bool
+	err = tx.QueryRow(`SELECT jenis, nama, aktif FROM referensi WHERE id = $1 FOR UPDATE`, id).Scan(&jenis, &namaLama, &aktif)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Println("Error mengambil referensi:", err)
+		}
+		return model.Referensi{}, err
+	}
+	if req.Aktif != nil {
+		aktif = *req.Aktif
+	}
+
+	_, err = tx.Exec(`UPDATE referensi SET kode = $1, nama = $2, induk_id = $3, aktif = $4, updated_at = $5 WHERE id = $6`,
+		nullableKode(req.Kode), req.Nama, nullableInt(req.IndukID), aktif, time.Now(), id,
+	)
+	if err != nil {
+		log.Println("Error updating referensi:", err)
+		return model.Referensi{}, err
+	}
+
+	if req.Nama != namaLama {
+		if err := insertReferensiAlias(tx, jenis, id, []string{namaLama}, req.Nama); err != nil {
+			return model.Referensi{}, err
+		}
+		for _, t := range referensiTargets[jenis] {
+			_, err := tx.Exec(`UPDATE `+t.tabel+` SET `+t.kolom+` = $1, updated_at = NOW() WHERE `+t.kolom+` = $2`, req.Nama, namaLama)
+			if err != nil {
+				log.Println("Error mengganti nilai referensi di", t.tabel+":", err)
+				return model.Referensi{}, err
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit referensi:", err)
+		return model.Referensi{}, err
+	}
+	return r.GetByID(id)
+}
+
+// insertReferensiAlias menyimpan alias untuk entri; alias yang sudah menunjuk entri lain dipindahkan.
+// Alias yang sama dengan nama kanonis dilewati.
+func insertReferensiAlias(db DBTX, jenis string, referensiID int, aliases []string, nama string) error {
+	for _, alias := range aliases {
+		normal := model.NormalizeReferensi(alias)
+		if normal == "" || normal == model.NormalizeReferensi(nama) {
+			continue
+		}
+		_, err := db.Exec(`INSERT INTO referensi_alias (jenis, alias, alias_normal, referensi_id)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (jenis, alias_normal) DO UPDATE SET alias = EXCLUDED.alias, referensi_id = EXCLUDED.referensi_id`,
+			jenis, strings.TrimSpace(alias), normal, referensiID,
+		)
+		if err != nil {
+			log.Println("Error inserting alias referensi:", err)
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *referensiRepository) Delete(id int) error {
+	result, err := r.db.Exec(`DELETE FROM referensi WHERE id = $1`, id)
+	if err != nil {
+		log.Println("Error deleting referensi:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// FindLegacy mengelompokkan nilai di kolom data yang belum persis sama dengan nama kanonis aktif,
+// diurutkan dari yang paling sering muncul. Saran diambil dari kecocokan nama/alias (skor 1)
+// atau nama yang paling mirip menurut pg_trgm.
+func (r *referensiRepository) FindLegacy(jenis string, limit int) ([]model.NilaiLegacy, error) {
+	targets := referensiTargets[jenis]
+	if len(targets) == 0 {
+		return []model.NilaiLegacy{}, nil
+	}
+	sumber := make([]string, 0, len(targets))
+	for _, t := range targets {
+		sumber = append(sumber, `SELECT TRIM(`+t.kolom+`) AS nilai FROM `+t.tabel+` WHERE COALESCE(TRIM(`+t.kolom+`), '') <> ''`)
+	}
+
+	rows, err := r.db.Query(`WITH nilai AS (
+			SELECT nilai, COUNT(*) AS jumlah FROM (`+strings.Join(sumber, " UNION ALL ")+`) s GROUP BY nilai
+		)
+		SELECT n.nilai, n.jumlah, COALESCE(saran.id, 0), COALESCE(saran.nama, ''), COALESCE(saran.skor, 0)
+		FROM nilai n
+		LEFT JOIN LATERAL (
+			SELECT c.id, c.nama, c.skor FROM (
+				SELECT r.id, r.nama,
+					CASE WHEN lower(r.nama) = `+normalSQL("n.nilai")+` OR EXISTS (
+						SELECT 1 FROM referensi_alias a WHERE a.referensi_id = r.id AND a.alias_normal = `+normalSQL("n.nilai")+`)
+					THEN 1.0::real ELSE similarity(lower(r.nama), `+normalSQL("n.nilai")+`) END AS skor
+				FROM referensi r WHERE r.jenis = $1 AND r.aktif
+			) c
+			WHERE c.skor >= 0.3
+			ORDER BY c.skor DESC, c.id ASC
+			LIMIT 1
+		) saran ON TRUE
+		WHERE NOT EXISTS (SELECT 1 FROM referensi r WHERE r.jenis = $1 AND r.aktif AND r.nama = n.nilai)
+		ORDER BY n.jumlah DESC, n.nilai ASC
+		LIMIT $2`, jenis, limit)
+	if err != nil {
+		log.Println("Error mencari nilai lama referensi:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.NilaiLegacy{}
+	for rows.Next() {
+		var v model.NilaiLegacy
+		if err := rows.Scan(&v.Nilai, &v.Jumlah, &v.SaranID, &v.SaranNama, &v.Skor); err != nil {
+			log.Println("Error men-scan nilai lama referensi:", err)
+			return nil, err
+		}
+		v.Skor = roundPercent(v.Skor)
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+// MapLegacy menyimpan nilai lama sebagai alias entri tujuan dan mengganti nilai tersebut
+// di seluruh kolom data dengan nama kanonis, dalam satu transaksi
+func (r *referensiRepository) MapLegacy(referensiID int, nilaiLama []string) (model.MappingLegacyResult, error) {
+	ref, err := r.GetByID(referensiID)
+	if err != nil {
+		return model.MappingLegacyResult{}, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		log.Println("Error memulai transaksi mapping referensi:", err)
+		return model.MappingLegacyResult{}, err
+	}
+	defer tx.Rollback()
+
+	if err := insertReferensiAlias(tx, ref.Jenis, ref.ID, nilaiLama, ref.Nama); err != nil {
+		return model.MappingLegacyResult{}, err
+	}
+
+	normal := make([]string, 0, len(nilaiLama))
+	for _, nilai := range nilaiLama {
+		if n := model.NormalizeReferensi(nilai); n != "" {
+			normal = append(normal, n)
+		}
+	}
+
+	var total int64
+	for _, t := range referensiTargets[ref.Jenis] {
+		result, err := tx.Exec(`UPDATE `+t.tabel+` SET `+t.kolom+` = $1, updated_at = NOW()
+			WHERE `+normalSQL(t.kolom)+` = ANY($2) AND `+t.kolom+` <> $1`,
+			ref.Nama, pq.Array(normal),
+		)
+		if err != nil {
+			log.Println("Error memetakan nilai lama di", t.tabel+":", err)
+			return model.MappingLegacyResult{}, err
+		}
+		n, _ := result.RowsAffected()
+		total += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Println("Error commit mapping referensi:", err)
+		return model.MappingLegacyResult{}, err
+	}
+
+	ref, err = r.GetByID(referensiID)
+	if err != nil {
+		return model.MappingLegacyResult{}, err
+	}
+	return model.MappingLegacyResult{Referensi: ref, AliasDitambah: nilaiLama, BarisDiperbarui: total}, nil
+}
+
+// CanonicalizeLegacy mengganti nilai yang sudah dikenali (beda huruf besar/spasi atau sama dengan alias)
+// dengan nama kanonisnya. Nilai yang belum dikenali dibiarkan untuk dipetakan admin lewat MapLegacy.
+func (r *referensiRepository) CanonicalizeLegacy(jenis string) (int64, error) {
+	var total int64
+	for _, t := range referensiTargets[jenis] {
+		result, err := r.db.Exec(`UPDATE `+t.tabel+` t SET `+t.kolom+` = ref.nama, updated_at = NOW()
+			FROM referensi ref
+			WHERE ref.jenis = $1 AND ref.aktif AND t.`+t.kolom+` <> ref.nama
+			  AND (lower(ref.nama) = `+normalSQL("t."+t.kolom)+` OR EXISTS (
+			       SELECT 1 FROM referensi_alias a
+			       WHERE a.referensi_id = ref.id AND a.alias_normal = `+normalSQL("t."+t.kolom)+`))`,
+			jenis,
+		)
+		if err != nil {
+			log.Println("Error merapikan nilai referensi di", t.tabel+":", err)
+			return total, err
+		}
+		n, _ := result.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"hello-fiber/app/model"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type RevisiRepository interface {
+	Record(entitas string, entitasID int, sebelum, sesudah interface{}, aksi string, revertDari, actorID int) (model.Revisi, error)
+	GetAll(entitas string, entitasID int) ([]model.Revisi, error)
+	GetByVersi(entitas string, entitasID, versi int) (model.Revisi, error)
+	GetLatest(entitas string, entitasID int) (model.Revisi, error)
+	GetAsOf(entitas string, entitasID int, waktu time.Time) (model.Revisi, error)
+}
+
+type revisiRepository struct {
+	db DBTX
+}
+
+func NewRevisiRepository(db *sql.DB) RevisiRepository {
+	return &revisiRepository{db: db}
+}
+
+// NewRevisiRepositoryTx agar revisi tersimpan di transaksi yang sama dengan perubahan record
+func NewRevisiRepositoryTx(tx *sql.Tx) RevisiRepository {
+	return &revisiRepository{db: tx}
+}
+
+// revisiFieldAbaikan field JSON yang tidak ikut disimpan di data revisi
+var revisiFieldAbaikan = []string{"id", "created_at", "updated_at", "is_delete", "trashed_at", "trashed_by"}
+
+// revisiData mengubah record menjadi data revisi dan mengembalikan updated_at record tersebut
+func revisiData(record interface{}) (map[string]interface{}, time.Time, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, time.Time{}, err
+	}
+	var updatedAt time.Time
+	if s, ok := data["updated_at"].(string); ok {
+		updatedAt, _ = time.Parse(time.RFC3339Nano, s)
+	}
+	for _, field := range revisiFieldAbaikan {
+		delete(data, field)
+	}
+	return data, updatedAt, nil
+}
+
+const revisiColumns = `id, entitas, entitas_id, versi, aksi, data, field_berubah, COALESCE(revert_dari, 0),
+		COALESCE(dibuat_oleh, 0), created_at`
+
+func scanRevisi(scanner interface{ Scan(...interface{}) error }) (model.Revisi, error) {
+	var rev model.Revisi
+	var data []byte
+	err := scanner.Scan(
+		&rev.ID, &rev.Entitas, &rev.EntitasID, &rev.Versi, &rev.Aksi, &data,
+		pq.Array(&rev.FieldBerubah), &rev.RevertDari, &rev.DibuatOleh, &rev.CreatedAt,
+	)
+	if err != nil {
+		return rev, err
+	}
+	if rev.FieldBerubah == nil {
+		rev.FieldBerubah = []string{}
+	}
+	err = json.Unmarshal(data, &rev.Data)
+	return rev, err
+}
+
+// bukaPII membuka salinan PII di data revisi alumni
+func (r *revisiRepository) bukaPII(rev *model.Revisi) error {
+	if rev.Entitas != model.RevisiEntitasAlumni {
+		return nil
+	}
+	return decryptPIIData(r.db, rev.Data)
+}
+
+func (r *revisiRepository) insert(rev model.Revisi) (model.Revisi, error) {
+	// PII alumni disimpan terenkripsi seperti di tabel alumni; rev.Data yang dikembalikan tetap plaintext
+	simpan := make(map[string]interface{}, len(rev.Data))
+	for k, v := range rev.Data {
+		simpan[k] = v
+	}
+	if rev.Entitas == model.RevisiEntitasAlumni {
+		if err := encryptPIIData(r.db, simpan); err != nil {
+			return model.Revisi{}, err
+		}
+	}
+	data, err := json.Marshal(simpan)
+	if err != nil {
+		return model.Revisi{}, err
+	}
+	if rev.CreatedAt.IsZero() {
+		rev.CreatedAt = time.Now()
+	}
+	err = r.db.QueryRow(`INSERT INTO revisi (entitas, entitas_id, versi, aksi, data, field_berubah, revert_dari, dibuat_oleh, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`,
+		rev.Entitas, rev.EntitasID, rev.Versi, rev.Aksi, data, pq.Array(rev.FieldBerubah),
+		nullableInt(rev.RevertDari), nullableInt(rev.DibuatOleh), rev.CreatedAt,
+	).Scan(&rev.ID)
+	if err != nil {
+		log.Println("Error menyimpan revisi:", err)
+	}
+	return rev, err
+}
+
+// Record menyimpan sesudah sebagai versi baru. Jika record belum punya revisi dan sebelum tidak nil,
+// nilai sebelum disimpan dulu sebagai revisi 'awal' agar perubahan pertama tetap bisa di-diff dan di-revert.
+func (r *revisiRepository) Record(entitas string, entitasID int, sebelum, sesudah interface{}, aksi string, revertDari, actorID int) (model.Revisi, error) {
+	data, _, err := revisiData(sesudah)
+	if err != nil {
+		return model.Revisi{}, err
+	}
+
+	terakhir, err := r.GetLatest(entitas, entitasID)
+	switch {
+	case err == sql.ErrNoRows && sebelum != nil:
+		awal, updatedAt, err := revisiData(sebelum)
+		if err != nil {
+			return model.Revisi{}, err
+		}
+		terakhir, err = r.insert(model.Revisi{
+			Entitas:      entitas,
+			EntitasID:    entitasID,
+			Versi:        1,
+			Aksi:         model.RevisiAksiAwal,
+			Data:         awal,
+			FieldBerubah: []string{},
+			CreatedAt:    updatedAt,
+		})
+		if err != nil {
+			return model.Revisi{}, err
+		}
+	case err == sql.ErrNoRows:
+		terakhir = model.Revisi{}
+	case err != nil:
+		return model.Revisi{}, err
+	}
+
+	berubah := []string{}
+	for _, p := range model.DiffRevisiData(terakhir.Data, data) {
+		berubah = append(berubah, p.Field)
+	}
+	return r.insert(model.Revisi{
+		Entitas:      entitas,
+		EntitasID:    entitasID,
+		Versi:        terakhir.Versi + 1,
+		Aksi:         aksi,
+		Data:         data,
+		FieldBerubah: berubah,
+		RevertDari:   revertDari,
+		DibuatOleh:   actorID,
+	})
+}
+
+func (r *revisiRepository) GetAll(entitas string, entitasID int) ([]model.Revisi, error) {
+	rows, err := r.db.Query(`SELECT `+revisiColumns+` FROM revisi
+		WHERE entitas = $1 AND entitas_id = $2 ORDER BY versi DESC`, entitas, entitasID)
+	if err != nil {
+		log.Println("Error men-query revisi:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.Revisi{}
+	for rows.Next() {
+		rev, err := scanRevisi(rows)
+		if err == nil {
+			err = r.bukaPII(&rev)
+		}
+		if err != nil {
+			log.Println("Error men-scan revisi:", err)
+			return nil, err
+		}
+		list = append(list, rev)
+	}
+	return list, rows.Err()
+}
+
+func (r *revisiRepository) getOne(query string, args ...interface{}) (model.Revisi, error) {
+	rev, err := scanRevisi(r.db.QueryRow(`SELECT `+revisiColumns+` FROM revisi `+query, args...))
+	if err == nil {
+		err = r.bukaPII(&rev)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		log.Println("Error mengambil revisi:", err)
+	}
+	return rev, err
+}
+
+func (r *revisiRepository) GetByVersi(entitas string, entitasID, versi int) (model.Revisi, error) {
+	return r.getOne(`WHERE entitas = $1 AND entitas_id = $2 AND versi = $3`, entitas, entitasID, versi)
+}
+
+func (r *revisiRepository) GetLatest(entitas string, entitasID int) (model.Revisi, error) {
+	return r.getOne(`WHERE entitas = $1 AND entitas_id = $2 ORDER BY versi DESC LIMIT 1`, entitas, entitasID)
+}
+
+// GetAsOf revisi yang berlaku pada waktu tertentu, yaitu revisi terakhir yang dibuat sebelum atau tepat pada waktu itu
+func (r *revisiRepository) GetAsOf(entitas string, entitasID int, waktu time.Time) (model.Revisi, error) {
+	return r.getOne(`WHERE entitas = $1 AND entitas_id = $2 AND created_at <= $3
+		ORDER BY created_at DESC, versi DESC LIMIT 1`, entitas, entitasID, waktu)
+}
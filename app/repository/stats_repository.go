@@ -0,0 +1,313 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"log"
+	"sort"
+	"strings"
+)
+
+type StatsRepository interface {
+	GetEmploymentRate(groupBy string, filter model.StatsFilter) ([]model.EmploymentRate, error)
+	GetAverageWaitingYears(filter model.StatsFilter) (float64, error)
+	GetDistribution(column string, filter model.StatsFilter) ([]model.DistributionItem, error)
+	GetGajiDistribution(kurs map[string]float64, filter model.StatsFilter) ([]model.DistributionItem, error)
+	GetTopPerusahaan(limit int, aktifSaja bool, filter model.StatsFilter) ([]model.TopPerusahaan, error)
+}
+
+type statsRepository struct {
+	db *sql.DB
+}
+
+func NewStatsRepository(db *sql.DB) StatsRepository {
+	return &statsRepository{db: db}
+}
+
+// buildStatsFilter menyusun kondisi WHERE untuk tabel alumni (alias a). Jika jenjang diisi, angkatan,
+// tahun_lulus, dan jurusan dicocokkan dengan riwayat pendidikan jenjang tersebut, bukan pendidikan utama.
+func buildStatsFilter(filter model.StatsFilter) (string, []interface{}) {
+	conditions := []string{"a.is_delete IS DISTINCT FROM 'hapus'"}
+	args := []interface{}{}
+
+	if filter.Jenjang != "" {
+		args = append(args, filter.Jenjang)
+		pendidikan := []string{"pd.alumni_id = a.id", fmt.Sprintf("pd.jenjang = $%d", len(args))}
+		if filter.Angkatan != 0 {
+			args = append(args, filter.Angkatan)
+			pendidikan = append(pendidikan, fmt.Sprintf("pd.tahun_masuk = $%d", len(args)))
+		}
+		if filter.TahunLulus != 0 {
+			args = append(args, filter.TahunLulus)
+			pendidikan = append(pendidikan, fmt.Sprintf("pd.tahun_lulus = $%d", len(args)))
+		}
+		if filter.Jurusan != "" {
+			args = append(args, filter.Jurusan)
+			pendidikan = append(pendidikan, fmt.Sprintf("pd.program_studi ILIKE $%d", len(args)))
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM pendidikan_alumni pd WHERE "+strings.Join(pendidikan, " AND ")+")")
+		return strings.Join(conditions, " AND "), args
+	}
+
+	if filter.Angkatan != 0 {
+		args = append(args, filter.Angkatan)
+		conditions = append(conditions, fmt.Sprintf("a.angkatan = $%d", len(args)))
+	}
+	if filter.TahunLulus != 0 {
+		args = append(args, filter.TahunLulus)
+		conditions = append(conditions, fmt.Sprintf("a.tahun_lulus = $%d", len(args)))
+	}
+	if filter.Jurusan != "" {
+		args = append(args, filter.Jurusan)
+		conditions = append(conditions, fmt.Sprintf("a.jurusan ILIKE $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetEmploymentRate menghitung alumni yang saat ini bekerja: punya pekerjaan (tidak di trash) berstatus aktif
+// atau cuti yang belum berakhir.
+// groupBy kosong menghasilkan satu baris untuk keseluruhan alumni. groupBy jenjang menghitung alumni di
+// setiap jenjang yang pernah ditempuhnya, sehingga alumni D3 dan S1 dihitung di kedua grup.
+func (r *statsRepository) GetEmploymentRate(groupBy string, filter model.StatsFilter) ([]model.EmploymentRate, error) {
+	validGroupColumns := map[string]string{
+		"":            "'semua'",
+		"angkatan":    "a.angkatan::text",
+		"tahun_lulus": "a.tahun_lulus::text",
+		"jurusan":     "a.jurusan",
+		"jenjang":     "j.jenjang",
+	}
+	groupExpr, ok := validGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("kolom grup tidak valid: %s", groupBy)
+	}
+
+	where, args := buildStatsFilter(filter)
+	from := "alumni a"
+	if groupBy == "jenjang" {
+		from = "alumni a JOIN (SELECT DISTINCT alumni_id, jenjang FROM pendidikan_alumni) j ON j.alumni_id = a.id"
+		if filter.Jenjang != "" {
+			args = append(args, filter.Jenjang)
+			where += fmt.Sprintf(" AND j.jenjang = $%d", len(args))
+		}
+	}
+	query := fmt.Sprintf(`SELECT %s AS grup, COUNT(*) AS total,
+		       COUNT(*) FILTER (WHERE EXISTS (
+		           SELECT 1 FROM pekerjaan_alumni p
+		           WHERE p.alumni_id = a.id AND p.is_delete IS DISTINCT FROM 'hapus'
+		             AND p.status_pekerjaan IN ('aktif', 'cuti')
+		             AND (p.tanggal_selesai_kerja IS NULL OR p.tanggal_selesai_kerja >= CURRENT_DATE)
+		       )) AS bekerja
+		FROM %s
+		WHERE %s
+		GROUP BY 1
+		ORDER BY 1`, groupExpr, from, where)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Println("Error men-query statistik keterserapan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []model.EmploymentRate{}
+	for rows.Next() {
+		var rate model.EmploymentRate
+		if err := rows.Scan(&rate.Grup, &rate.TotalAlumni, &rate.AlumniBekerja); err != nil {
+			log.Println("Error men-scan statistik keterserapan:", err)
+			return nil, err
+		}
+		if rate.TotalAlumni > 0 {
+			rate.Persentase = roundPercent(float64(rate.AlumniBekerja) * 100 / float64(rate.TotalAlumni))
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+// GetAverageWaitingYears menghitung rata-rata masa tunggu dalam tahun dari tahun lulus ke tahun mulai
+// pekerjaan pertama. Hanya tahun lulus yang tersimpan sehingga presisinya tahunan: lulus dan mulai bekerja
+// di tahun yang sama dihitung 0, begitu pula alumni yang sudah bekerja sebelum lulus.
+func (r *statsRepository) GetAverageWaitingYears(filter model.StatsFilter) (float64, error) {
+	where, args := buildStatsFilter(filter)
+	query := fmt.Sprintf(`SELECT COALESCE(AVG(GREATEST(EXTRACT(YEAR FROM f.mulai)::int - a.tahun_lulus, 0)), 0)
+		FROM alumni a
+		JOIN (
+		    SELECT alumni_id, MIN(tanggal_mulai_kerja::date) AS mulai
+		    FROM pekerjaan_alumni
+		    WHERE is_delete IS DISTINCT FROM 'hapus'
+		    GROUP BY alumni_id
+		) f ON f.alumni_id = a.id
+		WHERE a.tahun_lulus > 0 AND %s`, where)
+
+	var years float64
+	if err := r.db.QueryRow(query, args...).Scan(&years); err != nil {
+		log.Println("Error menghitung rata-rata masa tunggu:", err)
+		return 0, err
+	}
+	return roundPercent(years), nil
+}
+
+// GetDistribution menghitung sebaran pekerjaan berdasarkan kolom pekerjaan_alumni tertentu
+func (r *statsRepository) GetDistribution(column string, filter model.StatsFilter) ([]model.DistributionItem, error) {
+	validColumns := map[string]bool{
+		"bidang_industri": true, "lokasi_kerja": true,
+	}
+	if !validColumns[column] {
+		return nil, fmt.Errorf("kolom distribusi tidak valid: %s", column)
+	}
+
+	where, args := buildStatsFilter(filter)
+	query := fmt.Sprintf(`SELECT COALESCE(NULLIF(TRIM(p.%s), ''), 'Tidak diketahui') AS nilai, COUNT(*) AS jumlah,
+		       COUNT(*) * 100.0 / SUM(COUNT(*)) OVER () AS persentase
+		FROM pekerjaan_alumni p
+		JOIN alumni a ON a.id = p.alumni_id
+		WHERE p.is_delete IS DISTINCT FROM 'hapus' AND %s
+		GROUP BY 1
+		ORDER BY jumlah DESC, nilai ASC`, column, where)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Println("Error men-query distribusi", column+":", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.DistributionItem{}
+	for rows.Next() {
+		var item model.DistributionItem
+		if err := rows.Scan(&item.Nilai, &item.Jumlah, &item.Persentase); err != nil {
+			log.Println("Error men-scan distribusi", column+":", err)
+			return nil, err
+		}
+		item.Persentase = roundPercent(item.Persentase)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// bracketGaji batas atas (eksklusif, IDR per bulan) setiap bracket distribusi gaji; gaji di atas batas
+// terakhir masuk bracketGajiTeratas
+var bracketGaji = []struct {
+	batas int64
+	label string
+}{
+	{3000000, "< 3 juta"},
+	{5000000, "3-5 juta"},
+	{7000000, "5-7 juta"},
+	{10000000, "7-10 juta"},
+	{15000000, "10-15 juta"},
+	{20000000, "15-20 juta"},
+}
+
+const bracketGajiTeratas = ">= 20 juta"
+
+// GetGajiDistribution menghitung sebaran pekerjaan per bracket gaji bulanan dalam IDR. Nilai satu pekerjaan
+// adalah titik tengah gaji_min dan gaji_max (gaji_min jika tanpa batas atas), gaji tahunan dibagi 12, dan mata
+// uang asing dikalikan kurs. Mata uang tanpa kurs dikelompokkan terpisah. Urutan hasil mengikuti bracket.
+func (r *statsRepository) GetGajiDistribution(kurs map[string]float64, filter model.StatsFilter) ([]model.DistributionItem, error) {
+	where, args := buildStatsFilter(filter)
+
+	kursCases := []string{"WHEN 'IDR' THEN 1.0"}
+	mataUang := make([]string, 0, len(kurs))
+	for kode := range kurs {
+		mataUang = append(mataUang, kode)
+	}
+	sort.Strings(mataUang)
+	for _, kode := range mataUang {
+		args = append(args, kode, kurs[kode])
+		kursCases = append(kursCases, fmt.Sprintf("WHEN $%d THEN $%d::numeric", len(args)-1, len(args)))
+	}
+
+	brackets := []string{}
+	for i, b := range bracketGaji {
+		brackets = append(brackets, fmt.Sprintf("WHEN g.bulanan < %d THEN %d", b.batas, i+1))
+	}
+	query := fmt.Sprintf(`WITH g AS (
+		    SELECT (CASE WHEN p.gaji_max > 0 THEN (COALESCE(p.gaji_min, 0) + p.gaji_max) / 2.0 ELSE p.gaji_min END)
+		           * (CASE WHEN p.gaji_periode = 'tahun' THEN 1.0 / 12 ELSE 1.0 END)
+		           * (CASE COALESCE(p.gaji_mata_uang, 'IDR') %s END) AS bulanan,
+		           p.gaji_min IS NULL AND COALESCE(p.gaji_max, 0) = 0 AS kosong
+		    FROM pekerjaan_alumni p
+		    JOIN alumni a ON a.id = p.alumni_id
+		    WHERE p.is_delete IS DISTINCT FROM 'hapus' AND %s
+		)
+		SELECT CASE WHEN g.kosong THEN -1 WHEN g.bulanan IS NULL THEN 0 %s ELSE %d END AS bracket,
+		       COUNT(*) AS jumlah, COUNT(*) * 100.0 / SUM(COUNT(*)) OVER () AS persentase
+		FROM g
+		GROUP BY 1
+		ORDER BY 1`, strings.Join(kursCases, " "), where, strings.Join(brackets, " "), len(bracketGaji)+1)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Println("Error men-query distribusi gaji:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []model.DistributionItem{}
+	for rows.Next() {
+		var bracket int
+		var item model.DistributionItem
+		if err := rows.Scan(&bracket, &item.Jumlah, &item.Persentase); err != nil {
+			log.Println("Error men-scan distribusi gaji:", err)
+			return nil, err
+		}
+		switch {
+		case bracket == -1:
+			item.Nilai = "Tidak diketahui"
+		case bracket == 0:
+			item.Nilai = "Mata uang tanpa kurs"
+		case bracket > len(bracketGaji):
+			item.Nilai = bracketGajiTeratas
+		default:
+			item.Nilai = bracketGaji[bracket-1].label
+		}
+		item.Persentase = roundPercent(item.Persentase)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetTopPerusahaan mengurutkan perusahaan berdasarkan jumlah alumni yang pernah bekerja di sana.
+// aktifSaja hanya menghitung pekerjaan yang masih berjalan (tanpa tanggal selesai).
+func (r *statsRepository) GetTopPerusahaan(limit int, aktifSaja bool, filter model.StatsFilter) ([]model.TopPerusahaan, error) {
+	where, args := buildStatsFilter(filter)
+	if aktifSaja {
+		where += " AND p.tanggal_selesai_kerja IS NULL"
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT pr.id, pr.nama, pr.bidang_industri, pr.kota,
+		       COUNT(DISTINCT p.alumni_id) AS jumlah_alumni,
+		       COUNT(DISTINCT p.alumni_id) FILTER (WHERE p.tanggal_selesai_kerja IS NULL) AS alumni_aktif
+		FROM pekerjaan_alumni p
+		JOIN perusahaan pr ON pr.id = p.perusahaan_id
+		JOIN alumni a ON a.id = p.alumni_id
+		WHERE p.is_delete IS DISTINCT FROM 'hapus' AND %s
+		GROUP BY pr.id
+		ORDER BY jumlah_alumni DESC, pr.nama ASC
+		LIMIT $%d`, where, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		log.Println("Error men-query top perusahaan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []model.TopPerusahaan{}
+	for rows.Next() {
+		var t model.TopPerusahaan
+		if err := rows.Scan(&t.PerusahaanID, &t.Nama, &t.BidangIndustri, &t.Kota, &t.JumlahAlumni, &t.AlumniAktif); err != nil {
+			log.Println("Error men-scan top perusahaan:", err)
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+func roundPercent(value float64) float64 {
+	return float64(int64(value*100+0.5)) / 100
+}
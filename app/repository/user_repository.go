@@ -10,6 +10,9 @@ import (
 type UserRepository interface {
 	Save(user model.User) (model.User, error)
 	FindByEmail(email string) (model.User, error)
+	FindByID(id int) (model.User, error)
+	GetByPerusahaan(perusahaanID int) ([]model.User, error)
+	SetPerusahaan(id, perusahaanID int) error
 }
 
 type userRepository struct {
@@ -25,9 +28,9 @@ func NewUserRepository(db *sql.DB) UserRepository {
 func (r *userRepository) Save(user model.User) (model.User, error) {
 	sqlStatement := `
 		INSERT INTO users (username, email, password, alumni_id, role_id, created_at) 
-		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, is_active`
 	var id int
-	err := r.db.QueryRow(sqlStatement, user.Username, user.Email, user.Password, user.AlumniID, user.RoleID, user.CreatedAt).Scan(&id)
+	err := r.db.QueryRow(sqlStatement, user.Username, user.Email, user.Password, user.AlumniID, user.RoleID, user.CreatedAt).Scan(&id, &user.IsActive)
 	if err != nil {
 		log.Println("Error inserting user:", err)
 		return model.User{}, err
@@ -38,12 +41,67 @@ func (r *userRepository) Save(user model.User) (model.User, error) {
 
 // FindByEmail untuk mencari user berdasarkan email
 func (r *userRepository) FindByEmail(email string) (model.User, error) {
-	sqlStatement := `SELECT id, username, email, password, role_id, created_at FROM users WHERE email=$1`
+	sqlStatement := `SELECT id, username, email, password, role_id, is_active, created_at FROM users WHERE email=$1`
 	var user model.User
-	err := r.db.QueryRow(sqlStatement, email).Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.RoleID, &user.CreatedAt)
+	err := r.db.QueryRow(sqlStatement, email).Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.RoleID, &user.IsActive, &user.CreatedAt)
 	if err != nil {
 		log.Println("Error finding user by email:", err)
 		return model.User{}, err
 	}
 	return user, nil
+}
+
+// FindByID untuk mencari user berdasarkan id, termasuk alumni_id dan perusahaan_id yang terhubung
+func (r *userRepository) FindByID(id int) (model.User, error) {
+	sqlStatement := `SELECT id, username, email, alumni_id, role_id, is_active, created_at, perusahaan_id FROM users WHERE id=$1`
+	user, err := scanUser(r.db.QueryRow(sqlStatement, id))
+	if err != nil {
+		log.Println("Error finding user by ID:", err)
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func scanUser(scanner interface{ Scan(...interface{}) error }) (model.User, error) {
+	var user model.User
+	var alumniID, perusahaanID sql.NullInt64
+	err := scanner.Scan(&user.ID, &user.Username, &user.Email, &alumniID, &user.RoleID, &user.IsActive, &user.CreatedAt, &perusahaanID)
+	user.AlumniID = int(alumniID.Int64)
+	user.PerusahaanID = int(perusahaanID.Int64)
+	return user, err
+}
+
+// GetByPerusahaan akun mitra yang terhubung dengan perusahaan
+func (r *userRepository) GetByPerusahaan(perusahaanID int) ([]model.User, error) {
+	rows, err := r.db.Query(`SELECT id, username, email, alumni_id, role_id, is_active, created_at, perusahaan_id
+		FROM users WHERE perusahaan_id = $1 ORDER BY id`, perusahaanID)
+	if err != nil {
+		log.Println("Error men-query mitra perusahaan:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []model.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			log.Println("Error men-scan mitra perusahaan:", err)
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// SetPerusahaan menghubungkan user dengan perusahaan sebagai mitra; perusahaanID 0 melepas hubungan
+func (r *userRepository) SetPerusahaan(id, perusahaanID int) error {
+	result, err := r.db.Exec(`UPDATE users SET perusahaan_id = $1 WHERE id = $2`, nullableInt(perusahaanID), id)
+	if err != nil {
+		log.Println("Error mengubah perusahaan user:", err)
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
\ No newline at end of file
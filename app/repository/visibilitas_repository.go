@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"database/sql"
+	"hello-fiber/app/model"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type AlumniVisibilitasRepository interface {
+	Get(alumniID int) (model.AlumniVisibilitas, error)
+	GetMany(alumniIDs []int) (map[int]model.AlumniVisibilitas, error)
+	Save(v model.AlumniVisibilitas, actorID int) (model.AlumniVisibilitas, error)
+}
+
+type alumniVisibilitasRepository struct {
+	db *sql.DB
+}
+
+func NewAlumniVisibilitasRepository(db *sql.DB) AlumniVisibilitasRepository {
+	return &alumniVisibilitasRepository{db: db}
+}
+
+// Get mengembalikan pengaturan default jika alumni belum pernah mengaturnya
+func (r *alumniVisibilitasRepository) Get(alumniID int) (model.AlumniVisibilitas, error) {
+	list, err := r.GetMany([]int{alumniID})
+	if err != nil {
+		return model.AlumniVisibilitas{}, err
+	}
+	return list[alumniID], nil
+}
+
+// GetMany pengaturan untuk beberapa alumni sekaligus; setiap id selalu ada di hasil
+func (r *alumniVisibilitasRepository) GetMany(alumniIDs []int) (map[int]model.AlumniVisibilitas, error) {
+	result := make(map[int]model.AlumniVisibilitas, len(alumniIDs))
+	for _, id := range alumniIDs {
+		result[id] = model.DefaultAlumniVisibilitas(id)
+	}
+	if len(alumniIDs) == 0 {
+		return result, nil
+	}
+
+	rows, err := r.db.Query(`SELECT alumni_id, email, no_telepon, alamat, foto, updated_at
+		FROM alumni_visibilitas WHERE alumni_id = ANY($1)`, pq.Array(alumniIDs))
+	if err != nil {
+		log.Println("Error men-query visibilitas alumni:", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v model.AlumniVisibilitas
+		if err := rows.Scan(&v.AlumniID, &v.Email, &v.NoTelepon, &v.Alamat, &v.Foto, &v.UpdatedAt); err != nil {
+			log.Println("Error men-scan visibilitas alumni:", err)
+			return nil, err
+		}
+		result[v.AlumniID] = v
+	}
+	return result, rows.Err()
+}
+
+func (r *alumniVisibilitasRepository) Save(v model.AlumniVisibilitas, actorID int) (model.AlumniVisibilitas, error) {
+	v.UpdatedAt = time.Now()
+	_, err := r.db.Exec(`INSERT INTO alumni_visibilitas (alumni_id, email, no_telepon, alamat, foto, diubah_oleh, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (alumni_id) DO UPDATE
+		SET email = EXCLUDED.email, no_telepon = EXCLUDED.no_telepon, alamat = EXCLUDED.alamat, foto = EXCLUDED.foto,
+		    diubah_oleh = EXCLUDED.diubah_oleh, updated_at = EXCLUDED.updated_at`,
+		v.AlumniID, v.Email, v.NoTelepon, v.Alamat, v.Foto, nullableInt(actorID), v.UpdatedAt,
+	)
+	if err != nil {
+		log.Println("Error menyimpan visibilitas alumni:", err)
+		return model.AlumniVisibilitas{}, err
+	}
+	return v, nil
+}
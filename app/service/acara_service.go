@@ -0,0 +1,993 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"hello-fiber/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// prefixTiketAcara versi format kode tiket: ACR1.<acara_id>.<pendaftaran_id>.<tanda tangan>
+const prefixTiketAcara = "ACR1"
+
+// kunciTiketAcara kunci HMAC tiket dari ACARA_TIKET_KEY, jatuh ke JWT_SECRET seperti signed URL berkas
+func kunciTiketAcara() ([]byte, error) {
+	kunci := utils.GetEnv("ACARA_TIKET_KEY", utils.GetEnv("JWT_SECRET", ""))
+	if kunci == "" {
+		return nil, errors.New("ACARA_TIKET_KEY belum dikonfigurasi")
+	}
+	return []byte(kunci), nil
+}
+
+// tandaTanganTiket HMAC-SHA256 (16 byte pertama) atas pendaftaran. didaftar_at ikut ditandatangani sehingga
+// tiket lama tidak berlaku lagi setelah alumni membatalkan lalu mendaftar ulang.
+func tandaTanganTiket(kunci []byte, p model.PendaftaranAcara) string {
+	mac := hmac.New(sha256.New, kunci)
+	fmt.Fprintf(mac, "%s:%d:%d:%d:%d", prefixTiketAcara, p.AcaraID, p.ID, p.AlumniID, p.DidaftarAt.UnixNano())
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// tiketSah membandingkan tanda tangan dari kode tiket dengan pendaftaran yang tersimpan dalam waktu konstan
+func tiketSah(kunci []byte, tandaTangan string, p model.PendaftaranAcara) bool {
+	return hmac.Equal([]byte(tandaTangan), []byte(tandaTanganTiket(kunci, p)))
+}
+
+func buatKodeTiket(p model.PendaftaranAcara) (string, error) {
+	kunci, err := kunciTiketAcara()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%d.%d.%s", prefixTiketAcara, p.AcaraID, p.ID, tandaTanganTiket(kunci, p)), nil
+}
+
+// parseKodeTiket memecah kode tiket tanpa memeriksa tanda tangan; ok=false jika formatnya salah
+func parseKodeTiket(kode string) (acaraID, pendaftaranID int, tandaTangan string, ok bool) {
+	bagian := strings.Split(strings.TrimSpace(kode), ".")
+	if len(bagian) != 4 || bagian[0] != prefixTiketAcara {
+		return 0, 0, "", false
+	}
+	acaraID, errAcara := strconv.Atoi(bagian[1])
+	pendaftaranID, errPendaftaran := strconv.Atoi(bagian[2])
+	if errAcara != nil || errPendaftaran != nil {
+		return 0, 0, "", false
+	}
+	return acaraID, pendaftaranID, bagian[3], true
+}
+
+func acaraTargetsAlumni(acara model.Acara, alumni model.Alumni) bool {
+	if acara.Angkatan != 0 && acara.Angkatan != alumni.Angkatan {
+		return false
+	}
+	if acara.Jurusan != "" && !strings.EqualFold(strings.TrimSpace(acara.Jurusan), strings.TrimSpace(alumni.Jurusan)) {
+		return false
+	}
+	return true
+}
+
+// GetAllAcaraService daftar acara dengan pagination, search, dan filter jenis/mendatang. Admin melihat semua
+// acara (filter ?status); alumni hanya acara dipublikasikan yang menyasar angkatan/jurusannya.
+func GetAllAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	sortBy := c.Query("sortBy", "mulai_at")
+	order := c.Query("order", "asc")
+	search := c.Query("search", "")
+
+	offset := (page - 1) * limit
+
+	filter := model.AcaraFilter{
+		Search:    search,
+		Jenis:     c.Query("jenis"),
+		Mendatang: c.QueryBool("mendatang"),
+	}
+	if filter.Jenis != "" && !model.ValidJenisAcara(filter.Jenis) {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "jenis harus reuni, karier, seminar, atau lainnya",
+		})
+	}
+	if roleID, _ := c.Locals("role_id").(int); roleID == 1 {
+		filter.Status = c.Query("status")
+	} else if alumni, err := getLoggedInAlumni(c, db); err == nil {
+		filter.Sasaran = &alumni
+		filter.AlumniID = alumni.ID
+	} else {
+		filter.Status = model.StatusAcaraDipublikasikan
+	}
+
+	sortByWhitelist := map[string]bool{"id": true, "judul": true, "mulai_at": true, "created_at": true, "updated_at": true}
+	if !sortByWhitelist[sortBy] {
+		sortBy = "id"
+	}
+	if strings.ToLower(order) != "desc" {
+		order = "asc"
+	}
+
+	acaraRepo := repository.NewAcaraRepository(db)
+	acara, err := acaraRepo.GetAcaraWithPagination(filter, sortBy, order, limit, offset)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Gagal mengambil acara dengan pagination"})
+	}
+
+	total, err := acaraRepo.CountAcara(filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Gagal menghitung acara dengan pagination"})
+	}
+
+	response := model.AcaraResponse{
+		Data: acara,
+		Meta: model.MetaInfo{
+			Page:   page,
+			Limit:  limit,
+			Total:  total,
+			Pages:  (total + limit - 1) / limit,
+			SortBy: sortBy,
+			Order:  order,
+			Search: search,
+		},
+	}
+
+	return c.JSON(response)
+}
+
+// GetAcaraByIDService detail acara; acara draft hanya terlihat oleh admin
+func GetAcaraByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	roleID, _ := c.Locals("role_id").(int)
+	acara, err := repository.NewAcaraRepository(db).GetByID(id, getAlumniPemirsa(c, db))
+	if err == nil && acara.Status == model.StatusAcaraDraft && roleID != 1 {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Acara tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Acara berhasil diambil",
+		"data":    acara,
+	})
+}
+
+// validasiAcara memeriksa field wajib, jenis, urutan waktu, kapasitas, dan sasaran acara
+func validasiAcara(req *model.AcaraRequest) []model.FieldError {
+	errs := []model.FieldError{}
+	req.Judul = strings.TrimSpace(req.Judul)
+	req.Deskripsi = strings.TrimSpace(req.Deskripsi)
+	req.Lokasi = strings.TrimSpace(req.Lokasi)
+	req.Jurusan = strings.TrimSpace(req.Jurusan)
+	if req.Jenis == "" {
+		req.Jenis = model.JenisAcaraLainnya
+	}
+
+	if req.Judul == "" {
+		errs = append(errs, model.FieldError{Field: "judul", Kode: "wajib", Pesan: "judul harus diisi"})
+	}
+	if !model.ValidJenisAcara(req.Jenis) {
+		errs = append(errs, model.FieldError{Field: "jenis", Kode: "tidak_valid", Pesan: "jenis harus reuni, karier, seminar, atau lainnya"})
+	}
+	if req.MulaiAt.IsZero() {
+		errs = append(errs, model.FieldError{Field: "mulai_at", Kode: "wajib", Pesan: "mulai_at harus diisi"})
+	}
+	if req.SelesaiAt.IsZero() {
+		req.SelesaiAt = req.MulaiAt
+	} else if req.SelesaiAt.Before(req.MulaiAt) {
+		errs = append(errs, model.FieldError{Field: "selesai_at", Kode: "tidak_valid", Pesan: "selesai_at tidak boleh sebelum mulai_at"})
+	}
+	if req.BatasDaftar != nil && req.BatasDaftar.After(req.MulaiAt) {
+		errs = append(errs, model.FieldError{Field: "batas_daftar", Kode: "tidak_valid", Pesan: "batas_daftar tidak boleh setelah acara dimulai"})
+	}
+	if req.Kapasitas < 0 {
+		errs = append(errs, model.FieldError{Field: "kapasitas", Kode: "tidak_valid", Pesan: "kapasitas tidak boleh negatif (0 berarti tanpa batas)"})
+	}
+	if req.Angkatan < 0 {
+		errs = append(errs, model.FieldError{Field: "angkatan", Kode: "tidak_valid", Pesan: "angkatan tidak valid"})
+	}
+	return errs
+}
+
+// CreateAcaraService membuat acara baru berstatus draft (admin only)
+func CreateAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.AcaraRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if errs := validasiAcara(&req); len(errs) > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi acara gagal",
+			"errors":  errs,
+		})
+	}
+
+	actorID, _ := c.Locals("user_id").(int)
+	acara, err := repository.NewAcaraRepository(db).Create(req, actorID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal membuat acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Acara berhasil dibuat",
+		"data":    acara,
+	})
+}
+
+// UpdateAcaraService mengubah acara (admin only); kursi tambahan langsung diisi dari daftar tunggu
+func UpdateAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.AcaraRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if errs := validasiAcara(&req); len(errs) > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi acara gagal",
+			"errors":  errs,
+		})
+	}
+
+	acaraRepo := repository.NewAcaraRepository(db)
+	if current, err := acaraRepo.GetByID(id, 0); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	acara, err := acaraRepo.Update(id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Acara tidak ditemukan",
+			})
+		}
+		if err == repository.ErrKapasitasKurang {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Kapasitas tidak boleh lebih kecil dari jumlah peserta yang sudah terdaftar",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengupdate acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Acara berhasil diupdate",
+		"data":    acara,
+	})
+}
+
+// UpdateStatusAcaraService mempublikasikan acara draft atau membatalkan acara (admin only).
+// Acara yang dibatalkan tidak dapat dipublikasikan lagi dan tiketnya tidak berlaku.
+func UpdateStatusAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.UpdateStatusAcaraRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	if req.Status != model.StatusAcaraDipublikasikan && req.Status != model.StatusAcaraDibatalkan {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Status acara harus dipublikasikan atau dibatalkan",
+		})
+	}
+
+	acaraRepo := repository.NewAcaraRepository(db)
+	current, err := acaraRepo.GetByID(id, 0)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Acara tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil acara",
+			"error":   err.Error(),
+		})
+	}
+	if current.Status == model.StatusAcaraDibatalkan || current.Status == req.Status {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Acara berstatus %s tidak dapat diubah menjadi %s", current.Status, req.Status),
+		})
+	}
+	middleware.Audit(c).Sebelum = current
+
+	acara, err := acaraRepo.UpdateStatus(id, req.Status)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengubah status acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": fmt.Sprintf("Acara berhasil %s", req.Status),
+		"data":    acara,
+	})
+}
+
+// DeleteAcaraService menghapus acara yang belum memiliki pendaftar (admin only); selebihnya cukup dibatalkan
+func DeleteAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	acaraRepo := repository.NewAcaraRepository(db)
+	total, err := acaraRepo.CountPendaftaran(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memeriksa pendaftaran acara",
+			"error":   err.Error(),
+		})
+	}
+	if total > 0 {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Acara sudah memiliki %d pendaftaran, batalkan acara alih-alih menghapusnya", total),
+		})
+	}
+
+	if current, err := acaraRepo.GetByID(id, 0); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	if err := acaraRepo.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Acara tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Acara berhasil dihapus",
+	})
+}
+
+// DaftarAcaraService RSVP alumni ke acara yang menyasar angkatan/jurusannya; masuk daftar tunggu jika penuh
+func DaftarAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	acaraRepo := repository.NewAcaraRepository(db)
+	acara, err := acaraRepo.GetByID(id, alumni.ID)
+	if err == nil && acara.Status == model.StatusAcaraDraft {
+		err = sql.ErrNoRows
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Acara tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil acara",
+			"error":   err.Error(),
+		})
+	}
+	if !acaraTargetsAlumni(acara, alumni) {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Acara ini tidak ditujukan untuk angkatan atau jurusan Anda",
+		})
+	}
+
+	pendaftaran, err := acaraRepo.Daftar(id, alumni.ID)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Acara tidak ditemukan",
+			})
+		case repository.ErrPendaftaranAcaraDitutup:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Pendaftaran acara sudah ditutup",
+			})
+		case repository.ErrSudahTerdaftar:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Anda sudah terdaftar di acara ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mendaftar acara",
+			"error":   err.Error(),
+		})
+	}
+
+	message := "Berhasil terdaftar di acara"
+	if pendaftaran.Status == model.StatusPendaftaranDaftarTunggu {
+		message = fmt.Sprintf("Kapasitas acara penuh, Anda berada di daftar tunggu nomor %d", pendaftaran.UrutanTunggu)
+	}
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    pendaftaran,
+	})
+}
+
+// BatalDaftarAcaraService alumni membatalkan pendaftaran atau keluar dari daftar tunggu
+func BatalDaftarAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	pendaftaran, err := repository.NewAcaraRepository(db).BatalDaftar(id, alumni.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Anda tidak terdaftar di acara ini",
+			})
+		}
+		if err == repository.ErrSudahCheckIn {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Anda sudah check-in di acara ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal membatalkan pendaftaran acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Pendaftaran acara berhasil dibatalkan",
+		"data":    pendaftaran,
+	})
+}
+
+// GetMyAcaraService riwayat pendaftaran dan kehadiran acara alumni yang login
+func GetMyAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	pendaftaran, err := repository.NewAcaraRepository(db).GetPendaftaranByAlumni(alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Acara berhasil diambil",
+		"data":    pendaftaran,
+	})
+}
+
+// GetMyTiketAcaraService tiket peserta terdaftar; format=png mengembalikan gambar QR code dari kode tiket
+func GetMyTiketAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	acaraRepo := repository.NewAcaraRepository(db)
+	pendaftaran, err := acaraRepo.GetPendaftaran(id, alumni.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Anda tidak terdaftar di acara ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil pendaftaran acara",
+			"error":   err.Error(),
+		})
+	}
+	switch pendaftaran.Status {
+	case model.StatusPendaftaranDaftarTunggu:
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Anda masih berada di daftar tunggu nomor %d", pendaftaran.UrutanTunggu),
+		})
+	case model.StatusPendaftaranDibatalkan:
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"message": "Anda tidak terdaftar di acara ini",
+		})
+	}
+
+	acara, err := acaraRepo.GetByID(id, alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil acara",
+			"error":   err.Error(),
+		})
+	}
+	if acara.Status == model.StatusAcaraDibatalkan {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": "Acara sudah dibatalkan",
+		})
+	}
+
+	kode, err := buatKodeTiket(pendaftaran)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal membuat tiket acara",
+			"error":   err.Error(),
+		})
+	}
+
+	if strings.ToLower(c.Query("format", "json")) == "png" {
+		gambar, err := utils.BuatQRCode(kode, 8)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Gagal membuat QR code tiket",
+				"error":   err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderContentType, "image/png")
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.Send(gambar)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Tiket acara berhasil diambil",
+		"data": model.TiketAcara{
+			PendaftaranID: pendaftaran.ID,
+			AcaraID:       acara.ID,
+			JudulAcara:    acara.Judul,
+			Lokasi:        acara.Lokasi,
+			MulaiAt:       acara.MulaiAt,
+			NamaAlumni:    pendaftaran.NamaAlumni,
+			Kode:          kode,
+		},
+	})
+}
+
+// GetPesertaAcaraService daftar peserta acara (admin atau petugas acara). Filter ?status; format=csv
+// mengunduh daftar hadir peserta terdaftar beserta data alumninya.
+func GetPesertaAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", model.StatusPendaftaranTerdaftar, model.StatusPendaftaranDaftarTunggu, model.StatusPendaftaranDibatalkan:
+	default:
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "status harus terdaftar, daftar_tunggu, atau dibatalkan",
+		})
+	}
+	csvFormat := strings.ToLower(c.Query("format", "json")) == "csv"
+	if csvFormat {
+		status = model.StatusPendaftaranTerdaftar
+	}
+
+	peserta, err := repository.NewAcaraRepository(db).GetPendaftaranByAcara(id, status)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil peserta acara",
+			"error":   err.Error(),
+		})
+	}
+
+	samarkanPesertaAcara(c, db, peserta)
+
+	if csvFormat {
+		data, err := kehadiranAcaraToCSV(peserta)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Gagal membuat file CSV kehadiran acara",
+				"error":   err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="kehadiran-acara-%d.csv"`, id))
+		return c.Send(data)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Peserta acara berhasil diambil",
+		"data":    peserta,
+	})
+}
+
+// samarkanPesertaAcara menyamarkan email peserta untuk petugas non-admin sesuai pengaturan visibilitas
+// pemiliknya. Jika pengaturan gagal dibaca, semua email disamarkan.
+func samarkanPesertaAcara(c *fiber.Ctx, db *sql.DB, list []model.PendaftaranAcara) {
+	if len(list) == 0 {
+		return
+	}
+	pemirsa, err := getPemirsaAlumni(c, db)
+	if err == nil && pemirsa.admin {
+		return
+	}
+	visibilitas := map[int]model.AlumniVisibilitas{}
+	if err == nil {
+		ids := make([]int, len(list))
+		for i, p := range list {
+			ids[i] = p.AlumniID
+		}
+		visibilitas, err = repository.NewAlumniVisibilitasRepository(db).GetMany(ids)
+	}
+
+	for i := range list {
+		p := &list[i]
+		if p.Email != "" && (err != nil || !pemirsa.bolehLihat(visibilitas[p.AlumniID].Email, p.AlumniID)) {
+			p.Email = samarkanEmail(p.Email)
+			p.Disamarkan = append(p.Disamarkan, "email")
+		}
+	}
+}
+
+func kehadiranAcaraToCSV(peserta []model.PendaftaranAcara) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{
+		"pendaftaran_id", "alumni_id", "nim", "nama", "angkatan", "jurusan", "email", "didaftar_at", "hadir", "check_in_at",
+	})
+	for _, p := range peserta {
+		hadir, checkInAt := "tidak", ""
+		if p.CheckInAt != nil {
+			hadir, checkInAt = "ya", p.CheckInAt.Format("2006-01-02 15:04:05")
+		}
+		w.Write([]string{
+			strconv.Itoa(p.ID), strconv.Itoa(p.AlumniID), p.NIM, p.NamaAlumni, strconv.Itoa(p.Angkatan), p.Jurusan, p.Email,
+			p.DidaftarAt.Format("2006-01-02 15:04:05"), hadir, checkInAt,
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// CheckInAcaraService memverifikasi kode tiket hasil scan QR dan mencatat kehadiran (admin atau petugas acara)
+func CheckInAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.CheckInRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	acaraID, pendaftaranID, tandaTangan, ok := parseKodeTiket(req.Kode)
+	if !ok {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Kode tiket tidak valid",
+		})
+	}
+	if acaraID != id {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Tiket ini bukan untuk acara ini",
+		})
+	}
+
+	acaraRepo := repository.NewAcaraRepository(db)
+	pendaftaran, err := acaraRepo.GetPendaftaranByID(pendaftaranID)
+	if err != nil && err != sql.ErrNoRows {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil pendaftaran acara",
+			"error":   err.Error(),
+		})
+	}
+	kunci, errKunci := kunciTiketAcara()
+	if errKunci != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memverifikasi tiket acara",
+			"error":   errKunci.Error(),
+		})
+	}
+	// pendaftaran yang tidak ada diperlakukan sama dengan tanda tangan salah
+	if err == sql.ErrNoRows || pendaftaran.AcaraID != id || !tiketSah(kunci, tandaTangan, pendaftaran) {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Kode tiket tidak valid",
+		})
+	}
+
+	actorID, _ := c.Locals("user_id").(int)
+	pendaftaran, err = acaraRepo.CheckIn(id, pendaftaranID, actorID)
+	hasil := []model.PendaftaranAcara{pendaftaran}
+	samarkanPesertaAcara(c, db, hasil)
+	pendaftaran = hasil[0]
+	if err != nil {
+		switch err {
+		case repository.ErrSudahCheckIn:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("%s sudah check-in pada %s", pendaftaran.NamaAlumni, pendaftaran.CheckInAt.Format("2006-01-02 15:04:05")),
+				"data":    pendaftaran,
+			})
+		case repository.ErrTiketTidakBerlaku:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Tiket tidak berlaku: pendaftaran dibatalkan, masih di daftar tunggu, atau acara tidak aktif",
+				"data":    pendaftaran,
+			})
+		case sql.ErrNoRows:
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Kode tiket tidak valid",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mencatat check-in",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": fmt.Sprintf("Check-in %s berhasil", pendaftaran.NamaAlumni),
+		"data":    pendaftaran,
+	})
+}
+
+// GetPetugasAcaraService akun panitia check-in acara (admin only)
+func GetPetugasAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	petugas, err := repository.NewAcaraRepository(db).GetPetugas(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil petugas acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Petugas acara berhasil diambil",
+		"data":    petugas,
+	})
+}
+
+// TambahPetugasAcaraService menunjuk akun user sebagai panitia check-in acara (admin only)
+func TambahPetugasAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.PetugasAcaraRequest
+	if err := c.BodyParser(&req); err != nil || req.UserID <= 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "user_id harus diisi",
+		})
+	}
+
+	acaraRepo := repository.NewAcaraRepository(db)
+	if _, err := acaraRepo.GetByID(id, 0); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Acara tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil acara",
+			"error":   err.Error(),
+		})
+	}
+	if _, err := repository.NewUserRepository(db).FindByID(req.UserID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "User tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data user",
+			"error":   err.Error(),
+		})
+	}
+
+	if err := acaraRepo.TambahPetugas(id, req.UserID); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menambah petugas acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Petugas acara berhasil ditambahkan",
+	})
+}
+
+// HapusPetugasAcaraService mencabut akses panitia check-in acara (admin only)
+func HapusPetugasAcaraService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	userID, err := strconv.Atoi(c.Params("user_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID user tidak valid",
+		})
+	}
+
+	if err := repository.NewAcaraRepository(db).HapusPetugas(id, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "User bukan petugas acara ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus petugas acara",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Petugas acara berhasil dihapus",
+	})
+}
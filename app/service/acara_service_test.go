@@ -0,0 +1,99 @@
+package service
+
+import (
+	"hello-fiber/app/model"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func pendaftaranTest() model.PendaftaranAcara {
+	return model.PendaftaranAcara{
+		ID:         42,
+		AcaraID:    7,
+		AlumniID:   15,
+		DidaftarAt: time.Date(2026, 3, 1, 9, 30, 0, 123456789, time.UTC),
+	}
+}
+
+func TestBuatKodeTiket(t *testing.T) {
+	t.Setenv("ACARA_TIKET_KEY", "kunci-tiket-test")
+	p := pendaftaranTest()
+
+	kode, err := buatKodeTiket(p)
+	if err != nil {
+		t.Fatalf("buatKodeTiket: %v", err)
+	}
+	if !strings.HasPrefix(kode, "ACR1.7.42.") {
+		t.Fatalf("kode tiket %q", kode)
+	}
+	acaraID, pendaftaranID, tandaTangan, ok := parseKodeTiket(" " + kode + "\n")
+	if !ok || acaraID != 7 || pendaftaranID != 42 {
+		t.Fatalf("parseKodeTiket = %d, %d, %v", acaraID, pendaftaranID, ok)
+	}
+	if !tiketSah([]byte("kunci-tiket-test"), tandaTangan, p) {
+		t.Fatal("tiket yang baru dibuat harus sah")
+	}
+}
+
+func TestKunciTiketAcara(t *testing.T) {
+	// t.Setenv memulihkan nilai asli setelah test; Unsetenv karena GetEnv hanya jatuh ke default jika variabel tidak ada
+	t.Setenv("ACARA_TIKET_KEY", "")
+	os.Unsetenv("ACARA_TIKET_KEY")
+	t.Setenv("JWT_SECRET", "rahasia-jwt")
+	if kunci, err := kunciTiketAcara(); err != nil || string(kunci) != "rahasia-jwt" {
+		t.Errorf("tanpa ACARA_TIKET_KEY harus memakai JWT_SECRET, dapat %q, %v", kunci, err)
+	}
+	t.Setenv("JWT_SECRET", "")
+	if _, err := buatKodeTiket(pendaftaranTest()); err == nil {
+		t.Error("tanpa kunci tiket harus gagal")
+	}
+}
+
+func TestTiketSahDitolak(t *testing.T) {
+	kunci := []byte("kunci-tiket-test")
+	p := pendaftaranTest()
+	tandaTangan := tandaTanganTiket(kunci, p)
+
+	// setiap field yang ditandatangani harus mengubah tanda tangan
+	diubah := map[string]func(p *model.PendaftaranAcara){
+		"acara lain":       func(p *model.PendaftaranAcara) { p.AcaraID++ },
+		"pendaftaran lain": func(p *model.PendaftaranAcara) { p.ID++ },
+		"alumni lain":      func(p *model.PendaftaranAcara) { p.AlumniID++ },
+		"daftar ulang":     func(p *model.PendaftaranAcara) { p.DidaftarAt = p.DidaftarAt.Add(time.Nanosecond) },
+	}
+	for nama, ubah := range diubah {
+		lain := p
+		ubah(&lain)
+		if tiketSah(kunci, tandaTangan, lain) {
+			t.Errorf("%s: tanda tangan lama harus ditolak", nama)
+		}
+	}
+
+	if tiketSah([]byte("kunci-lain"), tandaTangan, p) {
+		t.Error("tanda tangan dengan kunci lain harus ditolak")
+	}
+	rusak := []byte(tandaTangan)
+	rusak[0] ^= 1
+	for _, sig := range []string{string(rusak), tandaTangan[:len(tandaTangan)-1], "", tandaTangan + "A"} {
+		if tiketSah(kunci, sig, p) {
+			t.Errorf("tanda tangan %q harus ditolak", sig)
+		}
+	}
+}
+
+func TestParseKodeTiketTidakValid(t *testing.T) {
+	for _, kode := range []string{
+		"",
+		"ACR1.7.42",
+		"ACR2.7.42.abc",
+		"ACR1.x.42.abc",
+		"ACR1.7.y.abc",
+		"ACR1.7.42.abc.def",
+	} {
+		if _, _, _, ok := parseKodeTiket(kode); ok {
+			t.Errorf("parseKodeTiket(%q) harus gagal", kode)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"database/sql"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetDuplikatAlumniService untuk melihat pasangan alumni yang kemungkinan duplikat (admin only).
+// Kriteria: NIM sama, email sama, nomor telepon sama, dan nama mirip (trigram).
+func GetDuplikatAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	minSkor, err := strconv.ParseFloat(c.Query("min_skor", "0.5"), 64)
+	if err != nil || minSkor <= 0 || minSkor > 1 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "min_skor harus di antara 0 dan 1",
+		})
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	if limit < 1 {
+		limit = 50
+	}
+
+	list, err := repository.NewAlumniMergeRepository(db).FindDuplicates(minSkor, limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mencari duplikat alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Saran duplikat alumni berhasil diambil",
+		"data":    list,
+	})
+}
+
+// AbaikanDuplikatAlumniService untuk menandai pasangan alumni sebagai bukan duplikat (admin only)
+func AbaikanDuplikatAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.AbaikanDuplikatRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	if len(req.AlumniIDs) != 2 || req.AlumniIDs[0] == req.AlumniIDs[1] {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "alumni_ids harus berisi dua ID alumni yang berbeda",
+		})
+	}
+
+	actorID, _ := c.Locals("user_id").(int)
+	if err := repository.NewAlumniMergeRepository(db).Abaikan(req.AlumniIDs[0], req.AlumniIDs[1], actorID); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengabaikan pasangan duplikat",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Pasangan alumni tidak akan disarankan lagi sebagai duplikat",
+	})
+}
+
+// MergeAlumniService untuk menggabungkan alumni duplikat ke alumni :id (admin only). Pekerjaan, akun user,
+// pengajuan, dan respon kuesioner duplikat dipindahkan ke alumni :id, lalu duplikat disembunyikan.
+func MergeAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.MergeAlumniRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	if req.DuplikatID <= 0 || req.DuplikatID == id {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "duplikat_id harus berisi alumni selain alumni tujuan",
+		})
+	}
+
+	actorID, _ := c.Locals("user_id").(int)
+	result, err := repository.NewAlumniMergeRepository(db).Merge(id, req.DuplikatID, req.Catatan, actorID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tujuan atau duplikat tidak ditemukan",
+			})
+		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Email hasil penggabungan bentrok dengan alumni aktif lain",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menggabungkan alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Alumni berhasil digabungkan",
+		"data":    result,
+	})
+}
+
+// GetAllAlumniMergeService untuk melihat riwayat penggabungan alumni (admin only)
+func GetAllAlumniMergeService(c *fiber.Ctx, db *sql.DB) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+
+	list, err := repository.NewAlumniMergeRepository(db).GetAll(limit, (page-1)*limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat penggabungan alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Riwayat penggabungan alumni berhasil diambil",
+		"data":    list,
+	})
+}
+
+// UndoAlumniMergeService untuk membatalkan penggabungan alumni (admin only)
+func UndoAlumniMergeService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	actorID, _ := c.Locals("user_id").(int)
+	result, err := repository.NewAlumniMergeRepository(db).Undo(id, actorID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Penggabungan tidak ditemukan atau sudah dibatalkan",
+			})
+		}
+		if err == repository.ErrMergeDianonimkan {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni pada penggabungan ini sudah dianonimkan sehingga penggabungan tidak bisa dibatalkan",
+			})
+		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Email alumni duplikat sudah dipakai alumni aktif lain, ubah email tersebut sebelum membatalkan penggabungan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal membatalkan penggabungan alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Penggabungan alumni berhasil dibatalkan",
+		"data":    result,
+	})
+}
@@ -3,7 +3,9 @@ package service
 import (
 	"hello-fiber/app/model"
 	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
 	"database/sql"
+	"fmt"
 	"strconv"
 	"strings"
 	"github.com/gofiber/fiber/v2"
@@ -20,7 +22,7 @@ func GetAllAlumniWithPaginationService(c *fiber.Ctx, db *sql.DB) error {
 
 	sortByWhitelist := map[string]bool{
 		"id": true, "nim": true, "nama": true, "jurusan": true, 
-		"angkatan": true, "tahun_lulus": true, "email": true, "created_at": true,
+		"angkatan": true, "tahun_lulus": true, "created_at": true,
 	}
 	if !sortByWhitelist[sortBy] {
 		sortBy = "id"
@@ -29,16 +31,26 @@ func GetAllAlumniWithPaginationService(c *fiber.Ctx, db *sql.DB) error {
 		order = "asc"
 	}
 
-	alumni, err := repository.GetAlumniWithPagination(db, search, sortBy, order, limit, offset)
+	// ?skill=Go,Docker hanya menampilkan alumni yang memiliki semua skill tersebut
+	skillIDs, skill, msg := parseSkillFilter(c, db)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{"error": msg})
+	}
+
+	alumni, err := repository.GetAlumniWithPagination(db, search, skillIDs, sortBy, order, limit, offset)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch alumni"})
 	}
 
-	total, err := repository.CountAlumni(db, search)
+	total, err := repository.CountAlumni(db, search, skillIDs)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to count alumni"})
 	}
 
+	if err := samarkanAlumni(c, db, alumni); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to apply alumni visibility"})
+	}
+
 	// Buat response pakai model
 	response := model.AlumniResponse{
 		Data: alumni,
@@ -50,6 +62,7 @@ func GetAllAlumniWithPaginationService(c *fiber.Ctx, db *sql.DB) error {
 			SortBy: sortBy,
 			Order:  order,
 			Search: search,
+			Skill:  skill,
 		},
 	}
 
@@ -84,11 +97,27 @@ func GetAlumniByIDService(c *fiber.Ctx, db *sql.DB) error {
 			"error":   err.Error(),
 		})
 	}
+	if alumni.Pendidikan, err = repository.NewPendidikanAlumniRepository(db).GetByAlumni(id); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat pendidikan alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	list := []model.Alumni{alumni}
+	if err := samarkanAlumni(c, db, list); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menerapkan visibilitas data alumni",
+			"error":   err.Error(),
+		})
+	}
 
 	return c.JSON(fiber.Map{
 		"success": true,
 		"message": "Data alumni berhasil diambil",
-		"data":    alumni,
+		"data":    list[0],
 	})
 }
 
@@ -108,9 +137,61 @@ func CreateAlumniService(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
-	alumniRepo := repository.NewAlumniRepository(db)
-	alumni, err := alumniRepo.Create(req)
+	if req.Jenjang != "" && !model.ValidJenjang(req.Jenjang) {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Jenjang harus D3, D4, S1, S2, S3, atau Profesi",
+		})
+	}
+
+	if msg := resolveReferensi(db, model.ReferensiProgramStudi, "jurusan", &req.Jurusan); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	// email disimpan terenkripsi, keunikan dicek lewat blind index
+	existing, err := repository.NewAlumniRepository(db).FindByEmail(req.Email, 0)
+	if err == nil {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Email sudah dipakai alumni lain (ID %d)", existing.ID),
+		})
+	}
+	if err != sql.ErrNoRows {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memeriksa email alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	tx, err := db.Begin()
 	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memulai transaksi alumni",
+			"error":   err.Error(),
+		})
+	}
+	defer tx.Rollback()
+
+	actorID, _ := c.Locals("user_id").(int)
+	alumni, err := repository.NewAlumniRepositoryTx(tx).Create(req)
+	if err == nil {
+		_, err = repository.NewRevisiRepositoryTx(tx).Record(model.RevisiEntitasAlumni, alumni.ID, nil, alumni, model.RevisiAksiBuat, 0, actorID)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "NIM atau email sudah dipakai alumni lain",
+			})
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
 			"message": "Gagal menambah alumni. Pastikan NIM dan email belum digunakan",
@@ -151,8 +232,45 @@ func UpdateAlumniService(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
-	alumniRepo := repository.NewAlumniRepository(db)
-	alumni, err := alumniRepo.Update(id, req)
+	if msg := resolveReferensi(db, model.ReferensiProgramStudi, "jurusan", &req.Jurusan); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	// email disimpan terenkripsi, keunikan dicek lewat blind index
+	existing, err := repository.NewAlumniRepository(db).FindByEmail(req.Email, id)
+	if err == nil {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Email sudah dipakai alumni lain (ID %d)", existing.ID),
+		})
+	}
+	if err != sql.ErrNoRows {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memeriksa email alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memulai transaksi alumni",
+			"error":   err.Error(),
+		})
+	}
+	defer tx.Rollback()
+
+	actorID, _ := c.Locals("user_id").(int)
+	sebelum, alumni, err := updateAlumniWithRevisi(tx, id, req, model.RevisiAksiUbah, 0, actorID)
+	middleware.Audit(c).Sebelum = sebelum
+	if err == nil {
+		err = tx.Commit()
+	}
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
@@ -160,6 +278,12 @@ func UpdateAlumniService(c *fiber.Ctx, db *sql.DB) error {
 				"message": "Alumni tidak ditemukan",
 			})
 		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Email sudah dipakai alumni lain",
+			})
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
 			"message": "Gagal mengupdate alumni",
@@ -174,7 +298,7 @@ func UpdateAlumniService(c *fiber.Ctx, db *sql.DB) error {
 	})
 }
 
-// DeleteAlumniService untuk menghapus data alumni
+// DeleteAlumniService untuk memindahkan alumni ke trash beserta pekerjaannya
 func DeleteAlumniService(c *fiber.Ctx, db *sql.DB) error {
 	id, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
@@ -184,8 +308,12 @@ func DeleteAlumniService(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
+	actorID, _ := c.Locals("user_id").(int)
 	alumniRepo := repository.NewAlumniRepository(db)
-	err = alumniRepo.Delete(id)
+	if current, err := alumniRepo.GetByID(id); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	err = alumniRepo.Delete(id, actorID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
@@ -202,6 +330,97 @@ func DeleteAlumniService(c *fiber.Ctx, db *sql.DB) error {
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"message": "Alumni berhasil dihapus",
+		"message": "Alumni berhasil dihapus sementara",
+	})
+}
+
+// GetTrashedAlumniService untuk mengambil alumni yang ada di trash (admin only)
+func GetTrashedAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniRepo := repository.NewAlumniRepository(db)
+	data, err := alumniRepo.GetTrashed()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data trash alumni",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Berhasil mengambil data trash alumni",
+		"data":    data,
+	})
+}
+
+// RestoreTrashedAlumniService untuk me-restore alumni dari trash beserta pekerjaan dan user terkait (admin only)
+func RestoreTrashedAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	alumniRepo := repository.NewAlumniRepository(db)
+	alumni, err := alumniRepo.RestoreIfTrashed(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan atau tidak berstatus hapus",
+			})
+		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Email alumni ini sudah dipakai alumni aktif lain, ubah salah satu email sebelum me-restore",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal me-restore alumni dari trash",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Berhasil me-restore alumni dari trash",
+		"data":    alumni,
+	})
+}
+
+// HardDeleteTrashedAlumniService untuk menghapus permanen alumni yang ada di trash (admin only)
+func HardDeleteTrashedAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	alumniRepo := repository.NewAlumniRepository(db)
+	berkas, err := alumniRepo.HardDeleteIfTrashed(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan atau tidak berstatus hapus",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus permanen alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	hapusObjectBerkasTerhapus(c.UserContext(), db, berkas)
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Berhasil menghapus permanen alumni",
 	})
 }
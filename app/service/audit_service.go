@@ -0,0 +1,163 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maksAuditExport batas baris satu kali export CSV
+const maksAuditExport = 50000
+
+// GetAuditLogService untuk mencari audit log (admin only). Filter: user_id, aksi, resource_tipe, resource_id,
+// dari dan sampai (YYYY-MM-DD). format=csv mengunduh seluruh hasil filter sebagai CSV.
+func GetAuditLogService(c *fiber.Ctx, db *sql.DB) error {
+	filter, msg := parseAuditFilter(c)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	auditRepo := repository.NewAuditRepository(db)
+
+	if strings.ToLower(c.Query("format", "json")) == "csv" {
+		list, err := auditRepo.GetAll(filter, maksAuditExport, 0)
+		if err == nil {
+			var data []byte
+			if data, err = auditLogToCSV(list); err == nil {
+				c.Set(fiber.HeaderContentType, "text/csv")
+				c.Set(fiber.HeaderContentDisposition, `attachment; filename="audit-log.csv"`)
+				return c.Send(data)
+			}
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal membuat file CSV audit log",
+			"error":   err.Error(),
+		})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+
+	list, err := auditRepo.GetAll(filter, limit, (page-1)*limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil audit log",
+			"error":   err.Error(),
+		})
+	}
+	total, err := auditRepo.Count(filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghitung audit log",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Audit log berhasil diambil",
+		"data":    list,
+		"meta": model.MetaInfo{
+			Page:  page,
+			Limit: limit,
+			Total: total,
+			Pages: (total + limit - 1) / limit,
+		},
+	})
+}
+
+// parseAuditFilter membaca filter audit dari query; sampai mencakup seluruh hari tersebut
+func parseAuditFilter(c *fiber.Ctx) (model.AuditFilter, string) {
+	filter := model.AuditFilter{
+		Aksi:         c.Query("aksi"),
+		ResourceTipe: c.Query("resource_tipe"),
+		ResourceID:   c.Query("resource_id"),
+	}
+	if v := c.Query("user_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, "user_id tidak valid"
+		}
+		filter.UserID = id
+	}
+	dari, err := model.ParseTanggal(c.Query("dari"))
+	if err != nil {
+		return filter, err.Error()
+	}
+	sampai, err := model.ParseTanggal(c.Query("sampai"))
+	if err != nil {
+		return filter, err.Error()
+	}
+	if !dari.IsZero() {
+		filter.Dari = time.Date(dari.Year(), dari.Month(), dari.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if !sampai.IsZero() {
+		filter.Sampai = time.Date(sampai.Year(), sampai.Month(), sampai.Day(), 23, 59, 59, 999999999, time.Local)
+	}
+	return filter, ""
+}
+
+func auditLogToCSV(list []model.AuditLog) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{
+		"id", "waktu", "user_id", "role_id", "impersonator_id", "aksi", "resource_tipe", "resource_id",
+		"method", "path", "status", "ip", "request_id", "sebelum", "sesudah", "hash_sebelumnya", "hash",
+	})
+	optionalInt := func(v int) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.Itoa(v)
+	}
+	for _, e := range list {
+		w.Write([]string{
+			strconv.FormatInt(e.ID, 10), e.Waktu.Format("2006-01-02 15:04:05.000000"),
+			optionalInt(e.UserID), optionalInt(e.RoleID), optionalInt(e.ImpersonatorID),
+			e.Aksi, e.ResourceTipe, e.ResourceID, e.Method, e.Path, strconv.Itoa(e.Status), e.IP, e.RequestID,
+			string(e.Sebelum), string(e.Sesudah), e.HashSebelumnya, e.Hash,
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// VerifikasiAuditLogService untuk memeriksa rantai hash audit log (admin only)
+func VerifikasiAuditLogService(c *fiber.Ctx, db *sql.DB) error {
+	result, err := repository.NewAuditRepository(db).Verify()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memverifikasi audit log",
+			"error":   err.Error(),
+		})
+	}
+
+	message := "Rantai audit log utuh"
+	if !result.Valid {
+		message = "Rantai audit log rusak"
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    result,
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"hello-fiber/app/repository"
+	"hello-fiber/storage"
+	"hello-fiber/utils"
+	"log"
+	"strconv"
+	"time"
+)
+
+// berkasCleanupBatch jumlah object yang dicoba ulang per putaran
+const berkasCleanupBatch = 100
+
+// loadBerkasCleanupInterval membaca BERKAS_CLEANUP_INTERVAL_MINUTES; 0 menonaktifkan scheduler
+func loadBerkasCleanupInterval() time.Duration {
+	intervalMinutes, err := strconv.Atoi(utils.GetEnv("BERKAS_CLEANUP_INTERVAL_MINUTES", "60"))
+	if err != nil || intervalMinutes < 0 {
+		intervalMinutes = 60
+	}
+	return time.Duration(intervalMinutes) * time.Minute
+}
+
+// StartBerkasCleanupScheduler mencoba ulang penghapusan object berkas yang sebelumnya gagal dihapus dari storage
+func StartBerkasCleanupScheduler(db *sql.DB) {
+	interval := loadBerkasCleanupInterval()
+	if interval == 0 {
+		log.Println("Berkas cleanup scheduler dinonaktifkan (BERKAS_CLEANUP_INTERVAL_MINUTES=0)")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if total, err := ulangiHapusBerkas(db); err != nil {
+				log.Println("Error menjalankan berkas cleanup scheduler:", err)
+			} else if total > 0 {
+				log.Printf("Berkas cleanup scheduler menghapus %d object yang sebelumnya gagal dihapus\n", total)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// ulangiHapusBerkas menghapus object di berkas_hapus_gagal; yang masih gagal tetap tercatat dengan jumlah percobaan bertambah
+func ulangiHapusBerkas(db *sql.DB) (int, error) {
+	berkasRepo := repository.NewBerkasRepository(db)
+	keys, err := berkasRepo.GetHapusGagal(berkasCleanupBatch)
+	if err != nil || len(keys) == 0 {
+		return 0, err
+	}
+	st, err := storage.Default()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, key := range keys {
+		if err := st.Delete(context.Background(), key); err != nil {
+			berkasRepo.CatatHapusGagal(key, err.Error())
+			continue
+		}
+		if err := berkasRepo.HapusCatatanGagal(key); err != nil {
+			return total, err
+		}
+		total++
+	}
+	return total, nil
+}
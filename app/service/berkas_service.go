@@ -0,0 +1,615 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"hello-fiber/storage"
+	"hello-fiber/utils"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Tipe file yang diterima, ditentukan dari isi file (bukan header Content-Type dari client), beserta ekstensinya
+var (
+	tipeFotoDiizinkan = map[string]string{
+		"image/jpeg": ".jpg",
+		"image/png":  ".png",
+		"image/gif":  ".gif",
+	}
+	tipeDokumenDiizinkan = map[string]string{
+		"application/pdf": ".pdf",
+		"image/jpeg":      ".jpg",
+		"image/png":       ".png",
+	}
+)
+
+const ukuranThumbnail = 256
+
+func maxUkuranFoto() int64 {
+	return int64(envInt("UPLOAD_MAX_FOTO_KB", 2048)) * 1024
+}
+
+func maxUkuranDokumen() int64 {
+	return int64(envInt("UPLOAD_MAX_DOKUMEN_KB", 10240)) * 1024
+}
+
+func berkasURLTTL() time.Duration {
+	return time.Duration(envInt("BERKAS_URL_TTL_MINUTES", 15)) * time.Minute
+}
+
+func envInt(key string, def int) int {
+	value, err := strconv.Atoi(utils.GetEnv(key, strconv.Itoa(def)))
+	if err != nil || value <= 0 {
+		return def
+	}
+	return value
+}
+
+type unggahan struct {
+	data        []byte
+	namaAsli    string
+	contentType string
+	ext         string
+}
+
+// bacaUnggahan membaca field multipart "file" dan memvalidasi ukuran serta tipenya.
+// Jika gagal, status dan pesan untuk respons dikembalikan.
+func bacaUnggahan(c *fiber.Ctx, maxUkuran int64, tipeDiizinkan map[string]string) (unggahan, int, string) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		return unggahan{}, 400, "File harus dikirim sebagai multipart form field \"file\""
+	}
+	if header.Size <= 0 {
+		return unggahan{}, 400, "File kosong"
+	}
+	if header.Size > maxUkuran {
+		return unggahan{}, 413, fmt.Sprintf("Ukuran file maksimal %d KB", maxUkuran/1024)
+	}
+
+	f, err := header.Open()
+	if err != nil {
+		return unggahan{}, 400, "File tidak dapat dibaca"
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxUkuran+1))
+	if err != nil {
+		return unggahan{}, 400, "File tidak dapat dibaca"
+	}
+	if int64(len(data)) > maxUkuran {
+		return unggahan{}, 413, fmt.Sprintf("Ukuran file maksimal %d KB", maxUkuran/1024)
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := tipeDiizinkan[contentType]
+	if !ok {
+		diizinkan := make([]string, 0, len(tipeDiizinkan))
+		for tipe := range tipeDiizinkan {
+			diizinkan = append(diizinkan, tipe)
+		}
+		return unggahan{}, 415, fmt.Sprintf("Tipe file %s tidak didukung (hanya %s)", contentType, strings.Join(diizinkan, ", "))
+	}
+
+	return unggahan{
+		data:        data,
+		namaAsli:    path.Base(strings.ReplaceAll(header.Filename, "\\", "/")),
+		contentType: contentType,
+		ext:         ext,
+	}, 0, ""
+}
+
+func keyAcak() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// simpanUnggahan menyimpan file (dan thumbnail untuk gambar) ke storage dengan key di bawah prefix.
+// Jika salah satu gagal, object yang sudah tersimpan dihapus lagi.
+func simpanUnggahan(c *fiber.Ctx, db *sql.DB, st storage.Storage, prefix string, u unggahan) (model.Berkas, error) {
+	nama, err := keyAcak()
+	if err != nil {
+		return model.Berkas{}, err
+	}
+	sum := sha256.Sum256(u.data)
+	berkas := model.Berkas{
+		NamaAsli:    u.namaAsli,
+		ContentType: u.contentType,
+		Ukuran:      int64(len(u.data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+		StorageKey:  prefix + "/" + nama + u.ext,
+	}
+
+	var thumbnail []byte
+	if strings.HasPrefix(u.contentType, "image/") {
+		if thumbnail, err = utils.BuatThumbnail(u.data, ukuranThumbnail); err != nil {
+			return berkas, fmt.Errorf("gambar tidak valid: %w", err)
+		}
+		berkas.ThumbnailKey = prefix + "/" + nama + "_thumb.jpg"
+	}
+
+	ctx := c.UserContext()
+	if err := st.Put(ctx, berkas.StorageKey, bytes.NewReader(u.data), berkas.Ukuran, u.contentType); err != nil {
+		return berkas, err
+	}
+	if thumbnail != nil {
+		if err := st.Put(ctx, berkas.ThumbnailKey, bytes.NewReader(thumbnail), int64(len(thumbnail)), "image/jpeg"); err != nil {
+			hapusObjectBerkas(c.UserContext(), db, st, model.Berkas{StorageKey: berkas.StorageKey})
+			return berkas, err
+		}
+	}
+	return berkas, nil
+}
+
+// hapusObjectBerkas menghapus file dan thumbnail dari storage setelah barisnya terhapus. Kegagalan tidak
+// membatalkan request; key-nya dicatat di berkas_hapus_gagal dan dicoba ulang oleh StartBerkasCleanupScheduler.
+func hapusObjectBerkas(ctx context.Context, db *sql.DB, st storage.Storage, berkas model.Berkas) {
+	for _, key := range []string{berkas.StorageKey, berkas.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := st.Delete(ctx, key); err != nil {
+			log.Println("Error menghapus object berkas", key+":", err)
+			repository.NewBerkasRepository(db).CatatHapusGagal(key, err.Error())
+		}
+	}
+}
+
+// hapusObjectBerkasTerhapus menghapus object milik baris berkas yang sudah terhapus bersama pemiliknya
+// (hard delete alumni atau pekerjaan, penghapusan data pribadi); dipanggil setelah commit
+func hapusObjectBerkasTerhapus(ctx context.Context, db *sql.DB, list []model.Berkas) {
+	if len(list) == 0 {
+		return
+	}
+	st, err := storage.Default()
+	if err != nil {
+		log.Println("Error membuka storage untuk menghapus berkas:", err)
+		berkasRepo := repository.NewBerkasRepository(db)
+		for _, berkas := range list {
+			for _, key := range []string{berkas.StorageKey, berkas.ThumbnailKey} {
+				if key != "" {
+					berkasRepo.CatatHapusGagal(key, err.Error())
+				}
+			}
+		}
+		return
+	}
+	for _, berkas := range list {
+		hapusObjectBerkas(ctx, db, st, berkas)
+	}
+}
+
+// tandatanganiBerkas mengisi URL unduh sementara untuk file dan thumbnail
+func tandatanganiBerkas(st storage.Storage, berkas *model.Berkas) error {
+	ttl := berkasURLTTL()
+	url, err := st.SignedURL(berkas.StorageKey, ttl)
+	if err != nil {
+		return err
+	}
+	berkas.URL = url
+	if berkas.ThumbnailKey != "" {
+		if berkas.ThumbnailURL, err = st.SignedURL(berkas.ThumbnailKey, ttl); err != nil {
+			return err
+		}
+	}
+	berkas.URLExpiresAt = time.Now().Add(ttl)
+	return nil
+}
+
+func storageError(c *fiber.Ctx, err error) error {
+	return c.Status(500).JSON(fiber.Map{
+		"success": false,
+		"message": "Storage berkas tidak tersedia",
+		"error":   err.Error(),
+	})
+}
+
+// UploadFotoAlumniService untuk alumni (atau admin) mengunggah foto profil; foto lama diganti
+func UploadFotoAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	if _, err := repository.NewAlumniRepository(db).GetByID(alumniID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	u, status, msg := bacaUnggahan(c, maxUkuranFoto(), tipeFotoDiizinkan)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	st, err := storage.Default()
+	if err != nil {
+		return storageError(c, err)
+	}
+	berkas, err := simpanUnggahan(c, db, st, fmt.Sprintf("alumni/%d/foto", alumniID), u)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan foto",
+			"error":   err.Error(),
+		})
+	}
+	berkas.AlumniID = &alumniID
+	berkas.Jenis = model.JenisBerkasFoto
+	berkas.DiunggahOleh, _ = c.Locals("user_id").(int)
+
+	created, lama, err := repository.NewBerkasRepository(db).ReplaceFotoAlumni(berkas)
+	if err != nil {
+		hapusObjectBerkas(c.UserContext(), db, st, berkas)
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan data foto",
+			"error":   err.Error(),
+		})
+	}
+	for _, b := range lama {
+		hapusObjectBerkas(c.UserContext(), db, st, b)
+	}
+	if len(lama) > 0 {
+		middleware.Audit(c).Sebelum = lama[0]
+	}
+	middleware.Audit(c).Sesudah = created
+
+	if err := tandatanganiBerkas(st, &created); err != nil {
+		return storageError(c, err)
+	}
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Foto alumni berhasil diunggah",
+		"data":    created,
+	})
+}
+
+// cekAksesFotoAlumni admin dan pemilik selalu boleh; selain itu alumni harus masih tampil
+// (tidak di trash) dan pemirsa lolos tingkat visibilitas foto alumni tersebut
+func cekAksesFotoAlumni(c *fiber.Ctx, db *sql.DB, alumniID int) (int, string, error) {
+	pemirsa, err := getPemirsaAlumni(c, db)
+	if err != nil {
+		return 500, "Gagal memeriksa akses foto alumni", err
+	}
+	if pemirsa.admin || pemirsa.alumniID == alumniID {
+		return 0, "", nil
+	}
+
+	if _, err := repository.NewAlumniRepository(db).GetByID(alumniID); err != nil {
+		if err == sql.ErrNoRows {
+			return 404, "Alumni tidak ditemukan", nil
+		}
+		return 500, "Gagal mengambil data alumni", err
+	}
+	visibilitas, err := repository.NewAlumniVisibilitasRepository(db).Get(alumniID)
+	if err != nil {
+		return 500, "Gagal mengambil pengaturan visibilitas", err
+	}
+	if !pemirsa.bolehLihat(visibilitas.Foto, alumniID) {
+		return 403, "Foto alumni ini tidak dapat dilihat", nil
+	}
+	return 0, "", nil
+}
+
+// GetFotoAlumniService URL sementara untuk foto profil dan thumbnail alumni sesuai visibilitas foto
+func GetFotoAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	if status, msg, err := cekAksesFotoAlumni(c, db, alumniID); status != 0 {
+		resp := fiber.Map{
+			"success": false,
+			"message": msg,
+		}
+		if err != nil {
+			resp["error"] = err.Error()
+		}
+		return c.Status(status).JSON(resp)
+	}
+
+	berkas, err := repository.NewBerkasRepository(db).GetFotoAlumni(alumniID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni belum memiliki foto",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil foto alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	st, err := storage.Default()
+	if err != nil {
+		return storageError(c, err)
+	}
+	if err := tandatanganiBerkas(st, &berkas); err != nil {
+		return storageError(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Foto alumni berhasil diambil",
+		"data":    berkas,
+	})
+}
+
+// DeleteFotoAlumniService menghapus foto profil alumni beserta file-nya di storage
+func DeleteFotoAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	berkasRepo := repository.NewBerkasRepository(db)
+	berkas, err := berkasRepo.GetFotoAlumni(alumniID)
+	if err == nil {
+		err = berkasRepo.Delete(berkas.ID)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni belum memiliki foto",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus foto alumni",
+			"error":   err.Error(),
+		})
+	}
+	middleware.Audit(c).Sebelum = berkas
+
+	if st, err := storage.Default(); err == nil {
+		hapusObjectBerkas(c.UserContext(), db, st, berkas)
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Foto alumni berhasil dihapus",
+	})
+}
+
+// UploadDokumenPekerjaanService untuk alumni (atau admin) mengunggah bukti pekerjaan, misalnya offer letter atau SK
+func UploadDokumenPekerjaanService(c *fiber.Ctx, db *sql.DB) error {
+	pekerjaanID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	if _, err := repository.NewPekerjaanAlumniRepository(db).GetByID(pekerjaanID, model.ScopeActive); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pekerjaan alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	kategori := c.FormValue("kategori", model.KategoriDokumenLainnya)
+	if !model.ValidKategoriDokumen(kategori) {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi gagal",
+			"errors": []model.FieldError{{
+				Field: "kategori",
+				Kode:  "tidak_valid",
+				Pesan: "kategori harus offer_letter, sk, kontrak, atau lainnya",
+			}},
+		})
+	}
+
+	u, status, msg := bacaUnggahan(c, maxUkuranDokumen(), tipeDokumenDiizinkan)
+	if status != 0 {
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	st, err := storage.Default()
+	if err != nil {
+		return storageError(c, err)
+	}
+	berkas, err := simpanUnggahan(c, db, st, fmt.Sprintf("pekerjaan/%d/dokumen", pekerjaanID), u)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan dokumen",
+			"error":   err.Error(),
+		})
+	}
+	berkas.PekerjaanID = &pekerjaanID
+	berkas.Jenis = model.JenisBerkasDokumen
+	berkas.Kategori = kategori
+	berkas.DiunggahOleh, _ = c.Locals("user_id").(int)
+
+	created, err := repository.NewBerkasRepository(db).Create(berkas)
+	if err != nil {
+		hapusObjectBerkas(c.UserContext(), db, st, berkas)
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan data dokumen",
+			"error":   err.Error(),
+		})
+	}
+	middleware.Audit(c).Sesudah = created
+
+	if err := tandatanganiBerkas(st, &created); err != nil {
+		return storageError(c, err)
+	}
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Dokumen pekerjaan berhasil diunggah",
+		"data":    created,
+	})
+}
+
+// GetDokumenPekerjaanService daftar dokumen sebuah pekerjaan beserta URL unduh sementara
+func GetDokumenPekerjaanService(c *fiber.Ctx, db *sql.DB) error {
+	pekerjaanID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	list, err := repository.NewBerkasRepository(db).GetByPekerjaan(pekerjaanID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil dokumen pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+
+	st, err := storage.Default()
+	if err != nil {
+		return storageError(c, err)
+	}
+	for i := range list {
+		if err := tandatanganiBerkas(st, &list[i]); err != nil {
+			return storageError(c, err)
+		}
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Dokumen pekerjaan berhasil diambil",
+		"data":    list,
+	})
+}
+
+// DeleteDokumenPekerjaanService menghapus satu dokumen pekerjaan beserta file-nya di storage
+func DeleteDokumenPekerjaanService(c *fiber.Ctx, db *sql.DB) error {
+	pekerjaanID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	berkasID, err := strconv.Atoi(c.Params("berkas_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID dokumen tidak valid",
+		})
+	}
+
+	berkasRepo := repository.NewBerkasRepository(db)
+	berkas, err := berkasRepo.GetByID(berkasID)
+	if err == nil && (berkas.PekerjaanID == nil || *berkas.PekerjaanID != pekerjaanID) {
+		err = sql.ErrNoRows
+	}
+	if err == nil {
+		err = berkasRepo.Delete(berkas.ID)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Dokumen tidak ditemukan pada pekerjaan ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus dokumen pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+	middleware.Audit(c).Sebelum = berkas
+
+	if st, err := storage.Default(); err == nil {
+		hapusObjectBerkas(c.UserContext(), db, st, berkas)
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Dokumen pekerjaan berhasil dihapus",
+	})
+}
+
+// UnduhBerkasService melayani signed URL driver storage lokal. Tidak memakai JWT; akses dijamin oleh
+// tanda tangan HMAC dan masa berlaku di query string.
+func UnduhBerkasService(c *fiber.Ctx) error {
+	st, err := storage.Default()
+	if err != nil {
+		return storageError(c, err)
+	}
+	local, ok := st.(*storage.Local)
+	key := c.Query("key")
+	if !ok || !local.Verify(key, c.Query("exp"), c.Query("sig")) {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "URL unduh tidak valid atau sudah kedaluwarsa",
+		})
+	}
+
+	r, err := local.Get(c.UserContext(), key)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Berkas tidak ditemukan",
+			})
+		}
+		return storageError(c, err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = fiber.MIMEOctetStream
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, path.Base(key)))
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.SendStream(r)
+}
@@ -0,0 +1,432 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maksTagKeahlian batas jumlah tag per keahlian atau per alumni (tag admin)
+const maksTagKeahlian = 20
+
+// resolveSkill mencari referensi skill aktif dari nama atau alias. buatBaru=true (tag oleh admin) membuat
+// entri baru untuk nama yang belum dikenal. Mengembalikan pesan error untuk user jika gagal.
+func resolveSkill(refRepo repository.ReferensiRepository, field, nilai string, buatBaru bool) (model.Referensi, string) {
+	nilai = strings.Join(strings.Fields(nilai), " ")
+	ref, err := refRepo.Resolve(model.ReferensiSkill, nilai)
+	if err == nil {
+		return ref, ""
+	}
+	if err != sql.ErrNoRows {
+		return ref, fmt.Sprintf("Gagal memeriksa %s", field)
+	}
+	if !buatBaru {
+		return ref, fmt.Sprintf("%s \"%s\" tidak dikenali, pilih dari /api/referensi/%s", field, nilai, model.ReferensiSkill)
+	}
+
+	// entri nonaktif tidak dihidupkan lagi diam-diam lewat penandaan
+	if _, err := refRepo.FindByNama(model.ReferensiSkill, 0, nilai); err == nil {
+		return ref, fmt.Sprintf("%s \"%s\" sudah dinonaktifkan di /api/referensi/%s", field, nilai, model.ReferensiSkill)
+	}
+	ref, err = refRepo.Create(model.ReferensiSkill, model.ReferensiRequest{Nama: nilai})
+	if err != nil {
+		return ref, fmt.Sprintf("Gagal membuat tag %s \"%s\"", field, nilai)
+	}
+	return ref, ""
+}
+
+// resolveDaftarSkill resolveSkill untuk beberapa nilai sekaligus; nilai kosong dan duplikat dilewati
+func resolveDaftarSkill(db *sql.DB, field string, daftar []string, buatBaru bool) ([]int, string) {
+	refRepo := repository.NewReferensiRepository(db)
+	ids := []int{}
+	sudah := map[int]bool{}
+	for _, nilai := range daftar {
+		if strings.TrimSpace(nilai) == "" {
+			continue
+		}
+		ref, msg := resolveSkill(refRepo, field, nilai, buatBaru)
+		if msg != "" {
+			return nil, msg
+		}
+		if !sudah[ref.ID] {
+			sudah[ref.ID] = true
+			ids = append(ids, ref.ID)
+		}
+	}
+	return ids, ""
+}
+
+// parseSkillFilter membaca ?skill=Go,Docker pada daftar alumni menjadi id referensi skill
+func parseSkillFilter(c *fiber.Ctx, db *sql.DB) ([]int, []string, string) {
+	nama := []string{}
+	for _, nilai := range strings.Split(c.Query("skill"), ",") {
+		if nilai = strings.TrimSpace(nilai); nilai != "" {
+			nama = append(nama, nilai)
+		}
+	}
+	if len(nama) == 0 {
+		return nil, nil, ""
+	}
+	ids, msg := resolveDaftarSkill(db, "skill", nama, false)
+	return ids, nama, msg
+}
+
+// validasiKeahlian memeriksa request keahlian, menyeragamkan nama skill ke nama kanonisnya, dan
+// mengembalikan id referensi skill (0 untuk sertifikasi) serta id tag tambahan
+func validasiKeahlian(db *sql.DB, req *model.KeahlianAlumniRequest) ([]model.FieldError, int, []int, string) {
+	errs := []model.FieldError{}
+	req.Jenis = strings.TrimSpace(req.Jenis)
+	req.Nama = strings.TrimSpace(req.Nama)
+	req.Penerbit = strings.TrimSpace(req.Penerbit)
+
+	if !model.ValidJenisKeahlian(req.Jenis) {
+		errs = append(errs, model.FieldError{Field: "jenis", Kode: "tidak_valid", Pesan: "jenis harus skill atau sertifikasi"})
+	}
+	if req.Nama == "" {
+		errs = append(errs, model.FieldError{Field: "nama", Kode: "wajib", Pesan: "nama harus diisi"})
+	}
+	if req.Jenis == model.JenisKeahlianSertifikasi && req.Penerbit == "" {
+		errs = append(errs, model.FieldError{Field: "penerbit", Kode: "wajib", Pesan: "penerbit sertifikasi harus diisi"})
+	}
+	tahunMaks := time.Now().Year()
+	if req.Tahun != nil && (*req.Tahun < 1950 || *req.Tahun > tahunMaks) {
+		errs = append(errs, model.FieldError{Field: "tahun", Kode: "tidak_valid", Pesan: fmt.Sprintf("tahun harus antara 1950 dan %d", tahunMaks)})
+	}
+	if req.Jenis == model.JenisKeahlianSkill && !req.BerlakuSampai.IsZero() {
+		errs = append(errs, model.FieldError{Field: "berlaku_sampai", Kode: "tidak_valid", Pesan: "berlaku_sampai hanya untuk sertifikasi"})
+	}
+	if req.Tahun != nil && !req.BerlakuSampai.IsZero() && req.BerlakuSampai.Year() < *req.Tahun {
+		errs = append(errs, model.FieldError{Field: "berlaku_sampai", Kode: "sebelum_tahun", Pesan: "berlaku_sampai tidak boleh sebelum tahun terbit"})
+	}
+	if len(req.Tags) > maksTagKeahlian {
+		errs = append(errs, model.FieldError{Field: "tags", Kode: "tidak_valid", Pesan: fmt.Sprintf("maksimal %d tag", maksTagKeahlian)})
+	}
+	if len(errs) > 0 {
+		return errs, 0, nil, ""
+	}
+
+	skillID := 0
+	if req.Jenis == model.JenisKeahlianSkill {
+		ref, msg := resolveSkill(repository.NewReferensiRepository(db), "nama", req.Nama, false)
+		if msg != "" {
+			return errs, 0, nil, msg
+		}
+		req.Nama = ref.Nama
+		skillID = ref.ID
+	}
+	tagIDs, msg := resolveDaftarSkill(db, "tags", req.Tags, false)
+	return errs, skillID, tagIDs, msg
+}
+
+// parseKeahlianRequest membaca dan memvalidasi body; respons error sudah dikirim jika ok=false
+func parseKeahlianRequest(c *fiber.Ctx, db *sql.DB) (model.KeahlianAlumniRequest, int, []int, bool, error) {
+	var req model.KeahlianAlumniRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, 0, nil, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	errs, skillID, tagIDs, msg := validasiKeahlian(db, &req)
+	if len(errs) > 0 {
+		return req, 0, nil, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi keahlian gagal",
+			"errors":  errs,
+		})
+	}
+	if msg != "" {
+		return req, 0, nil, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	return req, skillID, tagIDs, true, nil
+}
+
+// buildProfilKeahlian keahlian, tag admin, dan gabungan skill aktif satu alumni
+func buildProfilKeahlian(db *sql.DB, alumniID int) (model.ProfilKeahlian, error) {
+	keahlianRepo := repository.NewKeahlianAlumniRepository(db)
+	var profil model.ProfilKeahlian
+	var err error
+	if profil.Keahlian, err = keahlianRepo.GetByAlumni(alumniID); err != nil {
+		return profil, err
+	}
+	if profil.TagAdmin, err = keahlianRepo.GetTagAlumni(alumniID); err != nil {
+		return profil, err
+	}
+	profil.SkillAktif, err = keahlianRepo.GetSkillAktif(alumniID)
+	return profil, err
+}
+
+// GetMyKeahlianService skill dan sertifikasi milik alumni yang login
+func GetMyKeahlianService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	profil, err := buildProfilKeahlian(db, alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil keahlian alumni",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Keahlian alumni berhasil diambil",
+		"data":    profil,
+	})
+}
+
+// CreateMyKeahlianService alumni menambah skill atau sertifikasinya sendiri
+func CreateMyKeahlianService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	req, skillID, tagIDs, ok, err := parseKeahlianRequest(c, db)
+	if !ok {
+		return err
+	}
+
+	keahlian, err := repository.NewKeahlianAlumniRepository(db).Create(alumni.ID, req, skillID, tagIDs)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("%s %s sudah tercatat", req.Jenis, req.Nama),
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menambah keahlian",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Keahlian berhasil ditambahkan",
+		"data":    keahlian,
+	})
+}
+
+// UpdateMyKeahlianService alumni mengubah skill atau sertifikasinya sendiri
+func UpdateMyKeahlianService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	req, skillID, tagIDs, ok, err := parseKeahlianRequest(c, db)
+	if !ok {
+		return err
+	}
+
+	keahlianRepo := repository.NewKeahlianAlumniRepository(db)
+	if current, err := keahlianRepo.GetByID(alumni.ID, id); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	keahlian, err := keahlianRepo.Update(alumni.ID, id, req, skillID, tagIDs)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Keahlian tidak ditemukan",
+			})
+		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("%s %s sudah tercatat", req.Jenis, req.Nama),
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengupdate keahlian",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Keahlian berhasil diupdate",
+		"data":    keahlian,
+	})
+}
+
+// DeleteMyKeahlianService alumni menghapus skill atau sertifikasinya sendiri
+func DeleteMyKeahlianService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	keahlianRepo := repository.NewKeahlianAlumniRepository(db)
+	if current, err := keahlianRepo.GetByID(alumni.ID, id); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	if err := keahlianRepo.Delete(alumni.ID, id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Keahlian tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus keahlian",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Keahlian berhasil dihapus",
+	})
+}
+
+// GetKeahlianAlumniService keahlian dan tag satu alumni untuk dilihat user lain
+func GetKeahlianAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	if _, err := repository.NewAlumniRepository(db).GetByID(alumniID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	profil, err := buildProfilKeahlian(db, alumniID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil keahlian alumni",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Keahlian alumni berhasil diambil",
+		"data":    profil,
+	})
+}
+
+// UpdateTagAlumniService admin mengganti seluruh tag skill alumni; tag yang belum ada di kosakata dibuat (admin only)
+func UpdateTagAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.TagAlumniRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	if len(req.Tags) > maksTagKeahlian {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi tag gagal",
+			"errors": []model.FieldError{
+				{Field: "tags", Kode: "tidak_valid", Pesan: fmt.Sprintf("maksimal %d tag", maksTagKeahlian)},
+			},
+		})
+	}
+
+	if _, err := repository.NewAlumniRepository(db).GetByID(alumniID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	referensiIDs, msg := resolveDaftarSkill(db, "tags", req.Tags, true)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	keahlianRepo := repository.NewKeahlianAlumniRepository(db)
+	if current, err := keahlianRepo.GetTagAlumni(alumniID); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	actorID, _ := c.Locals("user_id").(int)
+	tags, err := keahlianRepo.SetTagAlumni(alumniID, referensiIDs, actorID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan tag alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Tag alumni berhasil disimpan",
+		"data":    tags,
+	})
+}
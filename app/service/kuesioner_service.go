@@ -0,0 +1,585 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateKuesionerService untuk membuat kuesioner tracer study baru (admin only)
+func CreateKuesionerService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.CreateKuesionerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	if req.Judul == "" || req.Periode == 0 || len(req.Bagian) == 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Judul, periode, dan minimal satu bagian harus diisi",
+		})
+	}
+	if msg := validateKuesionerRequest(req); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	kuesioner, err := repository.NewKuesionerRepository(db).Create(req)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menambah kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Kuesioner berhasil ditambahkan",
+		"data":    kuesioner,
+	})
+}
+
+func validateKuesionerRequest(req model.CreateKuesionerRequest) string {
+	for i, bagian := range req.Bagian {
+		if bagian.Judul == "" {
+			return fmt.Sprintf("Judul bagian ke-%d harus diisi", i+1)
+		}
+		for j, p := range bagian.Pertanyaan {
+			if p.Pertanyaan == "" {
+				return fmt.Sprintf("Pertanyaan ke-%d pada bagian ke-%d harus diisi", j+1, i+1)
+			}
+			switch p.Tipe {
+			case model.TipePertanyaanSingle, model.TipePertanyaanMulti:
+				if len(p.Opsi) < 2 {
+					return fmt.Sprintf("Pertanyaan \"%s\" harus memiliki minimal dua opsi", p.Pertanyaan)
+				}
+			case model.TipePertanyaanScale:
+				if p.SkalaMax <= p.SkalaMin {
+					return fmt.Sprintf("Skala maksimum pertanyaan \"%s\" harus lebih besar dari skala minimum", p.Pertanyaan)
+				}
+			case model.TipePertanyaanText, model.TipePertanyaanDate:
+			default:
+				return fmt.Sprintf("Tipe pertanyaan \"%s\" tidak valid (single, multi, scale, text, date)", p.Tipe)
+			}
+		}
+	}
+	return ""
+}
+
+// GetAllKuesionerService untuk mengambil semua kuesioner (admin only)
+func GetAllKuesionerService(c *fiber.Ctx, db *sql.DB) error {
+	data, err := repository.NewKuesionerRepository(db).GetAll()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data kuesioner",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data kuesioner berhasil diambil",
+		"data":    data,
+	})
+}
+
+// GetKuesionerByIDService untuk mengambil kuesioner lengkap dengan pertanyaannya; kuesioner draft hanya untuk admin
+func GetKuesionerByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	kuesioner, err := repository.NewKuesionerRepository(db).GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Kuesioner tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data kuesioner",
+			"error":   err.Error(),
+		})
+	}
+	if roleID, _ := c.Locals("role_id").(int); roleID != 1 && kuesioner.Status == model.StatusKuesionerDraft {
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"message": "Kuesioner tidak ditemukan",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data kuesioner berhasil diambil",
+		"data":    kuesioner,
+	})
+}
+
+// UpdateKuesionerStatusService untuk mengubah status kuesioner atau membuka periode baru (admin only)
+func UpdateKuesionerStatusService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.UpdateKuesionerStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	validStatus := map[string]bool{
+		"": true, model.StatusKuesionerDraft: true, model.StatusKuesionerAktif: true, model.StatusKuesionerDitutup: true,
+	}
+	if !validStatus[req.Status] || (req.Status == "" && req.Periode == 0) {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Status harus draft, aktif, atau ditutup, atau periode baru harus diisi",
+		})
+	}
+
+	kuesioner, err := repository.NewKuesionerRepository(db).UpdateStatus(id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Kuesioner tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengupdate status kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Status kuesioner berhasil diupdate",
+		"data":    kuesioner,
+	})
+}
+
+// DeleteKuesionerService untuk menghapus kuesioner beserta seluruh jawabannya (admin only)
+func DeleteKuesionerService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	if err := repository.NewKuesionerRepository(db).Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Kuesioner tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Kuesioner berhasil dihapus",
+	})
+}
+
+// GetMyKuesionerService untuk mengambil kuesioner aktif yang ditujukan ke alumni yang login
+func GetMyKuesionerService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	data, err := repository.NewKuesionerRepository(db).GetAvailableForAlumni(alumni)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data kuesioner berhasil diambil",
+		"data":    data,
+	})
+}
+
+// SubmitJawabanKuesionerService untuk mengisi kuesioner; setiap pengisian ulang disimpan sebagai versi baru
+func SubmitJawabanKuesionerService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	var req model.SubmitJawabanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	kuesionerRepo := repository.NewKuesionerRepository(db)
+	kuesioner, err := kuesionerRepo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Kuesioner tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	if kuesioner.Status != model.StatusKuesionerAktif || !kuesionerTargetsAlumni(kuesioner, alumni) {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Kuesioner tidak tersedia untuk alumni ini",
+		})
+	}
+
+	if msg := validateJawaban(kuesioner, req.Jawaban); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	respon, err := kuesionerRepo.SubmitRespon(kuesioner, alumni.ID, req.Jawaban)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan jawaban kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Jawaban kuesioner berhasil disimpan",
+		"data":    respon,
+	})
+}
+
+// GetMyJawabanKuesionerService untuk mengambil versi terakhir jawaban alumni yang login
+func GetMyJawabanKuesionerService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	kuesionerRepo := repository.NewKuesionerRepository(db)
+	kuesioner, err := kuesionerRepo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Kuesioner tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	periode, _ := strconv.Atoi(c.Query("periode", strconv.Itoa(kuesioner.Periode)))
+	respon, err := kuesionerRepo.GetLatestRespon(id, alumni.ID, periode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Belum ada jawaban untuk periode ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil jawaban kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Jawaban kuesioner berhasil diambil",
+		"data":    respon,
+	})
+}
+
+// GetHasilKuesionerService untuk mengambil tingkat respon dan agregasi jawaban (admin only)
+func GetHasilKuesionerService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	kuesionerRepo := repository.NewKuesionerRepository(db)
+	kuesioner, err := kuesionerRepo.GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Kuesioner tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	periode, _ := strconv.Atoi(c.Query("periode", strconv.Itoa(kuesioner.Periode)))
+
+	sasaran, err := kuesionerRepo.CountSasaran(kuesioner)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghitung sasaran kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	jawaban, responden, err := kuesionerRepo.GetLatestJawaban(id, periode)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil jawaban kuesioner",
+			"error":   err.Error(),
+		})
+	}
+
+	hasil := model.HasilKuesioner{
+		KuesionerID:    kuesioner.ID,
+		Periode:        periode,
+		TotalSasaran:   sasaran,
+		TotalResponden: responden,
+		Pertanyaan:     aggregateJawaban(kuesioner, jawaban),
+	}
+	if sasaran > 0 {
+		hasil.TingkatRespon = float64(int64(float64(responden)*10000/float64(sasaran)+0.5)) / 100
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Hasil kuesioner berhasil diambil",
+		"data":    hasil,
+	})
+}
+
+func kuesionerTargetsAlumni(kuesioner model.Kuesioner, alumni model.Alumni) bool {
+	if kuesioner.Angkatan != 0 && kuesioner.Angkatan != alumni.Angkatan {
+		return false
+	}
+	if kuesioner.Jurusan != "" && !strings.EqualFold(strings.TrimSpace(kuesioner.Jurusan), strings.TrimSpace(alumni.Jurusan)) {
+		return false
+	}
+	return true
+}
+
+// validateJawaban memastikan setiap jawaban sesuai tipe pertanyaannya dan pertanyaan wajib terisi
+func validateJawaban(kuesioner model.Kuesioner, items []model.JawabanItem) string {
+	pertanyaanByID := map[int]model.KuesionerPertanyaan{}
+	for _, bagian := range kuesioner.Bagian {
+		for _, p := range bagian.Pertanyaan {
+			pertanyaanByID[p.ID] = p
+		}
+	}
+
+	answered := map[int]bool{}
+	for _, item := range items {
+		p, ok := pertanyaanByID[item.PertanyaanID]
+		if !ok {
+			return fmt.Sprintf("Pertanyaan ID %d tidak termasuk dalam kuesioner ini", item.PertanyaanID)
+		}
+		if answered[p.ID] {
+			return fmt.Sprintf("Pertanyaan ID %d dijawab lebih dari sekali", p.ID)
+		}
+		answered[p.ID] = true
+
+		if !validJawabanValue(p, item.Jawaban) {
+			return fmt.Sprintf("Jawaban untuk pertanyaan \"%s\" tidak sesuai tipe %s", p.Pertanyaan, p.Tipe)
+		}
+	}
+
+	for _, p := range pertanyaanByID {
+		if p.Wajib && !answered[p.ID] {
+			return fmt.Sprintf("Pertanyaan \"%s\" wajib dijawab", p.Pertanyaan)
+		}
+	}
+	return ""
+}
+
+func validJawabanValue(p model.KuesionerPertanyaan, raw json.RawMessage) bool {
+	switch p.Tipe {
+	case model.TipePertanyaanSingle:
+		var value string
+		return json.Unmarshal(raw, &value) == nil && containsString(p.Opsi, value)
+	case model.TipePertanyaanMulti:
+		var values []string
+		if json.Unmarshal(raw, &values) != nil || len(values) == 0 {
+			return false
+		}
+		for _, v := range values {
+			if !containsString(p.Opsi, v) {
+				return false
+			}
+		}
+		return true
+	case model.TipePertanyaanScale:
+		var value int
+		return json.Unmarshal(raw, &value) == nil && value >= p.SkalaMin && value <= p.SkalaMax
+	case model.TipePertanyaanText:
+		var value string
+		return json.Unmarshal(raw, &value) == nil && value != ""
+	case model.TipePertanyaanDate:
+		var value string
+		if json.Unmarshal(raw, &value) != nil {
+			return false
+		}
+		_, err := time.Parse("2006-01-02", value)
+		return err == nil
+	}
+	return false
+}
+
+// aggregateJawaban menghitung frekuensi opsi, rata-rata skala, dan contoh jawaban teks per pertanyaan
+func aggregateJawaban(kuesioner model.Kuesioner, items []model.JawabanItem) []model.HasilPertanyaan {
+	const maxContoh = 10
+
+	hasilByID := map[int]*model.HasilPertanyaan{}
+	hasil := []model.HasilPertanyaan{}
+	for _, bagian := range kuesioner.Bagian {
+		for _, p := range bagian.Pertanyaan {
+			h := model.HasilPertanyaan{PertanyaanID: p.ID, Pertanyaan: p.Pertanyaan, Tipe: p.Tipe}
+			if p.Tipe == model.TipePertanyaanSingle || p.Tipe == model.TipePertanyaanMulti || p.Tipe == model.TipePertanyaanScale {
+				h.Frekuensi = map[string]int{}
+				for _, opsi := range p.Opsi {
+					h.Frekuensi[opsi] = 0
+				}
+			}
+			hasil = append(hasil, h)
+		}
+	}
+	for i := range hasil {
+		hasilByID[hasil[i].PertanyaanID] = &hasil[i]
+	}
+
+	totalSkala := map[int]int{}
+	for _, item := range items {
+		h, ok := hasilByID[item.PertanyaanID]
+		if !ok {
+			continue
+		}
+		h.JumlahJawab++
+
+		switch h.Tipe {
+		case model.TipePertanyaanSingle:
+			var value string
+			if json.Unmarshal(item.Jawaban, &value) == nil {
+				h.Frekuensi[value]++
+			}
+		case model.TipePertanyaanMulti:
+			var values []string
+			if json.Unmarshal(item.Jawaban, &values) == nil {
+				for _, v := range values {
+					h.Frekuensi[v]++
+				}
+			}
+		case model.TipePertanyaanScale:
+			var value int
+			if json.Unmarshal(item.Jawaban, &value) == nil {
+				h.Frekuensi[strconv.Itoa(value)]++
+				totalSkala[h.PertanyaanID] += value
+			}
+		case model.TipePertanyaanText, model.TipePertanyaanDate:
+			var value string
+			if json.Unmarshal(item.Jawaban, &value) == nil && len(h.Contoh) < maxContoh {
+				h.Contoh = append(h.Contoh, value)
+			}
+		}
+	}
+
+	for i := range hasil {
+		if hasil[i].Tipe == model.TipePertanyaanScale && hasil[i].JumlahJawab > 0 {
+			avg := float64(totalSkala[hasil[i].PertanyaanID]) / float64(hasil[i].JumlahJawab)
+			hasil[i].RataRata = float64(int64(avg*100+0.5)) / 100
+		}
+	}
+	return hasil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"database/sql"
+	"hello-fiber/app/repository"
+	"hello-fiber/utils"
+	"log"
+	"strconv"
+	"time"
+)
+
+// loadLowonganExpiryInterval membaca LOWONGAN_EXPIRY_INTERVAL_MINUTES; 0 menonaktifkan scheduler.
+// Daftar lowongan tetap menampilkan status efektif walau scheduler belum berjalan.
+func loadLowonganExpiryInterval() time.Duration {
+	intervalMinutes, err := strconv.Atoi(utils.GetEnv("LOWONGAN_EXPIRY_INTERVAL_MINUTES", "60"))
+	if err != nil || intervalMinutes < 0 {
+		intervalMinutes = 60
+	}
+	return time.Duration(intervalMinutes) * time.Minute
+}
+
+// StartLowonganExpiryScheduler menandai lowongan yang melewati batas lamaran sebagai kedaluwarsa secara berkala
+func StartLowonganExpiryScheduler(db *sql.DB) {
+	interval := loadLowonganExpiryInterval()
+	if interval == 0 {
+		log.Println("Lowongan expiry scheduler dinonaktifkan (LOWONGAN_EXPIRY_INTERVAL_MINUTES=0)")
+		return
+	}
+
+	go func() {
+		lowonganRepo := repository.NewLowonganRepository(db)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if total, err := lowonganRepo.ExpireLewatBatas(); err != nil {
+				log.Println("Error menjalankan lowongan expiry scheduler:", err)
+			} else if total > 0 {
+				log.Printf("Lowongan expiry scheduler menutup %d lowongan kedaluwarsa\n", total)
+			}
+			<-ticker.C
+		}
+	}()
+}
@@ -0,0 +1,727 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// perekrutLowongan user yang boleh memasang lowongan: admin (perusahaan bebas) atau mitra (perusahaannya sendiri)
+type perekrutLowongan struct {
+	admin        bool
+	perusahaanID int
+}
+
+func getPerekrutLowongan(c *fiber.Ctx, db *sql.DB) (perekrutLowongan, bool) {
+	if roleID, _ := c.Locals("role_id").(int); roleID == 1 {
+		return perekrutLowongan{admin: true}, true
+	}
+	userID, _ := c.Locals("user_id").(int)
+	user, err := repository.NewUserRepository(db).FindByID(userID)
+	if err != nil || !user.IsActive || user.PerusahaanID == 0 {
+		return perekrutLowongan{}, false
+	}
+	return perekrutLowongan{perusahaanID: user.PerusahaanID}, true
+}
+
+// getAlumniPemirsa alumni_id user yang login, 0 jika akun tidak terhubung dengan alumni aktif
+func getAlumniPemirsa(c *fiber.Ctx, db *sql.DB) int {
+	userID, _ := c.Locals("user_id").(int)
+	user, err := repository.NewUserRepository(db).FindByID(userID)
+	if err != nil || !user.IsActive {
+		return 0
+	}
+	return user.AlumniID
+}
+
+// parseLowonganFilter membaca filter status, bidang_industri, lokasi, perusahaan_id, gaji_min, gaji_max,
+// mata_uang dan disimpan dari query. Mengembalikan pesan error jika ada nilai yang tidak valid.
+func parseLowonganFilter(c *fiber.Ctx) (model.LowonganFilter, string) {
+	filter := model.LowonganFilter{
+		Search:         c.Query("search", ""),
+		Status:         c.Query("status"),
+		BidangIndustri: strings.TrimSpace(c.Query("bidang_industri")),
+		Lokasi:         strings.TrimSpace(c.Query("lokasi")),
+		MataUang:       strings.ToUpper(c.Query("mata_uang")),
+		DisimpanSaja:   c.QueryBool("disimpan"),
+	}
+
+	switch filter.Status {
+	case "", "semua", model.StatusLowonganDibuka, model.StatusLowonganDitutup, model.StatusLowonganKedaluwarsa:
+	default:
+		return filter, "status harus dibuka, ditutup, kedaluwarsa, atau semua"
+	}
+	if value := c.Query("perusahaan_id"); value != "" {
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return filter, "perusahaan_id tidak valid"
+		}
+		filter.PerusahaanID = id
+	}
+	for key, target := range map[string]*int64{"gaji_min": &filter.GajiMin, "gaji_max": &filter.GajiMax} {
+		if value := c.Query(key); value != "" {
+			var err error
+			if *target, err = strconv.ParseInt(value, 10, 64); err != nil || *target < 0 {
+				return filter, key + " harus berupa angka positif"
+			}
+		}
+	}
+	if filter.GajiMax != 0 && filter.GajiMax < filter.GajiMin {
+		return filter, "gaji_max tidak boleh lebih kecil dari gaji_min"
+	}
+	return filter, ""
+}
+
+// GetAllLowonganService daftar lowongan dengan pagination, search, sorting dan filter. Tanpa ?status hanya
+// lowongan yang masih menerima lamaran; ?disimpan=true hanya lowongan yang di-bookmark alumni yang login.
+func GetAllLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	sortBy := c.Query("sortBy", "batas_lamaran")
+	order := c.Query("order", "asc")
+
+	offset := (page - 1) * limit
+
+	filter, msg := parseLowonganFilter(c)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	filter.AlumniID = getAlumniPemirsa(c, db)
+
+	sortByWhitelist := map[string]bool{
+		"id": true, "posisi": true, "nama_perusahaan": true, "batas_lamaran": true,
+		"gaji_min": true, "created_at": true, "updated_at": true,
+	}
+	if !sortByWhitelist[sortBy] {
+		sortBy = "id"
+	}
+	if strings.ToLower(order) != "desc" {
+		order = "asc"
+	}
+
+	lowonganRepo := repository.NewLowonganRepository(db)
+	lowongan, err := lowonganRepo.GetLowonganWithPagination(filter, sortBy, order, limit, offset)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Gagal mengambil lowongan dengan pagination"})
+	}
+
+	total, err := lowonganRepo.CountLowongan(filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Gagal menghitung lowongan dengan pagination"})
+	}
+
+	response := model.LowonganResponse{
+		Data: lowongan,
+		Meta: model.MetaInfo{
+			Page:   page,
+			Limit:  limit,
+			Total:  total,
+			Pages:  (total + limit - 1) / limit,
+			SortBy: sortBy,
+			Order:  order,
+			Search: filter.Search,
+		},
+	}
+
+	return c.JSON(response)
+}
+
+func GetLowonganByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	lowongan, err := repository.NewLowonganRepository(db).GetByID(id, getAlumniPemirsa(c, db))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil lowongan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Lowongan berhasil diambil",
+		"data":    lowongan,
+	})
+}
+
+// validasiLowongan memeriksa request lowongan dan menyeragamkan bidang industri, lokasi, serta gaji.
+// batasHariIni=true mewajibkan batas_lamaran paling cepat hari ini (saat memasang lowongan baru).
+func validasiLowongan(db *sql.DB, req *model.LowonganRequest, batasHariIni bool) ([]model.FieldError, string) {
+	errs := []model.FieldError{}
+	req.Posisi = strings.TrimSpace(req.Posisi)
+	req.NamaPerusahaan = strings.TrimSpace(req.NamaPerusahaan)
+	req.Deskripsi = strings.TrimSpace(req.Deskripsi)
+
+	if msg := applyPerusahaan(db, req.PerusahaanID, &req.NamaPerusahaan, &req.BidangIndustri, &req.Lokasi); msg != "" {
+		return errs, msg
+	}
+	if req.NamaPerusahaan == "" {
+		errs = append(errs, model.FieldError{Field: "perusahaan_id", Kode: "wajib", Pesan: "perusahaan_id atau nama_perusahaan harus diisi"})
+	}
+	if req.Posisi == "" {
+		errs = append(errs, model.FieldError{Field: "posisi", Kode: "wajib", Pesan: "posisi harus diisi"})
+	}
+	if req.BatasLamaran.IsZero() {
+		errs = append(errs, model.FieldError{Field: "batas_lamaran", Kode: "wajib", Pesan: "batas_lamaran harus diisi"})
+	} else if batasHariIni && req.BatasLamaran.Before(model.Today().Time) {
+		errs = append(errs, model.FieldError{Field: "batas_lamaran", Kode: "tidak_valid", Pesan: "batas_lamaran tidak boleh sebelum hari ini"})
+	}
+	if len(errs) > 0 {
+		return errs, ""
+	}
+
+	if msg := resolvePekerjaanReferensi(db, &req.BidangIndustri, &req.Lokasi); msg != "" {
+		return errs, msg
+	}
+	var msg string
+	req.Gaji, req.GajiRange, msg = resolveGaji(req.Gaji, req.GajiRange)
+	return errs, msg
+}
+
+// parseLowonganRequest membaca dan memvalidasi body; mitra selalu memasang untuk perusahaannya sendiri.
+// Respons error sudah dikirim jika ok=false.
+func parseLowonganRequest(c *fiber.Ctx, db *sql.DB, perekrut perekrutLowongan, batasHariIni bool) (model.LowonganRequest, bool, error) {
+	var req model.LowonganRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if !perekrut.admin {
+		req.PerusahaanID = perekrut.perusahaanID
+	}
+
+	errs, msg := validasiLowongan(db, &req, batasHariIni)
+	if len(errs) > 0 {
+		return req, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi lowongan gagal",
+			"errors":  errs,
+		})
+	}
+	if msg != "" {
+		return req, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	return req, true, nil
+}
+
+// CreateLowonganService memasang lowongan baru (admin atau mitra perusahaan)
+func CreateLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	perekrut, ok := getPerekrutLowongan(c, db)
+	if !ok {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Hanya admin atau mitra perusahaan yang dapat memasang lowongan",
+		})
+	}
+
+	req, ok, err := parseLowonganRequest(c, db, perekrut, true)
+	if !ok {
+		return err
+	}
+
+	actorID, _ := c.Locals("user_id").(int)
+	lowongan, err := repository.NewLowonganRepository(db).Create(req, actorID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memasang lowongan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Lowongan berhasil dipasang",
+		"data":    lowongan,
+	})
+}
+
+// UpdateLowonganService mengubah isi lowongan (admin atau mitra pemilik)
+func UpdateLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	perekrut, _ := getPerekrutLowongan(c, db)
+
+	req, ok, err := parseLowonganRequest(c, db, perekrut, false)
+	if !ok {
+		return err
+	}
+
+	lowonganRepo := repository.NewLowonganRepository(db)
+	if current, err := lowonganRepo.GetByID(id, 0); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	lowongan, err := lowonganRepo.Update(id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengupdate lowongan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Lowongan berhasil diupdate",
+		"data":    lowongan,
+	})
+}
+
+// UpdateStatusLowonganService menutup lowongan atau membukanya kembali selama batas lamaran belum lewat
+func UpdateStatusLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.UpdateStatusLowonganRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	if req.Status != model.StatusLowonganDibuka && req.Status != model.StatusLowonganDitutup {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Status lowongan harus dibuka atau ditutup",
+		})
+	}
+
+	lowonganRepo := repository.NewLowonganRepository(db)
+	current, err := lowonganRepo.GetByID(id, 0)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil lowongan",
+			"error":   err.Error(),
+		})
+	}
+	if req.Status == model.StatusLowonganDibuka && current.BatasLamaran.Before(model.Today().Time) {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": "Batas lamaran sudah lewat, perpanjang batas_lamaran lewat PUT /api/lowongan/:id",
+		})
+	}
+	middleware.Audit(c).Sebelum = current
+
+	lowongan, err := lowonganRepo.UpdateStatus(id, req.Status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengubah status lowongan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": fmt.Sprintf("Lowongan berhasil %s", req.Status),
+		"data":    lowongan,
+	})
+}
+
+// DeleteLowonganService menghapus lowongan yang belum pernah dilamar; lowongan dengan lamaran cukup ditutup
+func DeleteLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	lowonganRepo := repository.NewLowonganRepository(db)
+	total, err := lowonganRepo.CountLamaran(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memeriksa lamaran lowongan",
+			"error":   err.Error(),
+		})
+	}
+	if total > 0 {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Lowongan sudah memiliki %d lamaran, tutup lowongan alih-alih menghapusnya", total),
+		})
+	}
+
+	if current, err := lowonganRepo.GetByID(id, 0); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	if err := lowonganRepo.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus lowongan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Lowongan berhasil dihapus",
+	})
+}
+
+// SimpanLowonganService alumni mem-bookmark lowongan
+func SimpanLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	lowonganRepo := repository.NewLowonganRepository(db)
+	if _, err := lowonganRepo.GetByID(id, alumni.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil lowongan",
+			"error":   err.Error(),
+		})
+	}
+	if err := lowonganRepo.Simpan(id, alumni.ID); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan lowongan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Lowongan berhasil disimpan",
+	})
+}
+
+// HapusSimpanLowonganService alumni menghapus bookmark lowongan
+func HapusSimpanLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	if err := repository.NewLowonganRepository(db).HapusSimpan(id, alumni.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan tidak ada di daftar simpanan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus lowongan dari simpanan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Lowongan berhasil dihapus dari simpanan",
+	})
+}
+
+// LamarLowonganService alumni mengirim lamaran ke lowongan yang masih dibuka
+func LamarLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.LamaranRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Request body tidak valid",
+			})
+		}
+	}
+
+	lamaran, err := repository.NewLowonganRepository(db).Lamar(id, alumni.ID, strings.TrimSpace(req.Catatan))
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan tidak ditemukan",
+			})
+		case repository.ErrLowonganDitutup:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Lowongan sudah ditutup atau melewati batas lamaran",
+			})
+		case repository.ErrSudahMelamar:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Anda sudah melamar lowongan ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengirim lamaran",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Lamaran berhasil dikirim",
+		"data":    lamaran,
+	})
+}
+
+// GetLamaranLowonganService daftar pelamar satu lowongan (admin atau mitra pemilik)
+func GetLamaranLowonganService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	lamaran, err := repository.NewLowonganRepository(db).GetLamaranByLowongan(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil lamaran lowongan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Lamaran lowongan berhasil diambil",
+		"data":    lamaran,
+	})
+}
+
+// UpdateStatusLamaranService perekrut memindahkan lamaran ke tahap diproses, wawancara, diterima, atau ditolak
+func UpdateStatusLamaranService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	lamaranID, err := strconv.Atoi(c.Params("lamaran_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID lamaran tidak valid",
+		})
+	}
+
+	var req model.UpdateStatusLamaranRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	if !model.ValidStatusLamaranPerekrut(req.Status) {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Status lamaran harus diproses, wawancara, diterima, atau ditolak",
+		})
+	}
+
+	lowonganRepo := repository.NewLowonganRepository(db)
+	if current, err := lowonganRepo.GetLamaranByID(lamaranID); err == nil && current.LowonganID == id {
+		middleware.Audit(c).Sebelum = current
+	}
+	lamaran, err := lowonganRepo.UpdateStatusLamaran(id, lamaranID, req.Status, strings.TrimSpace(req.Catatan))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lamaran tidak ditemukan",
+			})
+		}
+		if err == repository.ErrLamaranFinal {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Lamaran sudah diterima, ditolak, atau dibatalkan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengubah status lamaran",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Status lamaran berhasil diubah",
+		"data":    lamaran,
+	})
+}
+
+// GetMyLamaranService riwayat lamaran alumni yang login
+func GetMyLamaranService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	lamaran, err := repository.NewLowonganRepository(db).GetLamaranByAlumni(alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil lamaran",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Lamaran berhasil diambil",
+		"data":    lamaran,
+	})
+}
+
+// BatalkanMyLamaranService alumni membatalkan lamarannya sebelum ada keputusan akhir
+func BatalkanMyLamaranService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	lamaran, err := repository.NewLowonganRepository(db).BatalkanLamaran(alumni.ID, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Lamaran tidak ditemukan",
+			})
+		}
+		if err == repository.ErrLamaranFinal {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Lamaran sudah diterima, ditolak, atau dibatalkan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal membatalkan lamaran",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Lamaran berhasil dibatalkan",
+		"data":    lamaran,
+	})
+}
@@ -0,0 +1,556 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Bobot skor saran mentor; total maksimal 100
+const (
+	bobotMentorJurusan     = 30
+	bobotMentorBidang      = 25
+	bobotMentorLokasi      = 15
+	bobotMentorTopik       = 10
+	bobotMentorPerTahun    = 2 // per tahun pengalaman kerja, dibatasi maksSenioritasTahun
+	maksSenioritasTahun    = 10
+	defaultKapasitasMentor = 3
+	maksKapasitasMentor    = 20
+	maksTopikMentor        = 10
+	maksPanjangTopikMentor = 100
+	maksPanjangPesanMentor = 2000
+	defaultLimitSaran      = 10
+	maksLimitSaran         = 50
+)
+
+// ringkasKarier mengisi posisi saat ini, bidang industri, lokasi kerja, dan pengalaman mentor dari pekerjaannya.
+// Pengalaman dihitung seperti timeline alumni sehingga pekerjaan yang beririsan tidak dihitung dua kali.
+func ringkasKarier(m *model.Mentor, pekerjaanList []model.PekerjaanAlumni, hariIni model.Tanggal) {
+	timeline := buildTimeline(model.Alumni{}, pekerjaanList, nil, hariIni)
+	m.PengalamanBulan = timeline.Ringkasan.TotalPengalamanBulan
+	m.PengalamanTahun = timeline.Ringkasan.TotalPengalamanTahun
+	if n := len(timeline.PekerjaanSaatIni); n > 0 {
+		m.PosisiSaatIni = timeline.PekerjaanSaatIni[n-1].PosisiJabatan
+		m.PerusahaanSaatIni = timeline.PekerjaanSaatIni[n-1].NamaPerusahaan
+	}
+	for _, p := range pekerjaanList {
+		m.BidangIndustri = tambahUnik(m.BidangIndustri, p.BidangIndustri)
+		m.LokasiKerja = tambahUnik(m.LokasiKerja, p.LokasiKerja)
+	}
+}
+
+// tambahUnik menambah nilai yang belum ada (tanpa membedakan huruf besar/kecil); nilai kosong diabaikan
+func tambahUnik(list []string, nilai string) []string {
+	nilai = strings.TrimSpace(nilai)
+	if nilai == "" || cocokSalahSatu(list, nilai) != "" {
+		return list
+	}
+	return append(list, nilai)
+}
+
+// cocokSalahSatu nilai pertama di list yang sama dengan target, kosong jika tidak ada
+func cocokSalahSatu(list []string, target string) string {
+	for _, v := range list {
+		if strings.EqualFold(v, target) {
+			return v
+		}
+	}
+	return ""
+}
+
+// skorSaranMentor menghitung kecocokan mentor dengan minat mentee beserta alasannya
+func skorSaranMentor(m model.Mentor, jurusan string, bidang, lokasi []string, topik string) (int, []string) {
+	skor := 0
+	alasan := []string{}
+	if jurusan != "" && strings.EqualFold(m.Jurusan, jurusan) {
+		skor += bobotMentorJurusan
+		alasan = append(alasan, "jurusan_sama")
+	}
+	for _, b := range bidang {
+		if v := cocokSalahSatu(m.BidangIndustri, b); v != "" {
+			skor += bobotMentorBidang
+			alasan = append(alasan, fmt.Sprintf("bidang_industri_sama (%s)", v))
+			break
+		}
+	}
+	for _, l := range lokasi {
+		if v := cocokSalahSatu(m.LokasiKerja, l); v != "" {
+			skor += bobotMentorLokasi
+			alasan = append(alasan, fmt.Sprintf("lokasi_kerja_sama (%s)", v))
+			break
+		}
+	}
+	if topik != "" {
+		for _, t := range m.Topik {
+			if strings.Contains(strings.ToLower(t), strings.ToLower(topik)) {
+				skor += bobotMentorTopik
+				alasan = append(alasan, fmt.Sprintf("topik_cocok (%s)", t))
+				break
+			}
+		}
+	}
+	if m.PengalamanBulan >= 12 {
+		tahun := m.PengalamanBulan / 12
+		if tahun > maksSenioritasTahun {
+			tahun = maksSenioritasTahun
+		}
+		skor += tahun * bobotMentorPerTahun
+		alasan = append(alasan, fmt.Sprintf("senioritas (%.1f tahun pengalaman)", m.PengalamanTahun))
+	}
+	return skor, alasan
+}
+
+// validasiMentor menyeragamkan topik (tanpa duplikat) dan kapasitas profil mentor
+func validasiMentor(req *model.MentorRequest) []model.FieldError {
+	errs := []model.FieldError{}
+	topik := []string{}
+	for _, t := range req.Topik {
+		t = strings.TrimSpace(t)
+		if utf8.RuneCountInString(t) > maksPanjangTopikMentor {
+			errs = append(errs, model.FieldError{Field: "topik", Kode: "terlalu_panjang", Pesan: fmt.Sprintf("topik maksimal %d karakter", maksPanjangTopikMentor)})
+			break
+		}
+		topik = tambahUnik(topik, t)
+	}
+	req.Topik = topik
+	req.Bio = strings.TrimSpace(req.Bio)
+
+	if len(req.Topik) == 0 {
+		errs = append(errs, model.FieldError{Field: "topik", Kode: "wajib", Pesan: "topik harus diisi minimal satu"})
+	} else if len(req.Topik) > maksTopikMentor {
+		errs = append(errs, model.FieldError{Field: "topik", Kode: "terlalu_banyak", Pesan: fmt.Sprintf("topik maksimal %d", maksTopikMentor)})
+	}
+	if utf8.RuneCountInString(req.Bio) > maksPanjangPesanMentor {
+		errs = append(errs, model.FieldError{Field: "bio", Kode: "terlalu_panjang", Pesan: fmt.Sprintf("bio maksimal %d karakter", maksPanjangPesanMentor)})
+	}
+	if req.Kapasitas == 0 {
+		req.Kapasitas = defaultKapasitasMentor
+	}
+	if req.Kapasitas < 1 || req.Kapasitas > maksKapasitasMentor {
+		errs = append(errs, model.FieldError{Field: "kapasitas", Kode: "tidak_valid", Pesan: fmt.Sprintf("kapasitas harus antara 1 dan %d", maksKapasitasMentor)})
+	}
+	return errs
+}
+
+// GetMyMentorService profil mentor alumni yang login beserta ringkasan kariernya
+func GetMyMentorService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	mentorshipRepo := repository.NewMentorshipRepository(db)
+	mentor, err := mentorshipRepo.GetMentorByAlumni(alumni.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Anda belum terdaftar sebagai mentor",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil profil mentor",
+			"error":   err.Error(),
+		})
+	}
+	pekerjaan, err := mentorshipRepo.GetPekerjaanByAlumniIDs([]int{alumni.ID})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+	ringkasKarier(&mentor, pekerjaan[alumni.ID], model.Today())
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Profil mentor berhasil diambil",
+		"data":    mentor,
+	})
+}
+
+// SimpanMyMentorService mendaftar sebagai mentor atau mengubah profil mentor; aktif=false berhenti menerima
+// permintaan baru tanpa memutus mentoring yang berjalan
+func SimpanMyMentorService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	var req model.MentorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	if errs := validasiMentor(&req); len(errs) > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi profil mentor gagal",
+			"errors":  errs,
+		})
+	}
+
+	mentorshipRepo := repository.NewMentorshipRepository(db)
+	if current, err := mentorshipRepo.GetMentorByAlumni(alumni.ID); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	mentor, err := mentorshipRepo.SimpanMentor(alumni.ID, req)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan profil mentor",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Profil mentor berhasil disimpan",
+		"data":    mentor,
+	})
+}
+
+// GetSaranMentorService menyarankan mentor untuk alumni yang login. Kecocokan dinilai dari jurusan, bidang
+// industri dan lokasi kerja (dari riwayat pekerjaan mentee atau query bidang_industri/lokasi), topik, dan
+// senioritas pengalaman kerja mentor. Hanya mentor yang lulus lebih dulu dan masih punya kapasitas.
+func GetSaranMentorService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	filter := model.SaranMentorFilter{
+		Topik:          strings.TrimSpace(c.Query("topik")),
+		BidangIndustri: strings.TrimSpace(c.Query("bidang_industri")),
+		Lokasi:         strings.TrimSpace(c.Query("lokasi")),
+		Limit:          c.QueryInt("limit", defaultLimitSaran),
+	}
+	if filter.Limit < 1 || filter.Limit > maksLimitSaran {
+		filter.Limit = defaultLimitSaran
+	}
+	if msg := resolvePekerjaanReferensi(db, &filter.BidangIndustri, &filter.Lokasi); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	mentorshipRepo := repository.NewMentorshipRepository(db)
+	kandidat, err := mentorshipRepo.GetKandidatMentor(alumni)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil kandidat mentor",
+			"error":   err.Error(),
+		})
+	}
+	ids := []int{alumni.ID}
+	for _, m := range kandidat {
+		ids = append(ids, m.AlumniID)
+	}
+	pekerjaan, err := mentorshipRepo.GetPekerjaanByAlumniIDs(ids)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+
+	// minat mentee: nilai query menggantikan riwayat pekerjaannya sendiri
+	bidang, lokasi := []string{}, []string{}
+	for _, p := range pekerjaan[alumni.ID] {
+		bidang = tambahUnik(bidang, p.BidangIndustri)
+		lokasi = tambahUnik(lokasi, p.LokasiKerja)
+	}
+	if filter.BidangIndustri != "" {
+		bidang = []string{filter.BidangIndustri}
+	}
+	if filter.Lokasi != "" {
+		lokasi = []string{filter.Lokasi}
+	}
+
+	hariIni := model.Today()
+	saran := []model.SaranMentor{}
+	for _, m := range kandidat {
+		ringkasKarier(&m, pekerjaan[m.AlumniID], hariIni)
+		skor, alasan := skorSaranMentor(m, alumni.Jurusan, bidang, lokasi, filter.Topik)
+		if skor == 0 {
+			continue
+		}
+		saran = append(saran, model.SaranMentor{Mentor: m, Skor: skor, Alasan: alasan})
+	}
+	sort.SliceStable(saran, func(i, j int) bool {
+		if saran[i].Skor != saran[j].Skor {
+			return saran[i].Skor > saran[j].Skor
+		}
+		return saran[i].PengalamanBulan > saran[j].PengalamanBulan
+	})
+	if len(saran) > filter.Limit {
+		saran = saran[:filter.Limit]
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Saran mentor berhasil diambil",
+		"data":    saran,
+	})
+}
+
+// AjukanPermintaanMentorService mentee meminta mentoring ke mentor. Kontak kedua pihak belum dibuka sampai
+// mentor menerima permintaan.
+func AjukanPermintaanMentorService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	var req model.PermintaanMentorRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+	}
+	req.Topik = strings.TrimSpace(req.Topik)
+	req.Pesan = strings.TrimSpace(req.Pesan)
+	errs := []model.FieldError{}
+	if req.MentorID <= 0 {
+		errs = append(errs, model.FieldError{Field: "mentor_id", Kode: "wajib", Pesan: "mentor_id harus diisi"})
+	}
+	if utf8.RuneCountInString(req.Topik) > maksPanjangTopikMentor {
+		errs = append(errs, model.FieldError{Field: "topik", Kode: "terlalu_panjang", Pesan: fmt.Sprintf("topik maksimal %d karakter", maksPanjangTopikMentor)})
+	}
+	if utf8.RuneCountInString(req.Pesan) > maksPanjangPesanMentor {
+		errs = append(errs, model.FieldError{Field: "pesan", Kode: "terlalu_panjang", Pesan: fmt.Sprintf("pesan maksimal %d karakter", maksPanjangPesanMentor)})
+	}
+	if len(errs) > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi permintaan mentoring gagal",
+			"errors":  errs,
+		})
+	}
+
+	permintaan, err := repository.NewMentorshipRepository(db).Ajukan(alumni.ID, req)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Mentor tidak ditemukan",
+			})
+		case repository.ErrMentorDiriSendiri:
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Tidak dapat meminta mentoring ke diri sendiri",
+			})
+		case repository.ErrMentorTidakAktif:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Mentor sedang tidak menerima permintaan baru",
+			})
+		case repository.ErrMentorPenuh:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Kapasitas mentor sudah penuh",
+			})
+		case repository.ErrSudahMemintaMentor:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Anda masih memiliki permintaan aktif ke mentor ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengirim permintaan mentoring",
+			"error":   err.Error(),
+		})
+	}
+	permintaan.Peran = model.PeranMentee
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Permintaan mentoring berhasil dikirim",
+		"data":    permintaan,
+	})
+}
+
+// GetMyPermintaanMentorService permintaan mentoring alumni yang login sebagai mentor dan/atau mentee
+func GetMyPermintaanMentorService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	peran := c.Query("peran")
+	if peran != "" && peran != model.PeranMentor && peran != model.PeranMentee {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "peran harus mentor atau mentee",
+		})
+	}
+	status := c.Query("status")
+	switch status {
+	case "", model.StatusMentoringDiajukan, model.StatusMentoringDiterima, model.StatusMentoringDitolak,
+		model.StatusMentoringDibatalkan, model.StatusMentoringSelesai:
+	default:
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "status harus diajukan, diterima, ditolak, dibatalkan, atau selesai",
+		})
+	}
+
+	permintaan, err := repository.NewMentorshipRepository(db).GetPermintaanByAlumni(alumni.ID, peran, status)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil permintaan mentoring",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Permintaan mentoring berhasil diambil",
+		"data":    permintaan,
+	})
+}
+
+// ubahPermintaanMentor menjalankan aksi mentor/mentee pada satu permintaan dan memetakan error-nya ke respons
+func ubahPermintaanMentor(c *fiber.Ctx, db *sql.DB, berhasil string,
+	aksi func(repo repository.MentorshipRepository, alumniID, id int, balasan string) (model.PermintaanMentor, error)) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.ResponsPermintaanMentorRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Request body tidak valid",
+			})
+		}
+	}
+	req.Balasan = strings.TrimSpace(req.Balasan)
+	if utf8.RuneCountInString(req.Balasan) > maksPanjangPesanMentor {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("balasan maksimal %d karakter", maksPanjangPesanMentor),
+		})
+	}
+
+	mentorshipRepo := repository.NewMentorshipRepository(db)
+	if current, err := mentorshipRepo.GetPermintaanByID(id); err == nil &&
+		(current.AlumniID == alumni.ID || current.MentorAlumniID == alumni.ID) {
+		middleware.Audit(c).Sebelum = current
+	}
+	permintaan, err := aksi(mentorshipRepo, alumni.ID, id, req.Balasan)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Permintaan mentoring tidak ditemukan",
+			})
+		case repository.ErrStatusPermintaanMentor:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Status permintaan mentoring tidak memungkinkan aksi ini",
+			})
+		case repository.ErrMentorPenuh:
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Kapasitas mentor sudah penuh, selesaikan mentoring lain atau naikkan kapasitas",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengubah permintaan mentoring",
+			"error":   err.Error(),
+		})
+	}
+	permintaan.Peran = model.PeranMentee
+	if permintaan.MentorAlumniID == alumni.ID {
+		permintaan.Peran = model.PeranMentor
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": berhasil,
+		"data":    permintaan,
+	})
+}
+
+// TerimaPermintaanMentorService mentor menerima permintaan; kontak kedua pihak dibuka satu sama lain
+func TerimaPermintaanMentorService(c *fiber.Ctx, db *sql.DB) error {
+	return ubahPermintaanMentor(c, db, "Permintaan mentoring diterima",
+		func(repo repository.MentorshipRepository, alumniID, id int, balasan string) (model.PermintaanMentor, error) {
+			return repo.Terima(alumniID, id, balasan)
+		})
+}
+
+// TolakPermintaanMentorService mentor menolak permintaan yang belum dijawab
+func TolakPermintaanMentorService(c *fiber.Ctx, db *sql.DB) error {
+	return ubahPermintaanMentor(c, db, "Permintaan mentoring ditolak",
+		func(repo repository.MentorshipRepository, alumniID, id int, balasan string) (model.PermintaanMentor, error) {
+			return repo.Tolak(alumniID, id, balasan)
+		})
+}
+
+// BatalkanPermintaanMentorService mentee menarik permintaan yang belum dijawab mentor
+func BatalkanPermintaanMentorService(c *fiber.Ctx, db *sql.DB) error {
+	return ubahPermintaanMentor(c, db, "Permintaan mentoring dibatalkan",
+		func(repo repository.MentorshipRepository, alumniID, id int, _ string) (model.PermintaanMentor, error) {
+			return repo.Batalkan(alumniID, id)
+		})
+}
+
+// SelesaikanPermintaanMentorService mentor atau mentee mengakhiri mentoring yang berjalan
+func SelesaikanPermintaanMentorService(c *fiber.Ctx, db *sql.DB) error {
+	return ubahPermintaanMentor(c, db, "Mentoring diselesaikan",
+		func(repo repository.MentorshipRepository, alumniID, id int, _ string) (model.PermintaanMentor, error) {
+			return repo.Selesaikan(alumniID, id)
+		})
+}
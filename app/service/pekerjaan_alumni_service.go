@@ -3,26 +3,68 @@ package service
 import (
 	"hello-fiber/app/model"
 	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
 	"database/sql"
 	"strconv"
 	"strings"
 	"github.com/gofiber/fiber/v2"
 )
 
+// resolvePekerjaanScope membaca query scope (active, trashed, all). Selain active hanya untuk admin.
+// Jika scope ditolak, response error sudah dikirim dan scope yang dikembalikan kosong.
+func resolvePekerjaanScope(c *fiber.Ctx) (string, error) {
+	scope := c.Query("scope", model.ScopeActive)
+	validScopes := map[string]bool{
+		model.ScopeActive: true, model.ScopeTrashed: true, model.ScopeAll: true,
+	}
+	if !validScopes[scope] {
+		return "", c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Scope tidak valid (active, trashed, all) by service",
+		})
+	}
+	if roleID, _ := c.Locals("role_id").(int); scope != model.ScopeActive && roleID != 1 {
+		return "", c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Scope trashed dan all hanya untuk admin by service",
+		})
+	}
+	return scope, nil
+}
+
 // GetAllPekerjaanAlumniWithPaginationService untuk mengambil data pekerjaan alumni dengan pagination, search, dan sorting
 func GetAllPekerjaanAlumniWithPaginationService(c *fiber.Ctx, db *sql.DB) error {
+	scope, err := resolvePekerjaanScope(c)
+	if scope == "" {
+		return err
+	}
+	return paginatePekerjaanAlumni(c, db, scope, "id", "asc")
+}
+
+func paginatePekerjaanAlumni(c *fiber.Ctx, db *sql.DB, scope, defaultSortBy, defaultOrder string) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
-	sortBy := c.Query("sortBy", "id")
-	order := c.Query("order", "asc")
+	sortBy := c.Query("sortBy", defaultSortBy)
+	order := c.Query("order", defaultOrder)
 	search := c.Query("search", "")
 
 	offset := (page - 1) * limit
 
+	filter, msg := parsePekerjaanFilter(c)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	filter.Scope = scope
+	filter.Search = search
+
 	// Validasi input
 	sortByWhitelist := map[string]bool{
 		"id": true, "nama_perusahaan": true, "posisi_jabatan": true, "bidang_industri": true,
 		"lokasi_kerja": true, "tanggal_mulai_kerja": true, "status_pekerjaan": true, "created_at": true, "is_delete": true,
+		"trashed_at": true, "updated_at": true, "gaji_min": true, "tanggal_selesai_kerja": true,
 	}
 	if !sortByWhitelist[sortBy] {
 		sortBy = "id"
@@ -33,12 +75,12 @@ func GetAllPekerjaanAlumniWithPaginationService(c *fiber.Ctx, db *sql.DB) error
 
 	// Ambil data dari repository
 	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	pekerjaan, err := pekerjaanRepo.GetPekerjaanAlumniWithPagination(search, sortBy, order, limit, offset)
+	pekerjaan, err := pekerjaanRepo.GetPekerjaanAlumniWithPagination(filter, sortBy, order, limit, offset)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Gagal di ambil pekerjaan alumni dengan pagination by service"}) // Ganti pesan error sesuai kebutuhan
 	}
 
-	total, err := pekerjaanRepo.CountPekerjaanAlumni(search)
+	total, err := pekerjaanRepo.CountPekerjaanAlumni(filter)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Gagal menghitung pekerjaan alumni dengan pagination by service"}) // Ganti pesan error sesuai kebutuhan
 	}
@@ -54,12 +96,64 @@ func GetAllPekerjaanAlumniWithPaginationService(c *fiber.Ctx, db *sql.DB) error
 			SortBy: sortBy,
 			Order:  order,
 			Search: search,
+			Scope:  scope,
 		},
 	}
 
 	return c.JSON(response)
 }
 
+// parsePekerjaanFilter membaca filter rentang tanggal_mulai_dari, tanggal_mulai_sampai (YYYY-MM-DD),
+// gaji_min, gaji_max dan mata_uang dari query. Mengembalikan pesan error jika ada nilai yang tidak valid.
+func parsePekerjaanFilter(c *fiber.Ctx) (model.PekerjaanFilter, string) {
+	var filter model.PekerjaanFilter
+	var err error
+
+	if filter.MulaiDari, err = model.ParseTanggal(c.Query("tanggal_mulai_dari")); err != nil {
+		return filter, "tanggal_mulai_dari: " + err.Error()
+	}
+	if filter.MulaiSampai, err = model.ParseTanggal(c.Query("tanggal_mulai_sampai")); err != nil {
+		return filter, "tanggal_mulai_sampai: " + err.Error()
+	}
+	if !filter.MulaiDari.IsZero() && !filter.MulaiSampai.IsZero() && filter.MulaiSampai.Before(filter.MulaiDari.Time) {
+		return filter, "tanggal_mulai_sampai tidak boleh sebelum tanggal_mulai_dari"
+	}
+
+	for key, target := range map[string]*int64{"gaji_min": &filter.GajiMin, "gaji_max": &filter.GajiMax} {
+		if value := c.Query(key); value != "" {
+			if *target, err = strconv.ParseInt(value, 10, 64); err != nil || *target < 0 {
+				return filter, key + " harus berupa angka positif"
+			}
+		}
+	}
+	if filter.GajiMax != 0 && filter.GajiMax < filter.GajiMin {
+		return filter, "gaji_max tidak boleh lebih kecil dari gaji_min"
+	}
+	filter.MataUang = strings.ToUpper(c.Query("mata_uang"))
+	return filter, ""
+}
+
+// resolveGaji menentukan gaji terstruktur dari field gaji atau, jika kosong, dari teks gaji_range lama.
+// Mengembalikan gaji, label kanonisnya untuk kolom gaji_range, dan pesan error (kosong jika valid).
+func resolveGaji(gaji *model.GajiRange, gajiRange string) (*model.GajiRange, string, string) {
+	if gaji == nil {
+		if strings.TrimSpace(gajiRange) == "" {
+			return nil, "", ""
+		}
+		parsed, err := model.ParseGajiRange(gajiRange)
+		if err != nil {
+			return nil, "", "Gaji range tidak valid: " + err.Error()
+		}
+		gaji = &parsed
+	}
+
+	gaji.Normalize()
+	if err := gaji.Validate(); err != nil {
+		return nil, "", "Gaji tidak valid: " + err.Error()
+	}
+	return gaji, gaji.String(), ""
+}
+
 // GetAllPekerjaanAlumniService untuk mengambil semua data pekerjaan alumni
 func GetAllPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 	return GetAllPekerjaanAlumniWithPaginationService(c, db)
@@ -75,8 +169,13 @@ func GetPekerjaanAlumniByIDService(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
+	scope, err := resolvePekerjaanScope(c)
+	if scope == "" {
+		return err
+	}
+
 	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	pekerjaan, err := pekerjaanRepo.GetByID(id)
+	pekerjaan, err := pekerjaanRepo.GetByID(id, scope)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
@@ -131,21 +230,9 @@ func GetPekerjaanAlumniTrashByIDService(c *fiber.Ctx, db *sql.DB) error {
 	})
 }
 
+// GetTrashedPekerjaanAlumniService untuk mengambil trash pekerjaan alumni lengkap dengan search, sorting, dan pagination (admin only)
 func GetTrashedPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
-	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	data, err := pekerjaanRepo.GetTrashed()
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"success": false,
-			"message": "Gagal mengambil data trash pekerjaan alumni by service",
-			"error":   err.Error(),
-		})
-	}
-	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Berhasil mengambil data trash pekerjaan alumni by service",
-		"data":    data,
-	})
+	return paginatePekerjaanAlumni(c, db, model.ScopeTrashed, "trashed_at", "desc")
 }
 
 // GetPekerjaanAlumniByAlumniIDService untuk mengambil semua pekerjaan berdasarkan alumni ID
@@ -158,8 +245,13 @@ func GetPekerjaanAlumniByAlumniIDService(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
+	scope, err := resolvePekerjaanScope(c)
+	if scope == "" {
+		return err
+	}
+
 	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	pekerjaanList, err := pekerjaanRepo.GetByAlumniID(alumniID)
+	pekerjaanList, err := pekerjaanRepo.GetByAlumniID(alumniID, scope)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
@@ -182,15 +274,33 @@ func CreatePekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 		return c.Status(400).JSON(fiber.Map{
 			"success": false,
 			"message": "Request body tidak valid by service",
+			"error":   err.Error(),
+		})
+	}
+
+	if msg := applyPerusahaan(db, req.PerusahaanID, &req.NamaPerusahaan, &req.BidangIndustri, &req.LokasiKerja); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	if msg := resolvePekerjaanReferensi(db, &req.BidangIndustri, &req.LokasiKerja); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
 		})
 	}
 
 	// Validasi input
-	if req.AlumniID == 0 || req.NamaPerusahaan == "" || req.PosisiJabatan == "" || 
-	   req.BidangIndustri == "" || req.LokasiKerja == "" || req.TanggalMulaiKerja == "" {
+	errs := missingPekerjaanFields(req.NamaPerusahaan, req.PosisiJabatan, req.BidangIndustri, req.LokasiKerja, req.TanggalMulaiKerja)
+	if req.AlumniID == 0 {
+		errs = append([]model.FieldError{{Field: "alumni_id", Kode: "wajib", Pesan: "alumni_id harus diisi"}}, errs...)
+	}
+	if len(errs) > 0 {
 		return c.Status(400).JSON(fiber.Map{
 			"success": false,
 			"message": "Alumni ID, nama perusahaan, posisi jabatan, bidang industri, lokasi kerja, dan tanggal mulai kerja harus diisi by service",
+			"errors":  errs,
 		})
 	}
 
@@ -203,8 +313,55 @@ func CreatePekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 	// 	req.IsDelete = "tidak"
 	// }
 
-	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
+	var msg string
+	req.StatusPekerjaan, req.TanggalSelesaiKerja, msg = resolveStatusTransition(
+		"", req.StatusPekerjaan, req.TanggalMulaiKerja, req.TanggalSelesaiKerja,
+	)
+	if msg == "" {
+		req.Gaji, req.GajiRange, msg = resolveGaji(req.Gaji, req.GajiRange)
+	}
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	warnings, ok, err := checkPekerjaanKonsistensi(c, db, pekerjaanKonsistensi{
+		AlumniID:            req.AlumniID,
+		StatusPekerjaan:     req.StatusPekerjaan,
+		TanggalMulaiKerja:   req.TanggalMulaiKerja,
+		TanggalSelesaiKerja: req.TanggalSelesaiKerja,
+	})
+	if !ok {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memulai transaksi pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+	defer tx.Rollback()
+
+	actorID, _ := c.Locals("user_id").(int)
+	pekerjaanRepo := repository.NewPekerjaanAlumniRepositoryTx(tx)
 	pekerjaan, err := pekerjaanRepo.Create(req)
+	if err == nil {
+		err = pekerjaanRepo.AddStatusHistory(model.StatusPekerjaanHistory{
+			PekerjaanID: pekerjaan.ID,
+			StatusBaru:  pekerjaan.StatusPekerjaan,
+			DiubahOleh:  actorID,
+		})
+	}
+	if err == nil {
+		_, err = repository.NewRevisiRepositoryTx(tx).Record(model.RevisiEntitasPekerjaan, pekerjaan.ID, nil, pekerjaan, model.RevisiAksiBuat, 0, actorID)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"success": false,
@@ -214,9 +371,10 @@ func CreatePekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 	}
 
 	return c.Status(201).JSON(fiber.Map{
-		"success": true,
-		"message": "Pekerjaan alumni berhasil ditambahkan by service",
-		"data":    pekerjaan,
+		"success":  true,
+		"message":  "Pekerjaan alumni berhasil ditambahkan by service",
+		"data":     pekerjaan,
+		"warnings": warnings,
 	})
 }
 
@@ -234,20 +392,91 @@ func UpdatePekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 		return c.Status(400).JSON(fiber.Map{
 			"success": false,
 			"message": "Request body tidak valid by service",
+			"error":   err.Error(),
+		})
+	}
+
+	if msg := applyPerusahaan(db, req.PerusahaanID, &req.NamaPerusahaan, &req.BidangIndustri, &req.LokasiKerja); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	if msg := resolvePekerjaanReferensi(db, &req.BidangIndustri, &req.LokasiKerja); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
 		})
 	}
 
 	// Validasi input
-	if req.NamaPerusahaan == "" || req.PosisiJabatan == "" || 
-	   req.BidangIndustri == "" || req.LokasiKerja == "" || req.TanggalMulaiKerja == "" {
+	if errs := missingPekerjaanFields(req.NamaPerusahaan, req.PosisiJabatan, req.BidangIndustri, req.LokasiKerja, req.TanggalMulaiKerja); len(errs) > 0 {
 		return c.Status(400).JSON(fiber.Map{
 			"success": false,
 			"message": "Nama perusahaan, posisi jabatan, bidang industri, lokasi kerja, dan tanggal mulai kerja harus diisi by service",
+			"errors":  errs,
 		})
 	}
 
-	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	pekerjaan, err := pekerjaanRepo.Update(id, req)
+	current, err := repository.NewPekerjaanAlumniRepository(db).GetByID(id, model.ScopeActive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan alumni tidak ditemukan by service",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+
+	// Status kosong berarti status tidak diubah
+	if req.StatusPekerjaan == "" {
+		req.StatusPekerjaan = current.StatusPekerjaan
+	}
+	var msg string
+	req.StatusPekerjaan, req.TanggalSelesaiKerja, msg = resolveStatusTransition(
+		current.StatusPekerjaan, req.StatusPekerjaan, req.TanggalMulaiKerja, req.TanggalSelesaiKerja,
+	)
+	if msg == "" {
+		req.Gaji, req.GajiRange, msg = resolveGaji(req.Gaji, req.GajiRange)
+	}
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	warnings, ok, err := checkPekerjaanKonsistensi(c, db, pekerjaanKonsistensi{
+		AlumniID:            current.AlumniID,
+		PekerjaanID:         id,
+		StatusPekerjaan:     req.StatusPekerjaan,
+		TanggalMulaiKerja:   req.TanggalMulaiKerja,
+		TanggalSelesaiKerja: req.TanggalSelesaiKerja,
+	})
+	if !ok {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memulai transaksi pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+	defer tx.Rollback()
+
+	actorID, _ := c.Locals("user_id").(int)
+	middleware.Audit(c).Sebelum = current
+	pekerjaan, err := updatePekerjaanWithRevisi(tx, current, req, model.RevisiAksiUbah, 0, actorID)
+	if err == nil {
+		err = tx.Commit()
+	}
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
@@ -263,9 +492,10 @@ func UpdatePekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"success": true,
-		"message": "Pekerjaan alumni berhasil diupdate by service",
-		"data":    pekerjaan,
+		"success":  true,
+		"message":  "Pekerjaan alumni berhasil diupdate by service",
+		"data":     pekerjaan,
+		"warnings": warnings,
 	})
 }
 
@@ -294,9 +524,16 @@ func UpdatePekerjaanAlumniSementara(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
+	actorID, _ := c.Locals("user_id").(int)
 	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	trash, err := pekerjaanRepo.UpdateUser(id, req)
+	trash, err := pekerjaanRepo.UpdateUser(id, req, actorID)
 	if err != nil {
+		if err == repository.ErrPekerjaanViaAlumni {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan dihapus bersama alumninya, pulihkan atau hapus lewat alumni by service",
+			})
+		}
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
 				"success": false,
@@ -342,9 +579,16 @@ func UpdatePekerjaanAlumniAdmin(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
+	actorID, _ := c.Locals("user_id").(int)
 	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	trash, err := pekerjaanRepo.UpdateAdmin(id, req)
+	trash, err := pekerjaanRepo.UpdateAdmin(id, req, actorID)
 	if err != nil {
+		if err == repository.ErrPekerjaanViaAlumni {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan dihapus bersama alumninya, pulihkan atau hapus lewat alumni by service",
+			})
+		}
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
 				"success": false,
@@ -374,8 +618,12 @@ func DeletePekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
+	actorID, _ := c.Locals("user_id").(int)
 	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	err = pekerjaanRepo.Delete(id)
+	if current, err := pekerjaanRepo.GetByID(id, model.ScopeActive); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	err = pekerjaanRepo.Delete(id, actorID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
@@ -407,7 +655,17 @@ func HardDeleteTrashedPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 	}
 
 	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
-	if err := pekerjaanRepo.HardDeleteIfTrashed(id); err != nil {
+	if trash, err := pekerjaanRepo.GetTrashByID(id); err == nil {
+		middleware.Audit(c).Sebelum = trash
+	}
+	berkas, err := pekerjaanRepo.HardDeleteIfTrashed(id)
+	if err != nil {
+		if err == repository.ErrPekerjaanViaAlumni {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan dihapus bersama alumninya, pulihkan atau hapus lewat alumni by service",
+			})
+		}
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
 				"success": false,
@@ -421,6 +679,8 @@ func HardDeleteTrashedPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 		})
 	}
 
+	hapusObjectBerkasTerhapus(c.UserContext(), db, berkas)
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"message": "Berhasil menghapus permanen pekerjaan alumni by service",
@@ -439,6 +699,12 @@ func RestoreTrashedPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
 	item, err := pekerjaanRepo.RestoreIfTrashed(id)
 	if err != nil {
+		if err == repository.ErrPekerjaanViaAlumni {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan dihapus bersama alumninya, pulihkan atau hapus lewat alumni by service",
+			})
+		}
 		if err == sql.ErrNoRows {
 			return c.Status(404).JSON(fiber.Map{
 				"success": false,
@@ -457,3 +723,113 @@ func RestoreTrashedPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
 		"data":    item,
 	})
 }
+
+// BulkRestoreTrashedPekerjaanAlumniService untuk me-restore banyak trashed sekaligus berdasarkan ids atau filter (admin only)
+func BulkRestoreTrashedPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	return runBulkTrashService(c, db, false, func(repo repository.PekerjaanAlumniRepository, id int) ([]model.Berkas, error) {
+		_, err := repo.RestoreIfTrashed(id)
+		return nil, err
+	})
+}
+
+// BulkPurgeTrashedPekerjaanAlumniService untuk menghapus permanen banyak trashed sekaligus berdasarkan ids atau filter (admin only)
+func BulkPurgeTrashedPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	return runBulkTrashService(c, db, false, func(repo repository.PekerjaanAlumniRepository, id int) ([]model.Berkas, error) {
+		return repo.HardDeleteIfTrashed(id)
+	})
+}
+
+// EmptyTrashPekerjaanAlumniService untuk mengosongkan seluruh trash pekerjaan alumni (admin only)
+func EmptyTrashPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	return runBulkTrashService(c, db, true, func(repo repository.PekerjaanAlumniRepository, id int) ([]model.Berkas, error) {
+		return repo.HardDeleteIfTrashed(id)
+	})
+}
+
+// runBulkTrashService menjalankan operasi trash untuk setiap id dalam satu transaksi dan
+// mengembalikan laporan per id. Kesalahan database membatalkan seluruh transaksi. Object berkas milik
+// pekerjaan yang terhapus permanen baru dihapus dari storage setelah commit.
+func runBulkTrashService(c *fiber.Ctx, db *sql.DB, all bool, operation func(repository.PekerjaanAlumniRepository, int) ([]model.Berkas, error)) error {
+	var req model.BulkTrashRequest
+	if !all {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Request body tidak valid by service",
+			})
+		}
+		if len(req.IDs) == 0 && req.AlumniID == 0 && req.TrashedFrom == "" && req.TrashedTo == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Isi ids atau minimal satu filter (alumni_id, trashed_from, trashed_to) by service",
+			})
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memulai transaksi trash pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+	defer tx.Rollback()
+
+	pekerjaanRepo := repository.NewPekerjaanAlumniRepositoryTx(tx)
+	ids := req.IDs
+	if len(ids) == 0 {
+		ids, err = pekerjaanRepo.GetTrashedIDs(req.TrashFilter)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Gagal mengambil data trash pekerjaan alumni by service",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	report := model.BulkTrashResponse{Results: []model.BulkTrashResult{}}
+	berkasTerhapus := []model.Berkas{}
+	for _, id := range ids {
+		result := model.BulkTrashResult{ID: id, Success: true, Message: "Berhasil"}
+		berkas, err := operation(pekerjaanRepo, id)
+		if err == repository.ErrPekerjaanViaAlumni {
+			result.Success = false
+			result.Message = "Dilewati: pekerjaan dihapus bersama alumninya"
+			report.Skipped++
+		} else if err != nil {
+			if err != sql.ErrNoRows {
+				return c.Status(500).JSON(fiber.Map{
+					"success": false,
+					"message": "Gagal memproses trash pekerjaan alumni, seluruh perubahan dibatalkan by service",
+					"error":   err.Error(),
+					"id":      id,
+				})
+			}
+			result.Success = false
+			result.Message = "Data tidak ditemukan atau tidak berstatus hapus"
+			report.Failed++
+		} else {
+			report.Succeeded++
+			berkasTerhapus = append(berkasTerhapus, berkas...)
+		}
+		report.Results = append(report.Results, result)
+	}
+	report.Processed = len(ids)
+
+	if err := tx.Commit(); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan perubahan trash pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+	hapusObjectBerkasTerhapus(c.UserContext(), db, berkasTerhapus)
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Berhasil memproses trash pekerjaan alumni by service",
+		"data":    report,
+	})
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maksTanggalKedepan batas tanggal pekerjaan di masa depan, cukup untuk pekerjaan yang sudah diterima tapi belum dimulai
+const maksTanggalKedepan = 1 // tahun
+
+// resolveStatusTransition memvalidasi perpindahan status_pekerjaan dari -> ke sesuai
+// model.StatusPekerjaanTransitions dan menyesuaikan tanggal selesai kerja:
+// status berakhir (selesai, kontrak-berakhir) wajib punya tanggal selesai (default hari ini),
+// status berjalan (aktif, cuti) tidak punya tanggal selesai.
+// Status lama yang tidak dikenali (data lama atau pekerjaan baru) boleh pindah ke status mana pun.
+// Mengembalikan status ternormalisasi, tanggal selesai, dan pesan error (kosong jika valid).
+func resolveStatusTransition(from, to string, tanggalMulai, tanggalSelesai model.Tanggal) (string, model.Tanggal, string) {
+	from = strings.ToLower(strings.TrimSpace(from))
+	to = strings.ToLower(strings.TrimSpace(to))
+
+	if _, ok := model.StatusPekerjaanTransitions[to]; !ok {
+		return "", model.Tanggal{}, "Status pekerjaan tidak valid (aktif, selesai, cuti, kontrak-berakhir)"
+	}
+	if allowed, known := model.StatusPekerjaanTransitions[from]; known && from != to && !containsString(allowed, to) {
+		return "", model.Tanggal{}, fmt.Sprintf("Transisi status pekerjaan dari %s ke %s tidak diizinkan", from, to)
+	}
+
+	switch to {
+	case model.StatusPekerjaanSelesai, model.StatusPekerjaanKontrakBerakhir:
+		if tanggalSelesai.IsZero() {
+			tanggalSelesai = model.Today()
+		}
+	default:
+		tanggalSelesai = model.Tanggal{}
+	}
+	if msg := validateTanggalPekerjaan(tanggalMulai, tanggalSelesai); msg != "" {
+		return "", model.Tanggal{}, msg
+	}
+	return to, tanggalSelesai, ""
+}
+
+// validateTanggalPekerjaan memastikan tanggal selesai tidak sebelum tanggal mulai
+// dan keduanya tidak lebih dari maksTanggalKedepan tahun dari hari ini
+func validateTanggalPekerjaan(tanggalMulai, tanggalSelesai model.Tanggal) string {
+	batas := model.NewTanggal(time.Now().AddDate(maksTanggalKedepan, 0, 0))
+	switch {
+	case tanggalMulai.After(batas.Time):
+		return fmt.Sprintf("Tanggal mulai kerja tidak boleh lebih dari %d tahun ke depan", maksTanggalKedepan)
+	case tanggalSelesai.After(batas.Time):
+		return fmt.Sprintf("Tanggal selesai kerja tidak boleh lebih dari %d tahun ke depan", maksTanggalKedepan)
+	case !tanggalSelesai.IsZero() && tanggalSelesai.Before(tanggalMulai.Time):
+		return "Tanggal selesai kerja tidak boleh sebelum tanggal mulai kerja"
+	}
+	return ""
+}
+
+// UpdateStatusPekerjaanAlumniService untuk memindahkan status pekerjaan sesuai transisi yang diizinkan (admin only)
+func UpdateStatusPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid by service",
+		})
+	}
+
+	var req model.UpdateStatusPekerjaanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid by service",
+			"error":   err.Error(),
+		})
+	}
+
+	current, err := repository.NewPekerjaanAlumniRepository(db).GetByID(id, model.ScopeActive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan alumni tidak ditemukan by service",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+
+	status, tanggalSelesai, msg := resolveStatusTransition(
+		current.StatusPekerjaan, req.StatusPekerjaan, current.TanggalMulaiKerja, req.TanggalSelesaiKerja,
+	)
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	warnings, ok, err := checkPekerjaanKonsistensi(c, db, pekerjaanKonsistensi{
+		AlumniID:            current.AlumniID,
+		PekerjaanID:         id,
+		StatusPekerjaan:     status,
+		TanggalMulaiKerja:   current.TanggalMulaiKerja,
+		TanggalSelesaiKerja: tanggalSelesai,
+	})
+	if !ok {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memulai transaksi status pekerjaan by service",
+			"error":   err.Error(),
+		})
+	}
+	defer tx.Rollback()
+
+	actorID, _ := c.Locals("user_id").(int)
+	middleware.Audit(c).Sebelum = current
+	pekerjaanRepo := repository.NewPekerjaanAlumniRepositoryTx(tx)
+	pekerjaan, err := pekerjaanRepo.UpdateStatus(id, status, tanggalSelesai)
+	if err == nil && status != current.StatusPekerjaan {
+		err = pekerjaanRepo.AddStatusHistory(model.StatusPekerjaanHistory{
+			PekerjaanID: id,
+			StatusLama:  current.StatusPekerjaan,
+			StatusBaru:  status,
+			DiubahOleh:  actorID,
+			Catatan:     req.Catatan,
+		})
+	}
+	if err == nil {
+		_, err = repository.NewRevisiRepositoryTx(tx).Record(model.RevisiEntitasPekerjaan, id, current, pekerjaan, model.RevisiAksiStatus, 0, actorID)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan alumni tidak ditemukan by service",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengupdate status pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"message":  "Status pekerjaan alumni berhasil diupdate by service",
+		"data":     pekerjaan,
+		"warnings": warnings,
+	})
+}
+
+// GetStatusHistoryPekerjaanAlumniService untuk mengambil riwayat transisi status sebuah pekerjaan
+func GetStatusHistoryPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid by service",
+		})
+	}
+
+	scope, err := resolvePekerjaanScope(c)
+	if scope == "" {
+		return err
+	}
+
+	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
+	if _, err := pekerjaanRepo.GetByID(id, scope); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan alumni tidak ditemukan by service",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+
+	history, err := pekerjaanRepo.GetStatusHistory(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat status pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Riwayat status pekerjaan alumni berhasil diambil by service",
+		"data":    history,
+	})
+}
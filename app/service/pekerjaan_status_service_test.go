@@ -0,0 +1,69 @@
+package service
+
+import (
+	"hello-fiber/app/model"
+	"testing"
+	"time"
+)
+
+func TestResolveStatusTransitionDiizinkan(t *testing.T) {
+	mulai := model.NewTanggal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	for from, tujuan := range model.StatusPekerjaanTransitions {
+		for _, to := range tujuan {
+			status, _, msg := resolveStatusTransition(from, to, mulai, model.Tanggal{})
+			if msg != "" || status != to {
+				t.Errorf("%s -> %s: status %q, pesan %q; ingin diizinkan", from, to, status, msg)
+			}
+		}
+	}
+}
+
+func TestResolveStatusTransitionDitolak(t *testing.T) {
+	mulai := model.NewTanggal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	kasus := []struct{ from, to string }{
+		{model.StatusPekerjaanSelesai, model.StatusPekerjaanAktif},
+		{model.StatusPekerjaanSelesai, model.StatusPekerjaanCuti},
+		{model.StatusPekerjaanKontrakBerakhir, model.StatusPekerjaanSelesai},
+		{model.StatusPekerjaanKontrakBerakhir, model.StatusPekerjaanCuti},
+		{model.StatusPekerjaanAktif, "pensiun"},
+	}
+	for _, k := range kasus {
+		if status, _, msg := resolveStatusTransition(k.from, k.to, mulai, model.Tanggal{}); msg == "" {
+			t.Errorf("%s -> %s: status %q, ingin ditolak", k.from, k.to, status)
+		}
+	}
+}
+
+func TestResolveStatusTransitionStatusLamaTidakDikenal(t *testing.T) {
+	mulai := model.NewTanggal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	for _, from := range []string{"", "Bekerja"} {
+		if _, _, msg := resolveStatusTransition(from, model.StatusPekerjaanSelesai, mulai, model.Tanggal{}); msg != "" {
+			t.Errorf("dari %q: pesan %q, ingin diizinkan", from, msg)
+		}
+	}
+	// status sama tetap diizinkan meskipun tidak ada di daftar transisi, dan input dinormalisasi
+	if status, _, msg := resolveStatusTransition("selesai", " SELESAI ", mulai, model.Tanggal{}); msg != "" || status != model.StatusPekerjaanSelesai {
+		t.Errorf("selesai -> selesai: status %q, pesan %q", status, msg)
+	}
+}
+
+func TestResolveStatusTransitionTanggalSelesai(t *testing.T) {
+	mulai := model.NewTanggal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	selesai := model.NewTanggal(time.Date(2022, 6, 30, 0, 0, 0, 0, time.UTC))
+
+	_, tanggal, _ := resolveStatusTransition(model.StatusPekerjaanAktif, model.StatusPekerjaanSelesai, mulai, model.Tanggal{})
+	if tanggal.String() != model.Today().String() {
+		t.Errorf("status berakhir tanpa tanggal selesai: %s, ingin hari ini", tanggal)
+	}
+	_, tanggal, _ = resolveStatusTransition(model.StatusPekerjaanAktif, model.StatusPekerjaanKontrakBerakhir, mulai, selesai)
+	if tanggal.String() != selesai.String() {
+		t.Errorf("tanggal selesai %s, ingin %s", tanggal, selesai)
+	}
+	_, tanggal, _ = resolveStatusTransition(model.StatusPekerjaanKontrakBerakhir, model.StatusPekerjaanAktif, mulai, selesai)
+	if !tanggal.IsZero() {
+		t.Errorf("status berjalan harus tanpa tanggal selesai, dapat %s", tanggal)
+	}
+	if _, _, msg := resolveStatusTransition(model.StatusPekerjaanAktif, model.StatusPekerjaanSelesai, selesai, mulai); msg == "" {
+		t.Error("tanggal selesai sebelum tanggal mulai harus ditolak")
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// pekerjaanKonsistensi data yang diperiksa terhadap alumni dan riwayat pekerjaannya.
+// PekerjaanID diisi saat update agar pekerjaan itu sendiri tidak dihitung tumpang tindih.
+type pekerjaanKonsistensi struct {
+	AlumniID            int
+	PekerjaanID         int
+	StatusPekerjaan     string
+	TanggalMulaiKerja   model.Tanggal
+	TanggalSelesaiKerja model.Tanggal
+}
+
+// loadOverlapMode membaca PEKERJAAN_OVERLAP_MODE: warn (default) menyimpan pekerjaan dengan peringatan,
+// reject menolak pekerjaan aktif yang tumpang tindih dengan pekerjaan aktif lain
+func loadOverlapMode() string {
+	if strings.ToLower(utils.GetEnv("PEKERJAAN_OVERLAP_MODE", model.OverlapModeWarn)) == model.OverlapModeReject {
+		return model.OverlapModeReject
+	}
+	return model.OverlapModeWarn
+}
+
+// missingPekerjaanFields daftar field wajib pekerjaan yang masih kosong
+func missingPekerjaanFields(namaPerusahaan, posisiJabatan, bidangIndustri, lokasiKerja string, tanggalMulai model.Tanggal) []model.FieldError {
+	errs := []model.FieldError{}
+	wajib := []struct {
+		field  string
+		kosong bool
+	}{
+		{"nama_perusahaan", namaPerusahaan == ""},
+		{"posisi_jabatan", posisiJabatan == ""},
+		{"bidang_industri", bidangIndustri == ""},
+		{"lokasi_kerja", lokasiKerja == ""},
+		{"tanggal_mulai_kerja", tanggalMulai.IsZero()},
+	}
+	for _, w := range wajib {
+		if w.kosong {
+			errs = append(errs, model.FieldError{Field: w.field, Kode: "wajib", Pesan: w.field + " harus diisi"})
+		}
+	}
+	return errs
+}
+
+// isPekerjaanBerjalan status yang berarti alumni masih terikat pekerjaan tersebut
+func isPekerjaanBerjalan(status string) bool {
+	return status == model.StatusPekerjaanAktif || status == model.StatusPekerjaanCuti
+}
+
+// periodeTumpangTindih memeriksa irisan dua periode; tanggal selesai kosong berarti masih berjalan
+func periodeTumpangTindih(mulaiA, selesaiA, mulaiB, selesaiB model.Tanggal) bool {
+	return (selesaiB.IsZero() || !mulaiA.After(selesaiB.Time)) &&
+		(selesaiA.IsZero() || !mulaiB.After(selesaiA.Time))
+}
+
+// validatePekerjaanKonsistensi memeriksa bahwa alumni ada, tanggal mulai kerja tidak sebelum angkatan
+// (sebelum tahun lulus hanya peringatan), dan pekerjaan berjalan tidak tumpang tindih dengan pekerjaan
+// berjalan lain milik alumni yang sama (peringatan atau error sesuai PEKERJAAN_OVERLAP_MODE).
+func validatePekerjaanKonsistensi(db *sql.DB, data pekerjaanKonsistensi) (errs, warnings []model.FieldError, err error) {
+	errs, warnings = []model.FieldError{}, []model.FieldError{}
+
+	alumni, err := repository.NewAlumniRepository(db).GetByID(data.AlumniID)
+	if err == sql.ErrNoRows {
+		errs = append(errs, model.FieldError{
+			Field: "alumni_id",
+			Kode:  "tidak_ditemukan",
+			Pesan: fmt.Sprintf("Alumni dengan ID %d tidak ditemukan", data.AlumniID),
+		})
+		return errs, warnings, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tahunMulai := data.TanggalMulaiKerja.Year()
+	switch {
+	case alumni.Angkatan > 0 && tahunMulai < alumni.Angkatan:
+		errs = append(errs, model.FieldError{
+			Field: "tanggal_mulai_kerja",
+			Kode:  "sebelum_angkatan",
+			Pesan: fmt.Sprintf("Tanggal mulai kerja (%s) sebelum angkatan alumni (%d)", data.TanggalMulaiKerja, alumni.Angkatan),
+		})
+	case alumni.TahunLulus > 0 && tahunMulai < alumni.TahunLulus:
+		warnings = append(warnings, model.FieldError{
+			Field: "tanggal_mulai_kerja",
+			Kode:  "sebelum_lulus",
+			Pesan: fmt.Sprintf("Tanggal mulai kerja (%s) sebelum tahun lulus (%d)", data.TanggalMulaiKerja, alumni.TahunLulus),
+		})
+	}
+
+	if !isPekerjaanBerjalan(data.StatusPekerjaan) {
+		return errs, warnings, nil
+	}
+	lainnya, err := repository.NewPekerjaanAlumniRepository(db).GetByAlumniID(alumni.ID, model.ScopeActive)
+	if err != nil {
+		return nil, nil, err
+	}
+	mode := loadOverlapMode()
+	for _, p := range lainnya {
+		if p.ID == data.PekerjaanID || !isPekerjaanBerjalan(p.StatusPekerjaan) ||
+			!periodeTumpangTindih(data.TanggalMulaiKerja, data.TanggalSelesaiKerja, p.TanggalMulaiKerja, p.TanggalSelesaiKerja) {
+			continue
+		}
+		issue := model.FieldError{
+			Field: "status_pekerjaan",
+			Kode:  "tumpang_tindih",
+			Pesan: fmt.Sprintf("Alumni masih %s di %s (pekerjaan ID %d) sejak %s", p.StatusPekerjaan, p.NamaPerusahaan, p.ID, p.TanggalMulaiKerja),
+		}
+		if mode == model.OverlapModeReject {
+			errs = append(errs, issue)
+		} else {
+			warnings = append(warnings, issue)
+		}
+	}
+	return errs, warnings, nil
+}
+
+// checkPekerjaanKonsistensi menjalankan validatePekerjaanKonsistensi dan mengirim respons jika tidak lolos.
+// ok=false berarti respons sudah dikirim dan err adalah hasil pengirimannya.
+func checkPekerjaanKonsistensi(c *fiber.Ctx, db *sql.DB, data pekerjaanKonsistensi) (warnings []model.FieldError, ok bool, err error) {
+	errs, warnings, err := validatePekerjaanKonsistensi(db, data)
+	if err != nil {
+		return nil, false, c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memvalidasi data pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+	if len(errs) > 0 {
+		return nil, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Data pekerjaan tidak valid",
+			"errors":  errs,
+		})
+	}
+	return warnings, true, nil
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+// validasiPendidikan memeriksa request riwayat pendidikan dan menormalkan program studi ke nama referensi
+func validasiPendidikan(db *sql.DB, req *model.PendidikanAlumniRequest) ([]model.FieldError, string) {
+	errs := []model.FieldError{}
+	req.Jenjang = strings.TrimSpace(req.Jenjang)
+	req.ProgramStudi = strings.TrimSpace(req.ProgramStudi)
+
+	if !model.ValidJenjang(req.Jenjang) {
+		errs = append(errs, model.FieldError{Field: "jenjang", Kode: "tidak_valid", Pesan: "jenjang harus D3, D4, S1, S2, S3, atau Profesi"})
+	}
+	if req.ProgramStudi == "" {
+		errs = append(errs, model.FieldError{Field: "program_studi", Kode: "wajib", Pesan: "program_studi harus diisi"})
+	}
+	tahunMaks := time.Now().Year() + 1
+	if req.TahunMasuk < 1950 || req.TahunMasuk > tahunMaks {
+		errs = append(errs, model.FieldError{Field: "tahun_masuk", Kode: "tidak_valid", Pesan: fmt.Sprintf("tahun_masuk harus antara 1950 dan %d", tahunMaks)})
+	}
+	if req.TahunLulus != nil && (*req.TahunLulus < req.TahunMasuk || *req.TahunLulus > tahunMaks) {
+		errs = append(errs, model.FieldError{Field: "tahun_lulus", Kode: "sebelum_masuk", Pesan: "tahun_lulus tidak boleh sebelum tahun_masuk atau di masa depan"})
+	}
+	if req.IPK != nil && (*req.IPK < 0 || *req.IPK > 4) {
+		errs = append(errs, model.FieldError{Field: "ipk", Kode: "tidak_valid", Pesan: "ipk harus antara 0 dan 4"})
+	}
+	if len(errs) > 0 {
+		return errs, ""
+	}
+
+	return errs, resolveReferensi(db, model.ReferensiProgramStudi, "program_studi", &req.ProgramStudi)
+}
+
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
+
+// parsePendidikanRequest membaca dan memvalidasi body; respons error sudah dikirim jika ok=false
+func parsePendidikanRequest(c *fiber.Ctx, db *sql.DB) (model.PendidikanAlumniRequest, bool, error) {
+	var req model.PendidikanAlumniRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	errs, msg := validasiPendidikan(db, &req)
+	if len(errs) > 0 {
+		return req, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Validasi pendidikan gagal",
+			"errors":  errs,
+		})
+	}
+	if msg != "" {
+		return req, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	return req, true, nil
+}
+
+// GetPendidikanAlumniService riwayat pendidikan alumni, dari yang paling awal
+func GetPendidikanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	if _, err := repository.NewAlumniRepository(db).GetByID(alumniID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	list, err := repository.NewPendidikanAlumniRepository(db).GetByAlumni(alumniID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat pendidikan alumni",
+			"error":   err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Riwayat pendidikan alumni berhasil diambil",
+		"data":    list,
+	})
+}
+
+// CreatePendidikanAlumniService menambah riwayat pendidikan, misalnya S2 setelah S1
+func CreatePendidikanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	if _, err := repository.NewAlumniRepository(db).GetByID(alumniID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	req, ok, err := parsePendidikanRequest(c, db)
+	if !ok {
+		return err
+	}
+
+	pendidikan, err := repository.NewPendidikanAlumniRepository(db).Create(alumniID, req)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Alumni sudah memiliki pendidikan %s %s", req.Jenjang, req.ProgramStudi),
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menambah riwayat pendidikan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Riwayat pendidikan berhasil ditambahkan",
+		"data":    pendidikan,
+	})
+}
+
+// UpdatePendidikanAlumniService mengubah riwayat pendidikan; perubahan pendidikan utama ikut mengubah
+// jurusan/angkatan/tahun_lulus alumni
+func UpdatePendidikanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	pendidikanID, err := strconv.Atoi(c.Params("pendidikan_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID pendidikan tidak valid",
+		})
+	}
+
+	req, ok, err := parsePendidikanRequest(c, db)
+	if !ok {
+		return err
+	}
+
+	pendidikanRepo := repository.NewPendidikanAlumniRepository(db)
+	if current, err := pendidikanRepo.GetByID(alumniID, pendidikanID); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	pendidikan, err := pendidikanRepo.Update(alumniID, pendidikanID, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Riwayat pendidikan tidak ditemukan",
+			})
+		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Alumni sudah memiliki pendidikan %s %s", req.Jenjang, req.ProgramStudi),
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengupdate riwayat pendidikan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Riwayat pendidikan berhasil diupdate",
+		"data":    pendidikan,
+	})
+}
+
+// DeletePendidikanAlumniService menghapus riwayat pendidikan; pendidikan terakhir alumni tidak dapat dihapus
+func DeletePendidikanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	pendidikanID, err := strconv.Atoi(c.Params("pendidikan_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID pendidikan tidak valid",
+		})
+	}
+
+	pendidikanRepo := repository.NewPendidikanAlumniRepository(db)
+	if current, err := pendidikanRepo.GetByID(alumniID, pendidikanID); err == nil {
+		middleware.Audit(c).Sebelum = current
+	}
+	if err := pendidikanRepo.Delete(alumniID, pendidikanID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Riwayat pendidikan tidak ditemukan",
+			})
+		}
+		if err == repository.ErrPendidikanTerakhir {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Pendidikan terakhir alumni tidak dapat dihapus",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus riwayat pendidikan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Riwayat pendidikan berhasil dihapus",
+	})
+}
@@ -0,0 +1,407 @@
+package service
+
+import (
+	"database/sql"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SubmitPengajuanPekerjaanService untuk alumni mengajukan pekerjaan baru atau perubahan pekerjaannya sendiri
+func SubmitPengajuanPekerjaanService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	var req model.CreatePengajuanPekerjaanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+			"error":   err.Error(),
+		})
+	}
+
+	data := req.Data
+	if msg := applyPerusahaan(db, data.PerusahaanID, &data.NamaPerusahaan, &data.BidangIndustri, &data.LokasiKerja); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	if msg := resolvePekerjaanReferensi(db, &data.BidangIndustri, &data.LokasiKerja); msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	if errs := missingPekerjaanFields(data.NamaPerusahaan, data.PosisiJabatan, data.BidangIndustri, data.LokasiKerja, data.TanggalMulaiKerja); len(errs) > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Nama perusahaan, posisi jabatan, bidang industri, lokasi kerja, dan tanggal mulai kerja harus diisi",
+			"errors":  errs,
+		})
+	}
+	userID, _ := c.Locals("user_id").(int)
+	pengajuan := model.PengajuanPekerjaan{
+		AlumniID:     alumni.ID,
+		Jenis:        model.JenisPengajuanCreate,
+		Data:         data,
+		DiajukanOleh: userID,
+	}
+
+	var current *model.PekerjaanAlumni
+	if req.PekerjaanID != 0 {
+		pekerjaan, err := repository.NewPekerjaanAlumniRepository(db).GetByID(req.PekerjaanID, model.ScopeActive)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return c.Status(404).JSON(fiber.Map{
+					"success": false,
+					"message": "Pekerjaan alumni tidak ditemukan",
+				})
+			}
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Gagal mengambil data pekerjaan alumni",
+				"error":   err.Error(),
+			})
+		}
+		if pekerjaan.AlumniID != alumni.ID {
+			return c.Status(403).JSON(fiber.Map{
+				"success": false,
+				"message": "Forbidden: You can only update your own data",
+			})
+		}
+		pengajuan.Jenis = model.JenisPengajuanUpdate
+		pengajuan.PekerjaanID = pekerjaan.ID
+		current = &pekerjaan
+	}
+
+	fromStatus := ""
+	if current != nil {
+		fromStatus = current.StatusPekerjaan
+	}
+	if data.StatusPekerjaan == "" {
+		data.StatusPekerjaan = "aktif"
+		if current != nil {
+			data.StatusPekerjaan = current.StatusPekerjaan
+		}
+	}
+	var msg string
+	data.StatusPekerjaan, data.TanggalSelesaiKerja, msg = resolveStatusTransition(
+		fromStatus, data.StatusPekerjaan, data.TanggalMulaiKerja, data.TanggalSelesaiKerja,
+	)
+	if msg == "" {
+		data.Gaji, data.GajiRange, msg = resolveGaji(data.Gaji, data.GajiRange)
+	}
+	if msg != "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+	pengajuan.Data = data
+	warnings, ok, err := checkPekerjaanKonsistensi(c, db, pekerjaanKonsistensi{
+		AlumniID:            alumni.ID,
+		PekerjaanID:         pengajuan.PekerjaanID,
+		StatusPekerjaan:     data.StatusPekerjaan,
+		TanggalMulaiKerja:   data.TanggalMulaiKerja,
+		TanggalSelesaiKerja: data.TanggalSelesaiKerja,
+	})
+	if !ok {
+		return err
+	}
+
+	created, err := repository.NewPengajuanPekerjaanRepository(db).Create(pengajuan)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan pengajuan pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+	created.Diff = diffPekerjaan(current, created.Data)
+
+	return c.Status(201).JSON(fiber.Map{
+		"success":  true,
+		"message":  "Pengajuan pekerjaan berhasil dikirim dan menunggu persetujuan admin",
+		"data":     created,
+		"warnings": warnings,
+	})
+}
+
+// GetMyPengajuanPekerjaanService untuk alumni melihat riwayat pengajuannya sendiri
+func GetMyPengajuanPekerjaanService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	list, err := repository.NewPengajuanPekerjaanRepository(db).GetAll(c.Query("status", ""), alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pengajuan pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data pengajuan pekerjaan berhasil diambil",
+		"data":    list,
+	})
+}
+
+// GetAllPengajuanPekerjaanService untuk admin melihat antrean pengajuan beserta diff terhadap data saat ini
+func GetAllPengajuanPekerjaanService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, _ := strconv.Atoi(c.Query("alumni_id", "0"))
+	status := c.Query("status", model.StatusPengajuanPending)
+
+	list, err := repository.NewPengajuanPekerjaanRepository(db).GetAll(status, alumniID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pengajuan pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+
+	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
+	for i := range list {
+		if list[i].Diff, err = diffPengajuan(pekerjaanRepo, list[i]); err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Gagal membandingkan pengajuan dengan data pekerjaan",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data pengajuan pekerjaan berhasil diambil",
+		"data":    list,
+	})
+}
+
+// GetPengajuanPekerjaanByIDService untuk admin melihat detail satu pengajuan beserta diff-nya
+func GetPengajuanPekerjaanByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	pengajuan, err := repository.NewPengajuanPekerjaanRepository(db).GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pengajuan pekerjaan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pengajuan pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+
+	if pengajuan.Diff, err = diffPengajuan(repository.NewPekerjaanAlumniRepository(db), pengajuan); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal membandingkan pengajuan dengan data pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data pengajuan pekerjaan berhasil diambil",
+		"data":    pengajuan,
+	})
+}
+
+// ApprovePengajuanPekerjaanService untuk admin menyetujui pengajuan dan menerapkannya ke pekerjaan_alumni
+func ApprovePengajuanPekerjaanService(c *fiber.Ctx, db *sql.DB) error {
+	return reviewPengajuanPekerjaan(c, db, true)
+}
+
+// RejectPengajuanPekerjaanService untuk admin menolak pengajuan dengan komentar
+func RejectPengajuanPekerjaanService(c *fiber.Ctx, db *sql.DB) error {
+	return reviewPengajuanPekerjaan(c, db, false)
+}
+
+func reviewPengajuanPekerjaan(c *fiber.Ctx, db *sql.DB, approve bool) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.ReviewPengajuanPekerjaanRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Request body tidak valid",
+			})
+		}
+	}
+	if !approve && req.Komentar == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Komentar alasan penolakan harus diisi",
+		})
+	}
+
+	reviewerID, _ := c.Locals("user_id").(int)
+	pengajuanRepo := repository.NewPengajuanPekerjaanRepository(db)
+
+	// Status dan riwayat pekerjaan bisa sudah berubah sejak pengajuan dikirim, jadi transisi
+	// dan konsistensinya dicek ulang
+	warnings := []model.FieldError{}
+	if approve {
+		if msg := validatePengajuanTransition(db, pengajuanRepo, id); msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+		if pending, err := pengajuanRepo.GetByID(id); err == nil && pending.Status == model.StatusPengajuanPending {
+			var ok bool
+			warnings, ok, err = checkPekerjaanKonsistensi(c, db, pekerjaanKonsistensi{
+				AlumniID:            pending.AlumniID,
+				PekerjaanID:         pending.PekerjaanID,
+				StatusPekerjaan:     pending.Data.StatusPekerjaan,
+				TanggalMulaiKerja:   pending.Data.TanggalMulaiKerja,
+				TanggalSelesaiKerja: pending.Data.TanggalSelesaiKerja,
+			})
+			if !ok {
+				return err
+			}
+		}
+	}
+
+	var pengajuan model.PengajuanPekerjaan
+	if approve {
+		pengajuan, err = pengajuanRepo.Approve(id, reviewerID, req.Komentar)
+	} else {
+		pengajuan, err = pengajuanRepo.Reject(id, reviewerID, req.Komentar)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pengajuan tidak ditemukan, sudah ditinjau, atau pekerjaan yang diubah sudah tidak aktif",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal meninjau pengajuan pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+
+	message := "Pengajuan pekerjaan berhasil ditolak"
+	if approve {
+		message = "Pengajuan pekerjaan berhasil disetujui dan diterapkan"
+	}
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"message":  message,
+		"data":     pengajuan,
+		"warnings": warnings,
+	})
+}
+
+// diffPengajuan membandingkan pengajuan yang masih pending dengan data pekerjaan saat ini
+func diffPengajuan(pekerjaanRepo repository.PekerjaanAlumniRepository, pengajuan model.PengajuanPekerjaan) ([]model.FieldDiff, error) {
+	if pengajuan.Status != model.StatusPengajuanPending {
+		return nil, nil
+	}
+	if pengajuan.Jenis != model.JenisPengajuanUpdate {
+		return diffPekerjaan(nil, pengajuan.Data), nil
+	}
+
+	current, err := pekerjaanRepo.GetByID(pengajuan.PekerjaanID, model.ScopeAll)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return diffPekerjaan(nil, pengajuan.Data), nil
+		}
+		return nil, err
+	}
+	return diffPekerjaan(&current, pengajuan.Data), nil
+}
+
+// diffPekerjaan mengembalikan field yang berbeda; current nil berarti pekerjaan baru
+func diffPekerjaan(current *model.PekerjaanAlumni, data model.PekerjaanData) []model.FieldDiff {
+	var lama model.PekerjaanData
+	if current != nil {
+		lama = model.PekerjaanData{
+			NamaPerusahaan:      current.NamaPerusahaan,
+			PosisiJabatan:       current.PosisiJabatan,
+			BidangIndustri:      current.BidangIndustri,
+			LokasiKerja:         current.LokasiKerja,
+			GajiRange:           current.GajiRange,
+			TanggalMulaiKerja:   current.TanggalMulaiKerja,
+			TanggalSelesaiKerja: current.TanggalSelesaiKerja,
+			StatusPekerjaan:     current.StatusPekerjaan,
+			DeskripsiPekerjaan:  current.DeskripsiPekerjaan,
+		}
+	}
+
+	fields := []struct {
+		name       string
+		lama, baru string
+	}{
+		{"nama_perusahaan", lama.NamaPerusahaan, data.NamaPerusahaan},
+		{"posisi_jabatan", lama.PosisiJabatan, data.PosisiJabatan},
+		{"bidang_industri", lama.BidangIndustri, data.BidangIndustri},
+		{"lokasi_kerja", lama.LokasiKerja, data.LokasiKerja},
+		{"gaji_range", lama.GajiRange, data.GajiRange},
+		{"tanggal_mulai_kerja", lama.TanggalMulaiKerja.String(), data.TanggalMulaiKerja.String()},
+		{"tanggal_selesai_kerja", lama.TanggalSelesaiKerja.String(), data.TanggalSelesaiKerja.String()},
+		{"status_pekerjaan", lama.StatusPekerjaan, data.StatusPekerjaan},
+		{"deskripsi_pekerjaan", lama.DeskripsiPekerjaan, data.DeskripsiPekerjaan},
+	}
+
+	diff := []model.FieldDiff{}
+	for _, f := range fields {
+		if f.lama != f.baru {
+			diff = append(diff, model.FieldDiff{Field: f.name, Lama: f.lama, Baru: f.baru})
+		}
+	}
+	return diff
+}
+
+// validatePengajuanTransition memastikan status yang diajukan masih merupakan transisi yang sah dari status saat ini
+func validatePengajuanTransition(db *sql.DB, pengajuanRepo repository.PengajuanPekerjaanRepository, id int) string {
+	pengajuan, err := pengajuanRepo.GetByID(id)
+	if err != nil || pengajuan.Jenis != model.JenisPengajuanUpdate {
+		return ""
+	}
+	current, err := repository.NewPekerjaanAlumniRepository(db).GetByID(pengajuan.PekerjaanID, model.ScopeActive)
+	if err != nil {
+		return ""
+	}
+	_, _, msg := resolveStatusTransition(
+		current.StatusPekerjaan, pengajuan.Data.StatusPekerjaan,
+		pengajuan.Data.TanggalMulaiKerja, pengajuan.Data.TanggalSelesaiKerja,
+	)
+	return msg
+}
@@ -0,0 +1,566 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAllPerusahaanService untuk mengambil direktori perusahaan dengan search dan pagination
+func GetAllPerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	search := c.Query("search", "")
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	perusahaanRepo := repository.NewPerusahaanRepository(db)
+	list, err := perusahaanRepo.GetAll(search, limit, (page-1)*limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data perusahaan",
+			"error":   err.Error(),
+		})
+	}
+	total, err := perusahaanRepo.Count(search)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghitung data perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data perusahaan berhasil diambil",
+		"data":    list,
+		"meta": model.MetaInfo{
+			Page:   page,
+			Limit:  limit,
+			Total:  total,
+			Pages:  (total + limit - 1) / limit,
+			SortBy: "nama",
+			Order:  "asc",
+			Search: search,
+		},
+	})
+}
+
+// GetPerusahaanByIDService untuk mengambil detail perusahaan
+func GetPerusahaanByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	perusahaan, err := repository.NewPerusahaanRepository(db).GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Perusahaan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data perusahaan berhasil diambil",
+		"data":    perusahaan,
+	})
+}
+
+// CreatePerusahaanService untuk menambah perusahaan ke direktori (admin only)
+func CreatePerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	var req model.PerusahaanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	perusahaanRepo := repository.NewPerusahaanRepository(db)
+	if status, msg := validatePerusahaanRequest(db, perusahaanRepo, 0, &req); msg != "" {
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	perusahaan, err := perusahaanRepo.Create(req)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menambah perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Perusahaan berhasil ditambahkan",
+		"data":    perusahaan,
+	})
+}
+
+// UpdatePerusahaanService untuk mengubah data dan alias perusahaan (admin only)
+func UpdatePerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.PerusahaanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	perusahaanRepo := repository.NewPerusahaanRepository(db)
+	if status, msg := validatePerusahaanRequest(db, perusahaanRepo, id, &req); msg != "" {
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	perusahaan, err := perusahaanRepo.Update(id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Perusahaan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengupdate perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Perusahaan berhasil diupdate",
+		"data":    perusahaan,
+	})
+}
+
+// validatePerusahaanRequest memeriksa field wajib, menyeragamkan bidang industri dan kota dengan kosakata,
+// dan memastikan nama maupun alias belum dipakai perusahaan lain (selain id).
+// Mengembalikan status HTTP dan pesan error (kosong jika valid).
+func validatePerusahaanRequest(db *sql.DB, perusahaanRepo repository.PerusahaanRepository, id int, req *model.PerusahaanRequest) (int, string) {
+	req.Nama = strings.TrimSpace(req.Nama)
+	if model.NormalizeNamaPerusahaan(req.Nama) == "" {
+		return 400, "Nama perusahaan harus diisi"
+	}
+	if msg := resolveReferensi(db, model.ReferensiBidangIndustri, "bidang_industri", &req.BidangIndustri); msg != "" {
+		return 400, msg
+	}
+	if msg := resolveReferensi(db, model.ReferensiKota, "kota", &req.Kota); msg != "" {
+		return 400, msg
+	}
+
+	for _, nama := range append([]string{req.Nama}, req.Alias...) {
+		existing, err := perusahaanRepo.FindByNama(nama)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return 500, "Gagal memeriksa nama perusahaan"
+		}
+		if existing.ID != id {
+			return 409, fmt.Sprintf("Nama \"%s\" sudah dipakai perusahaan %s (ID %d), gunakan merge untuk menggabungkan", nama, existing.Nama, existing.ID)
+		}
+	}
+	return 0, ""
+}
+
+// DeletePerusahaanService untuk menghapus perusahaan yang tidak dirujuk pekerjaan maupun lowongan mana pun (admin only)
+func DeletePerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	perusahaanRepo := repository.NewPerusahaanRepository(db)
+	total, err := perusahaanRepo.CountPekerjaan(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memeriksa pekerjaan perusahaan",
+			"error":   err.Error(),
+		})
+	}
+	if total > 0 {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Perusahaan masih dirujuk %d pekerjaan, gabungkan ke perusahaan lain terlebih dahulu", total),
+		})
+	}
+	totalLowongan, err := perusahaanRepo.CountLowongan(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memeriksa lowongan perusahaan",
+			"error":   err.Error(),
+		})
+	}
+	if totalLowongan > 0 {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Perusahaan masih memiliki %d lowongan, gabungkan ke perusahaan lain terlebih dahulu", totalLowongan),
+		})
+	}
+
+	if err := perusahaanRepo.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Perusahaan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Perusahaan berhasil dihapus",
+	})
+}
+
+// MatchPerusahaanService untuk mencari perusahaan yang namanya mirip, dipakai saat mengisi nama perusahaan
+func MatchPerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	nama := c.Query("nama", "")
+	if strings.TrimSpace(nama) == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Query nama harus diisi",
+		})
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "5"))
+	if limit < 1 {
+		limit = 5
+	}
+
+	matches, err := repository.NewPerusahaanRepository(db).FindMatches(nama, limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mencocokkan nama perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Kandidat perusahaan berhasil diambil",
+		"data":    matches,
+	})
+}
+
+// GetDuplikatPerusahaanService untuk melihat pasangan perusahaan yang kemungkinan duplikat (admin only)
+func GetDuplikatPerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	minSkor, err := strconv.ParseFloat(c.Query("min_skor", "0.6"), 64)
+	if err != nil || minSkor <= 0 || minSkor > 1 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "min_skor harus di antara 0 dan 1",
+		})
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	if limit < 1 {
+		limit = 50
+	}
+
+	list, err := repository.NewPerusahaanRepository(db).FindDuplicates(minSkor, limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mencari duplikat perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Saran duplikat perusahaan berhasil diambil",
+		"data":    list,
+	})
+}
+
+// MergePerusahaanService untuk menggabungkan perusahaan duplikat ke perusahaan :id (admin only)
+func MergePerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.MergePerusahaanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	sumberIDs := []int{}
+	seen := map[int]bool{id: true}
+	for _, sumberID := range req.SumberIDs {
+		if !seen[sumberID] {
+			seen[sumberID] = true
+			sumberIDs = append(sumberIDs, sumberID)
+		}
+	}
+	if len(sumberIDs) == 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "sumber_ids harus berisi minimal satu perusahaan selain perusahaan tujuan",
+		})
+	}
+
+	result, err := repository.NewPerusahaanRepository(db).Merge(id, sumberIDs)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Perusahaan tujuan atau sumber tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menggabungkan perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": fmt.Sprintf("%d perusahaan berhasil digabungkan", len(result.Digabung)),
+		"data":    result,
+	})
+}
+
+// GetTopPerusahaanService untuk mengambil perusahaan dengan alumni terbanyak; mendukung filter statistik
+// (angkatan, tahun_lulus, jurusan) dan aktif=true untuk hanya menghitung pekerjaan yang masih berjalan
+func GetTopPerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+
+	list, err := repository.NewStatsRepository(db).GetTopPerusahaan(limit, c.QueryBool("aktif", false), parseStatsFilter(c))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil top perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Top perusahaan berhasil diambil",
+		"data":    list,
+	})
+}
+
+// applyPerusahaan mengisi nama perusahaan, serta bidang industri dan lokasi kerja yang kosong,
+// dari direktori jika perusahaan_id diisi. Mengembalikan pesan error jika perusahaan tidak ditemukan.
+func applyPerusahaan(db *sql.DB, perusahaanID int, nama, bidangIndustri, lokasiKerja *string) string {
+	if perusahaanID == 0 {
+		return ""
+	}
+	perusahaan, err := repository.NewPerusahaanRepository(db).GetByID(perusahaanID)
+	if err != nil {
+		return "Perusahaan tidak ditemukan di direktori"
+	}
+	*nama = perusahaan.Nama
+	if *bidangIndustri == "" {
+		*bidangIndustri = perusahaan.BidangIndustri
+	}
+	if *lokasiKerja == "" {
+		*lokasiKerja = perusahaan.Kota
+	}
+	return ""
+}
+
+// GetMitraPerusahaanService untuk melihat akun mitra yang dapat memasang lowongan atas nama perusahaan (admin only)
+func GetMitraPerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	mitra, err := repository.NewUserRepository(db).GetByPerusahaan(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil mitra perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Mitra perusahaan berhasil diambil",
+		"data":    mitra,
+	})
+}
+
+// TambahMitraPerusahaanService untuk menghubungkan akun user sebagai mitra perusahaan (admin only).
+// User yang sudah menjadi mitra perusahaan lain dipindahkan ke perusahaan ini.
+func TambahMitraPerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.MitraPerusahaanRequest
+	if err := c.BodyParser(&req); err != nil || req.UserID <= 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "user_id harus diisi",
+		})
+	}
+
+	if _, err := repository.NewPerusahaanRepository(db).GetByID(id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Perusahaan tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	user, err := userRepo.FindByID(req.UserID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "User tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data user",
+			"error":   err.Error(),
+		})
+	}
+	if user.RoleID == 1 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Admin sudah dapat memasang lowongan untuk semua perusahaan",
+		})
+	}
+
+	middleware.Audit(c).Sebelum = user
+	if err := userRepo.SetPerusahaan(user.ID, id); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menambah mitra perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Mitra perusahaan berhasil ditambahkan",
+	})
+}
+
+// HapusMitraPerusahaanService untuk melepas akun mitra dari perusahaan (admin only)
+func HapusMitraPerusahaanService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	userID, err := strconv.Atoi(c.Params("user_id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID user tidak valid",
+		})
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	user, err := userRepo.FindByID(userID)
+	if err != nil || user.PerusahaanID != id {
+		if err == nil || err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "User bukan mitra perusahaan ini",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data user",
+			"error":   err.Error(),
+		})
+	}
+
+	middleware.Audit(c).Sebelum = user
+	if err := userRepo.SetPerusahaan(userID, 0); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal melepas mitra perusahaan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Mitra perusahaan berhasil dilepas",
+	})
+}
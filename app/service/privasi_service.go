@@ -0,0 +1,395 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportDataPribadiService untuk user mengunduh seluruh data pribadinya: akun, data alumni, pekerjaan,
+// pengajuan, permintaan penghapusan, dan audit log terkait. format=zip mengunduh arsip berisi file JSON terpisah.
+func ExportDataPribadiService(c *fiber.Ctx, db *sql.DB) error {
+	userID, _ := c.Locals("user_id").(int)
+	user, err := repository.NewUserRepository(db).FindByID(userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "User tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data user",
+			"error":   err.Error(),
+		})
+	}
+	user.Password = ""
+
+	export, err := buildDataExport(db, user)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyusun export data pribadi",
+			"error":   err.Error(),
+		})
+	}
+
+	if strings.ToLower(c.Query("format", "json")) == "zip" {
+		data, err := dataExportToZip(export)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Gagal membuat file ZIP export data pribadi",
+				"error":   err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderContentType, "application/zip")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="data-pribadi-%d.zip"`, user.ID))
+		return c.Send(data)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Export data pribadi berhasil dibuat",
+		"data":    export,
+	})
+}
+
+func buildDataExport(db *sql.DB, user model.User) (model.DataExport, error) {
+	export := model.DataExport{
+		DibuatAt:              time.Now(),
+		User:                  user,
+		Pendidikan:            []model.PendidikanAlumni{},
+		Keahlian:              []model.KeahlianAlumni{},
+		Pekerjaan:             []model.PekerjaanAlumni{},
+		PengajuanPekerjaan:    []model.PengajuanPekerjaan{},
+		LamaranLowongan:       []model.LamaranLowongan{},
+		Acara:                 []model.PendaftaranAcara{},
+		Mentoring:             []model.PermintaanMentor{},
+		PermintaanPenghapusan: []model.PermintaanPenghapusan{},
+		Berkas:                []model.Berkas{},
+	}
+
+	var err error
+	if user.AlumniID != 0 {
+		alumni, errAlumni := repository.NewAlumniRepository(db).GetByID(user.AlumniID)
+		if errAlumni != nil && errAlumni != sql.ErrNoRows {
+			return export, errAlumni
+		}
+		if errAlumni == nil {
+			export.Alumni = &alumni
+		}
+		if export.Pendidikan, err = repository.NewPendidikanAlumniRepository(db).GetByAlumni(user.AlumniID); err != nil {
+			return export, err
+		}
+		if export.Keahlian, err = repository.NewKeahlianAlumniRepository(db).GetByAlumni(user.AlumniID); err != nil {
+			return export, err
+		}
+		if export.Pekerjaan, err = repository.NewPekerjaanAlumniRepository(db).GetByAlumniID(user.AlumniID, model.ScopeAll); err != nil {
+			return export, err
+		}
+		if export.PengajuanPekerjaan, err = repository.NewPengajuanPekerjaanRepository(db).GetAll("", user.AlumniID); err != nil {
+			return export, err
+		}
+		if export.LamaranLowongan, err = repository.NewLowonganRepository(db).GetLamaranByAlumni(user.AlumniID); err != nil {
+			return export, err
+		}
+		if export.Acara, err = repository.NewAcaraRepository(db).GetPendaftaranByAlumni(user.AlumniID); err != nil {
+			return export, err
+		}
+		mentorshipRepo := repository.NewMentorshipRepository(db)
+		mentor, errMentor := mentorshipRepo.GetMentorByAlumni(user.AlumniID)
+		if errMentor != nil && errMentor != sql.ErrNoRows {
+			return export, errMentor
+		}
+		if errMentor == nil {
+			export.Mentor = &mentor
+		}
+		if export.Mentoring, err = mentorshipRepo.GetPermintaanByAlumni(user.AlumniID, "", ""); err != nil {
+			return export, err
+		}
+		if export.PermintaanPenghapusan, err = repository.NewPenghapusanDataRepository(db).GetAll("", user.AlumniID); err != nil {
+			return export, err
+		}
+		if export.Berkas, err = repository.NewBerkasRepository(db).GetByAlumni(user.AlumniID); err != nil {
+			return export, err
+		}
+	}
+	export.AuditLog, err = repository.NewAuditRepository(db).GetBySubjek(user.ID, user.AlumniID)
+	return export, err
+}
+
+// dataExportToZip satu file JSON per bagian ditambah manifest.json
+func dataExportToZip(export model.DataExport) ([]byte, error) {
+	files := []struct {
+		nama string
+		isi  interface{}
+	}{
+		{"user.json", export.User},
+		{"alumni.json", export.Alumni},
+		{"pendidikan.json", export.Pendidikan},
+		{"keahlian.json", export.Keahlian},
+		{"pekerjaan.json", export.Pekerjaan},
+		{"pengajuan_pekerjaan.json", export.PengajuanPekerjaan},
+		{"lamaran_lowongan.json", export.LamaranLowongan},
+		{"acara.json", export.Acara},
+		{"mentor.json", export.Mentor},
+		{"mentoring.json", export.Mentoring},
+		{"permintaan_penghapusan.json", export.PermintaanPenghapusan},
+		{"berkas.json", export.Berkas},
+		{"audit_log.json", export.AuditLog},
+	}
+
+	manifest := fiber.Map{
+		"dibuat_at": export.DibuatAt,
+		"user_id":   export.User.ID,
+		"alumni_id": export.User.AlumniID,
+		"file":      []string{},
+	}
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	write := func(nama string, isi interface{}) error {
+		data, err := json.MarshalIndent(isi, "", "  ")
+		if err != nil {
+			return err
+		}
+		f, err := w.CreateHeader(&zip.FileHeader{Name: nama, Method: zip.Deflate, Modified: export.DibuatAt})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	for _, file := range files {
+		if err := write(file.nama, file.isi); err != nil {
+			return nil, err
+		}
+		manifest["file"] = append(manifest["file"].([]string), file.nama)
+	}
+	if err := write("manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AjukanPenghapusanDataService untuk alumni meminta data pribadinya dihapus; hanya satu permintaan pending per alumni
+func AjukanPenghapusanDataService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	var req model.CreatePermintaanPenghapusanRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Request body tidak valid",
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	penghapusanRepo := repository.NewPenghapusanDataRepository(db)
+	pending, err := penghapusanRepo.GetAll(model.StatusPengajuanPending, alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memeriksa permintaan penghapusan data",
+			"error":   err.Error(),
+		})
+	}
+	if len(pending) > 0 {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": "Masih ada permintaan penghapusan data yang menunggu persetujuan admin",
+			"data":    pending[0],
+		})
+	}
+
+	userID, _ := c.Locals("user_id").(int)
+	created, err := penghapusanRepo.Create(model.PermintaanPenghapusan{
+		AlumniID:     alumni.ID,
+		DiajukanOleh: userID,
+		Alasan:       strings.TrimSpace(req.Alasan),
+	})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan permintaan penghapusan data",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Permintaan penghapusan data berhasil dikirim dan menunggu persetujuan admin",
+		"data":    created,
+	})
+}
+
+// GetMyPenghapusanDataService untuk alumni melihat permintaan penghapusan dan tanda terimanya
+func GetMyPenghapusanDataService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	list, err := repository.NewPenghapusanDataRepository(db).GetAll(c.Query("status", ""), alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil permintaan penghapusan data",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Permintaan penghapusan data berhasil diambil",
+		"data":    list,
+	})
+}
+
+// GetAllPenghapusanDataService untuk admin melihat antrean permintaan penghapusan data
+func GetAllPenghapusanDataService(c *fiber.Ctx, db *sql.DB) error {
+	alumniID, _ := strconv.Atoi(c.Query("alumni_id", "0"))
+	status := c.Query("status", model.StatusPengajuanPending)
+
+	list, err := repository.NewPenghapusanDataRepository(db).GetAll(status, alumniID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil permintaan penghapusan data",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Permintaan penghapusan data berhasil diambil",
+		"data":    list,
+	})
+}
+
+// GetPenghapusanDataByIDService untuk admin melihat satu permintaan beserta tanda terimanya
+func GetPenghapusanDataByIDService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	permintaan, err := repository.NewPenghapusanDataRepository(db).GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Permintaan penghapusan data tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil permintaan penghapusan data",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Permintaan penghapusan data berhasil diambil",
+		"data":    permintaan,
+	})
+}
+
+// ApprovePenghapusanDataService untuk admin menyetujui permintaan dan menganonimkan data alumni
+func ApprovePenghapusanDataService(c *fiber.Ctx, db *sql.DB) error {
+	return reviewPenghapusanData(c, db, true)
+}
+
+// RejectPenghapusanDataService untuk admin menolak permintaan dengan komentar
+func RejectPenghapusanDataService(c *fiber.Ctx, db *sql.DB) error {
+	return reviewPenghapusanData(c, db, false)
+}
+
+func reviewPenghapusanData(c *fiber.Ctx, db *sql.DB, approve bool) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.ReviewPengajuanPekerjaanRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Request body tidak valid",
+			})
+		}
+	}
+	if !approve && req.Komentar == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Komentar alasan penolakan harus diisi",
+		})
+	}
+
+	reviewerID, _ := c.Locals("user_id").(int)
+	penghapusanRepo := repository.NewPenghapusanDataRepository(db)
+	var permintaan model.PermintaanPenghapusan
+	var berkas []model.Berkas
+	if approve {
+		permintaan, berkas, err = penghapusanRepo.Approve(id, reviewerID, req.Komentar)
+	} else {
+		permintaan, err = penghapusanRepo.Reject(id, reviewerID, req.Komentar)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Permintaan penghapusan data tidak ditemukan atau sudah ditinjau",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal meninjau permintaan penghapusan data",
+			"error":   err.Error(),
+		})
+	}
+
+	message := "Permintaan penghapusan data berhasil ditolak"
+	if approve {
+		message = "Data pribadi alumni berhasil dianonimkan"
+		// baris berkas sudah terhapus di transaksi approve; object yang gagal dihapus dicoba ulang scheduler
+		hapusObjectBerkasTerhapus(c.UserContext(), db, berkas)
+	}
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": message,
+		"data":    permintaan,
+	})
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseJenisReferensi membaca :jenis dari URL; string kosong berarti jenis tidak dikenal
+func parseJenisReferensi(c *fiber.Ctx) string {
+	jenis := strings.ReplaceAll(strings.ToLower(c.Params("jenis")), "-", "_")
+	if _, ok := model.ReferensiIndukJenis[jenis]; !ok {
+		return ""
+	}
+	return jenis
+}
+
+func jenisReferensiTidakValid(c *fiber.Ctx) error {
+	return c.Status(404).JSON(fiber.Map{
+		"success": false,
+		"message": "Jenis referensi tidak dikenal, gunakan fakultas, program_studi, bidang_industri, provinsi, atau kota",
+	})
+}
+
+// SearchReferensiService untuk autocomplete kosakata (?q=, ?induk_id=, ?limit=).
+// Admin dapat menyertakan entri nonaktif dengan ?semua=true.
+func SearchReferensiService(c *fiber.Ctx, db *sql.DB) error {
+	jenis := parseJenisReferensi(c)
+	if jenis == "" {
+		return jenisReferensiTidakValid(c)
+	}
+	indukID, _ := strconv.Atoi(c.Query("induk_id", "0"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+	roleID, _ := c.Locals("role_id").(int)
+	semua := c.Query("semua") == "true" && roleID == 1
+
+	list, err := repository.NewReferensiRepository(db).Search(jenis, c.Query("q", ""), indukID, limit, semua)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data referensi",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Data referensi berhasil diambil",
+		"data":    list,
+	})
+}
+
+// CreateReferensiService untuk menambah entri kosakata (admin only)
+func CreateReferensiService(c *fiber.Ctx, db *sql.DB) error {
+	jenis := parseJenisReferensi(c)
+	if jenis == "" {
+		return jenisReferensiTidakValid(c)
+	}
+
+	var req model.ReferensiRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	refRepo := repository.NewReferensiRepository(db)
+	if status, msg := validateReferensiRequest(refRepo, jenis, 0, &req); msg != "" {
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	ref, err := refRepo.Create(jenis, req)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menambah referensi. Pastikan kode belum digunakan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"success": true,
+		"message": "Referensi berhasil ditambahkan",
+		"data":    ref,
+	})
+}
+
+// UpdateReferensiService untuk mengubah entri kosakata (admin only). Mengganti nama ikut
+// memperbarui data yang memakai nama lama; nama lama tetap dikenali sebagai alias.
+func UpdateReferensiService(c *fiber.Ctx, db *sql.DB) error {
+	jenis := parseJenisReferensi(c)
+	if jenis == "" {
+		return jenisReferensiTidakValid(c)
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	var req model.ReferensiRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	refRepo := repository.NewReferensiRepository(db)
+	if existing, err := refRepo.GetByID(id); err != nil || existing.Jenis != jenis {
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"message": "Referensi tidak ditemukan",
+		})
+	}
+	if status, msg := validateReferensiRequest(refRepo, jenis, id, &req); msg != "" {
+		return c.Status(status).JSON(fiber.Map{
+			"success": false,
+			"message": msg,
+		})
+	}
+
+	ref, err := refRepo.Update(id, req)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Referensi tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengupdate referensi. Pastikan kode belum digunakan",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Referensi berhasil diupdate",
+		"data":    ref,
+	})
+}
+
+// validateReferensiRequest memeriksa nama, induk sesuai jenis, dan nama yang belum dipakai
+// di bawah induk yang sama. Mengembalikan status HTTP dan pesan error (kosong jika valid).
+func validateReferensiRequest(refRepo repository.ReferensiRepository, jenis string, id int, req *model.ReferensiRequest) (int, string) {
+	req.Nama = strings.Join(strings.Fields(req.Nama), " ")
+	req.Kode = strings.TrimSpace(req.Kode)
+	if req.Nama == "" {
+		return 400, "Nama referensi harus diisi"
+	}
+
+	indukJenis := model.ReferensiIndukJenis[jenis]
+	switch {
+	case indukJenis == "" && req.IndukID != 0:
+		return 400, fmt.Sprintf("Referensi %s tidak memiliki induk", jenis)
+	case indukJenis != jenis && indukJenis != "" && req.IndukID == 0:
+		return 400, fmt.Sprintf("induk_id (%s) harus diisi", indukJenis)
+	case req.IndukID != 0 && req.IndukID == id:
+		return 400, "Referensi tidak boleh menjadi induk dirinya sendiri"
+	}
+	if req.IndukID != 0 {
+		induk, err := refRepo.GetByID(req.IndukID)
+		if err != nil || induk.Jenis != indukJenis {
+			return 400, fmt.Sprintf("Induk harus berupa %s yang terdaftar", indukJenis)
+		}
+		if jenis == model.ReferensiBidangIndustri && induk.IndukID != 0 {
+			return 400, "Sub-bidang industri hanya boleh satu tingkat di bawah bidang utama"
+		}
+	}
+
+	existing, err := refRepo.FindByNama(jenis, req.IndukID, req.Nama)
+	if err != nil && err != sql.ErrNoRows {
+		return 500, "Gagal memeriksa nama referensi"
+	}
+	if err == nil && existing.ID != id {
+		return 409, fmt.Sprintf("Nama \"%s\" sudah terdaftar (ID %d)", req.Nama, existing.ID)
+	}
+	return 0, ""
+}
+
+// DeleteReferensiService untuk menghapus entri kosakata yang tidak punya turunan (admin only).
+// Data yang sudah memakai nama entri tidak berubah; nonaktifkan entri jika masih dipakai.
+func DeleteReferensiService(c *fiber.Ctx, db *sql.DB) error {
+	jenis := parseJenisReferensi(c)
+	if jenis == "" {
+		return jenisReferensiTidakValid(c)
+	}
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	refRepo := repository.NewReferensiRepository(db)
+	if existing, err := refRepo.GetByID(id); err != nil || existing.Jenis != jenis {
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"message": "Referensi tidak ditemukan",
+		})
+	}
+	total, err := refRepo.CountAnak(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memeriksa turunan referensi",
+			"error":   err.Error(),
+		})
+	}
+	if total > 0 {
+		return c.Status(409).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Referensi masih menjadi induk %d entri lain", total),
+		})
+	}
+
+	if err := refRepo.Delete(id); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Referensi tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menghapus referensi",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Referensi berhasil dihapus",
+	})
+}
+
+// GetLegacyReferensiService untuk melihat nilai lama yang belum kanonis beserta saran pemetaannya (admin only)
+func GetLegacyReferensiService(c *fiber.Ctx, db *sql.DB) error {
+	jenis := parseJenisReferensi(c)
+	if jenis == "" {
+		return jenisReferensiTidakValid(c)
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "50"))
+	if limit < 1 {
+		limit = 50
+	}
+
+	list, err := repository.NewReferensiRepository(db).FindLegacy(jenis, limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil nilai lama",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Nilai lama berhasil diambil",
+		"data":    list,
+	})
+}
+
+// MapLegacyReferensiService untuk memetakan nilai lama ke entri kanonis (admin only).
+// Nilai lama disimpan sebagai alias sehingga input berikutnya ikut dikenali.
+func MapLegacyReferensiService(c *fiber.Ctx, db *sql.DB) error {
+	jenis := parseJenisReferensi(c)
+	if jenis == "" {
+		return jenisReferensiTidakValid(c)
+	}
+
+	var req model.MappingLegacyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+	nilaiLama := []string{}
+	for _, nilai := range req.NilaiLama {
+		if nilai = strings.TrimSpace(nilai); nilai != "" && !containsString(nilaiLama, nilai) {
+			nilaiLama = append(nilaiLama, nilai)
+		}
+	}
+	if req.ReferensiID == 0 || len(nilaiLama) == 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "referensi_id dan nilai_lama harus diisi",
+		})
+	}
+
+	refRepo := repository.NewReferensiRepository(db)
+	ref, err := refRepo.GetByID(req.ReferensiID)
+	if err != nil || ref.Jenis != jenis {
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"message": "Referensi tujuan tidak ditemukan",
+		})
+	}
+	for _, nilai := range nilaiLama {
+		existing, err := refRepo.Resolve(jenis, nilai)
+		if err == nil && existing.ID != ref.ID && model.NormalizeReferensi(existing.Nama) == model.NormalizeReferensi(nilai) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("\"%s\" adalah nama entri lain (ID %d), tidak bisa dijadikan alias", nilai, existing.ID),
+			})
+		}
+	}
+
+	result, err := refRepo.MapLegacy(ref.ID, nilaiLama)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memetakan nilai lama",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Nilai lama berhasil dipetakan",
+		"data":    result,
+	})
+}
+
+// RapikanLegacyReferensiService untuk mengganti nilai yang sudah dikenali (beda huruf besar/spasi
+// atau sama dengan alias) dengan nama kanonisnya (admin only)
+func RapikanLegacyReferensiService(c *fiber.Ctx, db *sql.DB) error {
+	jenis := parseJenisReferensi(c)
+	if jenis == "" {
+		return jenisReferensiTidakValid(c)
+	}
+
+	total, err := repository.NewReferensiRepository(db).CanonicalizeLegacy(jenis)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal merapikan nilai lama",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Nilai lama berhasil dirapikan",
+		"data":    fiber.Map{"baris_diperbarui": total},
+	})
+}
+
+// resolveReferensi mengganti nilai dengan nama kanonis dari kosakata jenis (cocok nama atau alias).
+// Nilai kosong dilewati dan kosakata yang belum berisi entri aktif menerima nilai apa pun.
+// Mengembalikan pesan error jika nilai tidak dikenali.
+func resolveReferensi(db *sql.DB, jenis, field string, nilai *string) string {
+	if strings.TrimSpace(*nilai) == "" {
+		return ""
+	}
+	refRepo := repository.NewReferensiRepository(db)
+	ref, err := refRepo.Resolve(jenis, *nilai)
+	if err == nil {
+		*nilai = ref.Nama
+		return ""
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Sprintf("Gagal memeriksa %s", field)
+	}
+	if total, err := refRepo.CountAktif(jenis); err == nil && total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s \"%s\" tidak dikenali, pilih dari /api/referensi/%s", field, *nilai, jenis)
+}
+
+// resolvePekerjaanReferensi menyeragamkan bidang industri dan lokasi kerja pekerjaan dengan kosakata
+func resolvePekerjaanReferensi(db *sql.DB, bidangIndustri, lokasiKerja *string) string {
+	if msg := resolveReferensi(db, model.ReferensiBidangIndustri, "bidang_industri", bidangIndustri); msg != "" {
+		return msg
+	}
+	return resolveReferensi(db, model.ReferensiKota, "lokasi_kerja", lokasiKerja)
+}
@@ -0,0 +1,525 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/middleware"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// updateAlumniWithRevisi mengupdate alumni dan mencatat revisinya di transaksi tx;
+// mengembalikan nilai alumni sebelum dan sesudah update
+func updateAlumniWithRevisi(tx *sql.Tx, id int, req model.UpdateAlumniRequest, aksi string, revertDari, actorID int) (model.Alumni, model.Alumni, error) {
+	alumniRepo := repository.NewAlumniRepositoryTx(tx)
+	current, err := alumniRepo.GetByID(id)
+	if err != nil {
+		return model.Alumni{}, model.Alumni{}, err
+	}
+	alumni, err := alumniRepo.Update(id, req)
+	if err != nil {
+		return current, model.Alumni{}, err
+	}
+	_, err = repository.NewRevisiRepositoryTx(tx).Record(model.RevisiEntitasAlumni, id, current, alumni, aksi, revertDari, actorID)
+	return current, alumni, err
+}
+
+// updatePekerjaanWithRevisi mengupdate pekerjaan, menambah riwayat status jika status berubah,
+// dan mencatat revisinya di transaksi tx
+func updatePekerjaanWithRevisi(tx *sql.Tx, current model.PekerjaanAlumni, req model.UpdatePekerjaanAlumniRequest, aksi string, revertDari, actorID int) (model.PekerjaanAlumni, error) {
+	pekerjaanRepo := repository.NewPekerjaanAlumniRepositoryTx(tx)
+	pekerjaan, err := pekerjaanRepo.Update(current.ID, req)
+	if err != nil {
+		return model.PekerjaanAlumni{}, err
+	}
+	if pekerjaan.StatusPekerjaan != current.StatusPekerjaan {
+		history := model.StatusPekerjaanHistory{
+			PekerjaanID: current.ID,
+			StatusLama:  current.StatusPekerjaan,
+			StatusBaru:  pekerjaan.StatusPekerjaan,
+			DiubahOleh:  actorID,
+		}
+		if revertDari > 0 {
+			history.Catatan = fmt.Sprintf("Revert ke versi %d", revertDari)
+		}
+		if err := pekerjaanRepo.AddStatusHistory(history); err != nil {
+			return model.PekerjaanAlumni{}, err
+		}
+	}
+	_, err = repository.NewRevisiRepositoryTx(tx).Record(model.RevisiEntitasPekerjaan, current.ID, current, pekerjaan, aksi, revertDari, actorID)
+	return pekerjaan, err
+}
+
+// revisiEntitasLabel nama entitas untuk pesan respons
+var revisiEntitasLabel = map[string]string{
+	model.RevisiEntitasAlumni:    "Alumni",
+	model.RevisiEntitasPekerjaan: "Pekerjaan alumni",
+}
+
+// loadCurrentRevisiRecord mengambil record yang sedang aktif sebagai revisi tanpa versi,
+// untuk dibandingkan dengan revisi tersimpan
+func loadCurrentRevisiRecord(db *sql.DB, entitas string, id int) (interface{}, error) {
+	if entitas == model.RevisiEntitasAlumni {
+		return repository.NewAlumniRepository(db).GetByID(id)
+	}
+	return repository.NewPekerjaanAlumniRepository(db).GetByID(id, model.ScopeActive)
+}
+
+// GetRevisiAlumniService untuk mengambil daftar revisi alumni, terbaru lebih dulu (admin only)
+func GetRevisiAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	return getRevisiList(c, db, model.RevisiEntitasAlumni)
+}
+
+// GetRevisiPekerjaanAlumniService untuk mengambil daftar revisi pekerjaan alumni, terbaru lebih dulu (admin only)
+func GetRevisiPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	return getRevisiList(c, db, model.RevisiEntitasPekerjaan)
+}
+
+func getRevisiList(c *fiber.Ctx, db *sql.DB, entitas string) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	list, err := repository.NewRevisiRepository(db).GetAll(entitas, id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat revisi",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Riwayat revisi berhasil diambil",
+		"data":    list,
+	})
+}
+
+// GetRevisiAlumniByVersiService untuk mengambil satu versi alumni (admin only)
+func GetRevisiAlumniByVersiService(c *fiber.Ctx, db *sql.DB) error {
+	return getRevisiByVersi(c, db, model.RevisiEntitasAlumni)
+}
+
+// GetRevisiPekerjaanAlumniByVersiService untuk mengambil satu versi pekerjaan alumni (admin only)
+func GetRevisiPekerjaanAlumniByVersiService(c *fiber.Ctx, db *sql.DB) error {
+	return getRevisiByVersi(c, db, model.RevisiEntitasPekerjaan)
+}
+
+func getRevisiByVersi(c *fiber.Ctx, db *sql.DB, entitas string) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	versi, err := strconv.Atoi(c.Params("versi"))
+	if err != nil || versi < 1 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Versi tidak valid",
+		})
+	}
+
+	rev, err := repository.NewRevisiRepository(db).GetByVersi(entitas, id, versi)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Revisi tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil revisi",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Revisi berhasil diambil",
+		"data":    rev,
+	})
+}
+
+// GetDiffRevisiAlumniService untuk membandingkan dua versi alumni: ?dari=1&ke=3, ke kosong berarti versi terakhir (admin only)
+func GetDiffRevisiAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	return getDiffRevisi(c, db, model.RevisiEntitasAlumni)
+}
+
+// GetDiffRevisiPekerjaanAlumniService untuk membandingkan dua versi pekerjaan alumni (admin only)
+func GetDiffRevisiPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	return getDiffRevisi(c, db, model.RevisiEntitasPekerjaan)
+}
+
+func getDiffRevisi(c *fiber.Ctx, db *sql.DB, entitas string) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	dari, err := strconv.Atoi(c.Query("dari"))
+	if err != nil || dari < 1 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Parameter dari harus berisi nomor versi",
+		})
+	}
+	ke, err := strconv.Atoi(c.Query("ke", "0"))
+	if err != nil || ke < 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Parameter ke harus berisi nomor versi",
+		})
+	}
+
+	revisiRepo := repository.NewRevisiRepository(db)
+	lama, err := revisiRepo.GetByVersi(entitas, id, dari)
+	var baru model.Revisi
+	if err == nil {
+		if ke == 0 {
+			baru, err = revisiRepo.GetLatest(entitas, id)
+		} else {
+			baru, err = revisiRepo.GetByVersi(entitas, id, ke)
+		}
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Revisi tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil revisi",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Perbandingan revisi berhasil diambil",
+		"data": model.RevisiDiff{
+			Entitas:   entitas,
+			EntitasID: id,
+			Dari:      lama.Versi,
+			Ke:        baru.Versi,
+			Perubahan: model.DiffRevisiData(lama.Data, baru.Data),
+		},
+	})
+}
+
+// GetAlumniPerTanggalService untuk melihat data alumni seperti pada akhir tanggal tertentu: ?tanggal=YYYY-MM-DD (admin only)
+func GetAlumniPerTanggalService(c *fiber.Ctx, db *sql.DB) error {
+	return getRevisiPerTanggal(c, db, model.RevisiEntitasAlumni)
+}
+
+// GetPekerjaanAlumniPerTanggalService untuk melihat data pekerjaan alumni seperti pada akhir tanggal tertentu (admin only)
+func GetPekerjaanAlumniPerTanggalService(c *fiber.Ctx, db *sql.DB) error {
+	return getRevisiPerTanggal(c, db, model.RevisiEntitasPekerjaan)
+}
+
+func getRevisiPerTanggal(c *fiber.Ctx, db *sql.DB, entitas string) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	tanggal, err := model.ParseTanggal(c.Query("tanggal"))
+	if err != nil || tanggal.IsZero() {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Parameter tanggal harus diisi dengan format YYYY-MM-DD",
+		})
+	}
+	// akhir hari tanggal tersebut menurut waktu lokal server, sama seperti created_at yang disimpan
+	batas := time.Date(tanggal.Year(), tanggal.Month(), tanggal.Day(), 23, 59, 59, 999999999, time.Local)
+
+	rev, err := repository.NewRevisiRepository(db).GetAsOf(entitas, id, batas)
+	if err == sql.ErrNoRows {
+		// Record tanpa revisi sama sekali belum pernah diubah sejak dibuat, sehingga nilainya saat ini berlaku
+		// sejak created_at
+		_, errLatest := repository.NewRevisiRepository(db).GetLatest(entitas, id)
+		if errLatest == sql.ErrNoRows {
+			return getRecordTanpaRevisi(c, db, entitas, id, batas)
+		}
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("Tidak ada data %s pada tanggal %s", revisiEntitasLabel[entitas], tanggal),
+		})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil revisi",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": fmt.Sprintf("Data %s per %s berhasil diambil", revisiEntitasLabel[entitas], tanggal),
+		"data":    rev,
+	})
+}
+
+func getRecordTanpaRevisi(c *fiber.Ctx, db *sql.DB, entitas string, id int, batas time.Time) error {
+	record, err := loadCurrentRevisiRecord(db, entitas, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("%s tidak ditemukan", revisiEntitasLabel[entitas]),
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data",
+			"error":   err.Error(),
+		})
+	}
+
+	var createdAt time.Time
+	switch r := record.(type) {
+	case model.Alumni:
+		createdAt = r.CreatedAt
+	case model.PekerjaanAlumni:
+		createdAt = r.CreatedAt
+	}
+	if createdAt.After(batas) {
+		return c.Status(404).JSON(fiber.Map{
+			"success": false,
+			"message": fmt.Sprintf("%s belum ada pada tanggal tersebut", revisiEntitasLabel[entitas]),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": fmt.Sprintf("%s belum pernah diubah, data saat ini berlaku", revisiEntitasLabel[entitas]),
+		"data":    record,
+	})
+}
+
+// RevertAlumniService untuk mengembalikan alumni ke nilai versi :versi; revert dicatat sebagai revisi baru (admin only)
+func RevertAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	id, versi, rev, ok, err := loadRevisiUntukRevert(c, db, model.RevisiEntitasAlumni)
+	if !ok {
+		return err
+	}
+
+	var data model.Alumni
+	if err := decodeRevisiData(rev, &data); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Data revisi tidak dapat dibaca",
+			"error":   err.Error(),
+		})
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memulai transaksi alumni",
+			"error":   err.Error(),
+		})
+	}
+	defer tx.Rollback()
+
+	actorID, _ := c.Locals("user_id").(int)
+	sebelum, alumni, err := updateAlumniWithRevisi(tx, id, model.UpdateAlumniRequest{
+		Nama:       data.Nama,
+		Jurusan:    data.Jurusan,
+		Angkatan:   data.Angkatan,
+		TahunLulus: data.TahunLulus,
+		Email:      data.Email,
+		NoTelepon:  data.NoTelepon,
+		Alamat:     data.Alamat,
+	}, model.RevisiAksiRevert, versi, actorID)
+	middleware.Audit(c).Sebelum = sebelum
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan",
+			})
+		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Email pada versi ini sudah dipakai alumni lain",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengembalikan alumni ke versi sebelumnya",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": fmt.Sprintf("Alumni berhasil dikembalikan ke versi %d", versi),
+		"data":    alumni,
+	})
+}
+
+// RevertPekerjaanAlumniService untuk mengembalikan pekerjaan alumni ke nilai versi :versi (admin only).
+// Aturan transisi status tidak berlaku untuk revert, tetapi konsistensi dengan data alumni tetap diperiksa.
+func RevertPekerjaanAlumniService(c *fiber.Ctx, db *sql.DB) error {
+	id, versi, rev, ok, err := loadRevisiUntukRevert(c, db, model.RevisiEntitasPekerjaan)
+	if !ok {
+		return err
+	}
+
+	var data model.PekerjaanAlumni
+	if err := decodeRevisiData(rev, &data); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Data revisi tidak dapat dibaca",
+			"error":   err.Error(),
+		})
+	}
+
+	current, err := repository.NewPekerjaanAlumniRepository(db).GetByID(id, model.ScopeActive)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pekerjaan alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	// Perusahaan yang sudah dihapus atau digabung dicari ulang dari nama perusahaan
+	if applyPerusahaan(db, data.PerusahaanID, &data.NamaPerusahaan, &data.BidangIndustri, &data.LokasiKerja) != "" {
+		data.PerusahaanID = 0
+	}
+	warnings, ok, err := checkPekerjaanKonsistensi(c, db, pekerjaanKonsistensi{
+		AlumniID:            current.AlumniID,
+		PekerjaanID:         id,
+		StatusPekerjaan:     data.StatusPekerjaan,
+		TanggalMulaiKerja:   data.TanggalMulaiKerja,
+		TanggalSelesaiKerja: data.TanggalSelesaiKerja,
+	})
+	if !ok {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal memulai transaksi pekerjaan alumni",
+			"error":   err.Error(),
+		})
+	}
+	defer tx.Rollback()
+
+	actorID, _ := c.Locals("user_id").(int)
+	middleware.Audit(c).Sebelum = current
+	pekerjaan, err := updatePekerjaanWithRevisi(tx, current, model.UpdatePekerjaanAlumniRequest{
+		PerusahaanID:        data.PerusahaanID,
+		NamaPerusahaan:      data.NamaPerusahaan,
+		PosisiJabatan:       data.PosisiJabatan,
+		BidangIndustri:      data.BidangIndustri,
+		LokasiKerja:         data.LokasiKerja,
+		GajiRange:           data.GajiRange,
+		Gaji:                data.Gaji,
+		TanggalMulaiKerja:   data.TanggalMulaiKerja,
+		TanggalSelesaiKerja: data.TanggalSelesaiKerja,
+		StatusPekerjaan:     data.StatusPekerjaan,
+		DeskripsiPekerjaan:  data.DeskripsiPekerjaan,
+	}, model.RevisiAksiRevert, versi, actorID)
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pekerjaan alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengembalikan pekerjaan alumni ke versi sebelumnya",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success":  true,
+		"message":  fmt.Sprintf("Pekerjaan alumni berhasil dikembalikan ke versi %d", versi),
+		"data":     pekerjaan,
+		"warnings": warnings,
+	})
+}
+
+// loadRevisiUntukRevert membaca :id dan :versi lalu mengambil revisinya.
+// ok=false berarti respons sudah dikirim dan err adalah hasil pengirimannya.
+func loadRevisiUntukRevert(c *fiber.Ctx, db *sql.DB, entitas string) (id, versi int, rev model.Revisi, ok bool, err error) {
+	id, err = strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return 0, 0, rev, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+	versi, err = strconv.Atoi(c.Params("versi"))
+	if err != nil || versi < 1 {
+		return 0, 0, rev, false, c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Versi tidak valid",
+		})
+	}
+
+	rev, err = repository.NewRevisiRepository(db).GetByVersi(entitas, id, versi)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, rev, false, c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Revisi tidak ditemukan",
+			})
+		}
+		return 0, 0, rev, false, c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil revisi",
+			"error":   err.Error(),
+		})
+	}
+	return id, versi, rev, true, nil
+}
+
+// decodeRevisiData mengisi record dari data revisi
+func decodeRevisiData(rev model.Revisi, record interface{}) error {
+	raw, err := json.Marshal(rev.Data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, record)
+}
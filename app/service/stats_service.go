@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/utils"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTracerStudyStatsService untuk mengambil statistik tracer study (keterserapan kerja, masa tunggu, sebaran pekerjaan)
+func GetTracerStudyStatsService(c *fiber.Ctx, db *sql.DB) error {
+	filter := parseStatsFilter(c)
+
+	stats, err := buildTracerStudyStats(repository.NewStatsRepository(db), filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil statistik tracer study",
+			"error":   err.Error(),
+		})
+	}
+
+	if strings.ToLower(c.Query("format", "json")) == "csv" {
+		data, err := statsToCSV(stats)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Gagal membuat file CSV statistik",
+				"error":   err.Error(),
+			})
+		}
+		c.Set(fiber.HeaderContentType, "text/csv")
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="tracer-study-stats.csv"`)
+		return c.Send(data)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Statistik tracer study berhasil diambil",
+		"data":    stats,
+	})
+}
+
+// parseStatsFilter membaca filter angkatan, tahun_lulus, jurusan dan jenjang dari query
+func parseStatsFilter(c *fiber.Ctx) model.StatsFilter {
+	angkatan, _ := strconv.Atoi(c.Query("angkatan", "0"))
+	tahunLulus, _ := strconv.Atoi(c.Query("tahun_lulus", "0"))
+	return model.StatsFilter{
+		Angkatan:   angkatan,
+		TahunLulus: tahunLulus,
+		Jurusan:    c.Query("jurusan", ""),
+		Jenjang:    c.Query("jenjang", ""),
+	}
+}
+
+// loadGajiKurs membaca kurs mata uang asing ke IDR dari GAJI_KURS_IDR (format "USD=16000,SGD=12000").
+// Entri yang tidak valid diabaikan sehingga gaji dengan mata uang itu masuk kelompok tanpa kurs.
+func loadGajiKurs() map[string]float64 {
+	kurs := map[string]float64{}
+	for _, entri := range strings.Split(utils.GetEnv("GAJI_KURS_IDR", ""), ",") {
+		kode, nilai, ok := strings.Cut(entri, "=")
+		if !ok {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(nilai), 64)
+		kode = strings.ToUpper(strings.TrimSpace(kode))
+		if err != nil || rate <= 0 || kode == "" || kode == model.GajiMataUangDefault {
+			continue
+		}
+		kurs[kode] = rate
+	}
+	return kurs
+}
+
+func buildTracerStudyStats(statsRepo repository.StatsRepository, filter model.StatsFilter) (model.StatsResponse, error) {
+	stats := model.StatsResponse{Filter: filter}
+
+	overall, err := statsRepo.GetEmploymentRate("", filter)
+	if err != nil {
+		return stats, err
+	}
+	if len(overall) > 0 {
+		stats.Keseluruhan = overall[0]
+	}
+
+	if stats.PerAngkatan, err = statsRepo.GetEmploymentRate("angkatan", filter); err != nil {
+		return stats, err
+	}
+	if stats.PerTahunLulus, err = statsRepo.GetEmploymentRate("tahun_lulus", filter); err != nil {
+		return stats, err
+	}
+	if stats.PerJurusan, err = statsRepo.GetEmploymentRate("jurusan", filter); err != nil {
+		return stats, err
+	}
+	if stats.PerJenjang, err = statsRepo.GetEmploymentRate("jenjang", filter); err != nil {
+		return stats, err
+	}
+	if stats.RataRataMasaTungguTahun, err = statsRepo.GetAverageWaitingYears(filter); err != nil {
+		return stats, err
+	}
+	if stats.BidangIndustri, err = statsRepo.GetDistribution("bidang_industri", filter); err != nil {
+		return stats, err
+	}
+	if stats.LokasiKerja, err = statsRepo.GetDistribution("lokasi_kerja", filter); err != nil {
+		return stats, err
+	}
+	if stats.GajiRange, err = statsRepo.GetGajiDistribution(loadGajiKurs(), filter); err != nil {
+		return stats, err
+	}
+	if stats.TopPerusahaan, err = statsRepo.GetTopPerusahaan(10, false, filter); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// statsToCSV meratakan seluruh statistik menjadi satu tabel: kategori, nilai, jumlah, total, persentase
+func statsToCSV(stats model.StatsResponse) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"kategori", "nilai", "jumlah", "total", "persentase"})
+
+	writeRates := func(kategori string, rates []model.EmploymentRate) {
+		for _, rate := range rates {
+			w.Write([]string{
+				kategori, rate.Grup,
+				strconv.Itoa(rate.AlumniBekerja), strconv.Itoa(rate.TotalAlumni),
+				strconv.FormatFloat(rate.Persentase, 'f', 2, 64),
+			})
+		}
+	}
+	writeDistribution := func(kategori string, items []model.DistributionItem) {
+		total := 0
+		for _, item := range items {
+			total += item.Jumlah
+		}
+		for _, item := range items {
+			w.Write([]string{
+				kategori, item.Nilai,
+				strconv.Itoa(item.Jumlah), strconv.Itoa(total),
+				strconv.FormatFloat(item.Persentase, 'f', 2, 64),
+			})
+		}
+	}
+
+	writeRates("keterserapan", []model.EmploymentRate{stats.Keseluruhan})
+	writeRates("keterserapan_angkatan", stats.PerAngkatan)
+	writeRates("keterserapan_tahun_lulus", stats.PerTahunLulus)
+	writeRates("keterserapan_jurusan", stats.PerJurusan)
+	writeRates("keterserapan_jenjang", stats.PerJenjang)
+	w.Write([]string{"masa_tunggu_tahun", strconv.FormatFloat(stats.RataRataMasaTungguTahun, 'f', 2, 64), "", "", ""})
+	writeDistribution("bidang_industri", stats.BidangIndustri)
+	writeDistribution("lokasi_kerja", stats.LokasiKerja)
+	writeDistribution("gaji_range", stats.GajiRange)
+	for _, t := range stats.TopPerusahaan {
+		w.Write([]string{"top_perusahaan", t.Nama, strconv.Itoa(t.JumlahAlumni), "", ""})
+	}
+
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
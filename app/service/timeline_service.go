@@ -0,0 +1,246 @@
+package service
+
+import (
+	"database/sql"
+	"fmt"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAlumniTimelineService untuk mengambil timeline karir alumni: kelulusan, pekerjaan, jeda, dan transisi status
+func GetAlumniTimelineService(c *fiber.Ctx, db *sql.DB) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "ID tidak valid",
+		})
+	}
+
+	alumni, err := repository.NewAlumniRepository(db).GetByID(id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Alumni tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	if alumni.Pendidikan, err = repository.NewPendidikanAlumniRepository(db).GetByAlumni(id); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat pendidikan alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
+	pekerjaanList, err := pekerjaanRepo.GetByAlumniID(id, model.ScopeActive)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil data pekerjaan alumni",
+			"error":   err.Error(),
+		})
+	}
+	history, err := pekerjaanRepo.GetStatusHistoryByAlumniID(id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil riwayat status pekerjaan",
+			"error":   err.Error(),
+		})
+	}
+
+	list := []model.Alumni{alumni}
+	if err := samarkanAlumni(c, db, list); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menerapkan visibilitas data alumni",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Timeline alumni berhasil diambil",
+		"data":    buildTimeline(list[0], pekerjaanList, history, model.Today()),
+	})
+}
+
+// bulanAntara jumlah bulan penuh dari a sampai b (0 jika b sebelum a)
+func bulanAntara(a, b model.Tanggal) int {
+	bulan := (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+	if b.Day() < a.Day() {
+		bulan--
+	}
+	if bulan < 0 {
+		return 0
+	}
+	return bulan
+}
+
+// akhirPekerjaan batas akhir periode pekerjaan untuk perhitungan: pekerjaan berjalan sampai hari ini,
+// pekerjaan berakhir tanpa tanggal selesai (data lama) dianggap berakhir di tanggal mulainya
+func akhirPekerjaan(p model.PekerjaanAlumni, hariIni model.Tanggal) model.Tanggal {
+	switch {
+	case isPekerjaanBerjalan(p.StatusPekerjaan):
+		return hariIni
+	case p.TanggalSelesaiKerja.IsZero():
+		return p.TanggalMulaiKerja
+	case p.TanggalSelesaiKerja.After(hariIni.Time):
+		return hariIni
+	}
+	return p.TanggalSelesaiKerja
+}
+
+// buildTimeline menyusun timeline dari data alumni, pekerjaan, dan riwayat status; hariIni dipakai
+// sebagai akhir pekerjaan yang masih berjalan
+func buildTimeline(alumni model.Alumni, pekerjaanList []model.PekerjaanAlumni, history []model.StatusPekerjaanHistory, hariIni model.Tanggal) model.AlumniTimeline {
+	timeline := model.AlumniTimeline{
+		Alumni:           alumni,
+		PekerjaanSaatIni: []model.PekerjaanAlumni{},
+		Pekerjaan:        []model.TimelinePekerjaan{},
+		Jeda:             []model.JedaKarir{},
+		Events:           []model.TimelineEvent{},
+	}
+
+	sort.SliceStable(pekerjaanList, func(i, j int) bool {
+		return pekerjaanList[i].TanggalMulaiKerja.Before(pekerjaanList[j].TanggalMulaiKerja.Time)
+	})
+	riwayat := map[int][]model.StatusPekerjaanHistory{}
+	for _, h := range history {
+		riwayat[h.PekerjaanID] = append(riwayat[h.PekerjaanID], h)
+	}
+
+	if alumni.TahunLulus > 0 {
+		timeline.Ringkasan.TanggalLulus = model.NewTanggal(time.Date(alumni.TahunLulus, time.July, 1, 0, 0, 0, 0, time.UTC))
+		timeline.Events = append(timeline.Events, model.TimelineEvent{
+			Tanggal:    timeline.Ringkasan.TanggalLulus,
+			Jenis:      model.TimelineLulus,
+			Judul:      fmt.Sprintf("Lulus dari %s", alumni.Jurusan),
+			Keterangan: fmt.Sprintf("Angkatan %d, lulus %d", alumni.Angkatan, alumni.TahunLulus),
+		})
+	}
+	// kelulusan jenjang lain (misalnya D3 sebelum S1, atau S2); pendidikan utama sudah diwakili event di atas
+	for _, p := range alumni.Pendidikan {
+		if p.IsUtama || p.TahunLulus == nil {
+			continue
+		}
+		timeline.Events = append(timeline.Events, model.TimelineEvent{
+			Tanggal:    model.NewTanggal(time.Date(*p.TahunLulus, time.July, 1, 0, 0, 0, 0, time.UTC)),
+			Jenis:      model.TimelineLulus,
+			Judul:      fmt.Sprintf("Lulus %s %s", p.Jenjang, p.ProgramStudi),
+			Keterangan: fmt.Sprintf("Masuk %d, lulus %d", p.TahunMasuk, *p.TahunLulus),
+		})
+	}
+
+	// cakupan periode kerja yang sudah dilalui, untuk mencari jeda dan menghitung pengalaman tanpa dobel
+	var cakupanMulai, cakupanAkhir model.Tanggal
+	cakupanID := 0
+	totalBulan := 0
+	for _, p := range pekerjaanList {
+		berjalan := isPekerjaanBerjalan(p.StatusPekerjaan)
+		akhir := akhirPekerjaan(p, hariIni)
+		timeline.Pekerjaan = append(timeline.Pekerjaan, model.TimelinePekerjaan{
+			PekerjaanAlumni: p,
+			DurasiBulan:     bulanAntara(p.TanggalMulaiKerja, akhir),
+			Berjalan:        berjalan,
+			RiwayatStatus:   append([]model.StatusPekerjaanHistory{}, riwayat[p.ID]...),
+		})
+		if berjalan {
+			timeline.PekerjaanSaatIni = append(timeline.PekerjaanSaatIni, p)
+		}
+
+		timeline.Events = append(timeline.Events, model.TimelineEvent{
+			Tanggal:     p.TanggalMulaiKerja,
+			Jenis:       model.TimelineMulaiKerja,
+			Judul:       fmt.Sprintf("Mulai bekerja sebagai %s di %s", p.PosisiJabatan, p.NamaPerusahaan),
+			Keterangan:  p.LokasiKerja,
+			PekerjaanID: p.ID,
+		})
+		if !berjalan && !p.TanggalSelesaiKerja.IsZero() {
+			timeline.Events = append(timeline.Events, model.TimelineEvent{
+				Tanggal:     p.TanggalSelesaiKerja,
+				Jenis:       model.TimelineSelesaiKerja,
+				Judul:       fmt.Sprintf("Selesai bekerja di %s", p.NamaPerusahaan),
+				Keterangan:  p.StatusPekerjaan,
+				PekerjaanID: p.ID,
+			})
+		}
+		for _, h := range riwayat[p.ID] {
+			if h.StatusLama == "" {
+				continue
+			}
+			timeline.Events = append(timeline.Events, model.TimelineEvent{
+				Tanggal:     model.NewTanggal(h.CreatedAt),
+				Jenis:       model.TimelineStatus,
+				Judul:       fmt.Sprintf("Status pekerjaan di %s berubah dari %s ke %s", p.NamaPerusahaan, h.StatusLama, h.StatusBaru),
+				Keterangan:  h.Catatan,
+				PekerjaanID: p.ID,
+			})
+		}
+
+		// Pekerjaan yang belum dimulai tidak dihitung sebagai pengalaman maupun jeda
+		if p.TanggalMulaiKerja.After(hariIni.Time) {
+			continue
+		}
+		switch {
+		case cakupanAkhir.IsZero():
+			cakupanMulai, cakupanAkhir, cakupanID = p.TanggalMulaiKerja, akhir, p.ID
+		case p.TanggalMulaiKerja.After(cakupanAkhir.AddDate(0, 0, 1)):
+			jeda := model.JedaKarir{
+				Dari:               cakupanAkhir,
+				Sampai:             p.TanggalMulaiKerja,
+				DurasiBulan:        bulanAntara(cakupanAkhir, p.TanggalMulaiKerja),
+				SebelumPekerjaanID: cakupanID,
+				SesudahPekerjaanID: p.ID,
+			}
+			timeline.Jeda = append(timeline.Jeda, jeda)
+			timeline.Ringkasan.TotalJedaBulan += jeda.DurasiBulan
+			timeline.Events = append(timeline.Events, model.TimelineEvent{
+				Tanggal: jeda.Dari,
+				Jenis:   model.TimelineJeda,
+				Judul:   fmt.Sprintf("Jeda karir %d bulan", jeda.DurasiBulan),
+			})
+			totalBulan += bulanAntara(cakupanMulai, cakupanAkhir)
+			cakupanMulai, cakupanAkhir, cakupanID = p.TanggalMulaiKerja, akhir, p.ID
+		case akhir.After(cakupanAkhir.Time):
+			cakupanAkhir, cakupanID = akhir, p.ID
+		}
+	}
+	if !cakupanAkhir.IsZero() {
+		totalBulan += bulanAntara(cakupanMulai, cakupanAkhir)
+	}
+
+	timeline.Ringkasan.JumlahPekerjaan = len(pekerjaanList)
+	timeline.Ringkasan.TotalPengalamanBulan = totalBulan
+	timeline.Ringkasan.TotalPengalamanTahun = math.Round(float64(totalBulan)/12*10) / 10
+	if alumni.TahunLulus > 0 && len(pekerjaanList) > 0 {
+		pertama := pekerjaanList[0].TanggalMulaiKerja
+		bulan := 0
+		if pertama.Before(timeline.Ringkasan.TanggalLulus.Time) {
+			timeline.Ringkasan.BekerjaSebelumLulus = true
+		} else {
+			bulan = bulanAntara(timeline.Ringkasan.TanggalLulus, pertama)
+		}
+		timeline.Ringkasan.BulanKePekerjaanPertama = &bulan
+	}
+
+	sort.SliceStable(timeline.Events, func(i, j int) bool {
+		return timeline.Events[i].Tanggal.Before(timeline.Events[j].Tanggal.Time)
+	})
+	return timeline
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"hello-fiber/app/repository"
+	"hello-fiber/utils"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// trashPurgeConfig pengaturan penghapusan permanen otomatis untuk trash pekerjaan alumni
+type trashPurgeConfig struct {
+	Retention time.Duration
+	Interval  time.Duration
+	BatchSize int
+}
+
+var (
+	trashPurgeMu      sync.Mutex
+	nextTrashPurgeRun time.Time
+)
+
+// loadTrashPurgeConfig membaca TRASH_RETENTION_DAYS, TRASH_PURGE_INTERVAL_MINUTES dan TRASH_PURGE_BATCH_SIZE.
+// Retensi 0 menonaktifkan penghapusan otomatis.
+func loadTrashPurgeConfig() trashPurgeConfig {
+	retentionDays, err := strconv.Atoi(utils.GetEnv("TRASH_RETENTION_DAYS", "30"))
+	if err != nil || retentionDays < 0 {
+		retentionDays = 30
+	}
+	intervalMinutes, err := strconv.Atoi(utils.GetEnv("TRASH_PURGE_INTERVAL_MINUTES", "60"))
+	if err != nil || intervalMinutes <= 0 {
+		intervalMinutes = 60
+	}
+	batchSize, err := strconv.Atoi(utils.GetEnv("TRASH_PURGE_BATCH_SIZE", "100"))
+	if err != nil || batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return trashPurgeConfig{
+		Retention: time.Duration(retentionDays) * 24 * time.Hour,
+		Interval:  time.Duration(intervalMinutes) * time.Minute,
+		BatchSize: batchSize,
+	}
+}
+
+// StartTrashPurgeScheduler menjalankan penghapusan permanen trash pekerjaan secara berkala di background
+func StartTrashPurgeScheduler(db *sql.DB) {
+	cfg := loadTrashPurgeConfig()
+	if cfg.Retention == 0 {
+		log.Println("Trash purge scheduler dinonaktifkan (TRASH_RETENTION_DAYS=0)")
+		return
+	}
+
+	go func() {
+		pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			setNextTrashPurgeRun(time.Now().Add(cfg.Interval))
+			if total, err := purgeExpiredTrash(db, pekerjaanRepo, cfg); err != nil {
+				log.Println("Error menjalankan trash purge scheduler:", err)
+			} else if total > 0 {
+				log.Printf("Trash purge scheduler menghapus permanen %d pekerjaan alumni\n", total)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// purgeExpiredTrash menghapus trash kedaluwarsa per batch sampai tidak ada lagi yang tersisa, beserta
+// object berkas milik pekerjaan yang terhapus
+func purgeExpiredTrash(db *sql.DB, pekerjaanRepo repository.PekerjaanAlumniRepository, cfg trashPurgeConfig) (int64, error) {
+	var total int64
+	for {
+		purged, berkas, err := pekerjaanRepo.PurgeExpiredTrash(cfg.Retention, cfg.BatchSize)
+		if err != nil {
+			return total, err
+		}
+		hapusObjectBerkasTerhapus(context.Background(), db, berkas)
+		total += purged
+		if purged < int64(cfg.BatchSize) {
+			return total, nil
+		}
+	}
+}
+
+func setNextTrashPurgeRun(t time.Time) {
+	trashPurgeMu.Lock()
+	defer trashPurgeMu.Unlock()
+	nextTrashPurgeRun = t
+}
+
+func getNextTrashPurgeRun() time.Time {
+	trashPurgeMu.Lock()
+	defer trashPurgeMu.Unlock()
+	return nextTrashPurgeRun
+}
+
+// GetTrashPurgePreviewService untuk melihat pekerjaan di trash yang akan dihapus permanen berikutnya (admin only)
+func GetTrashPurgePreviewService(c *fiber.Ctx, db *sql.DB) error {
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit <= 0 {
+		limit = 20
+	}
+
+	cfg := loadTrashPurgeConfig()
+	pekerjaanRepo := repository.NewPekerjaanAlumniRepository(db)
+	candidates, err := pekerjaanRepo.GetPurgeCandidates(cfg.Retention, limit)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil jadwal purge trash pekerjaan alumni by service",
+			"error":   err.Error(),
+		})
+	}
+
+	now := time.Now()
+	expired := 0
+	for _, candidate := range candidates {
+		if !candidate.PurgeAt.After(now) {
+			expired++
+		}
+	}
+
+	meta := fiber.Map{
+		"retention_days": int(cfg.Retention.Hours() / 24),
+		"batch_size":     cfg.BatchSize,
+		"enabled":        cfg.Retention > 0,
+		"expired":        expired,
+	}
+	if next := getNextTrashPurgeRun(); !next.IsZero() {
+		meta["next_run"] = next
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Berhasil mengambil jadwal purge trash pekerjaan alumni by service",
+		"data":    candidates,
+		"meta":    meta,
+	})
+}
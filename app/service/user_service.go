@@ -5,6 +5,7 @@ import (
 	"hello-fiber/app/repository"
 	"hello-fiber/middleware"
 	"log"
+	"strconv"
 	"time"
 	"golang.org/x/crypto/bcrypt"
 	"database/sql"
@@ -54,6 +55,13 @@ func LoginService(c *fiber.Ctx, db *sql.DB) error {
 		return c.Status(400).SendString("Invalid input")
 	}
 
+	// Percobaan login yang gagal juga tercatat di audit log. Email tidak disimpan (field rahasia audit),
+	// yang dicatat adalah blind index-nya serta user yang cocok jika ada.
+	audit := middleware.Audit(c)
+	if bidx, err := repository.BlindIndexEmail(db, loginData.Email); err == nil && bidx != "" {
+		audit.Sesudah = fiber.Map{"email_bidx": bidx}
+	}
+
 	// Membuat repository user
 	userRepo := repository.NewUserRepository(db)
 
@@ -62,6 +70,7 @@ func LoginService(c *fiber.Ctx, db *sql.DB) error {
 	if err != nil {
 		return c.Status(401).SendString("Invalid credentials")
 	}
+	audit.ResourceTipe, audit.ResourceID = "user", strconv.Itoa(user.ID)
 
 	// Verifikasi password
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginData.Password))
@@ -69,6 +78,13 @@ func LoginService(c *fiber.Ctx, db *sql.DB) error {
 		return c.Status(401).SendString("Invalid credentials")
 	}
 
+	// User yang alumninya dipindahkan ke trash dinonaktifkan
+	if !user.IsActive {
+		return c.Status(403).SendString("Account is disabled")
+	}
+
+	audit.ActorID, audit.RoleID = user.ID, user.RoleID
+
 	tokenString, err := middleware.GenerateJWT(user)
 	if err != nil {
 		return c.Status(500).SendString("Error generating token")
@@ -83,8 +99,28 @@ func LoginService(c *fiber.Ctx, db *sql.DB) error {
 			Username: user.Username,
 			Email:    user.Email,
 			RoleID:   user.RoleID,
+			IsActive: user.IsActive,
 		},
 	}
 
 	return c.Status(200).JSON(response)
 }
+
+// getLoggedInAlumni mengambil data alumni yang terhubung (users.alumni_id) dengan user yang sedang login.
+// Mengembalikan sql.ErrNoRows jika user tidak terhubung ke alumni mana pun.
+func getLoggedInAlumni(c *fiber.Ctx, db *sql.DB) (model.Alumni, error) {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return model.Alumni{}, sql.ErrNoRows
+	}
+
+	user, err := repository.NewUserRepository(db).FindByID(userID)
+	if err != nil {
+		return model.Alumni{}, err
+	}
+	if user.AlumniID == 0 || !user.IsActive {
+		return model.Alumni{}, sql.ErrNoRows
+	}
+
+	return repository.NewAlumniRepository(db).GetByID(user.AlumniID)
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"database/sql"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// pemirsaAlumni user yang sedang melihat data alumni; alumniID 0 berarti akun tidak terhubung dengan alumni
+type pemirsaAlumni struct {
+	admin    bool
+	alumniID int
+}
+
+func getPemirsaAlumni(c *fiber.Ctx, db *sql.DB) (pemirsaAlumni, error) {
+	if roleID, _ := c.Locals("role_id").(int); roleID == 1 {
+		return pemirsaAlumni{admin: true}, nil
+	}
+	userID, _ := c.Locals("user_id").(int)
+	user, err := repository.NewUserRepository(db).FindByID(userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return pemirsaAlumni{}, nil
+		}
+		return pemirsaAlumni{}, err
+	}
+	if !user.IsActive {
+		return pemirsaAlumni{}, nil
+	}
+	return pemirsaAlumni{alumniID: user.AlumniID}, nil
+}
+
+// bolehLihat admin dan pemilik data selalu boleh; selain itu mengikuti tingkat visibilitas field
+func (p pemirsaAlumni) bolehLihat(level string, pemilikID int) bool {
+	if p.admin || (p.alumniID != 0 && p.alumniID == pemilikID) {
+		return true
+	}
+	switch level {
+	case model.VisibilitasPublik:
+		return true
+	case model.VisibilitasAlumni:
+		return p.alumniID != 0
+	}
+	return false
+}
+
+// samarkanEmail menyisakan huruf pertama dan domain, misalnya b***@gmail.com
+func samarkanEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return "***"
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// samarkanTelepon menyisakan dua digit pertama dan terakhir
+func samarkanTelepon(telepon string) string {
+	r := []rune(telepon)
+	if len(r) <= 6 {
+		return strings.Repeat("*", len(r))
+	}
+	return string(r[:2]) + strings.Repeat("*", len(r)-4) + string(r[len(r)-2:])
+}
+
+// samarkanAlumni membentuk data kontak alumni sesuai pemirsa: email dan nomor telepon disamarkan, alamat dikosongkan
+func samarkanAlumni(c *fiber.Ctx, db *sql.DB, list []model.Alumni) error {
+	if len(list) == 0 {
+		return nil
+	}
+	pemirsa, err := getPemirsaAlumni(c, db)
+	if err != nil {
+		return err
+	}
+	if pemirsa.admin {
+		return nil
+	}
+
+	ids := make([]int, len(list))
+	for i, a := range list {
+		ids[i] = a.ID
+	}
+	visibilitas, err := repository.NewAlumniVisibilitasRepository(db).GetMany(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range list {
+		a := &list[i]
+		v := visibilitas[a.ID]
+		if a.Email != "" && !pemirsa.bolehLihat(v.Email, a.ID) {
+			a.Email = samarkanEmail(a.Email)
+			a.Disamarkan = append(a.Disamarkan, "email")
+		}
+		if a.NoTelepon != "" && !pemirsa.bolehLihat(v.NoTelepon, a.ID) {
+			a.NoTelepon = samarkanTelepon(a.NoTelepon)
+			a.Disamarkan = append(a.Disamarkan, "no_telepon")
+		}
+		if a.Alamat != "" && !pemirsa.bolehLihat(v.Alamat, a.ID) {
+			a.Alamat = ""
+			a.Disamarkan = append(a.Disamarkan, "alamat")
+		}
+	}
+	return nil
+}
+
+// GetMyVisibilitasService untuk alumni melihat pengaturan visibilitas data kontaknya
+func GetMyVisibilitasService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	visibilitas, err := repository.NewAlumniVisibilitasRepository(db).Get(alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil pengaturan visibilitas",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Pengaturan visibilitas berhasil diambil",
+		"data":    visibilitas,
+	})
+}
+
+// UpdateMyVisibilitasService untuk alumni mengatur siapa yang boleh melihat email, nomor telepon, dan alamatnya
+func UpdateMyVisibilitasService(c *fiber.Ctx, db *sql.DB) error {
+	alumni, err := getLoggedInAlumni(c, db)
+	if err != nil {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Akun ini tidak terhubung dengan data alumni",
+		})
+	}
+
+	var req model.UpdateAlumniVisibilitasRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Request body tidak valid",
+		})
+	}
+
+	visibilitasRepo := repository.NewAlumniVisibilitasRepository(db)
+	visibilitas, err := visibilitasRepo.Get(alumni.ID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal mengambil pengaturan visibilitas",
+			"error":   err.Error(),
+		})
+	}
+
+	errs := []model.FieldError{}
+	fields := []struct {
+		name  string
+		value string
+		dest  *string
+	}{
+		{"email", req.Email, &visibilitas.Email},
+		{"no_telepon", req.NoTelepon, &visibilitas.NoTelepon},
+		{"alamat", req.Alamat, &visibilitas.Alamat},
+		{"foto", req.Foto, &visibilitas.Foto},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		level := strings.ToLower(strings.TrimSpace(f.value))
+		if !model.ValidVisibilitas(level) {
+			errs = append(errs, model.FieldError{Field: f.name, Kode: "tidak_valid", Pesan: f.name + " harus publik, alumni, atau admin"})
+			continue
+		}
+		*f.dest = level
+	}
+	if len(errs) > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Pengaturan visibilitas tidak valid",
+			"errors":  errs,
+		})
+	}
+
+	userID, _ := c.Locals("user_id").(int)
+	saved, err := visibilitasRepo.Save(visibilitas, userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"success": false,
+			"message": "Gagal menyimpan pengaturan visibilitas",
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"message": "Pengaturan visibilitas berhasil disimpan",
+		"data":    saved,
+	})
+}
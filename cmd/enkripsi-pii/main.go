@@ -0,0 +1,94 @@
+// Command enkripsi-pii mengenkripsi kolom PII alumni beserta salinannya di revisi dan alumni_merge.snapshot
+// yang masih plaintext (setelah migrasi 014) dan menjalankan rotasi kunci secara offline:
+//
+//   - kunci_pii yang masih dibungkus kunci master lama dibungkus ulang dengan kunci master aktif. Untuk rotasi
+//     kunci master, set PII_MASTER_KEY/PII_MASTER_KEY_ID ke kunci baru dan PII_MASTER_KEY_LAMA="id:base64".
+//   - -kunci-data-baru membuat kunci data aktif baru; seluruh nilai lalu dienkripsi ulang dengan kunci itu.
+//   - -hapus-kunci-lama menghapus kunci data non-aktif yang sudah tidak dipakai nilai mana pun. Jalankan
+//     paling cepat 5 menit setelah rotasi agar aplikasi yang berjalan sudah memuat kunci data baru.
+//
+// Setiap langkah aman dijalankan ulang.
+//
+//	go run ./cmd/enkripsi-pii [-kunci-data-baru] [-hapus-kunci-lama] [-batch 500] [-dry-run]
+package main
+
+import (
+	"flag"
+	"hello-fiber/app/repository"
+	"hello-fiber/database"
+	"hello-fiber/utils"
+	"log"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	kunciDataBaru := flag.Bool("kunci-data-baru", false, "buat kunci data baru dan enkripsi ulang seluruh data")
+	hapusKunciLama := flag.Bool("hapus-kunci-lama", false, "hapus kunci data non-aktif yang sudah tidak dipakai")
+	batch := flag.Int("batch", 500, "jumlah alumni per transaksi")
+	dryRun := flag.Bool("dry-run", false, "hanya hitung alumni yang perlu dienkripsi tanpa menyimpan")
+	flag.Parse()
+
+	// Kunci master PII harus datang dari luar repository (environment atau file di luar direktori kerja)
+	if err := utils.CekSumberMasterKey(".env"); err != nil {
+		log.Fatal("Error konfigurasi kunci master PII: ", err)
+	}
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	db := database.ConnectDB()
+	defer db.Close()
+
+	if err := repository.InitPIIKeyring(db); err != nil {
+		log.Fatal("Error menyiapkan kunci enkripsi PII: ", err)
+	}
+	kunciRepo := repository.NewKunciPIIRepository(db)
+
+	if *dryRun {
+		total, err := kunciRepo.EnkripsiUlangAlumni(*batch, true)
+		if err != nil {
+			log.Fatal("Error memeriksa data alumni: ", err)
+		}
+		log.Printf("Dry run: %d alumni perlu dienkripsi (ulang)\n", total)
+		salinan, err := kunciRepo.EnkripsiUlangSalinan(*batch, true)
+		if err != nil {
+			log.Fatal("Error memeriksa salinan data alumni: ", err)
+		}
+		log.Printf("Dry run: %d revisi/snapshot merge perlu dienkripsi (ulang)\n", salinan)
+		return
+	}
+
+	rewrapped, err := kunciRepo.RewrapKunci()
+	if err != nil {
+		log.Fatal("Error membungkus ulang kunci: ", err)
+	}
+	log.Printf("Kunci master: %d kunci dibungkus ulang dengan kunci master aktif\n", rewrapped)
+
+	if *kunciDataBaru {
+		id, err := kunciRepo.RotasiKunciData()
+		if err != nil {
+			log.Fatal("Error membuat kunci data baru: ", err)
+		}
+		log.Printf("Kunci data: kunci %d sekarang aktif\n", id)
+	}
+
+	total, err := kunciRepo.EnkripsiUlangAlumni(*batch, false)
+	if err != nil {
+		log.Fatal("Error mengenkripsi data alumni: ", err)
+	}
+	log.Printf("Alumni: %d baris dienkripsi dengan kunci data aktif\n", total)
+
+	salinan, err := kunciRepo.EnkripsiUlangSalinan(*batch, false)
+	if err != nil {
+		log.Fatal("Error mengenkripsi salinan data alumni: ", err)
+	}
+	log.Printf("Salinan: %d revisi/snapshot merge dienkripsi dengan kunci data aktif\n", salinan)
+
+	if *hapusKunciLama {
+		dihapus, err := kunciRepo.HapusKunciDataLama()
+		if err != nil {
+			log.Fatal("Error menghapus kunci data lama: ", err)
+		}
+		log.Printf("Kunci data: %d kunci lama dihapus\n", dihapus)
+	}
+}
@@ -0,0 +1,139 @@
+// Command migrate-pekerjaan merapikan data pekerjaan lama setelah migrasi dijalankan:
+// mengurai gaji_range menjadi kolom gaji terstruktur, melaporkan tanggal lama yang tidak terbaca
+// oleh migrasi 006, menghubungkan pekerjaan ke direktori perusahaan (migrasi 007), dan menyeragamkan
+// jurusan, bidang industri, serta lokasi yang sudah dikenali kosakata referensi (migrasi 008).
+// Nilai yang belum dikenali dipetakan admin lewat /api/referensi/:jenis/legacy. Setiap langkah aman dijalankan ulang.
+//
+//	go run ./cmd/migrate-pekerjaan [-dry-run]
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"hello-fiber/database"
+	"log"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "hanya tampilkan hasil parsing tanpa menyimpan")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Fatal("Error loading .env file")
+	}
+	db := database.ConnectDB()
+	defer db.Close()
+
+	if err := migrateGaji(db, *dryRun); err != nil {
+		log.Fatal("Error migrasi gaji: ", err)
+	}
+	if err := reportTanggalTidakTerbaca(db); err != nil {
+		log.Fatal("Error memeriksa tanggal lama: ", err)
+	}
+	if *dryRun {
+		return
+	}
+
+	linked, err := repository.NewPerusahaanRepository(db).BackfillPekerjaan()
+	if err != nil {
+		log.Fatal("Error menghubungkan pekerjaan ke perusahaan: ", err)
+	}
+	log.Printf("Perusahaan: %d pekerjaan dihubungkan ke direktori perusahaan\n", linked)
+
+	refRepo := repository.NewReferensiRepository(db)
+	for _, jenis := range []string{model.ReferensiProgramStudi, model.ReferensiBidangIndustri, model.ReferensiKota} {
+		total, err := refRepo.CanonicalizeLegacy(jenis)
+		if err != nil {
+			log.Fatal("Error merapikan nilai "+jenis+": ", err)
+		}
+		log.Printf("Referensi %s: %d baris diseragamkan ke nama kanonis\n", jenis, total)
+	}
+}
+
+type gajiLama struct {
+	id        int
+	gajiRange string
+}
+
+// migrateGaji mengisi gaji_min, gaji_max, gaji_mata_uang, gaji_periode dari gaji_range yang belum diurai
+// dan mengganti gaji_range dengan label kanonis. Teks asli tetap ada di pekerjaan_alumni_legacy.
+func migrateGaji(db *sql.DB, dryRun bool) error {
+	rows, err := db.Query(`SELECT id, gaji_range FROM pekerjaan_alumni
+		WHERE gaji_min IS NULL AND COALESCE(TRIM(gaji_range), '') <> ''
+		ORDER BY id`)
+	if err != nil {
+		return err
+	}
+	var list []gajiLama
+	for rows.Next() {
+		var g gajiLama
+		if err := rows.Scan(&g.id, &g.gajiRange); err != nil {
+			rows.Close()
+			return err
+		}
+		list = append(list, g)
+	}
+	rows.Close()
+
+	berhasil, gagal := 0, 0
+	for _, g := range list {
+		gaji, err := model.ParseGajiRange(g.gajiRange)
+		if err != nil {
+			gagal++
+			log.Printf("pekerjaan %d: %v\n", g.id, err)
+			continue
+		}
+		berhasil++
+		if dryRun {
+			log.Printf("pekerjaan %d: %q -> %s\n", g.id, g.gajiRange, gaji)
+			continue
+		}
+
+		var gajiMax interface{}
+		if gaji.Max != 0 {
+			gajiMax = gaji.Max
+		}
+		_, err = db.Exec(`UPDATE pekerjaan_alumni
+			SET gaji_min = $1, gaji_max = $2, gaji_mata_uang = $3, gaji_periode = $4, gaji_range = $5
+			WHERE id = $6`,
+			gaji.Min, gajiMax, gaji.MataUang, gaji.Periode, gaji.String(), g.id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Gaji: %d berhasil diurai, %d tidak dikenali (perbaiki manual)\n", berhasil, gagal)
+	return nil
+}
+
+// reportTanggalTidakTerbaca menampilkan baris yang tanggal aslinya tidak bisa dikonversi;
+// tanggal mulainya sudah diisi tanggal pembuatan data dan tanggal selesainya kosong
+func reportTanggalTidakTerbaca(db *sql.DB) error {
+	rows, err := db.Query(`SELECT pekerjaan_id, COALESCE(tanggal_mulai_kerja, ''), COALESCE(tanggal_selesai_kerja, '')
+		FROM pekerjaan_alumni_legacy
+		WHERE (COALESCE(TRIM(tanggal_mulai_kerja), '') <> '' AND parse_tanggal_lama(tanggal_mulai_kerja) IS NULL)
+		   OR (COALESCE(TRIM(tanggal_selesai_kerja), '') <> '' AND parse_tanggal_lama(tanggal_selesai_kerja) IS NULL)
+		ORDER BY pekerjaan_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	total := 0
+	for rows.Next() {
+		var id int
+		var mulai, selesai string
+		if err := rows.Scan(&id, &mulai, &selesai); err != nil {
+			return err
+		}
+		total++
+		log.Printf("pekerjaan %d: tanggal lama tidak terbaca (mulai %q, selesai %q)\n", id, mulai, selesai)
+	}
+	log.Printf("Tanggal: %d pekerjaan perlu diperiksa manual\n", total)
+	return rows.Err()
+}
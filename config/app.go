@@ -4,7 +4,11 @@ import (
 	// "database/sql"
 	"hello-fiber/route"
 	"hello-fiber/middleware"
+	"hello-fiber/app/service"
+	"hello-fiber/app/repository"
+	"log"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"hello-fiber/database"  // Mengimpor package database
 )
 
@@ -12,14 +16,32 @@ func NewApp() *fiber.App {
 	// Connect to the database
 	db := database.ConnectDB()
 
+	// Kunci enkripsi PII alumni harus siap sebelum request pertama
+	if err := repository.InitPIIKeyring(db); err != nil {
+		log.Fatal("Error menyiapkan kunci enkripsi PII: ", err)
+	}
+
 	// Initialize the Fiber application
-	app := fiber.New()
+	// BodyLimit dinaikkan dari default 4 MB agar unggahan dokumen (UPLOAD_MAX_DOKUMEN_KB, default 10 MB) muat
+	app := fiber.New(fiber.Config{
+		BodyLimit: 12 * 1024 * 1024,
+	})
 
 	// Middleware
+	app.Use(requestid.New())
 	app.Use(middleware.LoggerMiddleware)
 
 	// Set up routes, passing db as a dependency to the route handler
 	route.SetupRoutes(app, db)
 
+	// Background job: hapus permanen trash pekerjaan yang melewati masa retensi
+	service.StartTrashPurgeScheduler(db)
+
+	// Background job: tandai lowongan yang melewati batas lamaran sebagai kedaluwarsa
+	service.StartLowonganExpiryScheduler(db)
+
+	// Background job: coba ulang penghapusan object berkas yang gagal dihapus dari storage
+	service.StartBerkasCleanupScheduler(db)
+
 	return app
 }
\ No newline at end of file
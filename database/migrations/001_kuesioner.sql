@@ -0,0 +1,52 @@
+-- Kuesioner tracer study: definisi kuesioner, bagian, pertanyaan, dan jawaban alumni
+
+CREATE TABLE IF NOT EXISTS kuesioner (
+    id            SERIAL PRIMARY KEY,
+    judul         VARCHAR(255) NOT NULL,
+    deskripsi     TEXT NOT NULL DEFAULT '',
+    periode       INT NOT NULL,
+    angkatan      INT,
+    jurusan       VARCHAR(100),
+    status        VARCHAR(20) NOT NULL DEFAULT 'draft',
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS kuesioner_bagian (
+    id            SERIAL PRIMARY KEY,
+    kuesioner_id  INT NOT NULL REFERENCES kuesioner(id) ON DELETE CASCADE,
+    judul         VARCHAR(255) NOT NULL,
+    urutan        INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS kuesioner_pertanyaan (
+    id            SERIAL PRIMARY KEY,
+    bagian_id     INT NOT NULL REFERENCES kuesioner_bagian(id) ON DELETE CASCADE,
+    pertanyaan    TEXT NOT NULL,
+    tipe          VARCHAR(20) NOT NULL,
+    opsi          JSONB NOT NULL DEFAULT '[]',
+    skala_min     INT NOT NULL DEFAULT 0,
+    skala_max     INT NOT NULL DEFAULT 0,
+    wajib         BOOLEAN NOT NULL DEFAULT FALSE,
+    urutan        INT NOT NULL DEFAULT 0
+);
+
+-- Setiap pengisian ulang pada periode yang sama menambah versi baru
+CREATE TABLE IF NOT EXISTS kuesioner_respon (
+    id            SERIAL PRIMARY KEY,
+    kuesioner_id  INT NOT NULL REFERENCES kuesioner(id) ON DELETE CASCADE,
+    alumni_id     INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    periode       INT NOT NULL,
+    versi         INT NOT NULL,
+    submitted_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (kuesioner_id, alumni_id, periode, versi)
+);
+
+CREATE TABLE IF NOT EXISTS kuesioner_jawaban (
+    id             SERIAL PRIMARY KEY,
+    respon_id      INT NOT NULL REFERENCES kuesioner_respon(id) ON DELETE CASCADE,
+    pertanyaan_id  INT NOT NULL REFERENCES kuesioner_pertanyaan(id) ON DELETE CASCADE,
+    jawaban        JSONB NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_kuesioner_respon_alumni ON kuesioner_respon (kuesioner_id, periode, alumni_id);
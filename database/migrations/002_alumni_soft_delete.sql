@@ -0,0 +1,9 @@
+-- Soft delete alumni: alumni yang dihapus masuk trash, pekerjaan ikut di-trash dan user terkait dinonaktifkan
+
+ALTER TABLE alumni ADD COLUMN IF NOT EXISTS is_delete VARCHAR(10) NOT NULL DEFAULT 'tidak';
+
+-- Menandai pekerjaan yang masuk trash karena alumninya dihapus, agar restore alumni
+-- hanya mengembalikan pekerjaan tersebut (bukan pekerjaan yang dihapus terpisah)
+ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS dihapus_via_alumni BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,10 @@
+-- Waktu dan pelaku pemindahan pekerjaan ke trash, terpisah dari updated_at,
+-- dipakai scheduler untuk menghapus permanen trash yang melewati masa retensi
+
+ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS trashed_at TIMESTAMP;
+ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS trashed_by INT REFERENCES users(id) ON DELETE SET NULL;
+
+-- Data trash lama belum punya trashed_at; gunakan updated_at sebagai perkiraan
+UPDATE pekerjaan_alumni SET trashed_at = updated_at WHERE is_delete = 'hapus' AND trashed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pekerjaan_alumni_trashed_at ON pekerjaan_alumni (trashed_at) WHERE is_delete = 'hapus';
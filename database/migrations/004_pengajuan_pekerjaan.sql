@@ -0,0 +1,18 @@
+-- Pengajuan perubahan pekerjaan oleh alumni yang menunggu persetujuan admin
+
+CREATE TABLE IF NOT EXISTS pengajuan_pekerjaan (
+    id             SERIAL PRIMARY KEY,
+    alumni_id      INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    pekerjaan_id   INT REFERENCES pekerjaan_alumni(id) ON DELETE CASCADE,
+    jenis          VARCHAR(10) NOT NULL,
+    data           JSONB NOT NULL,
+    status         VARCHAR(10) NOT NULL DEFAULT 'pending',
+    diajukan_oleh  INT REFERENCES users(id) ON DELETE SET NULL,
+    ditinjau_oleh  INT REFERENCES users(id) ON DELETE SET NULL,
+    komentar       TEXT NOT NULL DEFAULT '',
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    reviewed_at    TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pengajuan_pekerjaan_status ON pengajuan_pekerjaan (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_pengajuan_pekerjaan_alumni ON pengajuan_pekerjaan (alumni_id);
@@ -0,0 +1,13 @@
+-- Riwayat transisi status_pekerjaan
+
+CREATE TABLE IF NOT EXISTS status_pekerjaan_history (
+    id            SERIAL PRIMARY KEY,
+    pekerjaan_id  INT NOT NULL REFERENCES pekerjaan_alumni(id) ON DELETE CASCADE,
+    status_lama   VARCHAR(30) NOT NULL DEFAULT '',
+    status_baru   VARCHAR(30) NOT NULL,
+    diubah_oleh   INT REFERENCES users(id) ON DELETE SET NULL,
+    catatan       TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_pekerjaan_history_pekerjaan ON status_pekerjaan_history (pekerjaan_id, created_at);
@@ -0,0 +1,64 @@
+-- Tanggal pekerjaan menjadi DATE dan gaji menjadi rentang terstruktur.
+-- Nilai teks lama disimpan di pekerjaan_alumni_legacy sebelum dikonversi.
+-- Setelah migrasi ini, jalankan `go run ./cmd/migrate-pekerjaan` untuk mengurai gaji_range lama
+-- dan melihat baris yang tanggalnya tidak bisa dibaca.
+
+CREATE TABLE IF NOT EXISTS pekerjaan_alumni_legacy (
+    pekerjaan_id          INT PRIMARY KEY REFERENCES pekerjaan_alumni(id) ON DELETE CASCADE,
+    tanggal_mulai_kerja   TEXT,
+    tanggal_selesai_kerja TEXT,
+    gaji_range            TEXT,
+    created_at            TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO pekerjaan_alumni_legacy (pekerjaan_id, tanggal_mulai_kerja, tanggal_selesai_kerja, gaji_range)
+SELECT id, tanggal_mulai_kerja::text, tanggal_selesai_kerja::text, gaji_range
+FROM pekerjaan_alumni
+ON CONFLICT (pekerjaan_id) DO NOTHING;
+
+-- parse_tanggal_lama membaca format yang pernah dipakai (YYYY-MM-DD, DD/MM/YYYY, DD-MM-YYYY,
+-- YYYY/MM/DD, YYYY-MM, MM/YYYY, YYYY); selain itu NULL
+CREATE OR REPLACE FUNCTION parse_tanggal_lama(value TEXT) RETURNS DATE AS $$
+DECLARE
+    v TEXT := btrim(value);
+BEGIN
+    IF v IS NULL OR v = '' THEN
+        RETURN NULL;
+    ELSIF v ~ '^\d{4}-\d{1,2}-\d{1,2}' THEN
+        RETURN to_date(substring(v from '^\d{4}-\d{1,2}-\d{1,2}'), 'YYYY-MM-DD');
+    ELSIF v ~ '^\d{4}/\d{1,2}/\d{1,2}$' THEN
+        RETURN to_date(v, 'YYYY/MM/DD');
+    ELSIF v ~ '^\d{1,2}[/-]\d{1,2}[/-]\d{4}$' THEN
+        RETURN to_date(translate(v, '/', '-'), 'DD-MM-YYYY');
+    ELSIF v ~ '^\d{4}-\d{1,2}$' THEN
+        RETURN to_date(v || '-01', 'YYYY-MM-DD');
+    ELSIF v ~ '^\d{1,2}/\d{4}$' THEN
+        RETURN to_date('01/' || v, 'DD/MM/YYYY');
+    ELSIF v ~ '^\d{4}$' THEN
+        RETURN make_date(v::int, 1, 1);
+    END IF;
+    RETURN NULL;
+EXCEPTION WHEN others THEN
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Tanggal mulai yang tidak terbaca diisi tanggal pembuatan data agar tetap ada nilainya;
+-- nilai aslinya tetap ada di pekerjaan_alumni_legacy
+ALTER TABLE pekerjaan_alumni
+    ALTER COLUMN tanggal_mulai_kerja TYPE DATE
+        USING COALESCE(parse_tanggal_lama(tanggal_mulai_kerja::text), created_at::date),
+    ALTER COLUMN tanggal_selesai_kerja TYPE DATE
+        USING parse_tanggal_lama(tanggal_selesai_kerja::text);
+
+ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS gaji_min BIGINT;
+ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS gaji_max BIGINT;
+ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS gaji_mata_uang VARCHAR(3);
+ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS gaji_periode VARCHAR(10);
+
+ALTER TABLE pekerjaan_alumni DROP CONSTRAINT IF EXISTS chk_pekerjaan_gaji_range;
+ALTER TABLE pekerjaan_alumni ADD CONSTRAINT chk_pekerjaan_gaji_range
+    CHECK (gaji_min IS NULL OR gaji_max IS NULL OR gaji_max >= gaji_min);
+
+CREATE INDEX IF NOT EXISTS idx_pekerjaan_alumni_tanggal_mulai ON pekerjaan_alumni (tanggal_mulai_kerja);
+CREATE INDEX IF NOT EXISTS idx_pekerjaan_alumni_gaji ON pekerjaan_alumni (gaji_mata_uang, gaji_min, gaji_max);
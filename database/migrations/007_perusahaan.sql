@@ -0,0 +1,30 @@
+-- Direktori perusahaan. nama_normal/alias_normal adalah model.NormalizeNamaPerusahaan dari nama,
+-- dipakai untuk pencocokan persis; pg_trgm dipakai untuk pencocokan mirip dan saran duplikat.
+-- Pekerjaan lama dihubungkan ke perusahaan dengan `go run ./cmd/migrate-pekerjaan`.
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS perusahaan (
+    id              SERIAL PRIMARY KEY,
+    nama            VARCHAR(200) NOT NULL,
+    nama_normal     VARCHAR(200) NOT NULL UNIQUE,
+    bidang_industri VARCHAR(100) NOT NULL DEFAULT '',
+    kota            VARCHAR(100) NOT NULL DEFAULT '',
+    website         VARCHAR(255) NOT NULL DEFAULT '',
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS perusahaan_alias (
+    id            SERIAL PRIMARY KEY,
+    perusahaan_id INT NOT NULL REFERENCES perusahaan(id) ON DELETE CASCADE,
+    alias         VARCHAR(200) NOT NULL,
+    alias_normal  VARCHAR(200) NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_perusahaan_nama_trgm ON perusahaan USING gin (nama_normal gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_perusahaan_alias_trgm ON perusahaan_alias USING gin (alias_normal gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_perusahaan_alias_perusahaan ON perusahaan_alias (perusahaan_id);
+
+ALTER TABLE pekerjaan_alumni ADD COLUMN IF NOT EXISTS perusahaan_id INT REFERENCES perusahaan(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_pekerjaan_alumni_perusahaan ON pekerjaan_alumni (perusahaan_id);
@@ -0,0 +1,196 @@
+-- Kosakata terkontrol (controlled vocabulary) yang dikelola admin:
+--   fakultas, program_studi (induk: fakultas)   -> alumni.jurusan
+--   bidang_industri (induk: bidang_industri)     -> pekerjaan_alumni.bidang_industri, perusahaan.bidang_industri
+--   provinsi, kota (induk: provinsi)             -> pekerjaan_alumni.lokasi_kerja, perusahaan.kota
+-- Nilai disimpan sebagai nama kanonis di kolom asal. referensi_alias memetakan nilai lama ke nama kanonis.
+-- Seed: 21 kategori KBLI 2020 beserta beberapa sub-bidang umum, 38 provinsi (kode Kemendagri) dan 98 kota.
+-- Kabupaten, program studi dan fakultas diisi admin lewat /api/referensi.
+
+CREATE TABLE IF NOT EXISTS referensi (
+    id         SERIAL PRIMARY KEY,
+    jenis      VARCHAR(30) NOT NULL,
+    kode       VARCHAR(20),
+    nama       VARCHAR(200) NOT NULL,
+    induk_id   INT REFERENCES referensi(id) ON DELETE RESTRICT,
+    aktif      BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_referensi_nama ON referensi (jenis, COALESCE(induk_id, 0), lower(nama));
+CREATE UNIQUE INDEX IF NOT EXISTS uq_referensi_kode ON referensi (jenis, kode) WHERE kode IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_referensi_nama_trgm ON referensi USING gin (lower(nama) gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS referensi_alias (
+    id           SERIAL PRIMARY KEY,
+    jenis        VARCHAR(30) NOT NULL,
+    alias        VARCHAR(200) NOT NULL,
+    alias_normal VARCHAR(200) NOT NULL,
+    referensi_id INT NOT NULL REFERENCES referensi(id) ON DELETE CASCADE,
+    UNIQUE (jenis, alias_normal)
+);
+
+-- Kategori KBLI 2020
+INSERT INTO referensi (jenis, kode, nama) VALUES
+    ('bidang_industri', 'A', 'Pertanian, Kehutanan dan Perikanan'),
+    ('bidang_industri', 'B', 'Pertambangan dan Penggalian'),
+    ('bidang_industri', 'C', 'Industri Pengolahan'),
+    ('bidang_industri', 'D', 'Pengadaan Listrik, Gas, Uap/Air Panas dan Udara Dingin'),
+    ('bidang_industri', 'E', 'Pengadaan Air, Pengelolaan Sampah dan Daur Ulang'),
+    ('bidang_industri', 'F', 'Konstruksi'),
+    ('bidang_industri', 'G', 'Perdagangan Besar dan Eceran'),
+    ('bidang_industri', 'H', 'Pengangkutan dan Pergudangan'),
+    ('bidang_industri', 'I', 'Penyediaan Akomodasi dan Makan Minum'),
+    ('bidang_industri', 'J', 'Informasi dan Komunikasi'),
+    ('bidang_industri', 'K', 'Aktivitas Keuangan dan Asuransi'),
+    ('bidang_industri', 'L', 'Real Estat'),
+    ('bidang_industri', 'M', 'Aktivitas Profesional, Ilmiah dan Teknis'),
+    ('bidang_industri', 'N', 'Aktivitas Penyewaan, Ketenagakerjaan, Agen Perjalanan dan Penunjang Usaha'),
+    ('bidang_industri', 'O', 'Administrasi Pemerintahan, Pertahanan dan Jaminan Sosial Wajib'),
+    ('bidang_industri', 'P', 'Pendidikan'),
+    ('bidang_industri', 'Q', 'Aktivitas Kesehatan Manusia dan Aktivitas Sosial'),
+    ('bidang_industri', 'R', 'Kesenian, Hiburan dan Rekreasi'),
+    ('bidang_industri', 'S', 'Aktivitas Jasa Lainnya'),
+    ('bidang_industri', 'T', 'Aktivitas Rumah Tangga sebagai Pemberi Kerja'),
+    ('bidang_industri', 'U', 'Aktivitas Badan Internasional dan Ekstra Internasional')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO referensi (jenis, kode, nama, induk_id)
+SELECT 'bidang_industri', v.kode, v.nama, k.id
+FROM (VALUES
+    ('B', 'B-MIGAS', 'Minyak dan Gas Bumi'),
+    ('C', 'C-MAKMIN', 'Makanan dan Minuman'),
+    ('C', 'C-OTO', 'Otomotif'),
+    ('C', 'C-FARMASI', 'Farmasi'),
+    ('C', 'C-TEKSTIL', 'Tekstil dan Garmen'),
+    ('C', 'C-ELEKTRONIK', 'Elektronik'),
+    ('G', 'G-ECOMMERCE', 'E-Commerce'),
+    ('H', 'H-LOGISTIK', 'Logistik'),
+    ('J', 'J-TI', 'Teknologi Informasi'),
+    ('J', 'J-TELKO', 'Telekomunikasi'),
+    ('J', 'J-MEDIA', 'Media dan Penerbitan'),
+    ('K', 'K-BANK', 'Perbankan'),
+    ('K', 'K-ASURANSI', 'Asuransi'),
+    ('K', 'K-FINTECH', 'Teknologi Finansial'),
+    ('M', 'M-KONSULTAN', 'Konsultan'),
+    ('M', 'M-RISET', 'Riset dan Pengembangan'),
+    ('O', 'O-PEMERINTAH', 'Pemerintahan'),
+    ('O', 'O-BUMN', 'Badan Usaha Milik Negara'),
+    ('P', 'P-PT', 'Pendidikan Tinggi'),
+    ('Q', 'Q-RS', 'Rumah Sakit')
+) AS v(induk, kode, nama)
+JOIN referensi k ON k.jenis = 'bidang_industri' AND k.kode = v.induk
+ON CONFLICT DO NOTHING;
+
+-- Provinsi (kode wilayah Kemendagri); 99 untuk pekerjaan di luar negeri
+INSERT INTO referensi (jenis, kode, nama) VALUES
+    ('provinsi', '11', 'Aceh'),
+    ('provinsi', '12', 'Sumatera Utara'),
+    ('provinsi', '13', 'Sumatera Barat'),
+    ('provinsi', '14', 'Riau'),
+    ('provinsi', '15', 'Jambi'),
+    ('provinsi', '16', 'Sumatera Selatan'),
+    ('provinsi', '17', 'Bengkulu'),
+    ('provinsi', '18', 'Lampung'),
+    ('provinsi', '19', 'Kepulauan Bangka Belitung'),
+    ('provinsi', '21', 'Kepulauan Riau'),
+    ('provinsi', '31', 'DKI Jakarta'),
+    ('provinsi', '32', 'Jawa Barat'),
+    ('provinsi', '33', 'Jawa Tengah'),
+    ('provinsi', '34', 'DI Yogyakarta'),
+    ('provinsi', '35', 'Jawa Timur'),
+    ('provinsi', '36', 'Banten'),
+    ('provinsi', '51', 'Bali'),
+    ('provinsi', '52', 'Nusa Tenggara Barat'),
+    ('provinsi', '53', 'Nusa Tenggara Timur'),
+    ('provinsi', '61', 'Kalimantan Barat'),
+    ('provinsi', '62', 'Kalimantan Tengah'),
+    ('provinsi', '63', 'Kalimantan Selatan'),
+    ('provinsi', '64', 'Kalimantan Timur'),
+    ('provinsi', '65', 'Kalimantan Utara'),
+    ('provinsi', '71', 'Sulawesi Utara'),
+    ('provinsi', '72', 'Sulawesi Tengah'),
+    ('provinsi', '73', 'Sulawesi Selatan'),
+    ('provinsi', '74', 'Sulawesi Tenggara'),
+    ('provinsi', '75', 'Gorontalo'),
+    ('provinsi', '76', 'Sulawesi Barat'),
+    ('provinsi', '81', 'Maluku'),
+    ('provinsi', '82', 'Maluku Utara'),
+    ('provinsi', '91', 'Papua'),
+    ('provinsi', '92', 'Papua Barat'),
+    ('provinsi', '93', 'Papua Selatan'),
+    ('provinsi', '94', 'Papua Tengah'),
+    ('provinsi', '95', 'Papua Pegunungan'),
+    ('provinsi', '96', 'Papua Barat Daya'),
+    ('provinsi', '99', 'Luar Negeri')
+ON CONFLICT DO NOTHING;
+
+-- Kota (termasuk lima kota administrasi DKI Jakarta)
+INSERT INTO referensi (jenis, nama, induk_id)
+SELECT 'kota', v.nama, p.id
+FROM (VALUES
+    ('11', 'Banda Aceh'), ('11', 'Sabang'), ('11', 'Langsa'), ('11', 'Lhokseumawe'), ('11', 'Subulussalam'),
+    ('12', 'Medan'), ('12', 'Binjai'), ('12', 'Tebing Tinggi'), ('12', 'Pematangsiantar'), ('12', 'Tanjungbalai'),
+    ('12', 'Sibolga'), ('12', 'Padangsidimpuan'), ('12', 'Gunungsitoli'),
+    ('13', 'Padang'), ('13', 'Bukittinggi'), ('13', 'Padang Panjang'), ('13', 'Pariaman'), ('13', 'Payakumbuh'),
+    ('13', 'Sawahlunto'), ('13', 'Solok'),
+    ('14', 'Pekanbaru'), ('14', 'Dumai'),
+    ('15', 'Jambi'), ('15', 'Sungai Penuh'),
+    ('16', 'Palembang'), ('16', 'Prabumulih'), ('16', 'Pagar Alam'), ('16', 'Lubuklinggau'),
+    ('17', 'Bengkulu'),
+    ('18', 'Bandar Lampung'), ('18', 'Metro'),
+    ('19', 'Pangkalpinang'),
+    ('21', 'Batam'), ('21', 'Tanjungpinang'),
+    ('31', 'Jakarta Pusat'), ('31', 'Jakarta Utara'), ('31', 'Jakarta Barat'), ('31', 'Jakarta Selatan'), ('31', 'Jakarta Timur'),
+    ('32', 'Bandung'), ('32', 'Bekasi'), ('32', 'Bogor'), ('32', 'Cimahi'), ('32', 'Cirebon'), ('32', 'Depok'),
+    ('32', 'Sukabumi'), ('32', 'Tasikmalaya'), ('32', 'Banjar'),
+    ('33', 'Semarang'), ('33', 'Surakarta'), ('33', 'Magelang'), ('33', 'Salatiga'), ('33', 'Pekalongan'), ('33', 'Tegal'),
+    ('34', 'Yogyakarta'),
+    ('35', 'Surabaya'), ('35', 'Malang'), ('35', 'Batu'), ('35', 'Blitar'), ('35', 'Kediri'), ('35', 'Madiun'),
+    ('35', 'Mojokerto'), ('35', 'Pasuruan'), ('35', 'Probolinggo'),
+    ('36', 'Serang'), ('36', 'Cilegon'), ('36', 'Tangerang'), ('36', 'Tangerang Selatan'),
+    ('51', 'Denpasar'),
+    ('52', 'Mataram'), ('52', 'Bima'),
+    ('53', 'Kupang'),
+    ('61', 'Pontianak'), ('61', 'Singkawang'),
+    ('62', 'Palangka Raya'),
+    ('63', 'Banjarmasin'), ('63', 'Banjarbaru'),
+    ('64', 'Samarinda'), ('64', 'Balikpapan'), ('64', 'Bontang'),
+    ('65', 'Tarakan'),
+    ('71', 'Manado'), ('71', 'Bitung'), ('71', 'Tomohon'), ('71', 'Kotamobagu'),
+    ('72', 'Palu'),
+    ('73', 'Makassar'), ('73', 'Parepare'), ('73', 'Palopo'),
+    ('74', 'Kendari'), ('74', 'Baubau'),
+    ('75', 'Gorontalo'),
+    ('81', 'Ambon'), ('81', 'Tual'),
+    ('82', 'Ternate'), ('82', 'Tidore Kepulauan'),
+    ('91', 'Jayapura'),
+    ('96', 'Sorong')
+) AS v(provinsi, nama)
+JOIN referensi p ON p.jenis = 'provinsi' AND p.kode = v.provinsi
+ON CONFLICT DO NOTHING;
+
+-- Penulisan lama yang umum
+INSERT INTO referensi_alias (jenis, alias, alias_normal, referensi_id)
+SELECT v.jenis, v.alias, lower(v.alias), r.id
+FROM (VALUES
+    ('kota', 'Jakarta', 'Jakarta Pusat'),
+    ('kota', 'Solo', 'Surakarta'),
+    ('kota', 'Jogja', 'Yogyakarta'),
+    ('kota', 'Jogjakarta', 'Yogyakarta'),
+    ('kota', 'Pematang Siantar', 'Pematangsiantar'),
+    ('kota', 'Palangkaraya', 'Palangka Raya'),
+    ('bidang_industri', 'IT', 'Teknologi Informasi'),
+    ('bidang_industri', 'Teknologi', 'Teknologi Informasi'),
+    ('bidang_industri', 'Software', 'Teknologi Informasi'),
+    ('bidang_industri', 'Bank', 'Perbankan'),
+    ('bidang_industri', 'Banking', 'Perbankan'),
+    ('bidang_industri', 'Fintech', 'Teknologi Finansial'),
+    ('bidang_industri', 'Manufaktur', 'Industri Pengolahan'),
+    ('bidang_industri', 'Manufacturing', 'Industri Pengolahan'),
+    ('bidang_industri', 'Retail', 'Perdagangan Besar dan Eceran'),
+    ('bidang_industri', 'Government', 'Pemerintahan'),
+    ('bidang_industri', 'BUMN', 'Badan Usaha Milik Negara')
+) AS v(jenis, alias, nama)
+JOIN referensi r ON r.jenis = v.jenis AND r.nama = v.nama
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,33 @@
+-- Deteksi dan penggabungan alumni duplikat.
+-- Alumni yang digabung tidak dihapus: baris tetap ada dengan is_delete = 'hapus' dan digabung_ke = id alumni
+-- tujuan, sehingga tidak muncul di daftar maupun trash dan penggabungan bisa dibatalkan dari alumni_merge.
+
+ALTER TABLE alumni ADD COLUMN IF NOT EXISTS digabung_ke INT REFERENCES alumni(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_alumni_nama_trgm ON alumni USING gin (lower(nama) gin_trgm_ops);
+
+-- Riwayat penggabungan beserta data untuk undo (snapshot: model.AlumniMergeSnapshot)
+CREATE TABLE IF NOT EXISTS alumni_merge (
+    id              SERIAL PRIMARY KEY,
+    survivor_id     INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    duplikat_id     INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    skor            REAL NOT NULL DEFAULT 0,
+    catatan         TEXT NOT NULL DEFAULT '',
+    snapshot        JSONB NOT NULL,
+    digabung_oleh   INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    dibatalkan_at   TIMESTAMP,
+    dibatalkan_oleh INT REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_alumni_merge_survivor ON alumni_merge (survivor_id);
+
+-- Pasangan yang sudah ditinjau admin dan dinyatakan bukan duplikat (alumni_id_a < alumni_id_b)
+CREATE TABLE IF NOT EXISTS alumni_duplikat_diabaikan (
+    alumni_id_a    INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    alumni_id_b    INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    diabaikan_oleh INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (alumni_id_a, alumni_id_b),
+    CHECK (alumni_id_a < alumni_id_b)
+);
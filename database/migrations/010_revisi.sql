@@ -0,0 +1,21 @@
+-- Riwayat revisi alumni dan pekerjaan_alumni.
+-- Setiap revisi menyimpan salinan lengkap field yang bisa diubah (data), sehingga tampilan per tanggal dan
+-- revert cukup membaca satu baris. Record yang sudah ada sebelum migrasi ini mendapat revisi 'awal' berisi
+-- nilai sebelum perubahan pertamanya, dengan created_at = updated_at record saat itu.
+
+CREATE TABLE IF NOT EXISTS revisi (
+    id            SERIAL PRIMARY KEY,
+    entitas       VARCHAR(30) NOT NULL,
+    entitas_id    INT NOT NULL,
+    versi         INT NOT NULL,
+    aksi          VARCHAR(20) NOT NULL,
+    data          JSONB NOT NULL,
+    field_berubah TEXT[] NOT NULL DEFAULT '{}',
+    revert_dari   INT,
+    dibuat_oleh   INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (entitas, entitas_id, versi),
+    CHECK (entitas IN ('alumni', 'pekerjaan_alumni'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_revisi_entitas_waktu ON revisi (entitas, entitas_id, created_at);
@@ -0,0 +1,44 @@
+-- Audit log append-only untuk setiap request yang mengubah data serta login.
+-- Setiap baris menyimpan hash baris sebelumnya (hash_sebelumnya) dan hash dirinya sendiri, sehingga perubahan
+-- atau penghapusan baris di tengah rantai terdeteksi oleh GET /api/audit/verifikasi.
+-- Kolom user tidak memakai foreign key agar entri tetap utuh walaupun user dihapus.
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id              BIGSERIAL PRIMARY KEY,
+    waktu           TIMESTAMP NOT NULL,
+    user_id         INT,
+    role_id         INT,
+    impersonator_id INT,
+    aksi            VARCHAR(30) NOT NULL,
+    resource_tipe   VARCHAR(50) NOT NULL DEFAULT '',
+    resource_id     VARCHAR(100) NOT NULL DEFAULT '',
+    method          VARCHAR(10) NOT NULL,
+    path            TEXT NOT NULL,
+    status          INT NOT NULL,
+    ip              VARCHAR(64) NOT NULL DEFAULT '',
+    request_id      VARCHAR(64) NOT NULL DEFAULT '',
+    sebelum         JSONB,
+    sesudah         JSONB,
+    hash_sebelumnya CHAR(64) NOT NULL,
+    hash            CHAR(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_waktu ON audit_log (waktu);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log (user_id, waktu);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log (resource_tipe, resource_id, waktu);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log bersifat append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_log_append_only ON audit_log;
+CREATE TRIGGER trg_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+DROP TRIGGER IF EXISTS trg_audit_log_no_truncate ON audit_log;
+CREATE TRIGGER trg_audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,29 @@
+-- Permintaan penghapusan data pribadi (UU PDP) oleh alumni.
+-- Setelah disetujui admin, data pribadi alumni dianonimkan sementara data untuk statistik (angkatan, jurusan,
+-- tahun lulus, bidang industri, lokasi, gaji, tanggal kerja) dipertahankan. Tanda terima disimpan permanen.
+-- alumni_id menjadi NULL jika alumni dihapus permanen dari trash; id-nya tetap tercatat di tanda_terima.
+
+ALTER TABLE alumni ADD COLUMN IF NOT EXISTS dianonimkan_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS permintaan_penghapusan (
+    id            SERIAL PRIMARY KEY,
+    alumni_id     INT REFERENCES alumni(id) ON DELETE SET NULL,
+    diajukan_oleh INT REFERENCES users(id) ON DELETE SET NULL,
+    alasan        TEXT NOT NULL DEFAULT '',
+    status        VARCHAR(10) NOT NULL DEFAULT 'pending',
+    ditinjau_oleh INT REFERENCES users(id) ON DELETE SET NULL,
+    komentar      TEXT NOT NULL DEFAULT '',
+    tanda_terima  JSONB,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    reviewed_at   TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_permintaan_penghapusan_status ON permintaan_penghapusan (status, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permintaan_penghapusan_pending
+    ON permintaan_penghapusan (alumni_id) WHERE status = 'pending';
+
+-- tabel yang dibuat sebelum alumni_id nullable: tanda terima tidak ikut terhapus bersama alumni
+ALTER TABLE permintaan_penghapusan ALTER COLUMN alumni_id DROP NOT NULL;
+ALTER TABLE permintaan_penghapusan DROP CONSTRAINT IF EXISTS permintaan_penghapusan_alumni_id_fkey;
+ALTER TABLE permintaan_penghapusan ADD CONSTRAINT permintaan_penghapusan_alumni_id_fkey
+    FOREIGN KEY (alumni_id) REFERENCES alumni(id) ON DELETE SET NULL;
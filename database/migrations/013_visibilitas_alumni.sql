@@ -0,0 +1,15 @@
+-- Pengaturan visibilitas data kontak alumni per field.
+-- publik: semua user yang login, alumni: hanya user yang terhubung dengan data alumni, admin: hanya admin.
+-- Pemilik data dan admin selalu melihat nilai aslinya. Alumni tanpa baris di tabel ini memakai nilai default.
+
+CREATE TABLE IF NOT EXISTS alumni_visibilitas (
+    alumni_id   INT PRIMARY KEY REFERENCES alumni(id) ON DELETE CASCADE,
+    email       VARCHAR(10) NOT NULL DEFAULT 'alumni',
+    no_telepon  VARCHAR(10) NOT NULL DEFAULT 'admin',
+    alamat      VARCHAR(10) NOT NULL DEFAULT 'admin',
+    diubah_oleh INT REFERENCES users(id) ON DELETE SET NULL,
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (email IN ('publik', 'alumni', 'admin')),
+    CHECK (no_telepon IN ('publik', 'alumni', 'admin')),
+    CHECK (alamat IN ('publik', 'alumni', 'admin'))
+);
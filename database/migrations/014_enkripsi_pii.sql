@@ -0,0 +1,33 @@
+-- Enkripsi kolom PII alumni (email, no_telepon, alamat) di level aplikasi.
+-- Nilai disimpan sebagai "pii:<id kunci data>:<base64>" (AES-256-GCM). Kunci data disimpan di kunci_pii
+-- dalam keadaan terbungkus oleh kunci master dari PII_MASTER_KEY / PII_MASTER_KEY_FILE.
+-- email_bidx dan telepon_bidx berisi HMAC nilai yang dinormalisasi untuk pencarian tepat dan deteksi duplikat.
+-- Setelah migrasi ini jalankan `go run ./cmd/enkripsi-pii` untuk mengenkripsi data lama.
+-- Salinan PII di revisi dan alumni_merge.snapshot juga dienkripsi (enkripsi-pii ikut memproses salinan lama);
+-- audit_log tidak menyimpan email, no_telepon, dan alamat.
+
+CREATE TABLE IF NOT EXISTS kunci_pii (
+    id               SERIAL PRIMARY KEY,
+    jenis            VARCHAR(10) NOT NULL CHECK (jenis IN ('data', 'indeks')),
+    master_key_id    VARCHAR(50) NOT NULL,
+    kunci_terbungkus BYTEA NOT NULL,
+    aktif            BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    rewrapped_at     TIMESTAMP
+);
+
+-- hanya satu kunci aktif per jenis
+CREATE UNIQUE INDEX IF NOT EXISTS idx_kunci_pii_aktif ON kunci_pii (jenis) WHERE aktif;
+
+ALTER TABLE alumni ALTER COLUMN email TYPE TEXT;
+ALTER TABLE alumni ALTER COLUMN no_telepon TYPE TEXT;
+ALTER TABLE alumni ALTER COLUMN alamat TYPE TEXT;
+ALTER TABLE alumni ADD COLUMN IF NOT EXISTS email_bidx VARCHAR(64);
+ALTER TABLE alumni ADD COLUMN IF NOT EXISTS telepon_bidx VARCHAR(64);
+
+-- email unik di antara alumni aktif. Email ganda yang sudah ada harus digabung lewat merge alumni sebelum
+-- enkripsi-pii mengisi email_bidx-nya.
+DROP INDEX IF EXISTS idx_alumni_email_bidx;
+CREATE UNIQUE INDEX idx_alumni_email_bidx ON alumni (email_bidx)
+    WHERE email_bidx IS NOT NULL AND is_delete IS DISTINCT FROM 'hapus';
+CREATE INDEX IF NOT EXISTS idx_alumni_telepon_bidx ON alumni (telepon_bidx);
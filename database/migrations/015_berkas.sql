@@ -0,0 +1,38 @@
+-- Berkas unggahan: foto profil alumni dan dokumen bukti pekerjaan (offer letter, SK, kontrak).
+-- Isi file ada di storage (lokal atau S3-compatible); tabel ini hanya menyimpan metadata dan key object.
+-- Tepat satu dari alumni_id / pekerjaan_id terisi. Baris ikut terhapus saat pemiliknya dihapus permanen;
+-- aplikasi mengambil key-nya sebelum delete dan menghapus object di storage setelah commit. Object yang
+-- gagal dihapus dicatat di berkas_hapus_gagal dan dicoba ulang berkala.
+
+CREATE TABLE IF NOT EXISTS berkas (
+    id            SERIAL PRIMARY KEY,
+    alumni_id     INT REFERENCES alumni(id) ON DELETE CASCADE,
+    pekerjaan_id  INT REFERENCES pekerjaan_alumni(id) ON DELETE CASCADE,
+    jenis         VARCHAR(10) NOT NULL,
+    kategori      VARCHAR(20) NOT NULL DEFAULT '',
+    nama_asli     TEXT NOT NULL DEFAULT '',
+    content_type  VARCHAR(100) NOT NULL,
+    ukuran        BIGINT NOT NULL,
+    sha256        CHAR(64) NOT NULL,
+    storage_key   TEXT NOT NULL UNIQUE,
+    thumbnail_key TEXT,
+    diunggah_oleh INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (jenis IN ('foto', 'dokumen')),
+    CHECK ((alumni_id IS NULL) <> (pekerjaan_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_berkas_alumni ON berkas (alumni_id, jenis) WHERE alumni_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_berkas_pekerjaan ON berkas (pekerjaan_id) WHERE pekerjaan_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS berkas_hapus_gagal (
+    storage_key TEXT PRIMARY KEY,
+    error       TEXT NOT NULL DEFAULT '',
+    percobaan   INT NOT NULL DEFAULT 1,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Foto profil ikut diatur di alumni_visibilitas seperti data kontak; default hanya sesama alumni.
+ALTER TABLE alumni_visibilitas ADD COLUMN IF NOT EXISTS foto VARCHAR(10) NOT NULL DEFAULT 'alumni'
+    CHECK (foto IN ('publik', 'alumni', 'admin'));
@@ -0,0 +1,40 @@
+-- Riwayat pendidikan alumni: satu alumni dapat memiliki beberapa jenjang (misalnya D3 lalu S1, atau S1 lalu S2).
+-- Kolom alumni.jurusan/angkatan/tahun_lulus tetap ada untuk client lama dan selalu sama dengan pendidikan
+-- utama (is_utama): program_studi -> jurusan, tahun_masuk -> angkatan, tahun_lulus -> tahun_lulus.
+-- Data lama dimigrasikan sebagai pendidikan utama jenjang S1 karena jenjangnya tidak pernah dicatat;
+-- koreksi jenjang lewat PUT /api/alumni/:id/pendidikan/:pendidikan_id.
+
+CREATE TABLE IF NOT EXISTS pendidikan_alumni (
+    id                SERIAL PRIMARY KEY,
+    alumni_id         INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    jenjang           VARCHAR(10) NOT NULL,
+    program_studi     VARCHAR(200) NOT NULL,
+    tahun_masuk       INT NOT NULL,
+    tahun_lulus       INT,
+    ipk               NUMERIC(3, 2),
+    judul_tugas_akhir TEXT NOT NULL DEFAULT '',
+    is_utama          BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (jenjang IN ('D3', 'D4', 'S1', 'S2', 'S3', 'Profesi')),
+    CHECK (tahun_lulus IS NULL OR tahun_lulus >= tahun_masuk),
+    CHECK (ipk IS NULL OR (ipk >= 0 AND ipk <= 4))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pendidikan_alumni_program
+    ON pendidikan_alumni (alumni_id, jenjang, lower(program_studi));
+CREATE UNIQUE INDEX IF NOT EXISTS uq_pendidikan_alumni_utama
+    ON pendidikan_alumni (alumni_id) WHERE is_utama;
+CREATE INDEX IF NOT EXISTS idx_pendidikan_alumni_jenjang ON pendidikan_alumni (jenjang);
+
+INSERT INTO pendidikan_alumni (alumni_id, jenjang, program_studi, tahun_masuk, tahun_lulus, is_utama, created_at, updated_at)
+SELECT a.id, 'S1', a.jurusan, a.angkatan, NULLIF(a.tahun_lulus, 0), TRUE, a.created_at, a.updated_at
+FROM alumni a
+WHERE NOT EXISTS (SELECT 1 FROM pendidikan_alumni p WHERE p.alumni_id = a.id)
+  AND (a.tahun_lulus = 0 OR a.tahun_lulus >= a.angkatan);
+
+-- Alumni dengan tahun_lulus < angkatan (data tidak konsisten) tetap dimigrasikan tanpa tahun lulus
+INSERT INTO pendidikan_alumni (alumni_id, jenjang, program_studi, tahun_masuk, tahun_lulus, is_utama, created_at, updated_at)
+SELECT a.id, 'S1', a.jurusan, a.angkatan, NULL, TRUE, a.created_at, a.updated_at
+FROM alumni a
+WHERE NOT EXISTS (SELECT 1 FROM pendidikan_alumni p WHERE p.alumni_id = a.id);
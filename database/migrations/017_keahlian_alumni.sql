@@ -0,0 +1,97 @@
+-- Skill dan sertifikasi alumni untuk pencarian oleh mitra industri.
+-- Tag skill memakai kosakata referensi jenis 'skill' (nama kanonis + alias, dikelola di /api/referensi/skill).
+--   keahlian_alumni      skill atau sertifikasi yang diisi alumni sendiri lewat /api/me/keahlian;
+--                        referensi_id terisi untuk jenis skill, nama disalin dari referensi saat disimpan
+--   keahlian_alumni_tag  tag skill tambahan per keahlian, misalnya sertifikasi AWS -> AWS, Cloud Computing
+--   alumni_tag           tag skill yang diberikan admin langsung ke alumni
+-- Sertifikasi dengan berlaku_sampai yang sudah lewat tidak dihitung saat filter ?skill= pada GET /api/alumni.
+-- Menghapus referensi skill ikut menghapus tag-nya; keahlian jenis skill tetap ada dengan nama terakhirnya.
+
+CREATE TABLE IF NOT EXISTS keahlian_alumni (
+    id             SERIAL PRIMARY KEY,
+    alumni_id      INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    jenis          VARCHAR(20) NOT NULL,
+    referensi_id   INT REFERENCES referensi(id) ON DELETE SET NULL,
+    nama           VARCHAR(200) NOT NULL,
+    penerbit       VARCHAR(200) NOT NULL DEFAULT '',
+    tahun          INT,
+    berlaku_sampai DATE,
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (jenis IN ('skill', 'sertifikasi'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_keahlian_alumni_nama
+    ON keahlian_alumni (alumni_id, jenis, lower(nama), lower(penerbit));
+CREATE INDEX IF NOT EXISTS idx_keahlian_alumni_referensi ON keahlian_alumni (referensi_id);
+
+CREATE TABLE IF NOT EXISTS keahlian_alumni_tag (
+    keahlian_id  INT NOT NULL REFERENCES keahlian_alumni(id) ON DELETE CASCADE,
+    referensi_id INT NOT NULL REFERENCES referensi(id) ON DELETE CASCADE,
+    PRIMARY KEY (keahlian_id, referensi_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_keahlian_alumni_tag_referensi ON keahlian_alumni_tag (referensi_id);
+
+CREATE TABLE IF NOT EXISTS alumni_tag (
+    id            SERIAL PRIMARY KEY,
+    alumni_id     INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    referensi_id  INT NOT NULL REFERENCES referensi(id) ON DELETE CASCADE,
+    ditandai_oleh INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (alumni_id, referensi_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_alumni_tag_referensi ON alumni_tag (referensi_id);
+
+-- Seed skill umum; admin dapat menambah, menonaktifkan, atau memberi alias lewat /api/referensi/skill
+INSERT INTO referensi (jenis, nama) VALUES
+    ('skill', 'Go'),
+    ('skill', 'Java'),
+    ('skill', 'Python'),
+    ('skill', 'JavaScript'),
+    ('skill', 'TypeScript'),
+    ('skill', 'PHP'),
+    ('skill', 'Kotlin'),
+    ('skill', 'SQL'),
+    ('skill', 'PostgreSQL'),
+    ('skill', 'MySQL'),
+    ('skill', 'Docker'),
+    ('skill', 'Kubernetes'),
+    ('skill', 'Linux'),
+    ('skill', 'Git'),
+    ('skill', 'Cloud Computing'),
+    ('skill', 'AWS'),
+    ('skill', 'Google Cloud'),
+    ('skill', 'Microsoft Azure'),
+    ('skill', 'Jaringan Komputer'),
+    ('skill', 'Keamanan Siber'),
+    ('skill', 'Data Analysis'),
+    ('skill', 'Machine Learning'),
+    ('skill', 'UI/UX Design'),
+    ('skill', 'Manajemen Proyek'),
+    ('skill', 'Akuntansi'),
+    ('skill', 'Microsoft Excel'),
+    ('skill', 'Bahasa Inggris'),
+    ('skill', 'Bahasa Jepang'),
+    ('skill', 'Public Speaking')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO referensi_alias (jenis, alias, alias_normal, referensi_id)
+SELECT 'skill', v.alias, lower(v.alias), r.id
+FROM (VALUES
+    ('Golang', 'Go'),
+    ('JS', 'JavaScript'),
+    ('TS', 'TypeScript'),
+    ('Postgres', 'PostgreSQL'),
+    ('K8s', 'Kubernetes'),
+    ('Amazon Web Services', 'AWS'),
+    ('GCP', 'Google Cloud'),
+    ('Azure', 'Microsoft Azure'),
+    ('Cyber Security', 'Keamanan Siber'),
+    ('Analisis Data', 'Data Analysis'),
+    ('Project Management', 'Manajemen Proyek'),
+    ('English', 'Bahasa Inggris')
+) AS v(alias, nama)
+JOIN referensi r ON r.jenis = 'skill' AND r.nama = v.nama
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,61 @@
+-- Papan lowongan kerja untuk alumni.
+--   users.perusahaan_id  akun mitra industri; user yang terhubung ke perusahaan dapat memasang lowongan atas
+--                        nama perusahaan itu dan memproses lamarannya. Dihubungkan admin lewat /api/perusahaan/:id/mitra.
+--   lowongan             status dibuka -> ditutup (oleh pemasang) atau kedaluwarsa (otomatis setelah batas_lamaran
+--                        lewat, lihat LOWONGAN_EXPIRY_INTERVAL_MINUTES). Gaji memakai kolom terstruktur seperti
+--                        pekerjaan_alumni (gaji_max NULL berarti tanpa batas atas).
+--   lowongan_simpan      bookmark lowongan oleh alumni
+--   lamaran_lowongan     satu lamaran per alumni per lowongan: dikirim -> diproses -> wawancara -> diterima/ditolak,
+--                        atau dibatalkan oleh alumni sebelum keputusan akhir.
+-- Perusahaan yang masih memiliki lowongan tidak dapat dihapus; merge perusahaan ikut memindahkan lowongan dan mitranya.
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS perusahaan_id INT REFERENCES perusahaan(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_users_perusahaan ON users (perusahaan_id) WHERE perusahaan_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS lowongan (
+    id              SERIAL PRIMARY KEY,
+    perusahaan_id   INT NOT NULL REFERENCES perusahaan(id) ON DELETE RESTRICT,
+    posisi          VARCHAR(200) NOT NULL,
+    deskripsi       TEXT NOT NULL DEFAULT '',
+    bidang_industri VARCHAR(200) NOT NULL DEFAULT '',
+    lokasi          VARCHAR(200) NOT NULL DEFAULT '',
+    gaji_min        BIGINT,
+    gaji_max        BIGINT,
+    gaji_mata_uang  VARCHAR(3),
+    gaji_periode    VARCHAR(10),
+    batas_lamaran   DATE NOT NULL,
+    status          VARCHAR(20) NOT NULL DEFAULT 'dibuka',
+    dibuat_oleh     INT REFERENCES users(id) ON DELETE SET NULL,
+    ditutup_at      TIMESTAMP,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (status IN ('dibuka', 'ditutup', 'kedaluwarsa')),
+    CHECK (gaji_max IS NULL OR gaji_max >= gaji_min)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lowongan_status_batas ON lowongan (status, batas_lamaran);
+CREATE INDEX IF NOT EXISTS idx_lowongan_perusahaan ON lowongan (perusahaan_id);
+
+CREATE TABLE IF NOT EXISTS lowongan_simpan (
+    lowongan_id INT NOT NULL REFERENCES lowongan(id) ON DELETE CASCADE,
+    alumni_id   INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (lowongan_id, alumni_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lowongan_simpan_alumni ON lowongan_simpan (alumni_id);
+
+CREATE TABLE IF NOT EXISTS lamaran_lowongan (
+    id               SERIAL PRIMARY KEY,
+    lowongan_id      INT NOT NULL REFERENCES lowongan(id) ON DELETE CASCADE,
+    alumni_id        INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    status           VARCHAR(20) NOT NULL DEFAULT 'dikirim',
+    catatan_pelamar  TEXT NOT NULL DEFAULT '',
+    catatan_perekrut TEXT NOT NULL DEFAULT '',
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (lowongan_id, alumni_id),
+    CHECK (status IN ('dikirim', 'diproses', 'wawancara', 'diterima', 'ditolak', 'dibatalkan'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_lamaran_lowongan_alumni ON lamaran_lowongan (alumni_id);
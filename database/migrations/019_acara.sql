@@ -0,0 +1,54 @@
+-- Acara alumni (reuni, talkshow karier, dsb) dengan pendaftaran, kapasitas, dan check-in.
+--   acara               draft -> dipublikasikan -> dibatalkan. Sasaran angkatan/jurusan seperti kuesioner (NULL berarti
+--                       semua). kapasitas NULL berarti tanpa batas; batas_daftar NULL berarti sampai acara dimulai.
+--   pendaftaran_acara   satu baris per alumni per acara: terdaftar, daftar_tunggu (kapasitas penuh), atau dibatalkan.
+--                       Daftar tunggu dipromosikan urut didaftar_at saat ada kursi kosong. Tiket QR berisi payload
+--                       bertanda tangan HMAC (ACARA_TIKET_KEY) yang diverifikasi saat check-in.
+--   acara_petugas       akun panitia yang boleh melakukan check-in dan melihat peserta satu acara, selain admin.
+
+CREATE TABLE IF NOT EXISTS acara (
+    id            SERIAL PRIMARY KEY,
+    judul         VARCHAR(255) NOT NULL,
+    deskripsi     TEXT NOT NULL DEFAULT '',
+    jenis         VARCHAR(20) NOT NULL DEFAULT 'lainnya',
+    lokasi        VARCHAR(255) NOT NULL DEFAULT '',
+    mulai_at      TIMESTAMP NOT NULL,
+    selesai_at    TIMESTAMP NOT NULL,
+    batas_daftar  TIMESTAMP,
+    kapasitas     INT,
+    angkatan      INT,
+    jurusan       VARCHAR(100),
+    status        VARCHAR(20) NOT NULL DEFAULT 'draft',
+    dibuat_oleh   INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (jenis IN ('reuni', 'karier', 'seminar', 'lainnya')),
+    CHECK (status IN ('draft', 'dipublikasikan', 'dibatalkan')),
+    CHECK (selesai_at >= mulai_at),
+    CHECK (kapasitas IS NULL OR kapasitas > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_acara_status_mulai ON acara (status, mulai_at);
+
+CREATE TABLE IF NOT EXISTS pendaftaran_acara (
+    id             SERIAL PRIMARY KEY,
+    acara_id       INT NOT NULL REFERENCES acara(id) ON DELETE CASCADE,
+    alumni_id      INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    status         VARCHAR(20) NOT NULL DEFAULT 'terdaftar',
+    didaftar_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+    check_in_at    TIMESTAMP,
+    check_in_oleh  INT REFERENCES users(id) ON DELETE SET NULL,
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (acara_id, alumni_id),
+    CHECK (status IN ('terdaftar', 'daftar_tunggu', 'dibatalkan'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_pendaftaran_acara_antrian ON pendaftaran_acara (acara_id, status, didaftar_at);
+CREATE INDEX IF NOT EXISTS idx_pendaftaran_acara_alumni ON pendaftaran_acara (alumni_id);
+
+CREATE TABLE IF NOT EXISTS acara_petugas (
+    acara_id   INT NOT NULL REFERENCES acara(id) ON DELETE CASCADE,
+    user_id    INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (acara_id, user_id)
+);
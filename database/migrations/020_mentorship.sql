@@ -0,0 +1,44 @@
+-- Mentoring antara alumni senior dan lulusan baru.
+--   mentor              profil alumni yang bersedia menjadi mentor: topik, bio, dan kapasitas mentee aktif. Alumni
+--                       berhenti menerima permintaan baru dengan aktif = FALSE; mentee yang sudah diterima tetap berjalan.
+--   permintaan_mentor   permintaan mentee (alumni_id) ke mentor: diajukan -> diterima/ditolak oleh mentor, dibatalkan
+--                       oleh mentee sebelum dijawab, atau selesai setelah diterima. Hanya satu permintaan aktif
+--                       (diajukan/diterima) per pasangan. Kontak kedua pihak baru dibuka setelah diterima: email selalu,
+--                       nomor telepon hanya jika pemiliknya mengisi bagikan_telepon.
+-- Kapasitas dihitung dari permintaan berstatus diterima; saran mentor memakai jurusan, bidang industri dan lokasi
+-- kerja, serta pengalaman kerja dari pekerjaan_alumni.
+
+CREATE TABLE IF NOT EXISTS mentor (
+    id               SERIAL PRIMARY KEY,
+    alumni_id        INT NOT NULL UNIQUE REFERENCES alumni(id) ON DELETE CASCADE,
+    topik            TEXT[] NOT NULL DEFAULT '{}',
+    bio              TEXT NOT NULL DEFAULT '',
+    kapasitas        INT NOT NULL DEFAULT 3,
+    bagikan_telepon  BOOLEAN NOT NULL DEFAULT FALSE,
+    aktif            BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (kapasitas BETWEEN 1 AND 20)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentor_aktif ON mentor (aktif);
+
+CREATE TABLE IF NOT EXISTS permintaan_mentor (
+    id               SERIAL PRIMARY KEY,
+    mentor_id        INT NOT NULL REFERENCES mentor(id) ON DELETE CASCADE,
+    alumni_id        INT NOT NULL REFERENCES alumni(id) ON DELETE CASCADE,
+    status           VARCHAR(20) NOT NULL DEFAULT 'diajukan',
+    topik            VARCHAR(100) NOT NULL DEFAULT '',
+    pesan            TEXT NOT NULL DEFAULT '',
+    balasan          TEXT NOT NULL DEFAULT '',
+    bagikan_telepon  BOOLEAN NOT NULL DEFAULT FALSE,
+    direspons_at     TIMESTAMP,
+    selesai_at       TIMESTAMP,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (status IN ('diajukan', 'diterima', 'ditolak', 'dibatalkan', 'selesai'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_permintaan_mentor_aktif ON permintaan_mentor (mentor_id, alumni_id)
+    WHERE status IN ('diajukan', 'diterima');
+CREATE INDEX IF NOT EXISTS idx_permintaan_mentor_alumni ON permintaan_mentor (alumni_id);
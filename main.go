@@ -2,6 +2,7 @@ package main
 
 import (
 	"hello-fiber/config"
+	"hello-fiber/utils"
 	"log"
 	// "os"
 	// "database/sql"
@@ -10,6 +11,10 @@ import (
 )
 
 func main() {
+	// Kunci master PII harus datang dari luar repository (environment atau file di luar direktori kerja)
+	if err := utils.CekSumberMasterKey(".env"); err != nil {
+		log.Fatal("Error konfigurasi kunci master PII: ", err)
+	}
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
 		log.Fatal("Error loading .env file")
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"database/sql"
+	"encoding/json"
+	"hello-fiber/app/model"
+	"hello-fiber/app/repository"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditInfo detail entri audit untuk request yang sedang berjalan. Service boleh mengisi Sebelum/Sesudah
+// dan mengganti Aksi atau resource hasil turunan dari route; ActorID dipakai untuk request tanpa JWT (login).
+type AuditInfo struct {
+	Aksi         string
+	ResourceTipe string
+	ResourceID   string
+	ActorID      int
+	RoleID       int
+	Sebelum      interface{}
+	Sesudah      interface{}
+}
+
+const auditLocalsKey = "audit"
+
+// auditFieldRahasia key yang tidak pernah disimpan di snapshot audit, termasuk PII alumni yang di tabel
+// alumni disimpan terenkripsi (juga kontak di dalam permintaan mentoring)
+var auditFieldRahasia = map[string]bool{
+	"password": true, "token": true, "email": true, "no_telepon": true, "alamat": true,
+}
+
+// Audit mengembalikan AuditInfo request ini. Di luar AuditMiddleware (misalnya request GET) nilai yang
+// dikembalikan tidak disimpan, sehingga service tidak perlu memeriksa nil.
+func Audit(c *fiber.Ctx) *AuditInfo {
+	if info, ok := c.Locals(auditLocalsKey).(*AuditInfo); ok {
+		return info
+	}
+	return &AuditInfo{}
+}
+
+// auditAksiDariRoute menurunkan aksi dari method dan path route
+func auditAksiDariRoute(method, path string) string {
+	switch {
+	case strings.HasSuffix(path, "/login"):
+		return model.AuditAksiLogin
+	case strings.Contains(path, "/restore"), strings.HasSuffix(path, "/undo"):
+		return model.AuditAksiRestore
+	case strings.Contains(path, "/purge"), strings.HasSuffix(path, "/empty"):
+		return model.AuditAksiDelete
+	}
+	switch method {
+	case fiber.MethodPost:
+		return model.AuditAksiCreate
+	case fiber.MethodDelete:
+		return model.AuditAksiDelete
+	}
+	return model.AuditAksiUpdate
+}
+
+// auditResourceDariRoute segmen pertama setelah /api, misalnya "pekerjaan" untuk /api/pekerjaan/trash/:id
+func auditResourceDariRoute(path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api"), "/"), "/")
+	return segments[0]
+}
+
+// redactAudit menghapus field rahasia dari snapshot di semua tingkat
+func redactAudit(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if auditFieldRahasia[strings.ToLower(k)] {
+				delete(val, k)
+				continue
+			}
+			val[k] = redactAudit(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactAudit(item)
+		}
+	}
+	return v
+}
+
+// auditSnapshot mengubah snapshot menjadi JSON tanpa field rahasia
+func auditSnapshot(v interface{}) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil
+	}
+	raw, _ = json.Marshal(redactAudit(generic))
+	return raw
+}
+
+// AuditMiddleware mencatat setiap request POST/PUT/PATCH/DELETE ke audit_log setelah handler selesai,
+// termasuk request yang gagal. Jika service tidak mengisi Sesudah, field "data" dari respons sukses dipakai.
+// Kegagalan menulis audit hanya di-log agar respons yang sudah dibuat tetap terkirim.
+func AuditMiddleware(db *sql.DB) fiber.Handler {
+	auditRepo := repository.NewAuditRepository(db)
+	return func(c *fiber.Ctx) error {
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead || c.Method() == fiber.MethodOptions {
+			return c.Next()
+		}
+
+		info := &AuditInfo{}
+		c.Locals(auditLocalsKey, info)
+		handlerErr := c.Next()
+
+		status := c.Response().StatusCode()
+		if handlerErr != nil {
+			if fe, ok := handlerErr.(*fiber.Error); ok {
+				status = fe.Code
+			} else {
+				status = fiber.StatusInternalServerError
+			}
+		}
+
+		routePath := c.Route().Path
+		entry := model.AuditLog{
+			Aksi:         info.Aksi,
+			ResourceTipe: info.ResourceTipe,
+			ResourceID:   info.ResourceID,
+			UserID:       info.ActorID,
+			RoleID:       info.RoleID,
+			Method:       c.Method(),
+			Path:         c.OriginalURL(),
+			Status:       status,
+			IP:           c.IP(),
+			Sebelum:      auditSnapshot(info.Sebelum),
+			Sesudah:      auditSnapshot(info.Sesudah),
+		}
+		if requestID, ok := c.Locals("requestid").(string); ok {
+			entry.RequestID = requestID
+		}
+		if entry.UserID == 0 {
+			entry.UserID, _ = c.Locals("user_id").(int)
+			entry.RoleID, _ = c.Locals("role_id").(int)
+		}
+		entry.ImpersonatorID, _ = c.Locals("impersonator_id").(int)
+		if entry.Aksi == "" {
+			entry.Aksi = auditAksiDariRoute(c.Method(), routePath)
+		}
+		if entry.ResourceTipe == "" {
+			entry.ResourceTipe = auditResourceDariRoute(routePath)
+		}
+		if entry.ResourceID == "" {
+			if id := c.Params("id"); id != "" {
+				entry.ResourceID = id
+			}
+		}
+		if entry.Sesudah == nil && status < 400 {
+			var body struct {
+				Data interface{} `json:"data"`
+			}
+			if json.Unmarshal(c.Response().Body(), &body) == nil && body.Data != nil {
+				entry.Sesudah = auditSnapshot(body.Data)
+				if entry.ResourceID == "" && entry.Aksi == model.AuditAksiCreate {
+					if m, ok := body.Data.(map[string]interface{}); ok {
+						if id, ok := m["id"].(float64); ok {
+							entry.ResourceID = strconv.Itoa(int(id))
+						}
+					}
+				}
+			}
+		}
+
+		if _, err := auditRepo.Append(entry); err != nil {
+			log.Println("Error mencatat audit log:", err)
+		}
+		return handlerErr
+	}
+}
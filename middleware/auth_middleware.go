@@ -17,6 +17,8 @@ type Claims struct {
 	UserID int    `json:"user_id"`
 	Email  string `json:"email"`
 	RoleID int    `json:"role_id"`
+	// ImpersonatorID diisi jika token diterbitkan untuk admin yang bertindak sebagai user lain
+	ImpersonatorID int `json:"impersonator_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -36,8 +38,9 @@ func GenerateJWT(user model.User) (string, error) {
 	return token.SignedString(jwtSecret)
 }
 
-// JWTMiddleware validates JWT token
-func JWTMiddleware() fiber.Handler {
+// JWTMiddleware validates JWT token; token milik user yang sudah dinonaktifkan (misalnya karena
+// alumninya dihapus) ditolak meskipun belum kedaluwarsa
+func JWTMiddleware(db *sql.DB) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -65,17 +68,53 @@ func JWTMiddleware() fiber.Handler {
 			})
 		}
 
+		claims, ok := token.Claims.(*Claims)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{
+				"error": "Invalid or expired token",
+			})
+		}
+
+		// admin yang melakukan impersonasi juga harus masih aktif
+		for _, id := range []int{claims.UserID, claims.ImpersonatorID} {
+			if id == 0 {
+				continue
+			}
+			aktif, err := userAktif(db, id)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{
+					"error": "Gagal memeriksa status user",
+				})
+			}
+			if !aktif {
+				return c.Status(401).JSON(fiber.Map{
+					"error": "User tidak aktif",
+				})
+			}
+		}
+
 		// Extract claims and store in context
-		if claims, ok := token.Claims.(*Claims); ok {
-			c.Locals("user_id", claims.UserID)
-			c.Locals("email", claims.Email)
-			c.Locals("role_id", claims.RoleID)
+		c.Locals("user_id", claims.UserID)
+		c.Locals("email", claims.Email)
+		c.Locals("role_id", claims.RoleID)
+		if claims.ImpersonatorID != 0 {
+			c.Locals("impersonator_id", claims.ImpersonatorID)
 		}
 
 		return c.Next()
 	}
 }
 
+// userAktif false jika user sudah dinonaktifkan atau tidak ada lagi
+func userAktif(db *sql.DB, id int) (bool, error) {
+	var aktif bool
+	err := db.QueryRow(`SELECT is_active FROM users WHERE id = $1`, id).Scan(&aktif)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return aktif, err
+}
+
 // AdminOnlyMiddleware restricts access to admin users only
 func AdminOnlyMiddleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -123,4 +162,111 @@ func PekerjaanOwnerMiddleware(db *sql.DB) fiber.Handler {
 
         return c.Next()
     }
-}
\ No newline at end of file
+}
+
+// AdminOrPekerjaanOwnerMiddleware admin boleh mengakses semua pekerjaan, user lain hanya pekerjaan miliknya
+func AdminOrPekerjaanOwnerMiddleware(db *sql.DB) fiber.Handler {
+    ownerOnly := PekerjaanOwnerMiddleware(db)
+    return func(c *fiber.Ctx) error {
+        if roleID, _ := c.Locals("role_id").(int); roleID == 1 {
+            return c.Next()
+        }
+        return ownerOnly(c)
+    }
+}
+
+// AdminOrAlumniOwnerMiddleware admin boleh mengakses semua alumni, user lain hanya data alumni miliknya
+func AdminOrAlumniOwnerMiddleware(db *sql.DB) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        userID, ok := c.Locals("user_id").(int)
+        if !ok {
+            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+        }
+        if roleID, _ := c.Locals("role_id").(int); roleID == 1 {
+            return c.Next()
+        }
+
+        alumniID, err := strconv.Atoi(c.Params("id"))
+        if err != nil {
+            return c.Status(400).JSON(fiber.Map{"error": "Invalid alumni id"})
+        }
+
+        var userAlumniID sql.NullInt64
+        err = db.QueryRow("SELECT alumni_id FROM users WHERE id = $1", userID).Scan(&userAlumniID)
+        if err != nil {
+            return c.Status(404).JSON(fiber.Map{"error": "Tidak dapat alumni dari users"})
+        }
+
+        if !userAlumniID.Valid || int(userAlumniID.Int64) != alumniID {
+            return c.Status(403).JSON(fiber.Map{"error": "Forbidden: You can only update your own data"})
+        }
+
+        return c.Next()
+    }
+}
+
+// AdminOrLowonganOwnerMiddleware admin boleh mengelola semua lowongan, mitra hanya lowongan perusahaannya
+func AdminOrLowonganOwnerMiddleware(db *sql.DB) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        userID, ok := c.Locals("user_id").(int)
+        if !ok {
+            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+        }
+        if roleID, _ := c.Locals("role_id").(int); roleID == 1 {
+            return c.Next()
+        }
+
+        lowonganID, err := strconv.Atoi(c.Params("id"))
+        if err != nil {
+            return c.Status(400).JSON(fiber.Map{"error": "Invalid lowongan id"})
+        }
+
+        var lowonganPerusahaanID int
+        err = db.QueryRow("SELECT perusahaan_id FROM lowongan WHERE id = $1", lowonganID).Scan(&lowonganPerusahaanID)
+        if err != nil {
+            return c.Status(404).JSON(fiber.Map{"error": "Tidak dapat lowongan"})
+        }
+
+        var userPerusahaanID sql.NullInt64
+        err = db.QueryRow("SELECT perusahaan_id FROM users WHERE id = $1", userID).Scan(&userPerusahaanID)
+        if err != nil {
+            return c.Status(404).JSON(fiber.Map{"error": "Tidak dapat perusahaan dari users"})
+        }
+
+        if !userPerusahaanID.Valid || int(userPerusahaanID.Int64) != lowonganPerusahaanID {
+            return c.Status(403).JSON(fiber.Map{"error": "Forbidden: You can only manage your company's vacancies"})
+        }
+
+        return c.Next()
+    }
+}
+
+// AdminOrPetugasAcaraMiddleware admin boleh mengelola peserta semua acara, petugas hanya acara yang ditugaskan
+func AdminOrPetugasAcaraMiddleware(db *sql.DB) fiber.Handler {
+    return func(c *fiber.Ctx) error {
+        userID, ok := c.Locals("user_id").(int)
+        if !ok {
+            return c.Status(401).JSON(fiber.Map{"error": "Unauthorized"})
+        }
+        if roleID, _ := c.Locals("role_id").(int); roleID == 1 {
+            return c.Next()
+        }
+
+        acaraID, err := strconv.Atoi(c.Params("id"))
+        if err != nil {
+            return c.Status(400).JSON(fiber.Map{"error": "Invalid acara id"})
+        }
+
+        var petugas bool
+        err = db.QueryRow("SELECT EXISTS (SELECT 1 FROM acara_petugas WHERE acara_id = $1 AND user_id = $2)", acaraID, userID).Scan(&petugas)
+        if err != nil {
+            return c.Status(500).JSON(fiber.Map{"error": "Gagal memeriksa petugas acara"})
+        }
+
+        if !petugas {
+            return c.Status(403).JSON(fiber.Map{"error": "Forbidden: You are not assigned to this event"})
+        }
+
+        return c.Next()
+    }
+}
@@ -9,7 +9,7 @@ import (
 )
 
 func SetupRoutes(app *fiber.App, db *sql.DB) {
-	api := app.Group("/api")
+	api := app.Group("/api", middleware.AuditMiddleware(db))
 
 	// Route untuk registrasi dan login
 	api.Post("/register", func(c *fiber.Ctx) error {
@@ -20,19 +20,97 @@ func SetupRoutes(app *fiber.App, db *sql.DB) {
 		return service.LoginService(c, db)
 	})
 
-	protected := api.Group("/", middleware.JWTMiddleware())
+	// Signed URL berkas untuk driver storage lokal; akses dijamin tanda tangan di query string, bukan JWT
+	api.Get("/berkas/unduh", func(c *fiber.Ctx) error {
+		return service.UnduhBerkasService(c)
+	})
+
+	protected := api.Group("/", middleware.JWTMiddleware(db))
+
+	protected.Get("/stats", func(c *fiber.Ctx) error {
+		return service.GetTracerStudyStatsService(c, db)
+	})
 
 	alumni := protected.Group("/alumni")
 	alumni.Get("/", func(c *fiber.Ctx) error {
 		return service.GetAllAlumniService(c, db)
 	})
+	alumni.Get("/trash", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetTrashedAlumniService(c, db)
+	})
+	alumni.Get("/duplikat", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetDuplikatAlumniService(c, db)
+	})
+	alumni.Post("/duplikat/abaikan", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.AbaikanDuplikatAlumniService(c, db)
+	})
+	alumni.Get("/merge", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetAllAlumniMergeService(c, db)
+	})
+	alumni.Post("/merge/:id/undo", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.UndoAlumniMergeService(c, db)
+	})
 	alumni.Get("/:id", func(c *fiber.Ctx) error {
 		return service.GetAlumniByIDService(c, db)
 	})
+	alumni.Get("/:id/timeline", func(c *fiber.Ctx) error {
+		return service.GetAlumniTimelineService(c, db)
+	})
+	alumni.Get("/:id/pendidikan", func(c *fiber.Ctx) error {
+		return service.GetPendidikanAlumniService(c, db)
+	})
+	alumni.Post("/:id/pendidikan", middleware.AdminOrAlumniOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.CreatePendidikanAlumniService(c, db)
+	})
+	alumni.Put("/:id/pendidikan/:pendidikan_id", middleware.AdminOrAlumniOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.UpdatePendidikanAlumniService(c, db)
+	})
+	alumni.Delete("/:id/pendidikan/:pendidikan_id", middleware.AdminOrAlumniOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.DeletePendidikanAlumniService(c, db)
+	})
+	alumni.Get("/:id/keahlian", func(c *fiber.Ctx) error {
+		return service.GetKeahlianAlumniService(c, db)
+	})
+	alumni.Put("/:id/tag", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.UpdateTagAlumniService(c, db)
+	})
+	alumni.Get("/:id/foto", func(c *fiber.Ctx) error {
+		return service.GetFotoAlumniService(c, db)
+	})
+	alumni.Post("/:id/foto", middleware.AdminOrAlumniOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.UploadFotoAlumniService(c, db)
+	})
+	alumni.Delete("/:id/foto", middleware.AdminOrAlumniOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.DeleteFotoAlumniService(c, db)
+	})
+	alumni.Get("/:id/revisi", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetRevisiAlumniService(c, db)
+	})
+	alumni.Get("/:id/revisi/diff", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetDiffRevisiAlumniService(c, db)
+	})
+	alumni.Get("/:id/revisi/:versi", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetRevisiAlumniByVersiService(c, db)
+	})
+	alumni.Post("/:id/revisi/:versi/revert", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.RevertAlumniService(c, db)
+	})
+	alumni.Get("/:id/per-tanggal", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetAlumniPerTanggalService(c, db)
+	})
+	alumni.Put("/trash/:id/restore", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.RestoreTrashedAlumniService(c, db)
+	})
+	alumni.Delete("/trash/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.HardDeleteTrashedAlumniService(c, db)
+	})
 	
 	alumni.Post("/", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
 		return service.CreateAlumniService(c, db)
 	})
+	alumni.Post("/:id/merge", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.MergeAlumniService(c, db)
+	})
 	alumni.Put("/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
 		return service.UpdateAlumniService(c, db)
 	})
@@ -44,21 +122,75 @@ func SetupRoutes(app *fiber.App, db *sql.DB) {
 	pekerjaan.Get("/", func(c *fiber.Ctx) error {
 		return service.GetAllPekerjaanAlumniService(c, db)
 	})
-	pekerjaan.Get("/trash", func(c *fiber.Ctx) error {
+	pekerjaan.Get("/trash", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
 		return service.GetTrashedPekerjaanAlumniService(c, db)
 	})
+	pekerjaan.Get("/trash/purge-preview", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetTrashPurgePreviewService(c, db)
+	})
+	pekerjaan.Get("/pengajuan", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetAllPengajuanPekerjaanService(c, db)
+	})
+	pekerjaan.Get("/pengajuan/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetPengajuanPekerjaanByIDService(c, db)
+	})
+	pekerjaan.Put("/pengajuan/:id/approve", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.ApprovePengajuanPekerjaanService(c, db)
+	})
+	pekerjaan.Put("/pengajuan/:id/reject", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.RejectPengajuanPekerjaanService(c, db)
+	})
 	pekerjaan.Get("/alumni/:alumni_id", func(c *fiber.Ctx) error {
 		return service.GetPekerjaanAlumniByAlumniIDService(c, db)
 	})
 	pekerjaan.Get("/:id", func(c *fiber.Ctx) error {
 		return service.GetPekerjaanAlumniByIDService(c, db)
 	})
+	pekerjaan.Get("/:id/history", func(c *fiber.Ctx) error {
+		return service.GetStatusHistoryPekerjaanAlumniService(c, db)
+	})
+	pekerjaan.Get("/:id/dokumen", middleware.AdminOrPekerjaanOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.GetDokumenPekerjaanService(c, db)
+	})
+	pekerjaan.Post("/:id/dokumen", middleware.AdminOrPekerjaanOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.UploadDokumenPekerjaanService(c, db)
+	})
+	pekerjaan.Delete("/:id/dokumen/:berkas_id", middleware.AdminOrPekerjaanOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.DeleteDokumenPekerjaanService(c, db)
+	})
+	pekerjaan.Get("/:id/revisi", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetRevisiPekerjaanAlumniService(c, db)
+	})
+	pekerjaan.Get("/:id/revisi/diff", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetDiffRevisiPekerjaanAlumniService(c, db)
+	})
+	pekerjaan.Get("/:id/revisi/:versi", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetRevisiPekerjaanAlumniByVersiService(c, db)
+	})
+	pekerjaan.Post("/:id/revisi/:versi/revert", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.RevertPekerjaanAlumniService(c, db)
+	})
+	pekerjaan.Get("/:id/per-tanggal", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetPekerjaanAlumniPerTanggalService(c, db)
+	})
+	pekerjaan.Put("/:id/status", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.UpdateStatusPekerjaanAlumniService(c, db)
+	})
 	pekerjaan.Delete("/trash/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
 		return service.HardDeleteTrashedPekerjaanAlumniService(c, db)
 	})
 	pekerjaan.Put("/trash/:id/restore", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
 		return service.RestoreTrashedPekerjaanAlumniService(c, db)
 	})
+	pekerjaan.Post("/trash/restore", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.BulkRestoreTrashedPekerjaanAlumniService(c, db)
+	})
+	pekerjaan.Post("/trash/purge", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.BulkPurgeTrashedPekerjaanAlumniService(c, db)
+	})
+	pekerjaan.Post("/trash/empty", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.EmptyTrashPekerjaanAlumniService(c, db)
+	})
 	
 	pekerjaan.Post("/", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
 		return service.CreatePekerjaanAlumniService(c, db)
@@ -75,4 +207,272 @@ func SetupRoutes(app *fiber.App, db *sql.DB) {
 	pekerjaan.Delete("/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
 		return service.DeletePekerjaanAlumniService(c, db)
 	})
+
+	perusahaan := protected.Group("/perusahaan")
+	perusahaan.Get("/", func(c *fiber.Ctx) error {
+		return service.GetAllPerusahaanService(c, db)
+	})
+	perusahaan.Get("/match", func(c *fiber.Ctx) error {
+		return service.MatchPerusahaanService(c, db)
+	})
+	perusahaan.Get("/top", func(c *fiber.Ctx) error {
+		return service.GetTopPerusahaanService(c, db)
+	})
+	perusahaan.Get("/duplikat", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetDuplikatPerusahaanService(c, db)
+	})
+	perusahaan.Get("/:id", func(c *fiber.Ctx) error {
+		return service.GetPerusahaanByIDService(c, db)
+	})
+	perusahaan.Post("/", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.CreatePerusahaanService(c, db)
+	})
+	perusahaan.Post("/:id/merge", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.MergePerusahaanService(c, db)
+	})
+	perusahaan.Put("/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.UpdatePerusahaanService(c, db)
+	})
+	perusahaan.Delete("/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.DeletePerusahaanService(c, db)
+	})
+	perusahaan.Get("/:id/mitra", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetMitraPerusahaanService(c, db)
+	})
+	perusahaan.Post("/:id/mitra", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.TambahMitraPerusahaanService(c, db)
+	})
+	perusahaan.Delete("/:id/mitra/:user_id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.HapusMitraPerusahaanService(c, db)
+	})
+
+	// Lowongan: dipasang admin atau mitra perusahaan, dilamar dan disimpan alumni
+	lowongan := protected.Group("/lowongan")
+	lowongan.Get("/", func(c *fiber.Ctx) error {
+		return service.GetAllLowonganService(c, db)
+	})
+	lowongan.Get("/:id", func(c *fiber.Ctx) error {
+		return service.GetLowonganByIDService(c, db)
+	})
+	lowongan.Post("/", func(c *fiber.Ctx) error {
+		return service.CreateLowonganService(c, db)
+	})
+	lowongan.Put("/:id", middleware.AdminOrLowonganOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.UpdateLowonganService(c, db)
+	})
+	lowongan.Put("/:id/status", middleware.AdminOrLowonganOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.UpdateStatusLowonganService(c, db)
+	})
+	lowongan.Delete("/:id", middleware.AdminOrLowonganOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.DeleteLowonganService(c, db)
+	})
+	lowongan.Post("/:id/simpan", func(c *fiber.Ctx) error {
+		return service.SimpanLowonganService(c, db)
+	})
+	lowongan.Delete("/:id/simpan", func(c *fiber.Ctx) error {
+		return service.HapusSimpanLowonganService(c, db)
+	})
+	lowongan.Post("/:id/lamaran", func(c *fiber.Ctx) error {
+		return service.LamarLowonganService(c, db)
+	})
+	lowongan.Get("/:id/lamaran", middleware.AdminOrLowonganOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.GetLamaranLowonganService(c, db)
+	})
+	lowongan.Put("/:id/lamaran/:lamaran_id", middleware.AdminOrLowonganOwnerMiddleware(db), func(c *fiber.Ctx) error {
+		return service.UpdateStatusLamaranService(c, db)
+	})
+
+	// Acara: dibuat admin, RSVP oleh alumni, check-in oleh admin atau petugas acara
+	acara := protected.Group("/acara")
+	acara.Get("/", func(c *fiber.Ctx) error {
+		return service.GetAllAcaraService(c, db)
+	})
+	acara.Get("/:id", func(c *fiber.Ctx) error {
+		return service.GetAcaraByIDService(c, db)
+	})
+	acara.Post("/", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.CreateAcaraService(c, db)
+	})
+	acara.Put("/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.UpdateAcaraService(c, db)
+	})
+	acara.Put("/:id/status", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.UpdateStatusAcaraService(c, db)
+	})
+	acara.Delete("/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.DeleteAcaraService(c, db)
+	})
+	acara.Post("/:id/daftar", func(c *fiber.Ctx) error {
+		return service.DaftarAcaraService(c, db)
+	})
+	acara.Delete("/:id/daftar", func(c *fiber.Ctx) error {
+		return service.BatalDaftarAcaraService(c, db)
+	})
+	acara.Get("/:id/peserta", middleware.AdminOrPetugasAcaraMiddleware(db), func(c *fiber.Ctx) error {
+		return service.GetPesertaAcaraService(c, db)
+	})
+	acara.Post("/:id/check-in", middleware.AdminOrPetugasAcaraMiddleware(db), func(c *fiber.Ctx) error {
+		return service.CheckInAcaraService(c, db)
+	})
+	acara.Get("/:id/petugas", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetPetugasAcaraService(c, db)
+	})
+	acara.Post("/:id/petugas", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.TambahPetugasAcaraService(c, db)
+	})
+	acara.Delete("/:id/petugas/:user_id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.HapusPetugasAcaraService(c, db)
+	})
+
+	// Mentoring: alumni mendaftar sebagai mentor lewat /me/mentor, mentee meminta mentor dari saran;
+	// kontak kedua pihak hanya dibuka setelah permintaan diterima
+	mentorship := protected.Group("/mentorship")
+	mentorship.Get("/saran", func(c *fiber.Ctx) error {
+		return service.GetSaranMentorService(c, db)
+	})
+	mentorship.Get("/permintaan", func(c *fiber.Ctx) error {
+		return service.GetMyPermintaanMentorService(c, db)
+	})
+	mentorship.Post("/permintaan", func(c *fiber.Ctx) error {
+		return service.AjukanPermintaanMentorService(c, db)
+	})
+	mentorship.Put("/permintaan/:id/terima", func(c *fiber.Ctx) error {
+		return service.TerimaPermintaanMentorService(c, db)
+	})
+	mentorship.Put("/permintaan/:id/tolak", func(c *fiber.Ctx) error {
+		return service.TolakPermintaanMentorService(c, db)
+	})
+	mentorship.Put("/permintaan/:id/batal", func(c *fiber.Ctx) error {
+		return service.BatalkanPermintaanMentorService(c, db)
+	})
+	mentorship.Put("/permintaan/:id/selesai", func(c *fiber.Ctx) error {
+		return service.SelesaikanPermintaanMentorService(c, db)
+	})
+
+	referensi := protected.Group("/referensi")
+	referensi.Get("/:jenis", func(c *fiber.Ctx) error {
+		return service.SearchReferensiService(c, db)
+	})
+	referensi.Get("/:jenis/legacy", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetLegacyReferensiService(c, db)
+	})
+	referensi.Post("/:jenis/legacy/mapping", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.MapLegacyReferensiService(c, db)
+	})
+	referensi.Post("/:jenis/legacy/rapikan", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.RapikanLegacyReferensiService(c, db)
+	})
+	referensi.Post("/:jenis", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.CreateReferensiService(c, db)
+	})
+	referensi.Put("/:jenis/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.UpdateReferensiService(c, db)
+	})
+	referensi.Delete("/:jenis/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.DeleteReferensiService(c, db)
+	})
+
+	kuesioner := protected.Group("/kuesioner")
+	kuesioner.Get("/", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetAllKuesionerService(c, db)
+	})
+	kuesioner.Get("/:id", func(c *fiber.Ctx) error {
+		return service.GetKuesionerByIDService(c, db)
+	})
+	kuesioner.Get("/:id/hasil", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.GetHasilKuesionerService(c, db)
+	})
+	kuesioner.Post("/", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.CreateKuesionerService(c, db)
+	})
+	kuesioner.Put("/:id/status", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.UpdateKuesionerStatusService(c, db)
+	})
+	kuesioner.Delete("/:id", middleware.AdminOnlyMiddleware(), func(c *fiber.Ctx) error {
+		return service.DeleteKuesionerService(c, db)
+	})
+
+	audit := protected.Group("/audit", middleware.AdminOnlyMiddleware())
+	audit.Get("/", func(c *fiber.Ctx) error {
+		return service.GetAuditLogService(c, db)
+	})
+	audit.Get("/verifikasi", func(c *fiber.Ctx) error {
+		return service.VerifikasiAuditLogService(c, db)
+	})
+
+	penghapusan := protected.Group("/penghapusan-data", middleware.AdminOnlyMiddleware())
+	penghapusan.Get("/", func(c *fiber.Ctx) error {
+		return service.GetAllPenghapusanDataService(c, db)
+	})
+	penghapusan.Get("/:id", func(c *fiber.Ctx) error {
+		return service.GetPenghapusanDataByIDService(c, db)
+	})
+	penghapusan.Put("/:id/approve", func(c *fiber.Ctx) error {
+		return service.ApprovePenghapusanDataService(c, db)
+	})
+	penghapusan.Put("/:id/reject", func(c *fiber.Ctx) error {
+		return service.RejectPenghapusanDataService(c, db)
+	})
+
+	me := protected.Group("/me")
+	me.Get("/visibilitas", func(c *fiber.Ctx) error {
+		return service.GetMyVisibilitasService(c, db)
+	})
+	me.Put("/visibilitas", func(c *fiber.Ctx) error {
+		return service.UpdateMyVisibilitasService(c, db)
+	})
+	me.Get("/keahlian", func(c *fiber.Ctx) error {
+		return service.GetMyKeahlianService(c, db)
+	})
+	me.Post("/keahlian", func(c *fiber.Ctx) error {
+		return service.CreateMyKeahlianService(c, db)
+	})
+	me.Put("/keahlian/:id", func(c *fiber.Ctx) error {
+		return service.UpdateMyKeahlianService(c, db)
+	})
+	me.Delete("/keahlian/:id", func(c *fiber.Ctx) error {
+		return service.DeleteMyKeahlianService(c, db)
+	})
+	me.Get("/data-export", func(c *fiber.Ctx) error {
+		return service.ExportDataPribadiService(c, db)
+	})
+	me.Get("/penghapusan-data", func(c *fiber.Ctx) error {
+		return service.GetMyPenghapusanDataService(c, db)
+	})
+	me.Post("/penghapusan-data", func(c *fiber.Ctx) error {
+		return service.AjukanPenghapusanDataService(c, db)
+	})
+	me.Get("/kuesioner", func(c *fiber.Ctx) error {
+		return service.GetMyKuesionerService(c, db)
+	})
+	me.Get("/kuesioner/:id/jawaban", func(c *fiber.Ctx) error {
+		return service.GetMyJawabanKuesionerService(c, db)
+	})
+	me.Post("/kuesioner/:id/jawaban", func(c *fiber.Ctx) error {
+		return service.SubmitJawabanKuesionerService(c, db)
+	})
+	me.Get("/pengajuan-pekerjaan", func(c *fiber.Ctx) error {
+		return service.GetMyPengajuanPekerjaanService(c, db)
+	})
+	me.Post("/pengajuan-pekerjaan", func(c *fiber.Ctx) error {
+		return service.SubmitPengajuanPekerjaanService(c, db)
+	})
+	me.Get("/lamaran", func(c *fiber.Ctx) error {
+		return service.GetMyLamaranService(c, db)
+	})
+	me.Put("/lamaran/:id/batal", func(c *fiber.Ctx) error {
+		return service.BatalkanMyLamaranService(c, db)
+	})
+	me.Get("/acara", func(c *fiber.Ctx) error {
+		return service.GetMyAcaraService(c, db)
+	})
+	me.Get("/acara/:id/tiket", func(c *fiber.Ctx) error {
+		return service.GetMyTiketAcaraService(c, db)
+	})
+	me.Get("/mentor", func(c *fiber.Ctx) error {
+		return service.GetMyMentorService(c, db)
+	})
+	me.Put("/mentor", func(c *fiber.Ctx) error {
+		return service.SimpanMyMentorService(c, db)
+	})
 }
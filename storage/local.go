@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// LocalDownloadPath route unduh untuk driver lokal; URL-nya ditandatangani HMAC sehingga tidak perlu JWT
+const LocalDownloadPath = "/api/berkas/unduh"
+
+// Local menyimpan object sebagai file di bawah satu direktori root
+type Local struct {
+	root       string
+	signingKey []byte
+	baseURL    string
+}
+
+func NewLocal(root string, signingKey []byte, baseURL string) (*Local, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+	return &Local{root: root, signingKey: signingKey, baseURL: baseURL}, nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put menulis ke file sementara lalu rename agar pembaca tidak pernah melihat file setengah jadi
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) signature(key string, exp int64) string {
+	mac := hmac.New(sha256.New, l.signingKey)
+	mac.Write([]byte(key))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(exp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (l *Local) SignedURL(key string, ttl time.Duration) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	exp := time.Now().Add(ttl).Unix()
+	q := url.Values{}
+	q.Set("key", key)
+	q.Set("exp", strconv.FormatInt(exp, 10))
+	q.Set("sig", l.signature(key, exp))
+	return l.baseURL + LocalDownloadPath + "?" + q.Encode(), nil
+}
+
+// Verify memeriksa tanda tangan dan masa berlaku URL dari SignedURL
+func (l *Local) Verify(key, exp, sig string) bool {
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(l.signature(key, expUnix)))
+}
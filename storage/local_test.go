@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalPutGetDelete(t *testing.T) {
+	l, err := NewLocal(t.TempDir(), []byte("kunci-test"), "http://localhost:3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	key := "alumni/7/foto/foto.png"
+
+	if err := l.Put(ctx, key, strings.NewReader("isi foto"), 8, "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rc, err := l.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(got) != "isi foto" {
+		t.Fatalf("Get = %q", got)
+	}
+
+	if err := l.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := l.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get setelah Delete: err = %v, ingin ErrNotFound", err)
+	}
+	if err := l.Delete(ctx, key); err != nil {
+		t.Errorf("Delete key yang sudah tidak ada harus berhasil: %v", err)
+	}
+}
+
+func TestLocalSignedURL(t *testing.T) {
+	l, err := NewLocal(t.TempDir(), []byte("kunci-test"), "http://localhost:3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := "pekerjaan/3/dokumen/kontrak kerja.pdf"
+	signed, err := l.SignedURL(key, time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	if !strings.HasPrefix(signed, "http://localhost:3000"+LocalDownloadPath+"?") {
+		t.Fatalf("SignedURL = %s", signed)
+	}
+	u, _ := url.Parse(signed)
+	q := u.Query()
+	if q.Get("key") != key || !l.Verify(q.Get("key"), q.Get("exp"), q.Get("sig")) {
+		t.Fatalf("URL valid ditolak: %s", signed)
+	}
+
+	exp, _ := strconv.ParseInt(q.Get("exp"), 10, 64)
+	sig := []byte(q.Get("sig"))
+	sig[0] ^= 1
+	dirusak := map[string][3]string{
+		"key lain":                  {"pekerjaan/4/dokumen/lain.pdf", q.Get("exp"), q.Get("sig")},
+		"masa berlaku diperpanjang": {key, strconv.FormatInt(exp+3600, 10), q.Get("sig")},
+		"signature diubah":          {key, q.Get("exp"), string(sig)},
+		"exp bukan angka":           {key, "besok", q.Get("sig")},
+	}
+	for nama, v := range dirusak {
+		if l.Verify(v[0], v[1], v[2]) {
+			t.Errorf("%s: Verify harus gagal", nama)
+		}
+	}
+
+	lain, _ := NewLocal(t.TempDir(), []byte("kunci-lain"), "")
+	if lain.Verify(q.Get("key"), q.Get("exp"), q.Get("sig")) {
+		t.Error("URL dari kunci lain harus ditolak")
+	}
+
+	kedaluwarsa, _ := l.SignedURL(key, -time.Minute)
+	u, _ = url.Parse(kedaluwarsa)
+	q = u.Query()
+	if l.Verify(q.Get("key"), q.Get("exp"), q.Get("sig")) {
+		t.Error("URL kedaluwarsa harus ditolak")
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	for _, key := range []string{"a.txt", "alumni/1/foto/x.jpg", "nama dengan spasi.pdf"} {
+		if err := validKey(key); err != nil {
+			t.Errorf("validKey(%q): %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "/etc/passwd", "../luar", "a/../../b", "a//b", "a/./b", `a\b`, "a/"} {
+		if err := validKey(key); err == nil {
+			t.Errorf("validKey(%q) harus gagal", key)
+		}
+	}
+}
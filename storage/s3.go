@@ -0,0 +1,256 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Config koneksi ke object storage S3-compatible. PathStyle=true untuk MinIO dan sejenisnya
+// (http://host:9000/bucket/key); false untuk virtual-hosted style AWS (https://bucket.host/key).
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+}
+
+// S3 driver S3-compatible yang menandatangani request sendiri dengan AWS Signature V4
+type S3 struct {
+	cfg      S3Config
+	endpoint *url.URL
+	client   *http.Client
+}
+
+const (
+	s3Algoritma       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3FormatTanggal   = "20060102T150405Z"
+)
+
+func NewS3(cfg S3Config) (*S3, error) {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT %q tidak valid", cfg.Endpoint)
+	}
+	return &S3{cfg: cfg, endpoint: endpoint, client: &http.Client{Timeout: 60 * time.Second}}, nil
+}
+
+// objectURL URL object tanpa query; path sudah di-encode sesuai aturan SigV4
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	prefix := strings.TrimRight(s.endpoint.Path, "/")
+	path, rawPath := "/"+key, "/"+s3EncodePath(key)
+	if s.cfg.PathStyle {
+		path, rawPath = "/"+s.cfg.Bucket+path, "/"+s3Encode(s.cfg.Bucket)+rawPath
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.Path, u.RawPath = prefix+path, prefix+rawPath
+	return &u
+}
+
+func (s *S3) do(ctx context.Context, method, key string, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	u := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	now := time.Now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3FormatTanggal))
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	headers := map[string]string{
+		"host":                 u.Host,
+		"x-amz-date":           req.Header.Get("X-Amz-Date"),
+		"x-amz-content-sha256": s3UnsignedPayload,
+	}
+	if contentType != "" {
+		headers["content-type"] = contentType
+	}
+	signedHeaders, canonicalHeaders := s3CanonicalHeaders(headers)
+	canonicalRequest := strings.Join([]string{
+		method, u.RawPath, "", canonicalHeaders, signedHeaders, s3UnsignedPayload,
+	}, "\n")
+
+	scope, signature := s.sign(now, canonicalRequest)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algoritma, s.cfg.AccessKey, scope, signedHeaders, signature))
+
+	return s.client.Do(req)
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, r, size, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, s3Error(resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete S3 mengembalikan 204 juga untuk key yang tidak ada
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+// SignedURL presigned GET URL (query-string SigV4); maksimal 7 hari sesuai batas S3
+func (s *S3) SignedURL(key string, ttl time.Duration) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	if ttl > 7*24*time.Hour {
+		ttl = 7 * 24 * time.Hour
+	}
+	return s.presign(key, ttl, time.Now().UTC()), nil
+}
+
+func (s *S3) presign(key string, ttl time.Duration, now time.Time) string {
+	u := s.objectURL(key)
+	scope := s.scope(now)
+
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", s3Algoritma)
+	q.Set("X-Amz-Credential", s.cfg.AccessKey+"/"+scope)
+	q.Set("X-Amz-Date", now.Format(s3FormatTanggal))
+	q.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	q.Set("X-Amz-SignedHeaders", "host")
+	query := s3CanonicalQuery(q)
+
+	_, canonicalHeaders := s3CanonicalHeaders(map[string]string{"host": u.Host})
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet, u.RawPath, query, canonicalHeaders, "host", s3UnsignedPayload,
+	}, "\n")
+	_, signature := s.sign(now, canonicalRequest)
+
+	return u.String() + "?" + query + "&X-Amz-Signature=" + signature
+}
+
+func (s *S3) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.cfg.Region + "/s3/aws4_request"
+}
+
+func (s *S3) sign(t time.Time, canonicalRequest string) (scope, signature string) {
+	scope = s.scope(t)
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algoritma, t.Format(s3FormatTanggal), scope, hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := s3HMAC([]byte("AWS4"+s.cfg.SecretKey), t.Format("20060102"))
+	key = s3HMAC(key, s.cfg.Region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	return scope, hex.EncodeToString(s3HMAC(key, stringToSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3CanonicalHeaders header terurut per nama; setiap baris diakhiri newline sesuai format SigV4
+func s3CanonicalHeaders(headers map[string]string) (signed, canonical string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	return strings.Join(names, ";"), b.String()
+}
+
+func s3CanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, s3Encode(k)+"="+s3Encode(q.Get(k)))
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Encode URI-encode RFC 3986: hanya A-Z a-z 0-9 - _ . ~ yang tidak di-encode
+func s3Encode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func s3EncodePath(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = s3Encode(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("S3 %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,82 @@
+// Package storage menyimpan file unggahan (foto alumni, dokumen pekerjaan) di filesystem lokal atau
+// object storage S3-compatible (AWS S3, MinIO). Driver dipilih lewat STORAGE_DRIVER.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hello-fiber/utils"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound object tidak ada di storage
+var ErrNotFound = errors.New("object tidak ditemukan")
+
+// Storage penyimpanan object berbasis key, misalnya "alumni/12/foto/ab12.jpg"
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL URL unduh yang hanya berlaku selama ttl dan tidak memerlukan JWT
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+var (
+	defaultOnce    sync.Once
+	defaultStorage Storage
+	defaultErr     error
+)
+
+// Default storage yang dikonfigurasi dari env, dibuat sekali per proses:
+//
+//	STORAGE_DRIVER=local (default): STORAGE_LOCAL_DIR, STORAGE_SIGNING_KEY, APP_BASE_URL
+//	STORAGE_DRIVER=s3: S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY, S3_PATH_STYLE
+func Default() (Storage, error) {
+	defaultOnce.Do(func() {
+		defaultStorage, defaultErr = newFromEnv()
+	})
+	return defaultStorage, defaultErr
+}
+
+func newFromEnv() (Storage, error) {
+	switch driver := strings.ToLower(utils.GetEnv("STORAGE_DRIVER", "local")); driver {
+	case "local":
+		signingKey := utils.GetEnv("STORAGE_SIGNING_KEY", utils.GetEnv("JWT_SECRET", ""))
+		if signingKey == "" {
+			return nil, errors.New("STORAGE_SIGNING_KEY belum dikonfigurasi")
+		}
+		return NewLocal(utils.GetEnv("STORAGE_LOCAL_DIR", "./uploads"), []byte(signingKey), utils.GetEnv("APP_BASE_URL", ""))
+	case "s3":
+		cfg := S3Config{
+			Endpoint:  utils.GetEnv("S3_ENDPOINT", "https://s3.amazonaws.com"),
+			Region:    utils.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:    utils.GetEnv("S3_BUCKET", ""),
+			AccessKey: utils.GetEnv("S3_ACCESS_KEY", ""),
+			SecretKey: utils.GetEnv("S3_SECRET_KEY", ""),
+			PathStyle: strings.ToLower(utils.GetEnv("S3_PATH_STYLE", "true")) == "true",
+		}
+		if cfg.Bucket == "" || cfg.AccessKey == "" || cfg.SecretKey == "" {
+			return nil, errors.New("S3_BUCKET, S3_ACCESS_KEY, dan S3_SECRET_KEY harus diisi")
+		}
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER %q tidak dikenal (local atau s3)", driver)
+	}
+}
+
+// validKey menolak key kosong, absolut, atau yang keluar dari root dengan ".."
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("key %q tidak valid", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("key %q tidak valid", key)
+		}
+	}
+	return nil
+}
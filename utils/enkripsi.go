@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// PanjangKunci panjang kunci AES-256 dalam byte
+const PanjangKunci = 32
+
+// MasterKeys kunci master untuk membungkus kunci data PII. AktifID dipakai untuk membungkus kunci baru;
+// kunci lama hanya dipakai untuk membuka kunci data yang belum dibungkus ulang.
+type MasterKeys struct {
+	AktifID string
+	Kunci   map[string][]byte
+}
+
+// LoadMasterKeys membaca kunci master dari PII_MASTER_KEY atau file PII_MASTER_KEY_FILE (base64 32 byte)
+// dengan id PII_MASTER_KEY_ID. Kunci lama untuk rotasi dibaca dari PII_MASTER_KEY_LAMA dengan format
+// "id:base64,id:base64".
+func LoadMasterKeys() (MasterKeys, error) {
+	keys := MasterKeys{AktifID: GetEnv("PII_MASTER_KEY_ID", "utama"), Kunci: map[string][]byte{}}
+
+	encoded := GetEnv("PII_MASTER_KEY", "")
+	if path := GetEnv("PII_MASTER_KEY_FILE", ""); encoded == "" && path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return keys, fmt.Errorf("gagal membaca PII_MASTER_KEY_FILE: %w", err)
+		}
+		encoded = string(data)
+	}
+	if strings.TrimSpace(encoded) == "" {
+		return keys, errors.New("PII_MASTER_KEY atau PII_MASTER_KEY_FILE belum dikonfigurasi")
+	}
+	aktif, err := DecodeKunci(encoded)
+	if err != nil {
+		return keys, fmt.Errorf("PII_MASTER_KEY tidak valid: %w", err)
+	}
+	keys.Kunci[keys.AktifID] = aktif
+
+	for _, item := range strings.Split(GetEnv("PII_MASTER_KEY_LAMA", ""), ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || id == "" {
+			return keys, errors.New("PII_MASTER_KEY_LAMA harus berformat id:base64")
+		}
+		if id == keys.AktifID {
+			return keys, fmt.Errorf("PII_MASTER_KEY_LAMA memakai id aktif %q", id)
+		}
+		lama, err := DecodeKunci(encoded)
+		if err != nil {
+			return keys, fmt.Errorf("PII_MASTER_KEY_LAMA %q tidak valid: %w", id, err)
+		}
+		keys.Kunci[id] = lama
+	}
+	return keys, nil
+}
+
+// CekSumberMasterKey menolak kunci master yang ikut tersimpan di repository: PII_MASTER_KEY dan
+// PII_MASTER_KEY_LAMA tidak boleh diisi di file dotenv, dan PII_MASTER_KEY_FILE harus berada di luar
+// direktori kerja. Dipanggil sebelum dotenv dimuat.
+func CekSumberMasterKey(dotenvPath string) error {
+	dotenv, err := godotenv.Read(dotenvPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, nama := range []string{"PII_MASTER_KEY", "PII_MASTER_KEY_LAMA"} {
+		if strings.TrimSpace(dotenv[nama]) != "" {
+			return fmt.Errorf("%s tidak boleh disimpan di %s, set lewat environment atau PII_MASTER_KEY_FILE di luar repository", nama, dotenvPath)
+		}
+	}
+
+	path := os.Getenv("PII_MASTER_KEY_FILE")
+	if path == "" {
+		path = dotenv["PII_MASTER_KEY_FILE"]
+	}
+	if path == "" {
+		return nil
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if diDalamDirektori(wd, path) {
+		return fmt.Errorf("PII_MASTER_KEY_FILE %s berada di dalam repository, pindahkan ke luar %s", path, wd)
+	}
+	return nil
+}
+
+// diDalamDirektori true jika path (setelah symlink dibuka) berada di dalam dir
+func diDalamDirektori(dir, path string) bool {
+	resolve := func(p string) string {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return p
+		}
+		if real, err := filepath.EvalSymlinks(abs); err == nil {
+			return real
+		}
+		return abs
+	}
+	rel, err := filepath.Rel(resolve(dir), resolve(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// DecodeKunci membaca kunci base64 (standar atau URL) sepanjang PanjangKunci
+func DecodeKunci(encoded string) ([]byte, error) {
+	encoded = strings.TrimSpace(encoded)
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		if key, err = base64.URLEncoding.DecodeString(encoded); err != nil {
+			return nil, errors.New("bukan base64")
+		}
+	}
+	if len(key) != PanjangKunci {
+		return nil, fmt.Errorf("panjang kunci harus %d byte", PanjangKunci)
+	}
+	return key, nil
+}
+
+// KunciAcak kunci baru dari crypto/rand
+func KunciAcak() ([]byte, error) {
+	key := make([]byte, PanjangKunci)
+	_, err := rand.Read(key)
+	return key, err
+}
+
+// Seal mengenkripsi plaintext dengan AES-GCM; hasilnya nonce diikuti ciphertext. aad mengikat ciphertext ke
+// konteksnya (misalnya nama kolom) sehingga tidak bisa dipindah ke kolom lain.
+func Seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// Open kebalikan Seal
+func Open(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext terlalu pendek")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// BlindIndex HMAC-SHA256 dari nilai yang sudah dinormalisasi, untuk pencarian tepat tanpa membuka enkripsi
+func BlindIndex(key []byte, konteks, nilai string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(konteks))
+	mac.Write([]byte{0})
+	mac.Write([]byte(nilai))
+	return hex.EncodeToString(mac.Sum(nil))
+}
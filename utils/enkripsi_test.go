@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func kunciTest(t *testing.T) []byte {
+	t.Helper()
+	key, err := KunciAcak()
+	if err != nil {
+		t.Fatalf("KunciAcak: %v", err)
+	}
+	return key
+}
+
+func TestSealOpen(t *testing.T) {
+	key := kunciTest(t)
+	sealed, err := Seal(key, []byte("budi@example.com"), []byte("alumni.email"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	plaintext, err := Open(key, sealed, []byte("alumni.email"))
+	if err != nil || string(plaintext) != "budi@example.com" {
+		t.Fatalf("Open = %q, %v", plaintext, err)
+	}
+
+	// nonce acak: plaintext yang sama menghasilkan ciphertext berbeda
+	lagi, _ := Seal(key, []byte("budi@example.com"), []byte("alumni.email"))
+	if bytes.Equal(sealed, lagi) {
+		t.Fatal("dua kali Seal menghasilkan ciphertext yang sama")
+	}
+}
+
+func TestOpenDitolak(t *testing.T) {
+	key := kunciTest(t)
+	sealed, err := Seal(key, []byte("Jl. Merdeka 1"), []byte("alumni.alamat"))
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if _, err := Open(key, sealed, []byte("alumni.email")); err == nil {
+		t.Error("AAD berbeda (nilai dipindah ke kolom lain) harus ditolak")
+	}
+	if _, err := Open(kunciTest(t), sealed, []byte("alumni.alamat")); err == nil {
+		t.Error("kunci berbeda harus ditolak")
+	}
+	rusak := append([]byte(nil), sealed...)
+	rusak[len(rusak)-1] ^= 1
+	if _, err := Open(key, rusak, []byte("alumni.alamat")); err == nil {
+		t.Error("ciphertext yang diubah harus ditolak")
+	}
+	if _, err := Open(key, sealed[:4], []byte("alumni.alamat")); err == nil {
+		t.Error("ciphertext terpotong harus ditolak")
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	key := kunciTest(t)
+	a := BlindIndex(key, "alumni.email", "budi@example.com")
+	if a != BlindIndex(key, "alumni.email", "budi@example.com") {
+		t.Fatal("blind index harus deterministik")
+	}
+	if a == BlindIndex(key, "alumni.no_telepon", "budi@example.com") {
+		t.Error("konteks berbeda harus menghasilkan blind index berbeda")
+	}
+	if a == BlindIndex(kunciTest(t), "alumni.email", "budi@example.com") {
+		t.Error("kunci berbeda harus menghasilkan blind index berbeda")
+	}
+	// pemisah konteks dan nilai mencegah tabrakan "ab"+"c" dengan "a"+"bc"
+	if BlindIndex(key, "ab", "c") == BlindIndex(key, "a", "bc") {
+		t.Error("batas konteks dan nilai harus ikut di-hash")
+	}
+}
+
+func TestDecodeKunci(t *testing.T) {
+	key := kunciTest(t)
+	for _, encoded := range []string{
+		base64.StdEncoding.EncodeToString(key),
+		base64.URLEncoding.EncodeToString(key),
+		" " + base64.StdEncoding.EncodeToString(key) + "\n",
+	} {
+		got, err := DecodeKunci(encoded)
+		if err != nil || !bytes.Equal(got, key) {
+			t.Errorf("DecodeKunci(%q) = %x, %v", encoded, got, err)
+		}
+	}
+	for _, encoded := range []string{"bukan base64!", base64.StdEncoding.EncodeToString(key[:16])} {
+		if _, err := DecodeKunci(encoded); err == nil {
+			t.Errorf("DecodeKunci(%q) harus gagal", encoded)
+		}
+	}
+}
+
+func TestLoadMasterKeys(t *testing.T) {
+	aktif, lama := kunciTest(t), kunciTest(t)
+	t.Setenv("PII_MASTER_KEY_ID", "k2")
+	t.Setenv("PII_MASTER_KEY", base64.StdEncoding.EncodeToString(aktif))
+	t.Setenv("PII_MASTER_KEY_FILE", "")
+	t.Setenv("PII_MASTER_KEY_LAMA", "k1:"+base64.StdEncoding.EncodeToString(lama))
+
+	keys, err := LoadMasterKeys()
+	if err != nil {
+		t.Fatalf("LoadMasterKeys: %v", err)
+	}
+	if keys.AktifID != "k2" || !bytes.Equal(keys.Kunci["k2"], aktif) || !bytes.Equal(keys.Kunci["k1"], lama) {
+		t.Fatalf("keys = %+v", keys)
+	}
+
+	t.Setenv("PII_MASTER_KEY_LAMA", "k2:"+base64.StdEncoding.EncodeToString(lama))
+	if _, err := LoadMasterKeys(); err == nil {
+		t.Error("kunci lama dengan id aktif harus ditolak")
+	}
+
+	t.Setenv("PII_MASTER_KEY", "")
+	t.Setenv("PII_MASTER_KEY_LAMA", "")
+	if _, err := LoadMasterKeys(); err == nil {
+		t.Error("tanpa kunci master harus gagal")
+	}
+}
+
+func TestCekSumberMasterKey(t *testing.T) {
+	repo, luar := t.TempDir(), t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("PII_MASTER_KEY_FILE", "")
+
+	tulis := func(isi string) {
+		if err := os.WriteFile(filepath.Join(repo, ".env"), []byte(isi), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := CekSumberMasterKey(".env"); err != nil {
+		t.Errorf("tanpa .env: %v", err)
+	}
+	tulis("PII_MASTER_KEY_ID=dev-1\nPII_MASTER_KEY=\n")
+	if err := CekSumberMasterKey(".env"); err != nil {
+		t.Errorf("PII_MASTER_KEY kosong di .env: %v", err)
+	}
+	tulis("PII_MASTER_KEY=" + base64.StdEncoding.EncodeToString(kunciTest(t)) + "\n")
+	if err := CekSumberMasterKey(".env"); err == nil || !strings.Contains(err.Error(), "PII_MASTER_KEY") {
+		t.Errorf("kunci di .env harus ditolak, err = %v", err)
+	}
+	tulis("PII_MASTER_KEY_LAMA=k1:abc\n")
+	if err := CekSumberMasterKey(".env"); err == nil {
+		t.Error("kunci lama di .env harus ditolak")
+	}
+
+	tulis("PII_MASTER_KEY_FILE=rahasia/master.key\n")
+	if err := CekSumberMasterKey(".env"); err == nil {
+		t.Error("file kunci di dalam repository harus ditolak")
+	}
+	tulis("PII_MASTER_KEY_FILE=" + filepath.Join(luar, "master.key") + "\n")
+	if err := CekSumberMasterKey(".env"); err != nil {
+		t.Errorf("file kunci di luar repository: %v", err)
+	}
+	t.Setenv("PII_MASTER_KEY_FILE", filepath.Join(repo, "..", filepath.Base(repo), "master.key"))
+	if err := CekSumberMasterKey(".env"); err == nil {
+		t.Error("PII_MASTER_KEY_FILE dari environment yang menunjuk ke dalam repository harus ditolak")
+	}
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+)
+
+// MaxPikselGambar batas jumlah piksel gambar yang mau di-decode, untuk menolak "decompression bomb"
+const MaxPikselGambar = 40_000_000
+
+// BuatThumbnail mengecilkan gambar JPEG/PNG/GIF agar sisi terpanjangnya maxSisi piksel lalu meng-encode-nya
+// sebagai JPEG. Gambar yang sudah lebih kecil tidak diperbesar. Transparansi diganti latar putih.
+func BuatThumbnail(data []byte, maxSisi int) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPikselGambar {
+		return nil, fmt.Errorf("ukuran gambar %dx%d tidak didukung", cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxSisi || h > maxSisi {
+		if w >= h {
+			w, h = maxSisi, max(1, h*maxSisi/b.Dx())
+		} else {
+			w, h = max(1, w*maxSisi/b.Dy()), maxSisi
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resizeArea(src, w, h), &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resizeArea mengecilkan gambar dengan merata-ratakan seluruh piksel sumber yang jatuh ke setiap piksel
+// tujuan (box filter), sehingga hasilnya tidak bergerigi seperti nearest-neighbour
+func resizeArea(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	// Gambar sumber diratakan ke RGBA di atas latar putih agar piksel transparan tidak menjadi hitam
+	flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, b.Min, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*b.Dy()/h, max((y+1)*b.Dy()/h, y*b.Dy()/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := x*b.Dx()/w, max((x+1)*b.Dx()/w, x*b.Dx()/w+1)
+
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1; sy++ {
+				off := flat.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(flat.Pix[off])
+					g += uint64(flat.Pix[off+1])
+					bl += uint64(flat.Pix[off+2])
+					off += 4
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: 255})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"errors"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// ErrQRTerlaluPanjang data melebihi kapasitas QR code versi terbesar pada level koreksi M
+var ErrQRTerlaluPanjang = errors.New("data terlalu panjang untuk QR code")
+
+// BuatQRCode meng-encode data (koreksi error level M) sebagai PNG hitam-putih dengan quiet zone 4 modul.
+// skala adalah ukuran satu modul dalam piksel.
+func BuatQRCode(data string, skala int) ([]byte, error) {
+	if data == "" {
+		return nil, errors.New("data QR code kosong")
+	}
+	if skala <= 0 {
+		skala = 8
+	}
+	// dengan data tidak kosong dan level tetap, New hanya gagal jika data melebihi kapasitas versi 40
+	q, err := qrcode.New(data, qrcode.Medium)
+	if err != nil {
+		return nil, ErrQRTerlaluPanjang
+	}
+	// ukuran negatif: setiap modul berukuran -ukuran piksel
+	return q.PNG(-skala)
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestBuatQRCodeSkalaModul(t *testing.T) {
+	gambar, err := BuatQRCode("1.42.abcdef0123456789", 8)
+	if err != nil {
+		t.Fatalf("BuatQRCode: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(gambar))
+	if err != nil {
+		t.Fatalf("hasil bukan PNG valid: %v", err)
+	}
+	// versi 2 (25 modul) ditambah quiet zone 4 modul di setiap sisi
+	if sisi := img.Bounds().Dx(); sisi != (25+8)*8 || img.Bounds().Dy() != sisi {
+		t.Fatalf("ukuran gambar %v, ingin %dx%d", img.Bounds(), (25+8)*8, (25+8)*8)
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Fatal("quiet zone harus terang")
+	}
+	if r, _, _, _ := img.At(4*8, 4*8).RGBA(); r != 0 {
+		t.Fatal("sudut finder pattern harus gelap")
+	}
+}
+
+func TestBuatQRCodeTerlaluPanjang(t *testing.T) {
+	// kapasitas mode byte versi 40 level M adalah 2331 byte
+	if _, err := BuatQRCode(strings.Repeat("x", 2332), 8); !errors.Is(err, ErrQRTerlaluPanjang) {
+		t.Fatalf("err = %v, ingin ErrQRTerlaluPanjang", err)
+	}
+	if _, err := BuatQRCode(strings.Repeat("x", 2331), 1); err != nil {
+		t.Fatalf("data tepat sebesar kapasitas harus berhasil: %v", err)
+	}
+}